@@ -5,6 +5,8 @@ package common
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -256,14 +258,33 @@ func (c *Config) GetUF() (types.UF, error) {
 	}
 }
 
-// validateCNPJ validates CNPJ format (basic format validation, not digit verification)
+// Patterns of validateCNPJ: the formatting to remove, keeping the letters of
+// alphanumeric CNPJs, and the accepted clean CPF and CNPJ
+var (
+	documentFormatting = regexp.MustCompile(`[^0-9A-Z]`)
+	cpfPattern         = regexp.MustCompile(`^[0-9]{11}$`)
+	cnpjPattern        = regexp.MustCompile(`^[0-9A-Z]{12}[0-9]{2}$`)
+)
+
+// validateCNPJ validates CNPJ format (basic format validation, not digit verification).
+// CNPJs may be alphanumeric (IN RFB 2.229/2024): 12 letters or digits followed
+// by 2 numeric check digits.
 func validateCNPJ(cnpj string) error {
-	// Remove non-numeric characters
-	cnpjClean := regexp.MustCompile(`[^0-9]`).ReplaceAllString(cnpj, "")
+	// Remove formatting characters, keeping the letters of alphanumeric CNPJs
+	cnpjClean := documentFormatting.ReplaceAllString(strings.ToUpper(cnpj), "")
 
 	// Check length (11 for CPF, 14 for CNPJ)
-	if len(cnpjClean) != 11 && len(cnpjClean) != 14 {
-		return fmt.Errorf("CNPJ/CPF must have 11 or 14 digits, got %d", len(cnpjClean))
+	switch len(cnpjClean) {
+	case 11:
+		if !cpfPattern.MatchString(cnpjClean) {
+			return fmt.Errorf("CPF must have only digits")
+		}
+	case 14:
+		if !cnpjPattern.MatchString(cnpjClean) {
+			return fmt.Errorf("CNPJ must have 12 alphanumeric characters followed by 2 check digits")
+		}
+	default:
+		return fmt.Errorf("CNPJ/CPF must have 11 or 14 characters, got %d", len(cnpjClean))
 	}
 
 	// Check if all digits are the same
@@ -296,7 +317,8 @@ func validateUF(uf string) error {
 	return fmt.Errorf("invalid UF: %s", uf)
 }
 
-// validateProxyConfig validates proxy configuration
+// validateProxyConfig validates proxy configuration. The host may be an IPv4
+// or IPv6 address (with or without brackets) or a hostname.
 func validateProxyConfig(proxy *ProxyConfig) error {
 	if proxy == nil {
 		return nil
@@ -313,18 +335,79 @@ func validateProxyConfig(proxy *ProxyConfig) error {
 		}
 	}
 
-	// Validate proxy IP format if provided
-	if proxy.ProxyIP != nil && *proxy.ProxyIP != "" {
-		// Basic IP format validation (IPv4)
-		ipRegex := regexp.MustCompile(`^(\d{1,3}\.){3}\d{1,3}$`)
-		if !ipRegex.MatchString(*proxy.ProxyIP) {
-			return errors.NewConfigError("invalid proxy IP format", "proxyIp", *proxy.ProxyIP)
+	// Validate proxy host format if provided
+	host := proxyValue(proxy.ProxyIP)
+	if host != "" {
+		if !isValidProxyHost(host) {
+			return errors.NewConfigError("invalid proxy host format", "proxyIp", host)
+		}
+		if proxyValue(proxy.ProxyPort) == "" {
+			return errors.NewConfigError("proxy port is required with the proxy host", "proxyPort", "")
 		}
 	}
 
+	// Validate credentials; basic auth cannot carry a colon in the user
+	user := proxyValue(proxy.ProxyUser)
+	if strings.Contains(user, ":") {
+		return errors.NewConfigError("proxy user cannot contain ':'", "proxyUser", user)
+	}
+	if user == "" && proxyValue(proxy.ProxyPass) != "" {
+		return errors.NewConfigError("proxy password requires a proxy user", "proxyUser", "")
+	}
+
 	return nil
 }
 
+// hostnamePattern matches a DNS hostname (RFC 1123)
+var hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// isValidProxyHost checks if host is an IP address or a hostname. Names whose
+// last label is numeric are rejected, as they are malformed IPv4 addresses.
+func isValidProxyHost(host string) bool {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		ip := net.ParseIP(host[1 : len(host)-1])
+		return ip != nil && ip.To4() == nil
+	}
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	if len(host) > 253 || !hostnamePattern.MatchString(host) {
+		return false
+	}
+	labels := strings.Split(host, ".")
+	_, err := strconv.Atoi(labels[len(labels)-1])
+	return err != nil
+}
+
+// proxyValue returns the trimmed value of an optional proxy setting
+func proxyValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
+
+// URL returns the address of the proxy, with the credentials for basic
+// authentication, or nil when no proxy host is configured
+func (p *ProxyConfig) URL() (*url.URL, error) {
+	if p == nil || proxyValue(p.ProxyIP) == "" {
+		return nil, nil
+	}
+	if err := validateProxyConfig(p); err != nil {
+		return nil, err
+	}
+
+	host := strings.TrimSuffix(strings.TrimPrefix(proxyValue(p.ProxyIP), "["), "]")
+	proxyURL := &url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(host, proxyValue(p.ProxyPort)),
+	}
+	if user := proxyValue(p.ProxyUser); user != "" {
+		proxyURL.User = url.UserPassword(user, proxyValue(p.ProxyPass))
+	}
+	return proxyURL, nil
+}
+
 // isAllSameDigits checks if all digits in the string are the same
 func isAllSameDigits(s string) bool {
 	if len(s) == 0 {
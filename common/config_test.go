@@ -83,6 +83,9 @@ func TestValidateConfigInvalidCNPJ(t *testing.T) {
 		{"123456789012345", true, "too long"},
 		{"11111111111111", true, "all same digits"},
 		{"abc123def456", true, "contains letters"},
+		{"12.ABC.345/01DE-35", false, "alphanumeric CNPJ"},
+		{"12ABC34501DEAB", true, "letters in the check digits"},
+		{"1234567890A", true, "CPF with letters"},
 	}
 
 	for _, test := range tests {
@@ -307,7 +310,14 @@ func TestValidateProxyConfig(t *testing.T) {
 	validPort := "8080"
 	validIP := "192.168.1.1"
 	invalidPort := "99999"
-	invalidIP := "invalid.ip"
+	invalidIP := "invalid_ip"
+	hostname := "proxy.empresa.local"
+	ipv6 := "2001:db8::1"
+	bracketedIPv6 := "[2001:db8::1]"
+	badIPv4 := "192.168.1.300"
+	user := "usuario"
+	userWithColon := "usu:ario"
+	pass := "senha"
 
 	tests := []struct {
 		proxy       *ProxyConfig
@@ -318,7 +328,15 @@ func TestValidateProxyConfig(t *testing.T) {
 		{&ProxyConfig{}, false, "empty proxy config"},
 		{&ProxyConfig{ProxyPort: &validPort, ProxyIP: &validIP}, false, "valid proxy config"},
 		{&ProxyConfig{ProxyPort: &invalidPort}, true, "invalid port number"},
-		{&ProxyConfig{ProxyIP: &invalidIP}, true, "invalid IP format"},
+		{&ProxyConfig{ProxyIP: &invalidIP, ProxyPort: &validPort}, true, "invalid host format"},
+		{&ProxyConfig{ProxyIP: &hostname, ProxyPort: &validPort}, false, "hostname"},
+		{&ProxyConfig{ProxyIP: &ipv6, ProxyPort: &validPort}, false, "IPv6 address"},
+		{&ProxyConfig{ProxyIP: &bracketedIPv6, ProxyPort: &validPort}, false, "bracketed IPv6 address"},
+		{&ProxyConfig{ProxyIP: &badIPv4, ProxyPort: &validPort}, true, "IPv4 address out of range"},
+		{&ProxyConfig{ProxyIP: &validIP}, true, "host without port"},
+		{&ProxyConfig{ProxyIP: &validIP, ProxyPort: &validPort, ProxyUser: &user, ProxyPass: &pass}, false, "credentials"},
+		{&ProxyConfig{ProxyIP: &validIP, ProxyPort: &validPort, ProxyPass: &pass}, true, "password without user"},
+		{&ProxyConfig{ProxyIP: &validIP, ProxyPort: &validPort, ProxyUser: &userWithColon}, true, "user with colon"},
 	}
 
 	for _, test := range tests {
@@ -331,3 +349,27 @@ func TestValidateProxyConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestProxyConfigURL(t *testing.T) {
+	host := "2001:db8::1"
+	port := "3128"
+	user := "usuario"
+	pass := "s3nh@"
+
+	proxyURL, err := (&ProxyConfig{ProxyIP: &host, ProxyPort: &port, ProxyUser: &user, ProxyPass: &pass}).URL()
+	if err != nil {
+		t.Fatalf("URL should not return error, got: %v", err)
+	}
+	if proxyURL.String() != "http://usuario:s3nh%40@[2001:db8::1]:3128" {
+		t.Errorf("Unexpected proxy URL %s", proxyURL)
+	}
+
+	proxyURL, err = (&ProxyConfig{}).URL()
+	if err != nil || proxyURL != nil {
+		t.Errorf("Empty proxy config should return nil URL, got %v, %v", proxyURL, err)
+	}
+
+	if _, err := (&ProxyConfig{ProxyIP: &host}).URL(); err == nil {
+		t.Error("URL should validate the configuration")
+	}
+}
@@ -0,0 +1,76 @@
+package danfe
+
+import (
+	"testing"
+)
+
+func TestCode128Patterns(t *testing.T) {
+	for i, pattern := range code128Patterns {
+		sum := 0
+		for _, c := range pattern {
+			sum += int(c - '0')
+		}
+
+		expected := 11
+		if i == code128Stop {
+			expected = 13
+		}
+
+		if sum != expected {
+			t.Errorf("Pattern %d (%s) should have %d modules, got %d", i, pattern, expected, sum)
+		}
+	}
+}
+
+func TestEncodeCode128(t *testing.T) {
+	tests := []struct {
+		data        string
+		symbols     int
+		shouldError bool
+		description string
+	}{
+		{"35240511222333000181550010000012341123456789", 22 + 3, false, "access key uses code set C"},
+		{"ABC-123", 7 + 3, false, "alphanumeric uses code set B"},
+		{"123", 3 + 3, false, "odd numeric uses code set B"},
+		{"", 0, true, "empty data"},
+		{"ação", 0, true, "characters outside code set B"},
+	}
+
+	for _, test := range tests {
+		modules, err := EncodeCode128(test.data)
+		if test.shouldError {
+			if err == nil {
+				t.Errorf("EncodeCode128(%q) (%s) should return error", test.data, test.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("EncodeCode128(%q) (%s) should not return error, got: %v", test.data, test.description, err)
+			continue
+		}
+
+		// Each symbol has 6 elements, the stop symbol has 7
+		expected := test.symbols*6 + 1
+		if len(modules) != expected {
+			t.Errorf("EncodeCode128(%q) (%s) expected %d elements, got %d", test.data, test.description, expected, len(modules))
+		}
+	}
+}
+
+func TestEncodeCode128Checksum(t *testing.T) {
+	// Start B (104) + P(48)*1 + J(42)*2 + J(42)*3 + 1(17)*4 + 2(18)*5 + 3(19)*6 + C(35)*7 = 879; 879 % 103 = 55
+	modules, err := EncodeCode128("PJJ123C")
+	if err != nil {
+		t.Fatalf("EncodeCode128 should not return error, got: %v", err)
+	}
+
+	checksumStart := (1 + 7) * 6
+	checksum := ""
+	for _, m := range modules[checksumStart : checksumStart+6] {
+		checksum += string(rune('0' + m))
+	}
+
+	if checksum != code128Patterns[55] {
+		t.Errorf("Expected checksum pattern %s, got %s", code128Patterns[55], checksum)
+	}
+}
@@ -0,0 +1,311 @@
+package danfe
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+	"github.com/adrianodrix/sped-nfe-go/types"
+	"github.com/adrianodrix/sped-nfe-go/utils"
+)
+
+// CCeCondicaoUso is the standard "condições de uso" text of the Carta de Correção,
+// printed when the event XML does not carry xCondUso
+const CCeCondicaoUso = "A Carta de Correcao e disciplinada pelo paragrafo 1o-A do art. 7o do Convenio S/N, " +
+	"de 15 de dezembro de 1970 e pode ser utilizada para regularizacao de erro ocorrido na emissao de " +
+	"documento fiscal, desde que o erro nao esteja relacionado com: I - as variaveis que determinam o " +
+	"valor do imposto tais como: base de calculo, aliquota, diferenca de preco, quantidade, valor da " +
+	"operacao ou da prestacao; II - a correcao de dados cadastrais que implique mudanca do remetente ou " +
+	"do destinatario; III - a data de emissao ou de saida."
+
+// Emitter holds the issuer data printed in the header of DANFE and event
+// printouts. Events only carry the author CNPJ/CPF, so the remaining data
+// must be supplied by the caller.
+type Emitter struct {
+	Name     string
+	Address  string
+	District string
+	City     string
+	UF       string
+	CEP      string
+	Phone    string
+	CNPJ     string
+	IE       string
+}
+
+// EventoPrinter renders the printout of CC-e and cancellation events
+type EventoPrinter struct {
+	proc    *nfe.ProcEventoNFe
+	emitter *Emitter
+}
+
+// page layout
+const (
+	marginLeft   = 10.0
+	contentWidth = A4Width - 2*marginLeft
+)
+
+// NewEventoPrinter creates a printer for an event registered by SEFAZ
+// (cStat 135, 136 or 155). Only CC-e and cancellation events are supported.
+func NewEventoPrinter(proc *nfe.ProcEventoNFe, emitter *Emitter) (*EventoPrinter, error) {
+	if proc == nil {
+		return nil, errors.NewValidationError("event cannot be nil", "procEventoNFe", "")
+	}
+	if !proc.IsRegistered() {
+		return nil, errors.NewValidationError("event not registered by SEFAZ", "cStat", proc.RetEvento.InfEvento.CStat)
+	}
+
+	switch proc.Evento.InfEvento.TpEvento {
+	case types.EvtCCe, types.EvtCancela, types.EvtCancelaSubstituicao:
+	default:
+		return nil, errors.NewValidationError("event type not supported for printing", "tpEvento", proc.Evento.InfEvento.TpEvento)
+	}
+
+	header := Emitter{}
+	if emitter != nil {
+		header = *emitter
+	}
+	if header.CNPJ == "" {
+		header.CNPJ = proc.Evento.InfEvento.CNPJ + proc.Evento.InfEvento.CPF
+	}
+
+	return &EventoPrinter{
+		proc:    proc,
+		emitter: &header,
+	}, nil
+}
+
+// RenderEvento parses a procEventoNFe XML and returns its PDF printout
+func RenderEvento(procEventoXML []byte, emitter *Emitter) ([]byte, error) {
+	proc, err := nfe.ParseProcEventoNFe(procEventoXML)
+	if err != nil {
+		return nil, err
+	}
+
+	printer, err := NewEventoPrinter(proc, emitter)
+	if err != nil {
+		return nil, err
+	}
+
+	return printer.Render()
+}
+
+// Render returns the PDF bytes of the event printout
+func (p *EventoPrinter) Render() ([]byte, error) {
+	doc, err := p.document()
+	if err != nil {
+		return nil, err
+	}
+	return doc.Bytes()
+}
+
+// WriteTo writes the PDF printout into w
+func (p *EventoPrinter) WriteTo(w io.Writer) (int64, error) {
+	doc, err := p.document()
+	if err != nil {
+		return 0, err
+	}
+	return doc.WriteTo(w)
+}
+
+// document lays out the printout
+func (p *EventoPrinter) document() (*Document, error) {
+	inf := p.proc.Evento.InfEvento
+	ret := p.proc.RetEvento.InfEvento
+
+	doc := NewDocument()
+	doc.SetTitle(p.title())
+	page := doc.AddPage()
+
+	y := 10.0
+	y = p.drawHeader(page, y)
+
+	y += 3
+	next, err := p.drawAccessKey(page, y)
+	if err != nil {
+		return nil, err
+	}
+	y = next
+
+	if inf.TpEvento == types.EvtCCe {
+		condUso := inf.DetEvento.XCondUso
+		if condUso == "" {
+			condUso = CCeCondicaoUso
+		}
+		y = p.drawTextBlock(page, y+3, "Condições de uso", condUso, 7)
+		y = p.drawTextBlock(page, y+3, "Correções a serem consideradas", inf.DetEvento.XCorrecao, 9)
+	} else {
+		y = p.drawTextBlock(page, y+3, "Justificativa do cancelamento", inf.DetEvento.XJust, 9)
+		page.Field(marginLeft, y+1, contentWidth/2, 9, "Protocolo de autorização da NF-e", inf.DetEvento.NProt, AlignLeft)
+		if inf.DetEvento.ChNFeRef != "" {
+			page.Field(marginLeft+contentWidth/2, y+1, contentWidth/2, 9, "Chave da NF-e substituta", inf.DetEvento.ChNFeRef, AlignLeft)
+		}
+		y += 10
+	}
+
+	y += 3
+	page.Field(marginLeft, y, contentWidth, 9, "Situação do evento",
+		fmt.Sprintf("%d - %s", ret.CStat, ret.XMotivo), AlignLeft)
+	y += 12
+
+	page.Paragraph(marginLeft, y, contentWidth, FontRegular, 7,
+		"Este documento é uma representação gráfica do evento e foi impresso apenas para sua informação, "+
+			"não possuindo validade fiscal. O evento deve ser recebido e mantido em arquivo eletrônico XML "+
+			"e pode ser consultado através dos portais das SEFAZ.")
+
+	if inf.TpAmb == types.TaHomologacao {
+		page.AlignedText(0, A4Height/2+40, A4Width, FontBold, 28, "SEM VALOR FISCAL", AlignCenter)
+		page.AlignedText(0, A4Height/2+50, A4Width, FontBold, 12, "EMITIDO EM AMBIENTE DE HOMOLOGAÇÃO", AlignCenter)
+	}
+
+	return doc, nil
+}
+
+// drawHeader draws the emitter block and the event identification block
+func (p *EventoPrinter) drawHeader(page *Page, y float64) float64 {
+	inf := p.proc.Evento.InfEvento
+	ret := p.proc.RetEvento.InfEvento
+	height := 32.0
+	leftWidth := 110.0
+	rightX := marginLeft + leftWidth
+	rightWidth := contentWidth - leftWidth
+
+	page.Rect(marginLeft, y, leftWidth, height, 0.2)
+	page.Rect(rightX, y, rightWidth, height, 0.2)
+
+	used := page.Paragraph(marginLeft+2, y+1, leftWidth-4, FontBold, 10, p.emitter.Name)
+	line := y + 2 + used
+	for _, text := range p.emitterLines() {
+		line += LineHeight(8)
+		page.Text(marginLeft+2, line, FontRegular, 8, text)
+	}
+
+	title, subtitle := p.titles()
+	page.AlignedText(rightX, y+6, rightWidth, FontBold, 10, title, AlignCenter)
+	page.AlignedText(rightX, y+10.5, rightWidth, FontRegular, 8, subtitle, AlignCenter)
+	page.Text(rightX+2, y+15, FontRegular, 7, "ID do evento:")
+	page.Text(rightX+2, y+18.5, FontRegular, 6.5, strings.TrimPrefix(inf.ID, "ID"))
+	page.Text(rightX+2, y+22.5, FontRegular, 7, fmt.Sprintf("Criado em: %s   Sequência: %d", formatDateTime(inf.DhEvento), inf.NSeqEvento))
+	page.Text(rightX+2, y+26, FontRegular, 7, "Protocolo: "+ret.NProt)
+	page.Text(rightX+2, y+29.5, FontRegular, 7, "Registrado em: "+formatDateTime(ret.DhRegEvento))
+
+	return y + height
+}
+
+// drawAccessKey draws the NF-e access key barcode and its decoded fields
+func (p *EventoPrinter) drawAccessKey(page *Page, y float64) (float64, error) {
+	chave := p.proc.Evento.InfEvento.ChNFe
+	height := 24.0
+	keyWidth := 120.0
+
+	page.Rect(marginLeft, y, keyWidth, height, 0.2)
+	page.Text(marginLeft+1, y+2.4, FontRegular, 5.5, "CHAVE DE ACESSO DA NF-E")
+	if err := page.Barcode128(marginLeft+5, y+4, keyWidth-10, 12, chave); err != nil {
+		return y, err
+	}
+
+	formatted, err := utils.FormatAccessKey(chave)
+	if err != nil {
+		formatted = chave
+	}
+	page.AlignedText(marginLeft, y+21, keyWidth, FontBold, 9, formatted, AlignCenter)
+
+	fieldX := marginLeft + keyWidth
+	fieldWidth := (contentWidth - keyWidth) / 2
+	components, err := utils.ParseAccessKey(chave)
+	if err == nil {
+		page.Field(fieldX, y, fieldWidth, height/2, "Modelo", fmt.Sprintf("%d", int(components.Model)), AlignCenter)
+		page.Field(fieldX+fieldWidth, y, fieldWidth, height/2, "Série", fmt.Sprintf("%d", components.Series), AlignCenter)
+		page.Field(fieldX, y+height/2, fieldWidth, height/2, "Número", fmt.Sprintf("%09d", components.Number), AlignCenter)
+		page.Field(fieldX+fieldWidth, y+height/2, fieldWidth, height/2, "Mês/ano da emissão",
+			components.DateTime.Format("01/2006"), AlignCenter)
+	} else {
+		page.Rect(fieldX, y, contentWidth-keyWidth, height, 0.2)
+	}
+
+	return y + height, nil
+}
+
+// drawTextBlock draws a captioned box sized to its wrapped text
+func (p *EventoPrinter) drawTextBlock(page *Page, y float64, caption, text string, size float64) float64 {
+	page.Section(marginLeft, y+2, caption)
+	y += 3
+	textHeight := float64(len(WrapText(text, FontRegular, size, contentWidth-4))) * LineHeight(size)
+	height := textHeight + 4
+	page.Rect(marginLeft, y, contentWidth, height, 0.2)
+	page.Paragraph(marginLeft+2, y+2, contentWidth-4, FontRegular, size, text)
+	return y + height
+}
+
+// emitterLines returns the address and document lines of the header
+func (p *EventoPrinter) emitterLines() []string {
+	e := p.emitter
+	lines := make([]string, 0, 4)
+	if e.Address != "" {
+		lines = append(lines, joinNonEmpty(" - ", e.Address, e.District))
+	}
+	if e.City != "" || e.UF != "" || e.CEP != "" {
+		cep := e.CEP
+		if cep != "" {
+			cep = "CEP " + utils.FormatCEP(cep)
+		}
+		lines = append(lines, joinNonEmpty(" - ", joinNonEmpty("/", e.City, e.UF), cep))
+	}
+	if e.Phone != "" {
+		lines = append(lines, "Fone: "+utils.FormatPhone(e.Phone))
+	}
+
+	document := e.CNPJ
+	if formatted, err := utils.FormatCNPJ(document); err == nil {
+		document = formatted
+	} else if formatted, err := utils.FormatCPF(document); err == nil {
+		document = formatted
+	}
+	lines = append(lines, joinNonEmpty("   ", "CNPJ/CPF: "+document, prefixNonEmpty("IE: ", e.IE)))
+	return lines
+}
+
+// title returns the document title stored in the PDF metadata
+func (p *EventoPrinter) title() string {
+	title, subtitle := p.titles()
+	return title + " " + subtitle
+}
+
+// titles returns the header title and subtitle for the event type
+func (p *EventoPrinter) titles() (string, string) {
+	if p.proc.Evento.InfEvento.TpEvento == types.EvtCCe {
+		return "Representação Gráfica de CC-e", "(Carta de Correção Eletrônica)"
+	}
+	return "Representação Gráfica de Evento", "(" + nfe.EventName(p.proc.Evento.InfEvento.TpEvento) + " de NF-e)"
+}
+
+// formatDateTime formats an XML date-time (AAAA-MM-DDThh:mm:ssTZD) as dd/mm/aaaa hh:mm:ss
+func formatDateTime(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Format("02/01/2006 15:04:05")
+}
+
+// joinNonEmpty joins the non-empty parts with sep
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if strings.TrimSpace(part) != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+// prefixNonEmpty prefixes value when it is not empty
+func prefixNonEmpty(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
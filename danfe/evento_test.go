@@ -0,0 +1,142 @@
+package danfe
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+func loadEvento(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("../nfe/testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+	return data
+}
+
+func TestRenderEventoCCe(t *testing.T) {
+	emitter := &Emitter{
+		Name:    "Empresa Exemplo LTDA",
+		Address: "Rua das Flores, 123",
+		City:    "São Paulo",
+		UF:      "SP",
+		CEP:     "01234567",
+		IE:      "123456789012",
+	}
+
+	pdf, err := RenderEvento(loadEvento(t, "procEventoNFe_cce.xml"), emitter)
+	if err != nil {
+		t.Fatalf("RenderEvento should not return error, got: %v", err)
+	}
+
+	content := string(pdf)
+	if !strings.HasPrefix(content, "%PDF-1.4") {
+		t.Error("Output should start with the PDF header")
+	}
+
+	if !strings.HasSuffix(content, "%%EOF\n") {
+		t.Error("Output should end with the PDF trailer")
+	}
+
+	expected := []string{
+		"Representa\xe7\xe3o Gr\xe1fica de CC-e",
+		"EMPRESA EXEMPLO LTDA",
+		"11.222.333/0001-81",
+		"3524 0511 2223 3300 0181 5500 1000 0012 3411 2345 6789",
+		"CONDI\xc7\xd5ES DE USO",
+		"Rua das Flores, 21",
+		"135240000012345",
+		"SEM VALOR FISCAL",
+	}
+	for _, text := range expected {
+		if !strings.Contains(strings.ToUpper(content), strings.ToUpper(text)) {
+			t.Errorf("PDF should contain %q", text)
+		}
+	}
+}
+
+func TestRenderEventoCancelamento(t *testing.T) {
+	pdf, err := RenderEvento(loadEvento(t, "procEventoNFe_cancel.xml"), nil)
+	if err != nil {
+		t.Fatalf("RenderEvento should not return error, got: %v", err)
+	}
+
+	content := string(pdf)
+	if !strings.Contains(content, "JUSTIFICATIVA DO CANCELAMENTO") {
+		t.Error("Cancellation printout should contain the justification section")
+	}
+
+	if !strings.Contains(content, "135240000000001") {
+		t.Error("Cancellation printout should contain the authorization protocol")
+	}
+
+	if strings.Contains(content, "CONDI\xc7\xd5ES DE USO") {
+		t.Error("Cancellation printout should not contain the CC-e conditions of use")
+	}
+
+	if strings.Contains(content, "SEM VALOR FISCAL") {
+		t.Error("Production event should not be marked as without fiscal value")
+	}
+}
+
+func TestNewEventoPrinterUnsupported(t *testing.T) {
+	proc, err := nfe.ParseProcEventoNFe(loadEvento(t, "procEventoNFe_cce.xml"))
+	if err != nil {
+		t.Fatalf("failed to parse event: %v", err)
+	}
+
+	proc.Evento.InfEvento.TpEvento = types.EvtCiencia
+	if _, err := NewEventoPrinter(proc, nil); err == nil {
+		t.Error("NewEventoPrinter should reject unsupported event types")
+	}
+
+	if _, err := NewEventoPrinter(nil, nil); err == nil {
+		t.Error("NewEventoPrinter should reject nil events")
+	}
+}
+
+func TestNewEventoPrinterNotRegistered(t *testing.T) {
+	proc, err := nfe.ParseProcEventoNFe(loadEvento(t, "procEventoNFe_cce.xml"))
+	if err != nil {
+		t.Fatalf("failed to parse event: %v", err)
+	}
+
+	for _, cStat := range []int{135, 136, 155} {
+		proc.RetEvento.InfEvento.CStat = cStat
+		if _, err := NewEventoPrinter(proc, nil); err != nil {
+			t.Errorf("NewEventoPrinter should accept cStat %d, got: %v", cStat, err)
+		}
+	}
+
+	proc.RetEvento.InfEvento.CStat = 573
+	if _, err := NewEventoPrinter(proc, nil); err == nil {
+		t.Error("NewEventoPrinter should reject events not registered by SEFAZ")
+	}
+}
+
+func TestEventoPrinterWriteTo(t *testing.T) {
+	proc, err := nfe.ParseProcEventoNFe(loadEvento(t, "procEventoNFe_cce.xml"))
+	if err != nil {
+		t.Fatalf("failed to parse event: %v", err)
+	}
+
+	printer, err := NewEventoPrinter(proc, &Emitter{Name: "Empresa"})
+	if err != nil {
+		t.Fatalf("NewEventoPrinter should not return error, got: %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := printer.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo should not return error, got: %v", err)
+	}
+
+	if n != int64(buf.Len()) || n == 0 {
+		t.Errorf("WriteTo reported %d bytes, buffer has %d", n, buf.Len())
+	}
+}
@@ -0,0 +1,80 @@
+package danfe
+
+import (
+	"github.com/adrianodrix/sped-nfe-go/utils"
+)
+
+// Font identifies one of the PDF base-14 fonts embedded by reference
+type Font int
+
+const (
+	// FontRegular is Helvetica
+	FontRegular Font = iota
+	// FontBold is Helvetica-Bold
+	FontBold
+)
+
+// resourceName returns the font resource name used in content streams
+func (f Font) resourceName() string {
+	if f == FontBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// baseFont returns the PDF base font name
+func (f Font) baseFont() string {
+	if f == FontBold {
+		return "Helvetica-Bold"
+	}
+	return "Helvetica"
+}
+
+// helveticaWidths holds the AFM glyph widths of Helvetica for ASCII 32..126
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// helveticaBoldWidths holds the AFM glyph widths of Helvetica-Bold for ASCII 32..126
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// glyphWidth returns the width of a rune in thousandths of the font size.
+// Accented Latin letters share the width of their base letter.
+func glyphWidth(font Font, r rune) int {
+	table := &helveticaWidths
+	if font == FontBold {
+		table = &helveticaBoldWidths
+	}
+
+	if r >= 32 && r <= 126 {
+		return table[r-32]
+	}
+
+	base := []rune(utils.RemoveAccents(string(r)))
+	if len(base) == 1 && base[0] >= 32 && base[0] <= 126 {
+		return table[base[0]-32]
+	}
+
+	return 556
+}
+
+// TextWidth returns the width in millimeters of a string set in the given font and size
+func TextWidth(s string, font Font, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		total += glyphWidth(font, r)
+	}
+	return float64(total) * size / 1000 * ptToMM
+}
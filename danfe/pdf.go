@@ -0,0 +1,273 @@
+// Package danfe provides printable representations (PDF) of NFe documents and
+// fiscal events. It ships a small dependency-free PDF writer with the layout
+// primitives shared by the DANFE and event printouts: labelled fields, wrapped
+// paragraphs, boxes and Code-128 barcodes.
+package danfe
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// Unit conversion factors between millimeters and PDF points
+const (
+	mmToPt = 72 / 25.4
+	ptToMM = 25.4 / 72
+)
+
+// A4 page size in millimeters
+const (
+	A4Width  = 210.0
+	A4Height = 297.0
+)
+
+// Align represents horizontal text alignment
+type Align int
+
+const (
+	// AlignLeft aligns text to the left edge
+	AlignLeft Align = iota
+	// AlignCenter centers text
+	AlignCenter
+	// AlignRight aligns text to the right edge
+	AlignRight
+)
+
+// Document is a minimal PDF 1.4 document made of pages drawn with
+// millimeter coordinates measured from the top-left corner
+type Document struct {
+	width  float64
+	height float64
+	pages  []*Page
+	title  string
+}
+
+// Page holds the content stream of a single page
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// NewDocument creates an empty A4 portrait document
+func NewDocument() *Document {
+	return &Document{
+		width:  A4Width,
+		height: A4Height,
+	}
+}
+
+// SetTitle sets the document title stored in the PDF metadata
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// AddPage appends a new blank page and returns it
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// PageCount returns the number of pages in the document
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Bytes renders the document and returns the PDF bytes
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo renders the document into w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		return 0, errors.NewValidationError("PDF document has no pages", "pages", 0)
+	}
+
+	var out bytes.Buffer
+	offsets := make([]int, 0)
+
+	// Object numbering: 1 catalog, 2 pages, 3-4 fonts, 5 info, then page/content pairs
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+i*2)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, font := range []Font{FontRegular, FontBold} {
+		writeObject(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.baseFont()))
+	}
+	writeObject(fmt.Sprintf("<< /Producer (sped-nfe-go) /Title (%s) >>", escapePDFString(d.title)))
+
+	for i, page := range d.pages {
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			d.width*mmToPt, d.height*mmToPt, 7+i*2))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xrefOffset := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// Width returns the page width in millimeters
+func (p *Page) Width() float64 {
+	return p.doc.width
+}
+
+// Height returns the page height in millimeters
+func (p *Page) Height() float64 {
+	return p.doc.height
+}
+
+// Text draws a single line of text with its baseline at (x, y)
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font.resourceName(), size, x*mmToPt, p.flipY(y), escapePDFString(text))
+}
+
+// AlignedText draws text aligned inside the horizontal span [x, x+w]
+func (p *Page) AlignedText(x, y, w float64, font Font, size float64, text string, align Align) {
+	switch align {
+	case AlignCenter:
+		x += (w - TextWidth(text, font, size)) / 2
+	case AlignRight:
+		x += w - TextWidth(text, font, size)
+	}
+	p.Text(x, y, font, size, text)
+}
+
+// Paragraph draws text wrapped to width w starting at the top y and
+// returns the height used in millimeters
+func (p *Page) Paragraph(x, y, w float64, font Font, size float64, text string) float64 {
+	lines := WrapText(text, font, size, w)
+	lineHeight := LineHeight(size)
+	for i, line := range lines {
+		p.Text(x, y+lineHeight*float64(i+1)-lineHeight*0.25, font, size, line)
+	}
+	return lineHeight * float64(len(lines))
+}
+
+// Line draws a straight line
+func (p *Page) Line(x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+		lineWidth*mmToPt, x1*mmToPt, p.flipY(y1), x2*mmToPt, p.flipY(y2))
+}
+
+// Rect draws the outline of a rectangle whose top-left corner is (x, y)
+func (p *Page) Rect(x, y, w, h, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f %.2f %.2f re S\n",
+		lineWidth*mmToPt, x*mmToPt, p.flipY(y+h), w*mmToPt, h*mmToPt)
+}
+
+// FillRect fills a rectangle with a gray level (0 = black, 1 = white)
+func (p *Page) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(&p.content, "q %.3f g %.2f %.2f %.2f %.2f re f Q\n",
+		gray, x*mmToPt, p.flipY(y+h), w*mmToPt, h*mmToPt)
+}
+
+// Field draws a DANFE style field: a box with a small caption on the top-left
+// corner and the value below it
+func (p *Page) Field(x, y, w, h float64, label, value string, align Align) {
+	p.Rect(x, y, w, h, 0.2)
+	p.Text(x+1, y+2.4, FontRegular, 5.5, strings.ToUpper(label))
+	p.AlignedText(x+1, y+h-1.6, w-2, FontBold, 9, value, align)
+}
+
+// Section draws a section caption above a block, as used between DANFE groups
+func (p *Page) Section(x, y float64, title string) {
+	p.Text(x, y, FontBold, 7, strings.ToUpper(title))
+}
+
+// flipY converts a top-based millimeter coordinate into a PDF bottom-based point coordinate
+func (p *Page) flipY(y float64) float64 {
+	return (p.doc.height - y) * mmToPt
+}
+
+// LineHeight returns the line height in millimeters for a font size in points
+func LineHeight(size float64) float64 {
+	return size * 1.25 * ptToMM
+}
+
+// WrapText breaks text into lines that fit in the given width.
+// Explicit line breaks are kept; a single word wider than the line is not split.
+func WrapText(text string, font Font, size, width float64) []string {
+	lines := make([]string, 0)
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		current := ""
+		for _, word := range words {
+			candidate := word
+			if current != "" {
+				candidate = current + " " + word
+			}
+			if TextWidth(candidate, font, size) <= width || current == "" {
+				current = candidate
+				continue
+			}
+			lines = append(lines, current)
+			current = word
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// escapePDFString encodes text as WinAnsi and escapes PDF string delimiters.
+// Characters without a WinAnsi representation are replaced.
+func escapePDFString(s string) string {
+	encoder := encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder())
+	encoded, err := encoder.String(s)
+	if err != nil {
+		encoded = s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(encoded); i++ {
+		c := encoded[i]
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package danfe
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocumentWithoutPages(t *testing.T) {
+	doc := NewDocument()
+	if _, err := doc.Bytes(); err == nil {
+		t.Error("Rendering a document without pages should return error")
+	}
+}
+
+func TestDocumentXrefOffsets(t *testing.T) {
+	doc := NewDocument()
+	doc.AddPage().Text(10, 10, FontRegular, 10, "Primeira página")
+	doc.AddPage().Rect(10, 10, 50, 20, 0.2)
+
+	pdf, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes should not return error, got: %v", err)
+	}
+	content := string(pdf)
+
+	if doc.PageCount() != 2 || !strings.Contains(content, "/Count 2") {
+		t.Error("Document should have 2 pages")
+	}
+
+	// Every xref entry must point at the start of its object
+	xref := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(content, -1)
+	if len(xref) == 0 {
+		t.Fatal("xref table should have entries")
+	}
+	for i, entry := range xref {
+		offset, _ := strconv.Atoi(entry[1])
+		expected := fmt.Sprintf("%d 0 obj", i+1)
+		if !strings.HasPrefix(content[offset:], expected) {
+			t.Errorf("xref entry %d should point to %q", i+1, expected)
+		}
+	}
+}
+
+func TestEscapePDFString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"simples", "simples"},
+		{"(parênteses)", "\\(par\xeanteses\\)"},
+		{"barra \\", "barra \\\\"},
+		{"ação", "a\xe7\xe3o"},
+	}
+
+	for _, test := range tests {
+		if result := escapePDFString(test.input); result != test.expected {
+			t.Errorf("escapePDFString(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	text := strings.Repeat("palavra ", 40)
+	lines := WrapText(text, FontRegular, 9, 60)
+
+	if len(lines) < 2 {
+		t.Fatalf("Long text should wrap, got %d lines", len(lines))
+	}
+
+	for _, line := range lines {
+		if TextWidth(line, FontRegular, 9) > 60 {
+			t.Errorf("Line %q exceeds the box width", line)
+		}
+	}
+
+	if len(WrapText("linha 1\nlinha 2", FontRegular, 9, 100)) != 2 {
+		t.Error("Explicit line breaks should be kept")
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if TextWidth("Ação", FontRegular, 10) != TextWidth("Acao", FontRegular, 10) {
+		t.Error("Accented letters should have the width of their base letter")
+	}
+
+	if TextWidth("W", FontBold, 10) <= TextWidth("i", FontBold, 10) {
+		t.Error("W should be wider than i")
+	}
+}
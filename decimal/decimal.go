@@ -0,0 +1,392 @@
+// Package decimal implements the fixed-point numbers of the NFe layout.
+//
+// A Decimal carries its number of decimal places, so a value built with
+// Money always renders with 2 decimals, a rate with 4 and a unit price with
+// up to 10, exactly as the layout fields require. Sums are exact and
+// products and quotients are rounded to an explicit precision with either
+// half-up or half-even (banker's) rounding, so totals of hundreds of items
+// never drift as float64 arithmetic does.
+//
+// The zero Decimal is unset: it reads as zero but is omitted when marshaled
+// to XML, which is how optional fields of the document model stay out of
+// the document. Values built by any constructor, parsed or computed are set,
+// including zeros.
+//
+// Example:
+//
+//	price := decimal.UnitPrice(10.5)
+//	qty := decimal.Quantity(3)
+//	total := price.Mul(qty, decimal.MoneyPlaces, decimal.RoundHalfUp) // 31.50
+package decimal
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// RoundingMode selects how ties are rounded
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds ties away from zero, the rule applied by most SEFAZ validations
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds ties to the even digit (ABNT NBR 5891, banker's rounding)
+	RoundHalfEven
+)
+
+// Decimal places of the layout fields
+const (
+	MoneyPlaces     = 2  // values (TDec_1302)
+	QuantityPlaces  = 4  // quantities (TDec_1104v)
+	RatePlaces      = 4  // rates and values per unit (TDec_0302a04, TDec_1104)
+	UnitPricePlaces = 10 // unit prices (TDec_1110v)
+	// MaxPlaces is the largest number of decimal places a Decimal holds
+	MaxPlaces = 18
+)
+
+// Decimal is a fixed-point decimal number: coef × 10^-places. The 128-bit
+// coefficient holds 38 significant digits, above the 21 of the largest layout
+// fields (TDec_1110v) and of their products; arithmetic beyond that panics,
+// as integer division by zero does.
+type Decimal struct {
+	coef   int128
+	places uint8
+	set    bool
+}
+
+var pow10 = func() [MaxPlaces + 1]int64 {
+	var p [MaxPlaces + 1]int64
+	p[0] = 1
+	for i := 1; i <= MaxPlaces; i++ {
+		p[i] = p[i-1] * 10
+	}
+	return p
+}()
+
+// New returns coef × 10^-places, e.g. New(12345, 2) is 123.45
+func New(coef int64, places int) Decimal {
+	checkPlaces(places)
+	return Decimal{coef: int128From(coef), places: uint8(places), set: true}
+}
+
+// Money returns v rounded half-up to the 2 decimals of values
+func Money(v float64) Decimal {
+	return fromFloat(v, MoneyPlaces, RoundHalfUp, MoneyPlaces)
+}
+
+// Quantity returns v rounded half-up to the 4 decimals of quantities
+func Quantity(v float64) Decimal {
+	return fromFloat(v, QuantityPlaces, RoundHalfUp, QuantityPlaces)
+}
+
+// Rate returns v rounded half-up to the 4 decimals of rates
+func Rate(v float64) Decimal {
+	return fromFloat(v, RatePlaces, RoundHalfUp, RatePlaces)
+}
+
+// UnitPrice returns v rounded half-up to at most 10 decimals, keeping at
+// least 2, e.g. 10.5 becomes 10.50 and 0.123456 keeps its 6 decimals
+func UnitPrice(v float64) Decimal {
+	return fromFloat(v, UnitPricePlaces, RoundHalfUp, MoneyPlaces)
+}
+
+// NewFromFloat converts v using its shortest decimal representation, so 0.1
+// becomes exactly 0.1 rather than its binary approximation. Digits beyond
+// MaxPlaces are rounded half-even.
+func NewFromFloat(v float64) Decimal {
+	s := formatFloat(v)
+	places := 0
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		places = min(len(s)-i-1, MaxPlaces)
+	}
+	return fromFloat(v, places, RoundHalfEven, 0)
+}
+
+// NewFromRat rounds r to places decimals
+func NewFromRat(r *big.Rat, places int, mode RoundingMode) Decimal {
+	checkPlaces(places)
+	return fromRat(r, places, mode, places)
+}
+
+// Parse reads a decimal in the layout notation: an optional sign, digits and
+// an optional fraction separated by a point. Surrounding spaces are ignored.
+func Parse(s string) (Decimal, error) {
+	text := strings.TrimSpace(s)
+	digits := strings.TrimLeft(text, "+-")
+	if len(text)-len(digits) > 1 {
+		return Decimal{}, errors.NewValidationError("invalid decimal value", "decimal", s)
+	}
+
+	integer, fraction, hasPoint := strings.Cut(digits, ".")
+	if (integer == "" && fraction == "") || (hasPoint && fraction == "") || !isDigits(integer) || !isDigits(fraction) {
+		return Decimal{}, errors.NewValidationError("invalid decimal value", "decimal", s)
+	}
+	if len(fraction) > MaxPlaces {
+		return Decimal{}, errors.NewValidationError(fmt.Sprintf("decimal value has more than %d places", MaxPlaces), "decimal", s)
+	}
+
+	coef, ok := new(big.Int).SetString(integer+fraction, 10)
+	if !ok {
+		return Decimal{}, errors.NewValidationError("invalid decimal value", "decimal", s)
+	}
+	if strings.HasPrefix(text, "-") {
+		coef.Neg(coef)
+	}
+
+	// Trailing zeros are dropped only when the value would not fit otherwise
+	places := len(fraction)
+	for coef.BitLen() > maxMagnitudeBits && places > 0 && new(big.Int).Rem(coef, big.NewInt(10)).Sign() == 0 {
+		coef.Quo(coef, big.NewInt(10))
+		places--
+	}
+	value, ok := int128FromBig(coef)
+	if !ok {
+		return Decimal{}, errors.NewValidationError("decimal value out of range", "decimal", s)
+	}
+	return Decimal{coef: value, places: uint8(places), set: true}, nil
+}
+
+// MustParse is like Parse but panics on invalid input. It simplifies the
+// initialization of constants and tests.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// IsSet reports whether the value was assigned; unset values are omitted from XML
+func (d Decimal) IsSet() bool {
+	return d.set
+}
+
+// IsZero reports whether the value is zero, set or not
+func (d Decimal) IsZero() bool {
+	return d.coef.sign() == 0
+}
+
+// Sign returns -1, 0 or 1 according to the sign of d
+func (d Decimal) Sign() int {
+	return d.coef.sign()
+}
+
+// Places returns the number of decimal places of d
+func (d Decimal) Places() int {
+	return int(d.places)
+}
+
+// Coefficient returns d × 10^Places(), the integer behind the value
+func (d Decimal) Coefficient() *big.Int {
+	return d.coef.big()
+}
+
+// Cmp compares the values of d and other: -1 if d < other, 0 if equal, 1 if greater
+func (d Decimal) Cmp(other Decimal) int {
+	if d.places == other.places {
+		return d.coef.cmp(other.coef)
+	}
+	return d.Rat().Cmp(other.Rat())
+}
+
+// Equal reports whether d and other have the same value regardless of their places
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: d.coef.neg(), places: d.places, set: true}
+}
+
+// Abs returns the absolute value of d
+func (d Decimal) Abs() Decimal {
+	if d.coef.sign() < 0 {
+		return d.Neg()
+	}
+	return Decimal{coef: d.coef, places: d.places, set: true}
+}
+
+// Add returns the exact sum d + other, with the larger number of places
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, places := align(d, other)
+	sum, ok := a.add(b)
+	if !ok {
+		panic("decimal: overflow")
+	}
+	return Decimal{coef: sum, places: places, set: true}
+}
+
+// Sub returns the exact difference d − other, with the larger number of places
+func (d Decimal) Sub(other Decimal) Decimal {
+	return d.Add(other.Neg())
+}
+
+// Mul returns d × other rounded to places decimals
+func (d Decimal) Mul(other Decimal, places int, mode RoundingMode) Decimal {
+	return NewFromRat(new(big.Rat).Mul(d.Rat(), other.Rat()), places, mode)
+}
+
+// Div returns d ÷ other rounded to places decimals. It panics when other is zero.
+func (d Decimal) Div(other Decimal, places int, mode RoundingMode) Decimal {
+	if other.coef.sign() == 0 {
+		panic("decimal: division by zero")
+	}
+	return NewFromRat(new(big.Rat).Quo(d.Rat(), other.Rat()), places, mode)
+}
+
+// Round returns d with exactly places decimals, rounding or padding with zeros
+func (d Decimal) Round(places int, mode RoundingMode) Decimal {
+	checkPlaces(places)
+	if places >= int(d.places) {
+		coef, ok := scaleUp(d.coef, places-int(d.places))
+		if !ok {
+			panic("decimal: overflow")
+		}
+		return Decimal{coef: coef, places: uint8(places), set: true}
+	}
+
+	divisor := uint64(pow10[int(d.places)-places])
+	quo, rem := d.coef.quoRem(divisor)
+	if roundUp(quo.lo%2 != 0, 2*rem, divisor, mode) {
+		quo, _ = quo.add(int128From(int64(d.coef.sign())))
+	}
+	return Decimal{coef: quo, places: uint8(places), set: true}
+}
+
+// Trim removes trailing zeros from the fraction, keeping at least minPlaces decimals
+func (d Decimal) Trim(minPlaces int) Decimal {
+	d.set = true
+	for int(d.places) > minPlaces {
+		quo, rem := d.coef.quoRem(10)
+		if rem != 0 {
+			break
+		}
+		d.coef = quo
+		d.places--
+	}
+	return d
+}
+
+// Rat returns the exact value of d as a rational number
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.coef.big(), big.NewInt(pow10[d.places]))
+}
+
+// Float64 returns the float64 nearest to d
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d with all of its decimal places, e.g. "123.40"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.coef.big()).String()
+	if d.places > 0 {
+		if len(digits) <= int(d.places) {
+			digits = strings.Repeat("0", int(d.places)-len(digits)+1) + digits
+		}
+		point := len(digits) - int(d.places)
+		digits = digits[:point] + "." + digits[point:]
+	}
+	if d.coef.sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// StringFixed formats d rounded half-up to exactly places decimals
+func (d Decimal) StringFixed(places int) string {
+	return d.Round(places, RoundHalfUp).String()
+}
+
+// fromFloat rounds v to places decimals, dropping trailing zeros down to minPlaces
+func fromFloat(v float64, places int, mode RoundingMode, minPlaces int) Decimal {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		panic("decimal: NaN or infinite value")
+	}
+	r, _ := new(big.Rat).SetString(formatFloat(v))
+	return fromRat(r, places, mode, minPlaces)
+}
+
+// fromRat rounds r to places decimals, dropping trailing zeros down to minPlaces
+func fromRat(r *big.Rat, places int, mode RoundingMode, minPlaces int) Decimal {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt64(pow10[places]))
+	num := new(big.Int).Abs(scaled.Num())
+	den := scaled.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	half := new(big.Int).Lsh(rem, 1).Cmp(den)
+	if half > 0 || (half == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1)) {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if scaled.Sign() < 0 {
+		quo.Neg(quo)
+	}
+
+	ten := big.NewInt(10)
+	for places > minPlaces && new(big.Int).Rem(quo, ten).Sign() == 0 {
+		quo.Quo(quo, ten)
+		places--
+	}
+	coef, ok := int128FromBig(quo)
+	if !ok {
+		panic("decimal: overflow")
+	}
+	return Decimal{coef: coef, places: uint8(places), set: true}
+}
+
+// roundUp reports whether the magnitude of a truncated quotient must be
+// incremented, given whether it is odd, twice the remainder and the divisor
+func roundUp(odd bool, twiceRem, divisor uint64, mode RoundingMode) bool {
+	switch {
+	case twiceRem > divisor:
+		return true
+	case twiceRem == divisor:
+		return mode == RoundHalfUp || odd
+	}
+	return false
+}
+
+// align returns the coefficients of a and b with the same number of places
+func align(a, b Decimal) (int128, int128, uint8) {
+	places := max(a.places, b.places)
+	ca, okA := scaleUp(a.coef, int(places-a.places))
+	cb, okB := scaleUp(b.coef, int(places-b.places))
+	if !okA || !okB {
+		panic("decimal: overflow")
+	}
+	return ca, cb, places
+}
+
+// scaleUp multiplies coef by 10^n reporting whether the result fits
+func scaleUp(coef int128, n int) (int128, bool) {
+	if n == 0 || coef.sign() == 0 {
+		return coef, true
+	}
+	return coef.mul(uint64(pow10[n]))
+}
+
+func checkPlaces(places int) {
+	if places < 0 || places > MaxPlaces {
+		panic(fmt.Sprintf("decimal: places must be between 0 and %d, got %d", MaxPlaces, places))
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
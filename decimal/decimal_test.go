@@ -0,0 +1,207 @@
+package decimal
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Decimal
+		expected string
+	}{
+		{"money", Money(100), "100.00"},
+		{"money rounds half-up", Money(1.005), "1.01"},
+		{"money negative", Money(-2.675), "-2.68"},
+		{"quantity", Quantity(1.5), "1.5000"},
+		{"rate", Rate(18), "18.0000"},
+		{"rate rounds", Rate(1.23456), "1.2346"},
+		{"unit price keeps 2 places", UnitPrice(10.5), "10.50"},
+		{"unit price keeps significant places", UnitPrice(0.123456), "0.123456"},
+		{"unit price rounds to 10 places", UnitPrice(0.12345678905), "0.1234567891"},
+		{"unit price large value", UnitPrice(1234567890.12), "1234567890.12"},
+		{"new", New(12345, 2), "123.45"},
+		{"new small", New(5, 4), "0.0005"},
+		{"new negative", New(-5, 2), "-0.05"},
+		{"from float", NewFromFloat(0.1), "0.1"},
+		{"from float integer", NewFromFloat(250), "250"},
+		{"from rat", NewFromRat(big.NewRat(1, 3), 4, RoundHalfUp), "0.3333"},
+		{"from rat half-even", NewFromRat(big.NewRat(1, 8), 2, RoundHalfEven), "0.12"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := test.value.String(); result != test.expected {
+				t.Errorf("String() = %q, expected %q", result, test.expected)
+			}
+			if !test.value.IsSet() {
+				t.Error("constructed values should be set")
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		places   int
+	}{
+		{"123.45", "123.45", 2},
+		{"  0.10 ", "0.10", 2},
+		{"-7", "-7", 0},
+		{"+1.5", "1.5", 1},
+		{".25", "0.25", 2},
+		{"100.0000000000", "100.0000000000", 10},
+		{"999999999999.0000000000", "999999999999.0000000000", 10},
+		{"99999999999.9999999999", "99999999999.9999999999", 10},
+		{"-99999999999.9999999999", "-99999999999.9999999999", 10},
+		{"100000000000000000000000000000000000000.00", "100000000000000000000000000000000000000", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			d, err := Parse(test.input)
+			if err != nil {
+				t.Fatalf("Parse should not return error, got: %v", err)
+			}
+			if d.String() != test.expected || d.Places() != test.places {
+				t.Errorf("Parse(%q) = %s with %d places, expected %s with %d", test.input, d, d.Places(), test.expected, test.places)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "-", "abc", "1.2.3", "1,5", "--1", "5.", "1e5", "0.1234567890123456789", "9999999999999999999999999999999999999999"} {
+		if _, err := Parse(invalid); err == nil {
+			t.Errorf("Parse(%q) should return error", invalid)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		value    string
+		places   int
+		mode     RoundingMode
+		expected string
+	}{
+		{"0.125", 2, RoundHalfUp, "0.13"},
+		{"0.125", 2, RoundHalfEven, "0.12"},
+		{"0.135", 2, RoundHalfEven, "0.14"},
+		{"-0.125", 2, RoundHalfUp, "-0.13"},
+		{"-0.125", 2, RoundHalfEven, "-0.12"},
+		{"2.675", 2, RoundHalfUp, "2.68"},
+		{"1.23456789", 4, RoundHalfUp, "1.2346"},
+		{"0.004", 2, RoundHalfUp, "0.00"},
+		{"100", 2, RoundHalfUp, "100.00"},
+		{"9.995", 2, RoundHalfUp, "10.00"},
+	}
+
+	for _, test := range tests {
+		result := MustParse(test.value).Round(test.places, test.mode)
+		if result.String() != test.expected {
+			t.Errorf("Round(%s, %d, %d) = %s, expected %s", test.value, test.places, test.mode, result, test.expected)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := MustParse("0.1"), MustParse("0.20")
+
+	if sum := a.Add(b); sum.String() != "0.30" {
+		t.Errorf("Add() = %s, expected 0.30", sum)
+	}
+	if diff := a.Sub(b); diff.String() != "-0.10" {
+		t.Errorf("Sub() = %s, expected -0.10", diff)
+	}
+	if product := UnitPrice(10.5).Mul(Quantity(3), MoneyPlaces, RoundHalfUp); product.String() != "31.50" {
+		t.Errorf("Mul() = %s, expected 31.50", product)
+	}
+	if quotient := Money(10).Div(Money(3), RatePlaces, RoundHalfUp); quotient.String() != "3.3333" {
+		t.Errorf("Div() = %s, expected 3.3333", quotient)
+	}
+
+	total := Decimal{}
+	for i := 0; i < 1000; i++ {
+		total = total.Add(Money(0.01))
+	}
+	if !total.Equal(Money(10)) {
+		t.Errorf("sum of 1000 × 0.01 = %s, expected 10.00", total)
+	}
+}
+
+func TestUnitPriceLayoutMaximum(t *testing.T) {
+	// TDec_1110v (vUnCom, vUnTrib) has 11 integer and 10 decimal digits and
+	// TDec_1104v (qCom, qTrib) 11 and 4
+	vUnCom := MustParse("99999999999.9999999999")
+	qCom := MustParse("99999999999.9999")
+
+	if vProd := qCom.Mul(vUnCom, MoneyPlaces, RoundHalfUp); vProd.String() != "9999999999999989999990.00" {
+		t.Errorf("qCom × vUnCom = %s, expected 9999999999999989999990.00", vProd)
+	}
+	vProd := MustParse("12345678901.2345").Mul(MustParse("98765432109.8765432109"), MoneyPlaces, RoundHalfUp)
+	if vProd.String() != "1219326311370211247063.39" {
+		t.Errorf("qCom × vUnCom = %s, expected 1219326311370211247063.39", vProd)
+	}
+
+	if rounded := vUnCom.Neg().Round(MoneyPlaces, RoundHalfUp); rounded.String() != "-100000000000.00" {
+		t.Errorf("Round() = %s, expected -100000000000.00", rounded)
+	}
+	if sum := vUnCom.Add(MustParse("0.0000000001")); sum.String() != "100000000000.0000000000" {
+		t.Errorf("Add() = %s, expected 100000000000.0000000000", sum)
+	}
+	if exact, _ := new(big.Rat).SetString("99999999999.9999999999"); vUnCom.Rat().Cmp(exact) != 0 || vUnCom.Cmp(qCom) != 1 {
+		t.Error("Rat or Cmp returned wrong result")
+	}
+	if price := UnitPrice(99999999999.12); price.String() != "99999999999.12" {
+		t.Errorf("UnitPrice() = %s, expected 99999999999.12", price)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	if !Money(18).Equal(Rate(18)) {
+		t.Error("values with different places should be equal")
+	}
+	if Money(1).Cmp(Money(2)) != -1 || Rate(2).Cmp(Money(1.5)) != 1 {
+		t.Error("Cmp returned wrong order")
+	}
+	if Money(-1).Sign() != -1 || Money(0).Sign() != 0 || !Money(0).IsZero() {
+		t.Error("Sign or IsZero returned wrong result")
+	}
+	if (Decimal{}).IsSet() || !Money(0).IsSet() {
+		t.Error("only the zero Decimal should be unset")
+	}
+}
+
+func TestTrim(t *testing.T) {
+	if result := MustParse("1.2300").Trim(0).String(); result != "1.23" {
+		t.Errorf("Trim(0) = %s, expected 1.23", result)
+	}
+	if result := MustParse("100.0000").Trim(2).String(); result != "100.00" {
+		t.Errorf("Trim(2) = %s, expected 100.00", result)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"division by zero", func() { Money(1).Div(Decimal{}, 2, RoundHalfUp) }},
+		{"overflow", func() { maxCoef := MustParse(strings.Repeat("9", 38)); maxCoef.Add(maxCoef) }},
+		{"invalid places", func() { New(1, MaxPlaces+1) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			test.fn()
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package decimal
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// int128 is a signed 128-bit integer in two's complement, hi × 2^64 + lo. It
+// is a comparable value, so Decimal values can be compared with ==.
+type int128 struct {
+	hi int64
+	lo uint64
+}
+
+// maxMagnitudeBits is the bit length of the largest magnitude held, keeping
+// the most negative value out so negation never overflows
+const maxMagnitudeBits = 127
+
+// int128From returns v as an int128
+func int128From(v int64) int128 {
+	return int128{hi: v >> 63, lo: uint64(v)}
+}
+
+// int128FromMagnitude returns hi × 2^64 + lo with the given sign, reporting
+// whether it fits
+func int128FromMagnitude(negative bool, hi, lo uint64) (int128, bool) {
+	if hi > math.MaxInt64 {
+		return int128{}, false
+	}
+	v := int128{hi: int64(hi), lo: lo}
+	if negative {
+		v = v.neg()
+	}
+	return v, true
+}
+
+// int128FromBig returns x as an int128, reporting whether it fits
+func int128FromBig(x *big.Int) (int128, bool) {
+	if x.BitLen() > maxMagnitudeBits {
+		return int128{}, false
+	}
+	magnitude := new(big.Int).Abs(x)
+	lo := new(big.Int).And(magnitude, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+	hi := magnitude.Rsh(magnitude, 64).Uint64()
+	return int128FromMagnitude(x.Sign() < 0, hi, lo)
+}
+
+// sign returns -1, 0 or 1 according to the sign of v
+func (v int128) sign() int {
+	switch {
+	case v.hi < 0:
+		return -1
+	case v.hi == 0 && v.lo == 0:
+		return 0
+	}
+	return 1
+}
+
+// cmp compares v and other: -1 if v < other, 0 if equal, 1 if greater
+func (v int128) cmp(other int128) int {
+	switch {
+	case v.hi < other.hi:
+		return -1
+	case v.hi > other.hi:
+		return 1
+	case v.lo < other.lo:
+		return -1
+	case v.lo > other.lo:
+		return 1
+	}
+	return 0
+}
+
+// neg returns -v; values are kept within maxMagnitudeBits, so it never overflows
+func (v int128) neg() int128 {
+	lo, borrow := bits.Sub64(0, v.lo, 0)
+	hi, _ := bits.Sub64(0, uint64(v.hi), borrow)
+	return int128{hi: int64(hi), lo: lo}
+}
+
+// magnitude returns the absolute value of v as two unsigned words
+func (v int128) magnitude() (hi, lo uint64) {
+	if v.hi < 0 {
+		v = v.neg()
+	}
+	return uint64(v.hi), v.lo
+}
+
+// add returns v + other, reporting whether the sum fits
+func (v int128) add(other int128) (int128, bool) {
+	lo, carry := bits.Add64(v.lo, other.lo, 0)
+	hi, _ := bits.Add64(uint64(v.hi), uint64(other.hi), carry)
+	sum := int128{hi: int64(hi), lo: lo}
+
+	if (v.hi < 0) == (other.hi < 0) && (sum.hi < 0) != (v.hi < 0) {
+		return int128{}, false
+	}
+	if sum.hi == math.MinInt64 && sum.lo == 0 {
+		return int128{}, false
+	}
+	return sum, true
+}
+
+// mul returns v × m, reporting whether the product fits
+func (v int128) mul(m uint64) (int128, bool) {
+	hi, lo := v.magnitude()
+	carry, lo := bits.Mul64(lo, m)
+	overflow, hi := bits.Mul64(hi, m)
+	hi, c := bits.Add64(hi, carry, 0)
+	if overflow != 0 || c != 0 {
+		return int128{}, false
+	}
+	return int128FromMagnitude(v.hi < 0, hi, lo)
+}
+
+// quoRem returns v ÷ d truncated toward zero and the magnitude of the remainder
+func (v int128) quoRem(d uint64) (int128, uint64) {
+	hi, lo := v.magnitude()
+	quoHi, rem := hi/d, hi%d
+	quoLo, rem := bits.Div64(rem, lo, d)
+	quo, _ := int128FromMagnitude(v.hi < 0, quoHi, quoLo)
+	return quo, rem
+}
+
+// big returns v as a big.Int
+func (v int128) big() *big.Int {
+	hi, lo := v.magnitude()
+	x := new(big.Int).SetUint64(hi)
+	x.Lsh(x, 64).Or(x, new(big.Int).SetUint64(lo))
+	if v.hi < 0 {
+		x.Neg(x)
+	}
+	return x
+}
@@ -0,0 +1,35 @@
+package decimal
+
+import "encoding/xml"
+
+// MarshalText formats d with all of its decimal places
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses d keeping the decimal places of the text
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalXML writes d as the character data of the element, writing nothing
+// when d is unset so optional fields are left out of the document
+func (d Decimal) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !d.set {
+		return nil
+	}
+	return e.EncodeElement(d.String(), start)
+}
+
+// MarshalXMLAttr writes d as an attribute, omitted when d is unset
+func (d Decimal) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if !d.set {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: d.String()}, nil
+}
@@ -0,0 +1,63 @@
+package decimal
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+type group struct {
+	XMLName xml.Name `xml:"ICMS00"`
+	Attr    Decimal  `xml:"v,attr,omitempty"`
+	VBC     Decimal  `xml:"vBC"`
+	PICMS   Decimal  `xml:"pICMS"`
+	VFCP    Decimal  `xml:"vFCP,omitempty"`
+}
+
+func TestMarshalXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    group
+		expected string
+	}{
+		{
+			name:     "unset values are omitted",
+			value:    group{VBC: Money(100), PICMS: Rate(18)},
+			expected: `<ICMS00><vBC>100.00</vBC><pICMS>18.0000</pICMS></ICMS00>`,
+		},
+		{
+			name:     "set zeros are written",
+			value:    group{Attr: New(1, 0), VBC: Money(0), PICMS: Rate(0), VFCP: Money(0)},
+			expected: `<ICMS00 v="1"><vBC>0.00</vBC><pICMS>0.0000</pICMS><vFCP>0.00</vFCP></ICMS00>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := xml.Marshal(test.value)
+			if err != nil {
+				t.Fatalf("Marshal should not return error, got: %v", err)
+			}
+			if string(data) != test.expected {
+				t.Errorf("Marshal() = %s, expected %s", data, test.expected)
+			}
+		})
+	}
+}
+
+func TestUnmarshalXML(t *testing.T) {
+	var g group
+	err := xml.Unmarshal([]byte(`<ICMS00 v="2"><vBC> 260.50 </vBC><pICMS>17.0000</pICMS></ICMS00>`), &g)
+	if err != nil {
+		t.Fatalf("Unmarshal should not return error, got: %v", err)
+	}
+	if g.VBC.String() != "260.50" || g.PICMS.String() != "17.0000" || g.Attr.String() != "2" {
+		t.Errorf("Unmarshal() = %+v", g)
+	}
+	if g.VFCP.IsSet() {
+		t.Error("absent elements should stay unset")
+	}
+
+	if err := xml.Unmarshal([]byte(`<ICMS00><vBC>1,50</vBC></ICMS00>`), &g); err == nil {
+		t.Error("Unmarshal should reject invalid decimals")
+	}
+}
@@ -18,8 +18,12 @@ Basic usage:
 		log.Fatal(err)
 	}
 
-	// Generate access key
-	accessKey := client.GenerateAccessKey("12345678000190", 55, 1, 123456, 1)
+	// Generate the access key of a document from its ide and emitter;
+	// cNF, cDV and the infNFe Id are filled in
+	accessKey, err := client.GenerateAccessKey(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 For more examples, see the examples/ directory in the repository.
 */
@@ -0,0 +1,505 @@
+package errors
+
+// cstatCatalog lists the SEFAZ return codes (cStat) of the NF-e/NFC-e
+// webservices with their standard xMotivo, as published in the Manual de
+// Orientação do Contribuinte and its technical notes. SEFAZ may append details
+// such as [nRec:...] or [chNFe:...] to the message it actually returns.
+var cstatCatalog = map[int]CStatInfo{
+	100: {100, "Autorizado o uso da NF-e", CategoryAuthorized, false},
+	101: {101, "Cancelamento de NF-e homologado", CategoryAuthorized, false},
+	102: {102, "Inutilização de número homologado", CategoryAuthorized, false},
+	103: {103, "Lote recebido com sucesso", CategoryProcessed, false},
+	104: {104, "Lote processado", CategoryProcessed, false},
+	105: {105, "Lote em processamento", CategoryTransient, true},
+	106: {106, "Lote não localizado", CategoryRejection, false},
+	107: {107, "Serviço em Operação", CategoryProcessed, false},
+	108: {108, "Serviço Paralisado Momentaneamente (curto prazo)", CategoryTransient, true},
+	109: {109, "Serviço Paralisado sem Previsão", CategoryTransient, true},
+	110: {110, "Uso Denegado", CategoryDenied, false},
+	111: {111, "Consulta cadastro com uma ocorrência", CategoryProcessed, false},
+	112: {112, "Consulta cadastro com mais de uma ocorrência", CategoryProcessed, false},
+	124: {124, "EPEC Autorizado", CategoryAuthorized, false},
+	128: {128, "Lote de Evento Processado", CategoryProcessed, false},
+	135: {135, "Evento registrado e vinculado a NF-e", CategoryAuthorized, false},
+	136: {136, "Evento registrado, mas não vinculado a NF-e", CategoryAuthorized, false},
+	137: {137, "Nenhum documento localizado para o Destinatário", CategoryProcessed, false},
+	138: {138, "Documento localizado para o Destinatário", CategoryProcessed, false},
+	139: {139, "Pedido de Download processado", CategoryProcessed, false},
+	140: {140, "Download disponibilizado", CategoryProcessed, false},
+	142: {142, "Ambiente de Contingência EPEC bloqueado para o Emitente", CategoryRejection, false},
+	150: {150, "Autorizado o uso da NF-e, autorização fora de prazo", CategoryAuthorized, false},
+	151: {151, "Cancelamento de NF-e homologado fora de prazo", CategoryAuthorized, false},
+	155: {155, "Cancelamento homologado fora de prazo", CategoryAuthorized, false},
+	201: {201, "Rejeição: Número máximo de numeração a inutilizar ultrapassou o limite", CategoryRejection, false},
+	202: {202, "Rejeição: Falha no reconhecimento da autoria ou integridade do arquivo digital", CategoryRejection, false},
+	203: {203, "Rejeição: Emissor não habilitado para emissão de NF-e", CategoryRejection, false},
+	204: {204, "Rejeição: Duplicidade de NF-e", CategoryDuplicate, false},
+	205: {205, "Rejeição: NF-e está denegada na base de dados da SEFAZ", CategoryDenied, false},
+	206: {206, "Rejeição: NF-e já está inutilizada na Base de dados da SEFAZ", CategoryDuplicate, false},
+	207: {207, "Rejeição: CNPJ do emitente inválido", CategoryRejection, false},
+	208: {208, "Rejeição: CNPJ do destinatário inválido", CategoryRejection, false},
+	209: {209, "Rejeição: IE do emitente inválida", CategoryRejection, false},
+	210: {210, "Rejeição: IE do destinatário inválida", CategoryRejection, false},
+	211: {211, "Rejeição: IE do substituto inválida", CategoryRejection, false},
+	212: {212, "Rejeição: Data de emissão NF-e posterior a data de recebimento", CategoryRejection, false},
+	213: {213, "Rejeição: CNPJ-Base do Emitente difere do CNPJ-Base do Certificado Digital", CategoryRejection, false},
+	214: {214, "Rejeição: Tamanho da mensagem excedeu o limite estabelecido", CategoryRejection, false},
+	215: {215, "Rejeição: Falha no schema XML", CategoryRejection, false},
+	216: {216, "Rejeição: Chave de Acesso difere da cadastrada", CategoryRejection, false},
+	217: {217, "Rejeição: NF-e não consta na base de dados da SEFAZ", CategoryRejection, false},
+	218: {218, "Rejeição: NF-e já está cancelada na base de dados da SEFAZ", CategoryDuplicate, false},
+	219: {219, "Rejeição: Circulação da NF-e verificada", CategoryRejection, false},
+	220: {220, "Rejeição: Prazo de Cancelamento superior ao previsto na Legislação", CategoryRejection, false},
+	221: {221, "Rejeição: Confirmado o recebimento da NF-e pelo destinatário", CategoryRejection, false},
+	222: {222, "Rejeição: Protocolo de Autorização de Uso difere do cadastrado", CategoryRejection, false},
+	223: {223, "Rejeição: CNPJ do transmissor do lote difere do CNPJ do transmissor da consulta", CategoryRejection, false},
+	224: {224, "Rejeição: A faixa inicial é maior que a faixa final", CategoryRejection, false},
+	225: {225, "Rejeição: Falha no Schema XML do lote de NFe", CategoryRejection, false},
+	226: {226, "Rejeição: Código da UF do Emitente diverge da UF autorizadora", CategoryRejection, false},
+	227: {227, "Rejeição: Erro na Chave de Acesso - Campo Id – falta a literal NFe", CategoryRejection, false},
+	228: {228, "Rejeição: Data de Emissão muito atrasada", CategoryRejection, false},
+	229: {229, "Rejeição: IE do emitente não informada", CategoryRejection, false},
+	230: {230, "Rejeição: IE do emitente não cadastrada", CategoryRejection, false},
+	231: {231, "Rejeição: IE do emitente não vinculada ao CNPJ", CategoryRejection, false},
+	232: {232, "Rejeição: IE do destinatário não informada", CategoryRejection, false},
+	233: {233, "Rejeição: IE do destinatário não cadastrada", CategoryRejection, false},
+	234: {234, "Rejeição: IE do destinatário não vinculada ao CNPJ", CategoryRejection, false},
+	235: {235, "Rejeição: Inscrição SUFRAMA inválida", CategoryRejection, false},
+	236: {236, "Rejeição: Chave de Acesso com dígito verificador inválido", CategoryRejection, false},
+	237: {237, "Rejeição: CPF do destinatário inválido", CategoryRejection, false},
+	238: {238, "Rejeição: Cabeçalho - Versão do arquivo XML superior a Versão vigente", CategoryRejection, false},
+	239: {239, "Rejeição: Cabeçalho - Versão do arquivo XML não suportada", CategoryRejection, false},
+	240: {240, "Rejeição: Cancelamento/Inutilização - Irregularidade Fiscal do Emitente", CategoryRejection, false},
+	241: {241, "Rejeição: Um número da faixa já foi utilizado", CategoryRejection, false},
+	242: {242, "Rejeição: Cabeçalho - Falha no Schema XML", CategoryRejection, false},
+	243: {243, "Rejeição: XML Mal Formado", CategoryRejection, false},
+	244: {244, "Rejeição: CNPJ do Certificado Digital difere do CNPJ da Matriz e do CNPJ do Emitente", CategoryRejection, false},
+	245: {245, "Rejeição: CNPJ Emitente não cadastrado", CategoryRejection, false},
+	246: {246, "Rejeição: CNPJ Destinatário não cadastrado", CategoryRejection, false},
+	247: {247, "Rejeição: Sigla da UF do Emitente diverge da UF autorizadora", CategoryRejection, false},
+	248: {248, "Rejeição: UF do Recibo diverge da UF autorizadora", CategoryRejection, false},
+	249: {249, "Rejeição: UF da Chave de Acesso diverge da UF autorizadora", CategoryRejection, false},
+	250: {250, "Rejeição: UF diverge da UF autorizadora", CategoryRejection, false},
+	251: {251, "Rejeição: UF/Município destinatário não pertence a SUFRAMA", CategoryRejection, false},
+	252: {252, "Rejeição: Ambiente informado diverge do Ambiente de recebimento", CategoryRejection, false},
+	253: {253, "Rejeição: Digito Verificador da chave de acesso composta inválida", CategoryRejection, false},
+	254: {254, "Rejeição: NF-e complementar não possui NF referenciada", CategoryRejection, false},
+	255: {255, "Rejeição: NF-e complementar possui mais de uma NF referenciada", CategoryRejection, false},
+	256: {256, "Rejeição: Uma NF-e da faixa já está inutilizada na Base de dados da SEFAZ", CategoryRejection, false},
+	257: {257, "Rejeição: Solicitante não habilitado para emissão da NF-e", CategoryRejection, false},
+	258: {258, "Rejeição: CNPJ da consulta inválido", CategoryRejection, false},
+	259: {259, "Rejeição: CNPJ da consulta não cadastrado como contribuinte na UF", CategoryRejection, false},
+	260: {260, "Rejeição: IE da consulta inválida", CategoryRejection, false},
+	261: {261, "Rejeição: IE da consulta não cadastrada como contribuinte na UF", CategoryRejection, false},
+	262: {262, "Rejeição: UF não fornece consulta por CPF", CategoryRejection, false},
+	263: {263, "Rejeição: CPF da consulta inválido", CategoryRejection, false},
+	264: {264, "Rejeição: CPF da consulta não cadastrado como contribuinte na UF", CategoryRejection, false},
+	265: {265, "Rejeição: Sigla da UF da consulta difere da UF do Web Service", CategoryRejection, false},
+	266: {266, "Rejeição: Série utilizada não permitida no Web Service", CategoryRejection, false},
+	267: {267, "Rejeição: NF Complementar referencia uma NF-e inexistente", CategoryRejection, false},
+	268: {268, "Rejeição: NF Complementar referencia uma outra NF-e Complementar", CategoryRejection, false},
+	269: {269, "Rejeição: CNPJ Emitente da NF Complementar difere do CNPJ da NF Referenciada", CategoryRejection, false},
+	270: {270, "Rejeição: Código Município do Fato Gerador: dígito inválido", CategoryRejection, false},
+	271: {271, "Rejeição: Código Município do Fato Gerador: difere da UF do emitente", CategoryRejection, false},
+	272: {272, "Rejeição: Código Município do Emitente: dígito inválido", CategoryRejection, false},
+	273: {273, "Rejeição: Código Município do Emitente: difere da UF do emitente", CategoryRejection, false},
+	274: {274, "Rejeição: Código Município do Destinatário: dígito inválido", CategoryRejection, false},
+	275: {275, "Rejeição: Código Município do Destinatário: difere da UF do Destinatário", CategoryRejection, false},
+	276: {276, "Rejeição: Código Município do Local de Retirada: dígito inválido", CategoryRejection, false},
+	277: {277, "Rejeição: Código Município do Local de Retirada: difere da UF do Local de Retirada", CategoryRejection, false},
+	278: {278, "Rejeição: Código Município do Local de Entrega: dígito inválido", CategoryRejection, false},
+	279: {279, "Rejeição: Código Município do Local de Entrega: difere da UF do Local de Entrega", CategoryRejection, false},
+	280: {280, "Rejeição: Certificado Transmissor inválido", CategoryRejection, false},
+	281: {281, "Rejeição: Certificado Transmissor Data Validade", CategoryRejection, false},
+	282: {282, "Rejeição: Certificado Transmissor sem CNPJ", CategoryRejection, false},
+	283: {283, "Rejeição: Certificado Transmissor - erro Cadeia de Certificação", CategoryRejection, false},
+	284: {284, "Rejeição: Certificado Transmissor revogado", CategoryRejection, false},
+	285: {285, "Rejeição: Certificado Transmissor difere ICP-Brasil", CategoryRejection, false},
+	286: {286, "Rejeição: Certificado Transmissor erro no acesso a LCR", CategoryTransient, true},
+	287: {287, "Rejeição: Código Município do FG - ISSQN: dígito inválido", CategoryRejection, false},
+	288: {288, "Rejeição: Código Município do FG - Transporte: dígito inválido", CategoryRejection, false},
+	289: {289, "Rejeição: Código da UF informada diverge da UF solicitada", CategoryRejection, false},
+	290: {290, "Rejeição: Certificado Assinatura inválido", CategoryRejection, false},
+	291: {291, "Rejeição: Certificado Assinatura Data Validade", CategoryRejection, false},
+	292: {292, "Rejeição: Certificado Assinatura sem CNPJ", CategoryRejection, false},
+	293: {293, "Rejeição: Certificado Assinatura - erro Cadeia de Certificação", CategoryRejection, false},
+	294: {294, "Rejeição: Certificado Assinatura revogado", CategoryRejection, false},
+	295: {295, "Rejeição: Certificado Assinatura difere ICP-Brasil", CategoryRejection, false},
+	296: {296, "Rejeição: Certificado Assinatura erro no acesso a LCR", CategoryTransient, true},
+	297: {297, "Rejeição: Assinatura difere do calculado", CategoryRejection, false},
+	298: {298, "Rejeição: Assinatura difere do padrão do Projeto", CategoryRejection, false},
+	299: {299, "Rejeição: XML da área de cabeçalho com codificação diferente de UTF-8", CategoryRejection, false},
+	301: {301, "Uso Denegado: Irregularidade fiscal do emitente", CategoryDenied, false},
+	302: {302, "Uso Denegado: Irregularidade fiscal do destinatário", CategoryDenied, false},
+	303: {303, "Uso Denegado: Destinatário não habilitado a operar na UF", CategoryDenied, false},
+	304: {304, "Rejeição: Pedido de Cancelamento para NF-e com evento da Suframa", CategoryRejection, false},
+	321: {321, "Rejeição: NF-e de devolução de mercadoria não possui documento fiscal referenciado", CategoryRejection, false},
+	323: {323, "Rejeição: CNPJ autorizado para download inválido", CategoryRejection, false},
+	324: {324, "Rejeição: CNPJ do produtor rural inválido", CategoryRejection, false},
+	325: {325, "Rejeição: CPF autorizado para download inválido", CategoryRejection, false},
+	326: {326, "Rejeição: CPF do produtor rural inválido", CategoryRejection, false},
+	327: {327, "Rejeição: CFOP inválido para NF-e com finalidade de devolução", CategoryRejection, false},
+	328: {328, "Rejeição: CFOP de devolução informado em NF-e que não tem finalidade de devolução", CategoryRejection, false},
+	329: {329, "Rejeição: Número da DI /DSI inválido", CategoryRejection, false},
+	330: {330, "Rejeição: Informar o Valor da AFRMM na importação por via marítima", CategoryRejection, false},
+	331: {331, "Rejeição: Informar o CNPJ do adquirente ou do encomendante nesta forma de importação", CategoryRejection, false},
+	332: {332, "Rejeição: CNPJ do adquirente ou do encomendante da importação inválido", CategoryRejection, false},
+	333: {333, "Rejeição: Informar a UF do adquirente ou do encomendante nesta forma de importação", CategoryRejection, false},
+	334: {334, "Rejeição: Número do processo de drawback não informado na importação", CategoryRejection, false},
+	335: {335, "Rejeição: Número do processo de drawback na importação inválido", CategoryRejection, false},
+	336: {336, "Rejeição: Informado o grupo de exportação no item para CFOP que não é de exportação", CategoryRejection, false},
+	337: {337, "Rejeição: Não informado o grupo de exportação no item", CategoryRejection, false},
+	338: {338, "Rejeição: Número de processo de drawback não informado na exportação", CategoryRejection, false},
+	339: {339, "Rejeição: Número de processo de drawback na exportação inválido", CategoryRejection, false},
+	340: {340, "Rejeição: Não informado o grupo de exportação indireta no item", CategoryRejection, false},
+	341: {341, "Rejeição: Número do registro de exportação inválido", CategoryRejection, false},
+	342: {342, "Rejeição: Chave de Acesso informada na Exportação Indireta com DV inválido", CategoryRejection, false},
+	343: {343, "Rejeição: Modelo da NF-e informada na Exportação Indireta diferente de 55", CategoryRejection, false},
+	344: {344, "Rejeição: Duplicidade de NF-e informada na Exportação Indireta (Chave de Acesso informada mais de uma vez)", CategoryRejection, false},
+	345: {345, "Rejeição: Chave de Acesso informada na Exportação Indireta não consta como NF-e referenciada", CategoryRejection, false},
+	346: {346, "Rejeição: Somatório das quantidades informadas na Exportação Indireta não corresponde a quantidade total do item", CategoryRejection, false},
+	347: {347, "Rejeição: Descrição do Combustível diverge da descrição adotada pela ANP", CategoryRejection, false},
+	348: {348, "Rejeição: NFC-e com grupo RECOPI", CategoryRejection, false},
+	349: {349, "Rejeição: Número RECOPI não informado", CategoryRejection, false},
+	350: {350, "Rejeição: Número RECOPI inválido", CategoryRejection, false},
+	351: {351, "Rejeição: Valor do ICMS da Operação no CST=51 difere do produto BC e Alíquota", CategoryRejection, false},
+	352: {352, "Rejeição: Valor do ICMS Diferido no CST=51 difere do produto Valor ICMS Operação e percentual diferimento", CategoryRejection, false},
+	353: {353, "Rejeição: Valor do ICMS no CST=51 não corresponde a diferença do ICMS operação e ICMS diferido", CategoryRejection, false},
+	354: {354, "Rejeição: Informado grupo de devolução de tributos para NF-e que não tem finalidade de devolução", CategoryRejection, false},
+	355: {355, "Rejeição: Informar o local de saída do Pais no caso da exportação", CategoryRejection, false},
+	356: {356, "Rejeição: Informar o local de saída do Pais somente no caso da exportação", CategoryRejection, false},
+	357: {357, "Rejeição: Chave de Acesso do grupo de Exportação Indireta inexistente", CategoryRejection, false},
+	358: {358, "Rejeição: Chave de Acesso do grupo de Exportação Indireta cancelada ou denegada", CategoryRejection, false},
+	359: {359, "Rejeição: NF-e de venda a Órgão Público sem informar a Nota de Empenho", CategoryRejection, false},
+	360: {360, "Rejeição: NF-e com Nota de Empenho inválida para a UF", CategoryRejection, false},
+	361: {361, "Rejeição: NF-e com Nota de Empenho inexistente na UF", CategoryRejection, false},
+	362: {362, "Rejeição: Venda de combustível sem informação do Transportador", CategoryRejection, false},
+	364: {364, "Rejeição: Total do valor da dedução do ISS difere do somatório dos itens", CategoryRejection, false},
+	365: {365, "Rejeição: Total de outras retenções difere do somatório dos itens", CategoryRejection, false},
+	366: {366, "Rejeição: Total do desconto incondicionado ISS difere do somatório dos itens", CategoryRejection, false},
+	367: {367, "Rejeição: Total do desconto condicionado ISS difere do somatório dos itens", CategoryRejection, false},
+	368: {368, "Rejeição: Total de ISS retido difere do somatório dos itens", CategoryRejection, false},
+	369: {369, "Rejeição: Não informado o grupo avulsa na emissão pelo Fisco", CategoryRejection, false},
+	370: {370, "Rejeição: Nota Fiscal Avulsa com tipo de emissão inválido", CategoryRejection, false},
+	372: {372, "Rejeição: Destinatário com identificação de estrangeiro com caracteres inválidos", CategoryRejection, false},
+	373: {373, "Rejeição: Descrição do primeiro item diferente de NOTA FISCAL EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL", CategoryRejection, false},
+	374: {374, "Rejeição: CFOP incompatível com o grupo de tributação", CategoryRejection, false},
+	375: {375, "Rejeição: NF-e com CFOP 5929 (Lançamento relativo a Cupom Fiscal) referencia uma NFC-e", CategoryRejection, false},
+	376: {376, "Rejeição: Data do Desembaraço Aduaneiro inválida", CategoryRejection, false},
+	378: {378, "Rejeição: Grupo de Combustível sem a informação de Encerrante", CategoryRejection, false},
+	379: {379, "Rejeição: Grupo de Encerrante na NF-e (modelo 55) para CFOP diferente de venda de combustível para consumidor final", CategoryRejection, false},
+	380: {380, "Rejeição: Valor do Encerrante final não é superior ao Encerrante inicial", CategoryRejection, false},
+	381: {381, "Rejeição: Grupo de tributação ICMS90, informando dados do ICMS-ST", CategoryRejection, false},
+	382: {382, "Rejeição: CFOP não permitido para o CST informado", CategoryRejection, false},
+	383: {383, "Rejeição: Item com CSOSN indevido", CategoryRejection, false},
+	384: {384, "Rejeição: CSOSN não permitido para a UF", CategoryRejection, false},
+	385: {385, "Rejeição: Grupo de tributação ICMS900, informando dados do ICMS-ST", CategoryRejection, false},
+	386: {386, "Rejeição: CFOP não permitido para o CSOSN informado", CategoryRejection, false},
+	387: {387, "Rejeição: Código de Enquadramento Legal do IPI inválido", CategoryRejection, false},
+	388: {388, "Rejeição: Código de Situação Tributária do IPI incompatível com o Código de Enquadramento Legal do IPI", CategoryRejection, false},
+	389: {389, "Rejeição: Código Município ISSQN inexistente", CategoryRejection, false},
+	390: {390, "Rejeição: Nota Fiscal com grupo de devolução de tributos", CategoryRejection, false},
+	391: {391, "Rejeição: Não informados os dados do cartão de crédito / débito nas Formas de Pagamento da Nota Fiscal", CategoryRejection, false},
+	392: {392, "Rejeição: Não informados os dados da operação de pagamento por cartão de crédito / débito", CategoryRejection, false},
+	393: {393, "Rejeição: NF-e com o grupo de Informações Suplementares", CategoryRejection, false},
+	394: {394, "Rejeição: Nota Fiscal sem a informação do QR-Code", CategoryRejection, false},
+	395: {395, "Rejeição: Endereço do site da UF da Consulta via QR-Code diverge do previsto", CategoryRejection, false},
+	396: {396, "Rejeição: Parâmetro do QR-Code inexistente (chAcesso)", CategoryRejection, false},
+	397: {397, "Rejeição: Parâmetro do QR-Code divergente da Nota Fiscal (chAcesso)", CategoryRejection, false},
+	398: {398, "Rejeição: Parâmetro nVersao do QR-Code difere do previsto", CategoryRejection, false},
+	399: {399, "Rejeição: Parâmetro de Identificação do destinatário no QR-Code para Nota Fiscal sem identificação do destinatário", CategoryRejection, false},
+	401: {401, "Rejeição: CPF do remetente inválido", CategoryRejection, false},
+	402: {402, "Rejeição: XML da área de dados com codificação diferente de UTF-8", CategoryRejection, false},
+	403: {403, "Rejeição: O grupo de informações da NF-e avulsa é de uso exclusivo do Fisco", CategoryRejection, false},
+	404: {404, "Rejeição: Uso de prefixo de namespace não permitido", CategoryRejection, false},
+	405: {405, "Rejeição: Código do país do emitente: dígito inválido", CategoryRejection, false},
+	406: {406, "Rejeição: Código do país do destinatário: dígito inválido", CategoryRejection, false},
+	407: {407, "Rejeição: O CPF só pode ser informado no campo emitente para a NF-e avulsa", CategoryRejection, false},
+	409: {409, "Rejeição: Campo cUF inexistente no elemento nfeCabecMsg do SOAP Header", CategoryRejection, false},
+	410: {410, "Rejeição: UF informada no campo cUF não é atendida pelo Web Service", CategoryRejection, false},
+	411: {411, "Rejeição: Campo versaoDados inexistente no elemento nfeCabecMsg do SOAP Header", CategoryRejection, false},
+	417: {417, "Rejeição: Total do ICMS superior ao valor limite estabelecido", CategoryRejection, false},
+	418: {418, "Rejeição: Total do ICMS ST superior ao valor limite estabelecido", CategoryRejection, false},
+	420: {420, "Rejeição: Cancelamento para NF-e já cancelada", CategoryDuplicate, false},
+	450: {450, "Rejeição: Modelo da NF-e diferente de 55", CategoryRejection, false},
+	451: {451, "Rejeição: Processo de emissão informado inválido", CategoryRejection, false},
+	452: {452, "Rejeição: Tipo Autorizador do Recibo diverge do Órgão Autorizador", CategoryRejection, false},
+	453: {453, "Rejeição: Ano de inutilização não pode ser superior ao Ano atual", CategoryRejection, false},
+	454: {454, "Rejeição: Ano de inutilização não pode ser inferior a 2006", CategoryRejection, false},
+	455: {455, "Rejeição: Órgão Autor do evento diferente da UF da Chave de Acesso", CategoryRejection, false},
+	461: {461, "Rejeição: Informado percentual de Gás Natural na mistura para produto diferente de GLP", CategoryRejection, false},
+	465: {465, "Rejeição: Número de Controle da FCI inexistente", CategoryRejection, false},
+	466: {466, "Rejeição: Evento com Tipo de Autor incompatível", CategoryRejection, false},
+	467: {467, "Rejeição: Dados da NF-e divergentes do EPEC", CategoryRejection, false},
+	468: {468, "Rejeição: NF-e com Tipo Emissão = 4, sem EPEC correspondente", CategoryRejection, false},
+	471: {471, "Rejeição: Informado NCM=00 indevidamente", CategoryRejection, false},
+	476: {476, "Rejeição: Código da UF diverge da UF da primeira NF-e do Lote", CategoryRejection, false},
+	477: {477, "Rejeição: Código do órgão diverge do órgão do primeiro evento do Lote", CategoryRejection, false},
+	478: {478, "Rejeição: Local da entrega não informado para faturamento direto de veículos novos", CategoryRejection, false},
+	479: {479, "Rejeição: Emissor em situação irregular perante o fisco", CategoryRejection, false},
+	480: {480, "Rejeição: CNPJ da Chave de acesso da NF-e informada diverge do CNPJ do emitente", CategoryRejection, false},
+	481: {481, "Rejeição: UF da Chave de acesso diverge do código da UF informada", CategoryRejection, false},
+	482: {482, "Rejeição: AA da Chave de acesso inválida", CategoryRejection, false},
+	483: {483, "Rejeição: MM da chave de acesso inválido", CategoryRejection, false},
+	484: {484, "Rejeição: Chave de Acesso com tipo de emissão diferente de 4 (posição 35 da Chave de Acesso)", CategoryRejection, false},
+	485: {485, "Rejeição: Duplicidade de numeração do EPEC (Modelo, CNPJ, Série e Número)", CategoryDuplicate, false},
+	489: {489, "Rejeição: CNPJ informado inválido (DV ou zeros)", CategoryRejection, false},
+	490: {490, "Rejeição: CPF informado inválido (DV ou zeros)", CategoryRejection, false},
+	491: {491, "Rejeição: O tpEvento informado inválido", CategoryRejection, false},
+	492: {492, "Rejeição: O verEvento informado inválido", CategoryRejection, false},
+	493: {493, "Rejeição: Evento não atende o Schema XML específico", CategoryRejection, false},
+	494: {494, "Rejeição: Chave de Acesso inexistente", CategoryRejection, false},
+	501: {501, "Rejeição: Pedido de Cancelamento intempestivo (NF-e autorizada a mais de 7 dias)", CategoryRejection, false},
+	502: {502, "Rejeição: Erro na Chave de Acesso - Campo Id não corresponde à concatenação dos campos correspondentes", CategoryRejection, false},
+	503: {503, "Rejeição: Série utilizada fora da faixa permitida no SCAN (900-999)", CategoryRejection, false},
+	504: {504, "Rejeição: Data de Entrada maior que a Data de Emissão", CategoryRejection, false},
+	505: {505, "Rejeição: Data de Entrada menor que a Data de Emissão", CategoryRejection, false},
+	506: {506, "Rejeição: Data de Saída menor que a Data de Emissão", CategoryRejection, false},
+	507: {507, "Rejeição: O CNPJ do destinatário/remetente não deve ser informado em operação com o exterior", CategoryRejection, false},
+	508: {508, "Rejeição: CST incompatível na operação com Não Contribuinte", CategoryRejection, false},
+	509: {509, "Rejeição: Informado código de município diferente de 9999999 para operação com o exterior", CategoryRejection, false},
+	510: {510, "Rejeição: Operação com Exterior e Código País destinatário é 1058 (Brasil) ou não informado", CategoryRejection, false},
+	511: {511, "Rejeição: Não é de Operação com Exterior e Código País destinatário difere de 1058 (Brasil)", CategoryRejection, false},
+	512: {512, "Rejeição: CNPJ do Local de Retirada inválido", CategoryRejection, false},
+	513: {513, "Rejeição: Código Município do Local de Retirada deve ser 9999999 para UF retirada = EX", CategoryRejection, false},
+	514: {514, "Rejeição: CNPJ do Local de Entrega inválido", CategoryRejection, false},
+	515: {515, "Rejeição: Código Município do Local de Entrega deve ser 9999999 para UF entrega = EX", CategoryRejection, false},
+	516: {516, "Rejeição: Falha no schema XML – inexiste a tag raiz esperada para a mensagem", CategoryRejection, false},
+	517: {517, "Rejeição: Falha no schema XML – inexiste atributo versao na tag raiz da mensagem", CategoryRejection, false},
+	518: {518, "Rejeição: CFOP de entrada para NF-e de saída", CategoryRejection, false},
+	519: {519, "Rejeição: CFOP de saída para NF-e de entrada", CategoryRejection, false},
+	520: {520, "Rejeição: CFOP de Operação com Exterior e UF destinatário difere de EX", CategoryRejection, false},
+	521: {521, "Rejeição: CFOP de Operação Estadual e UF do emitente difere da UF do destinatário para destinatário contribuinte do ICMS", CategoryRejection, false},
+	522: {522, "Rejeição: CFOP de Operação Estadual e UF emitente difere da UF remetente para remetente contribuinte do ICMS", CategoryRejection, false},
+	523: {523, "Rejeição: CFOP não é de Operação Estadual e UF emitente igual a UF destinatário", CategoryRejection, false},
+	524: {524, "Rejeição: CFOP de Operação com Exterior e não informado NCM", CategoryRejection, false},
+	525: {525, "Rejeição: CFOP de Importação e não informado dados da DI", CategoryRejection, false},
+	527: {527, "Rejeição: Operação de Exportação com informação de ICMS incompatível", CategoryRejection, false},
+	528: {528, "Rejeição: Valor do ICMS difere do produto BC e Alíquota", CategoryRejection, false},
+	529: {529, "Rejeição: NCM de informação obrigatória para produto tributado pelo IPI", CategoryRejection, false},
+	530: {530, "Rejeição: Operação com tributação de ISSQN sem informar a Inscrição Municipal", CategoryRejection, false},
+	531: {531, "Rejeição: Total da BC ICMS difere do somatório dos itens", CategoryRejection, false},
+	532: {532, "Rejeição: Total do ICMS difere do somatório dos itens", CategoryRejection, false},
+	533: {533, "Rejeição: Total da BC ICMS-ST difere do somatório dos itens", CategoryRejection, false},
+	534: {534, "Rejeição: Total do ICMS-ST difere do somatório dos itens", CategoryRejection, false},
+	535: {535, "Rejeição: Total do Frete difere do somatório dos itens", CategoryRejection, false},
+	536: {536, "Rejeição: Total do Seguro difere do somatório dos itens", CategoryRejection, false},
+	537: {537, "Rejeição: Total do Desconto difere do somatório dos itens", CategoryRejection, false},
+	538: {538, "Rejeição: Total do IPI difere do somatório dos itens", CategoryRejection, false},
+	539: {539, "Rejeição: Duplicidade de NF-e com diferença na Chave de Acesso", CategoryDuplicate, false},
+	540: {540, "Rejeição: CPF do Local de Retirada inválido", CategoryRejection, false},
+	541: {541, "Rejeição: CPF do Local de Entrega inválido", CategoryRejection, false},
+	542: {542, "Rejeição: CNPJ do Transportador inválido", CategoryRejection, false},
+	543: {543, "Rejeição: CPF do Transportador inválido", CategoryRejection, false},
+	544: {544, "Rejeição: IE do Transportador inválida", CategoryRejection, false},
+	545: {545, "Rejeição: Falha no schema XML – versão informada na versaoDados do SOAPHeader diverge da versão da mensagem", CategoryRejection, false},
+	546: {546, "Rejeição: Erro na Chave de Acesso – Campo Id – falta a literal NFe", CategoryRejection, false},
+	547: {547, "Rejeição: Dígito Verificador da Chave de Acesso da NF-e Referenciada inválido", CategoryRejection, false},
+	548: {548, "Rejeição: CNPJ da NF referenciada inválido", CategoryRejection, false},
+	549: {549, "Rejeição: CNPJ da NF referenciada de produtor inválido", CategoryRejection, false},
+	550: {550, "Rejeição: CPF da NF referenciada de produtor inválido", CategoryRejection, false},
+	551: {551, "Rejeição: IE da NF referenciada de produtor inválido", CategoryRejection, false},
+	552: {552, "Rejeição: Dígito Verificador da Chave de Acesso do CT-e Referenciado inválido", CategoryRejection, false},
+	553: {553, "Rejeição: Tipo autorizador do recibo diverge do Órgão Autorizador", CategoryRejection, false},
+	554: {554, "Rejeição: Série difere da faixa 0-899", CategoryRejection, false},
+	555: {555, "Rejeição: Tipo autorizador do protocolo diverge do Órgão Autorizador", CategoryRejection, false},
+	556: {556, "Rejeição: Justificativa de entrada em contingência não deve ser informada para tipo de emissão normal", CategoryRejection, false},
+	557: {557, "Rejeição: A Justificativa de entrada em contingência deve ser informada", CategoryRejection, false},
+	558: {558, "Rejeição: Data de entrada em contingência posterior a data de recebimento", CategoryRejection, false},
+	559: {559, "Rejeição: UF do Transportador não informada", CategoryRejection, false},
+	560: {560, "Rejeição: CNPJ base do emitente difere do CNPJ base da primeira NF-e do lote recebido", CategoryRejection, false},
+	561: {561, "Rejeição: Mês de Emissão informado na Chave de Acesso difere do Mês de Emissão da NF-e", CategoryRejection, false},
+	562: {562, "Rejeição: Código Numérico informado na Chave de Acesso difere do Código Numérico da NF-e", CategoryRejection, false},
+	563: {563, "Rejeição: Já existe pedido de Inutilização com a mesma faixa de inutilização", CategoryDuplicate, false},
+	564: {564, "Rejeição: Total do Produto / Serviço difere do somatório dos itens", CategoryRejection, false},
+	565: {565, "Rejeição: Falha no schema XML – inexiste a tag raiz esperada para o lote de NF-e", CategoryRejection, false},
+	567: {567, "Rejeição: Falha no schema XML – versão informada na versaoDados do SOAPHeader diverge da versão do lote de NF-e", CategoryRejection, false},
+	568: {568, "Rejeição: Falha no schema XML – inexiste atributo versao na tag raiz do lote de NF-e", CategoryRejection, false},
+	569: {569, "Rejeição: Data de entrada em contingência muito atrasada", CategoryRejection, false},
+	570: {570, "Rejeição: Tipo de Emissão 3, 6 ou 7 só é válido nas contingências SCAN/SVC", CategoryRejection, false},
+	571: {571, "Rejeição: O tpEmis informado diferente de 3 para contingência SCAN", CategoryRejection, false},
+	572: {572, "Rejeição: Erro Atributo ID do evento não corresponde a concatenação dos campos (ID + tpEvento + chNFe + nSeqEvento)", CategoryRejection, false},
+	573: {573, "Rejeição: Duplicidade de Evento", CategoryDuplicate, false},
+	574: {574, "Rejeição: O autor do evento diverge do emissor da NF-e", CategoryRejection, false},
+	575: {575, "Rejeição: O autor do evento diverge do destinatário da NF-e", CategoryRejection, false},
+	576: {576, "Rejeição: O autor do evento não é um órgão autorizado a gerar o evento", CategoryRejection, false},
+	577: {577, "Rejeição: A data do evento não pode ser menor que a data de emissão da NF-e", CategoryRejection, false},
+	578: {578, "Rejeição: A data do evento não pode ser maior que a data do processamento", CategoryRejection, false},
+	579: {579, "Rejeição: A data do evento não pode ser menor que a data de autorização para NF-e não emitida em contingência", CategoryRejection, false},
+	580: {580, "Rejeição: O evento exige uma NF-e autorizada", CategoryRejection, false},
+	587: {587, "Rejeição: Usar somente o namespace padrão da NF-e", CategoryRejection, false},
+	588: {588, "Rejeição: Não é permitida a presença de caracteres de edição no início/fim da mensagem ou entre as tags da mensagem", CategoryRejection, false},
+	589: {589, "Rejeição: Número do NSU informado superior ao maior NSU da base de dados da SEFAZ", CategoryRejection, false},
+	590: {590, "Rejeição: Informado CST para emissor do Simples Nacional (CRT=1)", CategoryRejection, false},
+	591: {591, "Rejeição: Informado CSOSN para emissor que não é do Simples Nacional (CRT diferente de 1)", CategoryRejection, false},
+	592: {592, "Rejeição: A NF-e deve ter pelo menos um item de produto sujeito ao ICMS", CategoryRejection, false},
+	593: {593, "Rejeição: CNPJ-Base consultado difere do CNPJ-Base do Certificado Digital", CategoryRejection, false},
+	594: {594, "Rejeição: O número de sequencia do evento informado é maior que o permitido", CategoryRejection, false},
+	596: {596, "Rejeição: Evento apresentado fora do prazo", CategoryRejection, false},
+	597: {597, "Rejeição: CFOP de Importação e não informado dados de IPI", CategoryRejection, false},
+	598: {598, "Rejeição: NF-e emitida em ambiente de homologação com Razão Social do destinatário diferente de NF-E EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL", CategoryRejection, false},
+	599: {599, "Rejeição: CFOP de Importação e não informado dados de II", CategoryRejection, false},
+	601: {601, "Rejeição: Total do II difere do somatório dos itens", CategoryRejection, false},
+	602: {602, "Rejeição: Total do PIS difere do somatório dos itens sujeitos ao ICMS", CategoryRejection, false},
+	603: {603, "Rejeição: Total do COFINS difere do somatório dos itens sujeitos ao ICMS", CategoryRejection, false},
+	604: {604, "Rejeição: Total do vOutro difere do somatório dos itens", CategoryRejection, false},
+	605: {605, "Rejeição: Total do vISS difere do somatório do vProd dos itens sujeitos ao ISSQN", CategoryRejection, false},
+	606: {606, "Rejeição: Total do vBC do ISS difere do somatório dos itens", CategoryRejection, false},
+	607: {607, "Rejeição: Total do ISS difere do somatório dos itens", CategoryRejection, false},
+	608: {608, "Rejeição: Total do PIS difere do somatório dos itens sujeitos ao ISSQN", CategoryRejection, false},
+	609: {609, "Rejeição: Total do COFINS difere do somatório dos itens sujeitos ao ISSQN", CategoryRejection, false},
+	610: {610, "Rejeição: Total da NF difere do somatório dos Valores compõe o valor Total da NF", CategoryRejection, false},
+	611: {611, "Rejeição: cEAN inválido", CategoryRejection, false},
+	612: {612, "Rejeição: cEANTrib inválido", CategoryRejection, false},
+	613: {613, "Rejeição: Chave de Acesso difere da existente em BD", CategoryRejection, false},
+	614: {614, "Rejeição: Chave de Acesso inválida (Código UF inválido)", CategoryRejection, false},
+	615: {615, "Rejeição: Chave de Acesso inválida (Ano menor que 06 ou Ano maior que Ano corrente)", CategoryRejection, false},
+	616: {616, "Rejeição: Chave de Acesso inválida (Mês menor que 1 ou Mês maior que 12)", CategoryRejection, false},
+	617: {617, "Rejeição: Chave de Acesso inválida (CNPJ zerado ou dígito inválido)", CategoryRejection, false},
+	618: {618, "Rejeição: Chave de Acesso inválida (modelo diferente de 55 e 65)", CategoryRejection, false},
+	619: {619, "Rejeição: Chave de Acesso inválida (número NF = 0)", CategoryRejection, false},
+	620: {620, "Rejeição: Chave de Acesso difere da existente em BD", CategoryRejection, false},
+	621: {621, "Rejeição: CPF Emitente não cadastrado", CategoryRejection, false},
+	622: {622, "Rejeição: IE emitente não vinculada ao CPF", CategoryRejection, false},
+	623: {623, "Rejeição: CPF Destinatário não cadastrado", CategoryRejection, false},
+	624: {624, "Rejeição: IE Destinatário não vinculada ao CPF", CategoryRejection, false},
+	625: {625, "Rejeição: Inscrição SUFRAMA deve ser informada na venda com isenção para ZFM", CategoryRejection, false},
+	626: {626, "Rejeição: CFOP de operação isenta para ZFM diferente do previsto", CategoryRejection, false},
+	627: {627, "Rejeição: O valor do ICMS desonerado deve ser informado", CategoryRejection, false},
+	628: {628, "Rejeição: Total da NF superior ao valor limite estabelecido pela SEFAZ", CategoryRejection, false},
+	629: {629, "Rejeição: Valor do Produto difere do produto Valor Unitário de Comercialização e Quantidade Comercial", CategoryRejection, false},
+	630: {630, "Rejeição: Valor do Produto difere do produto Valor Unitário de Tributação e Quantidade Tributável", CategoryRejection, false},
+	631: {631, "Rejeição: CNPJ-Base do Destinatário difere do CNPJ-Base do Certificado Digital", CategoryRejection, false},
+	632: {632, "Rejeição: Solicitação fora de prazo, a NF-e não está mais disponível para download", CategoryRejection, false},
+	633: {633, "Rejeição: NF-e indisponível para download devido a ausência de Manifestação do Destinatário", CategoryRejection, false},
+	634: {634, "Rejeição: Destinatário da NF-e não tem o mesmo CNPJ raiz do solicitante do download", CategoryRejection, false},
+	635: {635, "Rejeição: NF-e com mesmo número e série já transmitida e aguardando processamento", CategoryDuplicate, false},
+	650: {650, "Rejeição: Evento de Ciência da Emissão para NF-e Cancelada ou Denegada", CategoryRejection, false},
+	651: {651, "Rejeição: Evento de Desconhecimento da Operação para NF-e Cancelada ou Denegada", CategoryRejection, false},
+	653: {653, "Rejeição: NF-e Cancelada, arquivo indisponível para download", CategoryRejection, false},
+	654: {654, "Rejeição: NF-e Denegada, arquivo indisponível para download", CategoryRejection, false},
+	655: {655, "Rejeição: Evento de Ciência da Emissão informado após a manifestação final do destinatário", CategoryRejection, false},
+	656: {656, "Rejeição: Consumo Indevido", CategoryThrottling, true},
+	657: {657, "Rejeição: Código do Órgão diverge do órgão autorizador", CategoryRejection, false},
+	658: {658, "Rejeição: UF do destinatário da Chave de Acesso diverge da UF autorizadora", CategoryRejection, false},
+	660: {660, "Rejeição: CFOP de Combustível e não informado grupo de combustível da NF-e", CategoryRejection, false},
+	661: {661, "Rejeição: NF-e já existente para o número do EPEC informado", CategoryRejection, false},
+	662: {662, "Rejeição: Numeração do EPEC está inutilizada na Base de Dados da SEFAZ", CategoryRejection, false},
+	663: {663, "Rejeição: Alíquota do ICMS com valor superior a 4 por cento na operação de saída interestadual com produtos importados", CategoryRejection, false},
+	678: {678, "Rejeição: NF referenciada com UF diferente da NF-e complementar", CategoryRejection, false},
+	679: {679, "Rejeição: Modelo da NF-e referenciada diferente de 55/65", CategoryRejection, false},
+	680: {680, "Rejeição: Duplicidade de NF-e referenciada (Chave de Acesso referenciada mais de uma vez)", CategoryRejection, false},
+	681: {681, "Rejeição: Duplicidade de NF Modelo 1 referenciada (CNPJ, Modelo, Série e Número)", CategoryRejection, false},
+	682: {682, "Rejeição: Duplicidade de NF de Produtor referenciada (IE, Modelo, Série e Número)", CategoryRejection, false},
+	683: {683, "Rejeição: Modelo do CT-e referenciado diferente de 57", CategoryRejection, false},
+	684: {684, "Rejeição: Duplicidade de Cupom Fiscal referenciado (Modelo, Número de Ordem e COO)", CategoryRejection, false},
+	685: {685, "Rejeição: Total do Valor Aproximado dos Tributos difere do somatório dos itens", CategoryRejection, false},
+	686: {686, "Rejeição: NF Complementar referencia uma NF-e cancelada", CategoryRejection, false},
+	687: {687, "Rejeição: NF Complementar referencia uma NF-e denegada", CategoryRejection, false},
+	688: {688, "Rejeição: NF referenciada de Produtor com IE inexistente", CategoryRejection, false},
+	689: {689, "Rejeição: NF referenciada de Produtor com IE não vinculada ao CNPJ/CPF informado", CategoryRejection, false},
+	690: {690, "Rejeição: Pedido de Cancelamento para NF-e com CT-e", CategoryRejection, false},
+	691: {691, "Rejeição: Chave de Acesso da NF-e diverge da Chave de Acesso do EPEC", CategoryRejection, false},
+	693: {693, "Rejeição: Alíquota de ICMS superior a definida para a operação interestadual", CategoryRejection, false},
+	694: {694, "Rejeição: Não informado o grupo de ICMS para a UF de destino", CategoryRejection, false},
+	695: {695, "Rejeição: Informado indevidamente o grupo de ICMS para a UF de destino", CategoryRejection, false},
+	696: {696, "Rejeição: Operação com não contribuinte deve indicar operação com consumidor final", CategoryRejection, false},
+	697: {697, "Rejeição: Alíquota interestadual do ICMS com origem diferente do previsto", CategoryRejection, false},
+	698: {698, "Rejeição: Alíquota interestadual do ICMS incompatível com as UF envolvidas na operação", CategoryRejection, false},
+	699: {699, "Rejeição: Percentual do ICMS Interestadual para a UF de destino difere do previsto para o ano da Data de Emissão", CategoryRejection, false},
+	700: {700, "Rejeição: Mensagem de Lote versão 3.xx. Enviar para o Web Service nfeAutorizacao", CategoryRejection, false},
+	702: {702, "Rejeição: NFC-e não é aceita pela UF do Emitente", CategoryRejection, false},
+	703: {703, "Rejeição: Data-Hora de Emissão posterior ao horário de recebimento", CategoryRejection, false},
+	704: {704, "Rejeição: NFC-e com Data-Hora de emissão atrasada", CategoryRejection, false},
+	705: {705, "Rejeição: NFC-e com data de entrada/saída", CategoryRejection, false},
+	706: {706, "Rejeição: NFC-e para operação de entrada", CategoryRejection, false},
+	707: {707, "Rejeição: NFC-e para operação interestadual ou com o exterior", CategoryRejection, false},
+	708: {708, "Rejeição: NFC-e não pode referenciar documento fiscal", CategoryRejection, false},
+	709: {709, "Rejeição: NFC-e com formato de DANFE inválido", CategoryRejection, false},
+	710: {710, "Rejeição: NF-e com formato de DANFE inválido", CategoryRejection, false},
+	711: {711, "Rejeição: NF-e com contingência off-line", CategoryRejection, false},
+	712: {712, "Rejeição: NFC-e com contingência off-line para a UF", CategoryRejection, false},
+	713: {713, "Rejeição: Tipo de Emissão diferente de 6 ou 7 para contingência da SVC acessada", CategoryRejection, false},
+	714: {714, "Rejeição: NFC-e com opção de contingência inválida", CategoryRejection, false},
+	715: {715, "Rejeição: NFC-e com finalidade inválida", CategoryRejection, false},
+	716: {716, "Rejeição: NFC-e em operação não destinada a consumidor final", CategoryRejection, false},
+	717: {717, "Rejeição: NFC-e em operação não presencial", CategoryRejection, false},
+	718: {718, "Rejeição: NFC-e não deve informar IE de Substituto Tributário", CategoryRejection, false},
+	719: {719, "Rejeição: NF-e sem a identificação do destinatário", CategoryRejection, false},
+	720: {720, "Rejeição: Na operação com Exterior deve ser informada tag idEstrangeiro", CategoryRejection, false},
+	721: {721, "Rejeição: Operação interestadual deve informar CNPJ ou CPF", CategoryRejection, false},
+	723: {723, "Rejeição: Operação interna com idEstrangeiro informado deve ser para consumidor final", CategoryRejection, false},
+	724: {724, "Rejeição: NF-e sem o nome do destinatário", CategoryRejection, false},
+	725: {725, "Rejeição: NFC-e com CFOP inválido", CategoryRejection, false},
+	726: {726, "Rejeição: NF-e sem a informação de endereço do destinatário", CategoryRejection, false},
+	727: {727, "Rejeição: Operação com Exterior e UF diferente de EX", CategoryRejection, false},
+	728: {728, "Rejeição: NF-e sem informação da IE do destinatário", CategoryRejection, false},
+	729: {729, "Rejeição: NFC-e com informação da IE do destinatário", CategoryRejection, false},
+	730: {730, "Rejeição: NFC-e com Inscrição Suframa", CategoryRejection, false},
+	731: {731, "Rejeição: CFOP de operação com Exterior e idDest <> 3", CategoryRejection, false},
+	732: {732, "Rejeição: CFOP de operação interestadual e idDest <> 2", CategoryRejection, false},
+	733: {733, "Rejeição: CFOP de operação interna e idDest <> 1", CategoryRejection, false},
+	734: {734, "Rejeição: NFC-e com Unidade de Comercialização inválida", CategoryRejection, false},
+	735: {735, "Rejeição: NFC-e com Unidade de Tributação inválida", CategoryRejection, false},
+	736: {736, "Rejeição: NFC-e com grupo de Veículos novos", CategoryRejection, false},
+	737: {737, "Rejeição: NFC-e com grupo de Medicamentos", CategoryRejection, false},
+	738: {738, "Rejeição: NFC-e com grupo de Armamentos", CategoryRejection, false},
+	740: {740, "Rejeição: NFC-e com CST 51-Diferimento", CategoryRejection, false},
+	741: {741, "Rejeição: NFC-e com Partilha de ICMS entre UF", CategoryRejection, false},
+	742: {742, "Rejeição: NFC-e com grupo do IPI", CategoryRejection, false},
+	743: {743, "Rejeição: NFC-e com grupo do II", CategoryRejection, false},
+	745: {745, "Rejeição: NF-e sem grupo do PIS", CategoryRejection, false},
+	746: {746, "Rejeição: NFC-e com grupo do PIS-ST", CategoryRejection, false},
+	748: {748, "Rejeição: NF-e sem grupo da COFINS", CategoryRejection, false},
+	749: {749, "Rejeição: NFC-e com grupo da COFINS-ST", CategoryRejection, false},
+	750: {750, "Rejeição: NFC-e com valor total superior ao permitido para destinatário não identificado (Código)", CategoryRejection, false},
+	751: {751, "Rejeição: NFC-e com valor total superior ao permitido para destinatário não identificado (Nome)", CategoryRejection, false},
+	752: {752, "Rejeição: NFC-e com valor total superior ao permitido para destinatário não identificado (Endereço)", CategoryRejection, false},
+	753: {753, "Rejeição: NFC-e com Frete", CategoryRejection, false},
+	754: {754, "Rejeição: NFC-e com dados do Transportador", CategoryRejection, false},
+	755: {755, "Rejeição: NFC-e com dados de Retenção do ICMS no Transporte", CategoryRejection, false},
+	756: {756, "Rejeição: NFC-e com dados do veículo de Transporte", CategoryRejection, false},
+	757: {757, "Rejeição: NFC-e com dados de Reboque do veículo de Transporte", CategoryRejection, false},
+	758: {758, "Rejeição: NFC-e com dados do Vagão de Transporte", CategoryRejection, false},
+	759: {759, "Rejeição: NFC-e com dados da Balsa de Transporte", CategoryRejection, false},
+	760: {760, "Rejeição: NFC-e com dados de cobrança (Fatura, Duplicata)", CategoryRejection, false},
+	762: {762, "Rejeição: NFC-e com dados de compras (Empenho, Pedido, Contrato)", CategoryRejection, false},
+	763: {763, "Rejeição: NFC-e com dados de aquisição de Cana", CategoryRejection, false},
+	764: {764, "Rejeição: Solicitada resposta síncrona para Lote com mais de uma NF-e (indSinc=1)", CategoryRejection, false},
+	765: {765, "Rejeição: Lote só poderá conter NF-e ou NFC-e", CategoryRejection, false},
+	766: {766, "Rejeição: NFC-e com CST 50-Suspensão", CategoryRejection, false},
+	767: {767, "Rejeição: NFC-e com somatório dos pagamentos diferente do total da Nota Fiscal", CategoryRejection, false},
+	768: {768, "Rejeição: NF-e não deve possuir o grupo de Formas de Pagamento", CategoryRejection, false},
+	769: {769, "Rejeição: NFC-e deve possuir o grupo de Formas de Pagamento", CategoryRejection, false},
+	770: {770, "Rejeição: NFC-e autorizada há mais de 24 horas", CategoryRejection, false},
+	771: {771, "Rejeição: Operação Interestadual e UF de destino com EX", CategoryRejection, false},
+	772: {772, "Rejeição: Operação Interestadual e UF de destino igual à UF do emitente", CategoryRejection, false},
+	773: {773, "Rejeição: Operação Interna e UF de destino difere da UF do emitente", CategoryRejection, false},
+	774: {774, "Rejeição: NFC-e com indicador de item não participante do total", CategoryRejection, false},
+	775: {775, "Rejeição: Modelo da NFC-e diferente de 65", CategoryRejection, false},
+	776: {776, "Rejeição: Solicitada resposta síncrona para UF que não disponibiliza este atendimento (indSinc=1)", CategoryRejection, false},
+	777: {777, "Rejeição: Obrigatória a informação do NCM completo", CategoryRejection, false},
+	778: {778, "Rejeição: Informado NCM inexistente", CategoryRejection, false},
+	779: {779, "Rejeição: NFC-e com NCM incompatível", CategoryRejection, false},
+	780: {780, "Rejeição: Total da NFC-e superior ao valor limite estabelecido pela SEFAZ", CategoryRejection, false},
+	781: {781, "Rejeição: Emissor não habilitado para emissão da NFC-e", CategoryRejection, false},
+	782: {782, "Rejeição: NFC-e não é autorizada pelo SCAN", CategoryRejection, false},
+	783: {783, "Rejeição: NFC-e não é autorizada pela SVC", CategoryRejection, false},
+	785: {785, "Rejeição: NFC-e com entrega a domicílio não permitida pela UF", CategoryRejection, false},
+	786: {786, "Rejeição: NFC-e de entrega a domicílio sem dados do Transportador", CategoryRejection, false},
+	787: {787, "Rejeição: NFC-e de entrega a domicílio sem a identificação do destinatário", CategoryRejection, false},
+	788: {788, "Rejeição: NFC-e de entrega a domicílio sem o endereço do destinatário", CategoryRejection, false},
+	789: {789, "Rejeição: NFC-e para destinatário contribuinte de outra UF", CategoryRejection, false},
+	791: {791, "Rejeição: NF-e com indicação de destinatário isento de IE, com a informação da IE do destinatário", CategoryRejection, false},
+	792: {792, "Rejeição: Informada a IE do destinatário para operação com destinatário no Exterior", CategoryRejection, false},
+	793: {793, "Rejeição: Valor do ICMS relativo ao Fundo de Combate à Pobreza na UF de destino difere do calculado", CategoryRejection, false},
+	794: {794, "Rejeição: NF-e com indicativo de NFC-e com entrega a domicílio", CategoryRejection, false},
+	795: {795, "Rejeição: Total do ICMS desonerado difere do somatório dos itens", CategoryRejection, false},
+	796: {796, "Rejeição: Empresa sem Chave de Segurança para o QR-Code", CategoryRejection, false},
+	797: {797, "Rejeição: Valor total do ICMS relativo Fundo de Combate à Pobreza (FCP) da UF de destino difere do somatório do valor dos itens", CategoryRejection, false},
+	798: {798, "Rejeição: Valor total do ICMS Interestadual da UF de destino difere do somatório dos itens", CategoryRejection, false},
+	799: {799, "Rejeição: Valor total do ICMS Interestadual da UF do remetente difere do somatório dos itens", CategoryRejection, false},
+	806: {806, "Rejeição: Operação com ICMS-ST sem informação do CEST", CategoryRejection, false},
+	807: {807, "Rejeição: NFC-e com grupo de ICMS para a UF do destinatário", CategoryRejection, false},
+	999: {999, "Rejeição: Erro não catalogado", CategoryTransient, true},
+}
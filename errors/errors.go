@@ -112,6 +112,9 @@ func NewXMLError(message string, field string, cause error) *NFError {
 }
 
 // NewSEFAZError creates a new SEFAZ error
+//
+// Deprecated: use NewSEFAZStatusError, which keeps the cStat and its category
+// for errors.Is and retries.
 func NewSEFAZError(message string, value interface{}, cause error) *NFError {
 	return &NFError{
 		Type:    ErrSEFAZ,
@@ -0,0 +1,130 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+// CStatCategory groups SEFAZ return codes by how the caller should react
+type CStatCategory string
+
+const (
+	// CategoryAuthorized covers authorizations, homologated cancellations and
+	// inutilizações and registered events
+	CategoryAuthorized CStatCategory = "authorized"
+	// CategoryProcessed covers informational successes (lote recebido, serviço em operação, consultas)
+	CategoryProcessed CStatCategory = "processed"
+	// CategoryDenied covers uso denegado, a final state for the access key
+	CategoryDenied CStatCategory = "denied"
+	// CategoryRejection covers rejections that require fixing the message
+	CategoryRejection CStatCategory = "rejection"
+	// CategoryDuplicate covers requests SEFAZ already processed (duplicidade)
+	CategoryDuplicate CStatCategory = "duplicate"
+	// CategoryThrottling covers consumo indevido, which blocks the sender for a while
+	CategoryThrottling CStatCategory = "throttling"
+	// CategoryTransient covers temporary SEFAZ failures
+	CategoryTransient CStatCategory = "transient"
+)
+
+// CStatInfo describes a SEFAZ return code
+type CStatInfo struct {
+	Code     int
+	Message  string
+	Category CStatCategory
+	// Retryable reports whether the same request can be resent unchanged after a delay
+	Retryable bool
+}
+
+// LookupCStat returns the catalog entry for a return code
+func LookupCStat(cStat int) (CStatInfo, bool) {
+	info, ok := cstatCatalog[cStat]
+	return info, ok
+}
+
+// CStatDetails returns the catalog entry for a return code. Codes missing from
+// the catalog are classified by range: below 200 as processed, otherwise as
+// non-retryable rejections.
+func CStatDetails(cStat int) CStatInfo {
+	if info, ok := cstatCatalog[cStat]; ok {
+		return info
+	}
+	if cStat > 0 && cStat < 200 {
+		return CStatInfo{Code: cStat, Category: CategoryProcessed}
+	}
+	return CStatInfo{Code: cStat, Category: CategoryRejection}
+}
+
+// IsSuccessCStat reports whether a return code means the request was accepted
+func IsSuccessCStat(cStat int) bool {
+	category := CStatDetails(cStat).Category
+	return category == CategoryAuthorized || category == CategoryProcessed
+}
+
+// IsRetryableCStat reports whether a request answered with cStat can be resent unchanged
+func IsRetryableCStat(cStat int) bool {
+	return CStatDetails(cStat).Retryable
+}
+
+// SEFAZError is a SEFAZ response whose cStat is not a success
+type SEFAZError struct {
+	CStat    int
+	XMotivo  string
+	Category CStatCategory
+}
+
+// Sentinel SEFAZ errors for use with errors.Is, matching any cStat of a category
+var (
+	ErrSEFAZDenied     = &SEFAZError{Category: CategoryDenied}
+	ErrSEFAZRejection  = &SEFAZError{Category: CategoryRejection}
+	ErrSEFAZDuplicate  = &SEFAZError{Category: CategoryDuplicate}
+	ErrSEFAZThrottling = &SEFAZError{Category: CategoryThrottling}
+	ErrSEFAZTransient  = &SEFAZError{Category: CategoryTransient}
+)
+
+// NewSEFAZStatusError creates a typed SEFAZ error from a cStat and xMotivo.
+// The catalog message is used when xMotivo is empty.
+func NewSEFAZStatusError(cStat int, xMotivo string) *SEFAZError {
+	info := CStatDetails(cStat)
+	if xMotivo == "" {
+		xMotivo = info.Message
+	}
+	return &SEFAZError{
+		CStat:    cStat,
+		XMotivo:  xMotivo,
+		Category: info.Category,
+	}
+}
+
+// Error implements the error interface
+func (e *SEFAZError) Error() string {
+	return fmt.Sprintf("[%s] cStat %d: %s", ErrSEFAZ.Code, e.CStat, e.XMotivo)
+}
+
+// Retryable reports whether the request can be resent unchanged after a delay
+func (e *SEFAZError) Retryable() bool {
+	return CStatDetails(e.CStat).Retryable
+}
+
+// Is matches another SEFAZError with the same cStat, a category sentinel such
+// as ErrSEFAZDuplicate, or any NFError of type ErrSEFAZ
+func (e *SEFAZError) Is(target error) bool {
+	switch t := target.(type) {
+	case *SEFAZError:
+		if t.CStat != 0 && t.CStat != e.CStat {
+			return false
+		}
+		return t.Category == "" || t.Category == e.Category
+	case *NFError:
+		return t.Type != nil && t.Type.Code == ErrSEFAZ.Code
+	}
+	return false
+}
+
+// AsSEFAZError finds the first SEFAZError in the chain of err
+func AsSEFAZError(err error) (*SEFAZError, bool) {
+	var sefazErr *SEFAZError
+	if stderrors.As(err, &sefazErr) {
+		return sefazErr, true
+	}
+	return nil, false
+}
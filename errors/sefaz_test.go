@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLookupCStat(t *testing.T) {
+	tests := []struct {
+		cStat     int
+		category  CStatCategory
+		retryable bool
+	}{
+		{100, CategoryAuthorized, false},
+		{135, CategoryAuthorized, false},
+		{107, CategoryProcessed, false},
+		{105, CategoryTransient, true},
+		{108, CategoryTransient, true},
+		{109, CategoryTransient, true},
+		{110, CategoryDenied, false},
+		{302, CategoryDenied, false},
+		{204, CategoryDuplicate, false},
+		{539, CategoryDuplicate, false},
+		{573, CategoryDuplicate, false},
+		{215, CategoryRejection, false},
+		{217, CategoryRejection, false},
+		{656, CategoryThrottling, true},
+		{999, CategoryTransient, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.cStat), func(t *testing.T) {
+			info, ok := LookupCStat(tt.cStat)
+			if !ok {
+				t.Fatalf("LookupCStat(%d) not found", tt.cStat)
+			}
+			if info.Code != tt.cStat || info.Message == "" {
+				t.Errorf("LookupCStat(%d) = %+v", tt.cStat, info)
+			}
+			if info.Category != tt.category {
+				t.Errorf("Category = %s, want %s", info.Category, tt.category)
+			}
+			if info.Retryable != tt.retryable {
+				t.Errorf("Retryable = %v, want %v", info.Retryable, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestCStatCatalogConsistency(t *testing.T) {
+	if len(cstatCatalog) < 400 {
+		t.Errorf("catalog has %d entries, expected the full table", len(cstatCatalog))
+	}
+	for code, info := range cstatCatalog {
+		if info.Code != code {
+			t.Errorf("entry %d has Code %d", code, info.Code)
+		}
+		if info.Message == "" {
+			t.Errorf("entry %d has no message", code)
+		}
+		if info.Retryable && info.Category != CategoryTransient && info.Category != CategoryThrottling {
+			t.Errorf("entry %d is retryable with category %s", code, info.Category)
+		}
+	}
+}
+
+func TestCStatDetailsUnknown(t *testing.T) {
+	if info := CStatDetails(199); info.Category != CategoryProcessed {
+		t.Errorf("CStatDetails(199).Category = %s, want processed", info.Category)
+	}
+	if info := CStatDetails(998); info.Category != CategoryRejection || info.Retryable {
+		t.Errorf("CStatDetails(998) = %+v, want non-retryable rejection", info)
+	}
+	if !IsSuccessCStat(100) || !IsSuccessCStat(128) || IsSuccessCStat(204) {
+		t.Error("IsSuccessCStat returned unexpected results")
+	}
+	if !IsRetryableCStat(108) || IsRetryableCStat(539) {
+		t.Error("IsRetryableCStat returned unexpected results")
+	}
+}
+
+func TestSEFAZError(t *testing.T) {
+	err := NewSEFAZStatusError(204, "Rejeição: Duplicidade de NF-e [nRec:351000000000001]")
+
+	if err.Error() != "[SEFAZ] cStat 204: Rejeição: Duplicidade de NF-e [nRec:351000000000001]" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if err.Category != CategoryDuplicate || err.Retryable() {
+		t.Errorf("unexpected classification %+v", err)
+	}
+
+	if empty := NewSEFAZStatusError(217, ""); empty.XMotivo != "Rejeição: NF-e não consta na base de dados da SEFAZ" {
+		t.Errorf("XMotivo fallback = %q", empty.XMotivo)
+	}
+}
+
+func TestSEFAZErrorIsAs(t *testing.T) {
+	wrapped := fmt.Errorf("authorizing: %w", NewSEFAZStatusError(539, ""))
+
+	if !errors.Is(wrapped, ErrSEFAZDuplicate) {
+		t.Error("errors.Is should match the duplicate sentinel")
+	}
+	if errors.Is(wrapped, ErrSEFAZRejection) {
+		t.Error("errors.Is should not match another category")
+	}
+	if !errors.Is(wrapped, &SEFAZError{CStat: 539}) {
+		t.Error("errors.Is should match the same cStat")
+	}
+	if errors.Is(wrapped, &SEFAZError{CStat: 204}) {
+		t.Error("errors.Is should not match another cStat")
+	}
+	if !errors.Is(wrapped, NewSEFAZError("", nil, nil)) {
+		t.Error("errors.Is should match NFError of type SEFAZ")
+	}
+
+	var sefazErr *SEFAZError
+	if !errors.As(wrapped, &sefazErr) || sefazErr.CStat != 539 {
+		t.Errorf("errors.As failed: %v", sefazErr)
+	}
+
+	if found, ok := AsSEFAZError(WrapError(wrapped, ErrNetwork, "request failed")); !ok || found.CStat != 539 {
+		t.Error("AsSEFAZError should find the error through NFError causes")
+	}
+	if _, ok := AsSEFAZError(NewNetworkError("timeout", nil)); ok {
+		t.Error("AsSEFAZError should not find an error that is not in the chain")
+	}
+}
@@ -2,4 +2,4 @@ module github.com/adrianodrix/sped-nfe-go
 
 go 1.24.4
 
-require golang.org/x/text v0.26.0 // indirect
+require golang.org/x/text v0.26.0
@@ -0,0 +1,238 @@
+// Package ibpt computes the approximate tax burden of sales to consumers
+// required by the Lei da Transparência (Lei 12.741/2012) using the tables
+// published by the IBPT (De Olho no Imposto).
+//
+// Tables are loaded offline from the CSV files of each UF and can be refreshed
+// through a Provider, such as the IBPT API authenticated by
+// common.Config.TokenIBPT:
+//
+//	table := ibpt.NewTable()
+//	if _, err := table.LoadFile("TabelaIBPTaxSP25.1.A.csv"); err != nil {
+//		log.Fatal(err)
+//	}
+//	service := ibpt.NewService(table, nil)
+//	summary, err := service.ApplyItems(ctx, items, "SP")
+//	// each item has vTotTrib; summary.VTotTrib goes to ICMSTot
+//	infCpl := summary.Message()
+package ibpt
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+// Kind identifies the nomenclature of an entry code
+type Kind int
+
+const (
+	KindNCM   Kind = 0 // Nomenclatura Comum do Mercosul (goods)
+	KindNBS   Kind = 1 // Nomenclatura Brasileira de Serviços
+	KindLC116 Kind = 2 // Lista de serviços da LC 116/2003
+)
+
+// Entry holds the approximate tax rates of a code in a UF
+type Entry struct {
+	Code        string
+	Ex          string
+	Kind        Kind
+	Description string
+	// National and Imported are the federal rates for national and imported
+	// goods; State and Municipal the rates of the other spheres
+	National  decimal.Decimal
+	Imported  decimal.Decimal
+	State     decimal.Decimal
+	Municipal decimal.Decimal
+
+	ValidFrom  time.Time
+	ValidUntil time.Time
+	Key        string
+	Version    string
+	Source     string
+}
+
+// ValidAt reports whether the entry is valid at t; ValidUntil includes the whole day
+func (e Entry) ValidAt(t time.Time) bool {
+	return !t.Before(e.ValidFrom) && t.Before(e.ValidUntil.AddDate(0, 0, 1))
+}
+
+// Amounts holds the approximate taxes of a value by sphere
+type Amounts struct {
+	Federal   decimal.Decimal
+	State     decimal.Decimal
+	Municipal decimal.Decimal
+}
+
+// Total returns the sum of the taxes of every sphere
+func (a Amounts) Total() decimal.Decimal {
+	return decimal.Money(0).Add(a.Federal).Add(a.State).Add(a.Municipal)
+}
+
+// add returns the sum of a and b
+func (a Amounts) add(b Amounts) Amounts {
+	return Amounts{Federal: a.Federal.Add(b.Federal), State: a.State.Add(b.State), Municipal: a.Municipal.Add(b.Municipal)}
+}
+
+// Amounts computes the approximate taxes of a value; imported goods use the
+// imported federal rate
+func (e Entry) Amounts(value decimal.Decimal, imported bool) Amounts {
+	federal := e.National
+	if imported {
+		federal = e.Imported
+	}
+	tax := func(rate decimal.Decimal) decimal.Decimal {
+		r := new(big.Rat).Mul(value.Rat(), rate.Rat())
+		return decimal.NewFromRat(r.Quo(r, big.NewRat(100, 1)), decimal.MoneyPlaces, decimal.RoundHalfUp)
+	}
+	return Amounts{Federal: tax(federal), State: tax(e.State), Municipal: tax(e.Municipal)}
+}
+
+// IsImported reports whether an origin (orig) uses the imported federal rate:
+// foreign goods (1, 2) and national goods with import content above 40% (3, 8)
+func IsImported(orig int) bool {
+	switch orig {
+	case 1, 2, 3, 8:
+		return true
+	}
+	return false
+}
+
+// Query identifies the code looked up in the table or in a Provider. The
+// description, unit, value and GTIN are sent to the IBPT API, which requires them.
+type Query struct {
+	UF          string
+	Code        string
+	Ex          string
+	Kind        Kind
+	Description string
+	Unit        string
+	Value       decimal.Decimal
+	GTIN        string
+}
+
+// Provider fetches entries that are missing or expired in the table
+type Provider interface {
+	Lookup(ctx context.Context, query Query) (Entry, error)
+}
+
+// Service looks up entries in the table, refreshing them through the
+// provider when they are missing or expired
+type Service struct {
+	Table    *Table
+	Provider Provider
+	// Now returns the date used to check the validity of the entries
+	Now func() time.Time
+}
+
+// NewService creates a service over the table; provider may be nil to work offline
+func NewService(table *Table, provider Provider) *Service {
+	if table == nil {
+		table = NewTable()
+	}
+	return &Service{Table: table, Provider: provider, Now: time.Now}
+}
+
+// Entry returns the entry of the query valid today
+func (s *Service) Entry(ctx context.Context, query Query) (Entry, error) {
+	now := s.Now()
+	entry, found := s.Table.Lookup(query.UF, query.Code, query.Ex)
+	if found && entry.ValidAt(now) {
+		return entry, nil
+	}
+
+	if s.Provider == nil {
+		if found {
+			return Entry{}, errors.NewValidationError(fmt.Sprintf("IBPT entry of %s expired on %s", query.Code, entry.ValidUntil.Format(dateLayout)), "codigo", query.Code)
+		}
+		return Entry{}, errors.NewValidationError("IBPT entry not found for "+query.UF, "codigo", query.Code)
+	}
+
+	refreshed, err := s.Provider.Lookup(ctx, query)
+	if err != nil {
+		return Entry{}, err
+	}
+	if !refreshed.ValidAt(now) {
+		return Entry{}, errors.NewValidationError("IBPT provider returned an entry out of validity", "codigo", query.Code)
+	}
+	s.Table.Add(query.UF, refreshed)
+	return refreshed, nil
+}
+
+// Summary holds the approximate taxes of a document and the table used
+type Summary struct {
+	Amounts
+	VTotTrib decimal.Decimal
+	Source   string
+	Key      string
+	Version  string
+}
+
+// Message returns the consumer message for infCpl, in the format recommended
+// by the IBPT
+func (s Summary) Message() string {
+	source := strings.TrimSpace(s.Source + " " + s.Key)
+	return fmt.Sprintf("Trib aprox R$ %s Federal, R$ %s Estadual e R$ %s Municipal. Fonte: %s",
+		formatBRL(s.Federal), formatBRL(s.State), formatBRL(s.Municipal), source)
+}
+
+// ItemValue returns the value of an item subject to the approximate taxes:
+// vProd + vFrete + vSeg + vOutro − vDesc
+func ItemValue(prod nfe.Produto) decimal.Decimal {
+	return decimal.Money(0).Add(prod.VProd).Add(prod.VFrete).Add(prod.VSeg).Add(prod.VOutro).Sub(prod.VDesc)
+}
+
+// ApplyItems computes the approximate taxes of each item by its NCM and
+// EXTIPI in the UF, setting the item vTotTrib, and returns the totals of the
+// document
+func (s *Service) ApplyItems(ctx context.Context, items []nfe.Item, uf string) (Summary, error) {
+	var summary Summary
+	for i := range items {
+		prod := items[i].Prod
+		value := ItemValue(prod)
+		entry, err := s.Entry(ctx, Query{
+			UF: uf, Code: prod.NCM, Ex: prod.EXTIPI, Kind: KindNCM,
+			Description: prod.XProd, Unit: prod.UCom, Value: value, GTIN: prod.CEAN,
+		})
+		if err != nil {
+			return Summary{}, err
+		}
+
+		amounts := entry.Amounts(value, IsImported(items[i].Imposto.ICMS.Origin()))
+		items[i].Imposto.VTotTrib = amounts.Total()
+		summary.Amounts = summary.Amounts.add(amounts)
+		if summary.Key == "" {
+			summary.Source, summary.Key, summary.Version = entry.Source, entry.Key, entry.Version
+		}
+	}
+
+	summary.Federal = decimal.Money(0).Add(summary.Federal)
+	summary.State = decimal.Money(0).Add(summary.State)
+	summary.Municipal = decimal.Money(0).Add(summary.Municipal)
+	summary.VTotTrib = summary.Total()
+	return summary, nil
+}
+
+// formatBRL formats a value in the Brazilian notation: 1.234,56
+func formatBRL(value decimal.Decimal) string {
+	text := value.StringFixed(decimal.MoneyPlaces)
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	integer, fraction, _ := strings.Cut(text, ".")
+
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + grouped.String() + "," + fraction
+}
@@ -0,0 +1,131 @@
+package ibpt
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+func testService(t *testing.T, provider Provider) *Service {
+	t.Helper()
+	table := NewTable()
+	if _, err := table.LoadCSV(strings.NewReader(testCSV), "SP"); err != nil {
+		t.Fatal(err)
+	}
+	service := NewService(table, provider)
+	service.Now = func() time.Time { return time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local) }
+	return service
+}
+
+func TestEntryAmounts(t *testing.T) {
+	entry := Entry{National: decimal.MustParse("13.45"), Imported: decimal.MustParse("15.45"), State: decimal.MustParse("25"), Municipal: decimal.MustParse("0")}
+
+	national := entry.Amounts(decimal.Money(59.9), false)
+	expected := Amounts{Federal: decimal.Money(8.06), State: decimal.Money(14.98), Municipal: decimal.Money(0)}
+	if national != expected {
+		t.Errorf("Amounts() = %+v, expected %+v", national, expected)
+	}
+	if total := national.Total(); total != decimal.Money(23.04) {
+		t.Errorf("Total() = %s, expected 23.04", total)
+	}
+
+	if imported := entry.Amounts(decimal.Money(100), true); imported.Federal != decimal.Money(15.45) {
+		t.Errorf("imported goods should use the imported rate, got %s", imported.Federal)
+	}
+}
+
+func TestApplyItems(t *testing.T) {
+	items := []nfe.Item{
+		{
+			NItem:   1,
+			Prod:    nfe.Produto{NCM: "22030000", VProd: decimal.Money(59.9), IndTot: 1},
+			Imposto: nfe.Imposto{ICMS: nfe.ICMS{ICMSSN102: &nfe.ICMSSN102{Orig: 0, CSOSN: "102"}}},
+		},
+		{
+			NItem: 2,
+			Prod: nfe.Produto{
+				NCM: "84713012", EXTIPI: "01", VProd: decimal.Money(2000), VFrete: decimal.Money(50), VDesc: decimal.Money(50), IndTot: 1,
+			},
+			Imposto: nfe.Imposto{ICMS: nfe.ICMS{ICMS00: &nfe.ICMS00{Orig: 1, CST: "00"}}},
+		},
+	}
+
+	summary, err := testService(t, nil).ApplyItems(context.Background(), items, "SP")
+	if err != nil {
+		t.Fatalf("ApplyItems should not return error, got: %v", err)
+	}
+
+	if items[0].Imposto.VTotTrib != decimal.Money(23.04) {
+		t.Errorf("item 1 vTotTrib = %s, expected 23.04", items[0].Imposto.VTotTrib)
+	}
+	// 2000.00 × (13.25% imported + 18%)
+	if items[1].Imposto.VTotTrib != decimal.Money(625) {
+		t.Errorf("item 2 vTotTrib = %s, expected 625.00", items[1].Imposto.VTotTrib)
+	}
+	if summary.VTotTrib != decimal.Money(648.04) || summary.Federal != decimal.Money(273.06) || summary.State != decimal.Money(374.98) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	expected := "Trib aprox R$ 273,06 Federal, R$ 374,98 Estadual e R$ 0,00 Municipal. Fonte: IBPT/empresometro.com.br 5D9A3A"
+	if message := summary.Message(); message != expected {
+		t.Errorf("Message() = %q, expected %q", message, expected)
+	}
+
+	if total := nfe.CalculateTotals(items); total.VTotTrib != decimal.Money(648.04) {
+		t.Errorf("ICMSTot vTotTrib = %s, expected 648.04", total.VTotTrib)
+	}
+}
+
+func TestServiceEntry(t *testing.T) {
+	service := testService(t, nil)
+	ctx := context.Background()
+
+	if _, err := service.Entry(ctx, Query{UF: "SP", Code: "99999999"}); err == nil {
+		t.Error("expected error for a code not in the table")
+	}
+
+	service.Now = func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.Local) }
+	if _, err := service.Entry(ctx, Query{UF: "SP", Code: "22030000"}); err == nil {
+		t.Error("expected error for an expired entry")
+	}
+
+	provider := &fakeProvider{entry: Entry{
+		Code: "22030000", National: decimal.MustParse("13.45"), State: decimal.MustParse("25"),
+		ValidFrom: time.Date(2025, 7, 1, 0, 0, 0, 0, time.Local), ValidUntil: time.Date(2025, 12, 31, 0, 0, 0, 0, time.Local),
+		Key: "6E0B4B", Version: "25.2.A",
+	}}
+	service.Provider = provider
+	entry, err := service.Entry(ctx, Query{UF: "SP", Code: "22030000"})
+	if err != nil {
+		t.Fatalf("Entry should not return error, got: %v", err)
+	}
+	if entry.Version != "25.2.A" || provider.calls != 1 {
+		t.Errorf("expected the entry refreshed by the provider, got %+v after %d calls", entry, provider.calls)
+	}
+	if _, err := service.Entry(ctx, Query{UF: "SP", Code: "22030000"}); err != nil || provider.calls != 1 {
+		t.Errorf("the refreshed entry should be stored in the table, got %v after %d calls", err, provider.calls)
+	}
+}
+
+func TestFormatBRL(t *testing.T) {
+	tests := map[string]string{"0": "0,00", "5.5": "5,50", "1234.56": "1.234,56", "-1234567.8": "-1.234.567,80"}
+	for input, expected := range tests {
+		if result := formatBRL(decimal.MustParse(input)); result != expected {
+			t.Errorf("formatBRL(%s) = %s, expected %s", input, result, expected)
+		}
+	}
+}
+
+type fakeProvider struct {
+	entry Entry
+	calls int
+}
+
+func (p *fakeProvider) Lookup(ctx context.Context, query Query) (Entry, error) {
+	p.calls++
+	return p.entry, nil
+}
@@ -0,0 +1,188 @@
+package ibpt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/common"
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/utils"
+)
+
+// DefaultBaseURL is the address of the IBPT API (De Olho no Imposto)
+const DefaultBaseURL = "https://apidoni.ibpt.org.br/api/v1"
+
+// HTTPProvider fetches entries from the IBPT API
+type HTTPProvider struct {
+	BaseURL    string
+	Token      string
+	CNPJ       string
+	HTTPClient *http.Client
+}
+
+// NewHTTPProvider creates a provider authenticated by the TokenIBPT and CNPJ
+// of the configuration
+func NewHTTPProvider(config *common.Config) (*HTTPProvider, error) {
+	if config == nil {
+		return nil, errors.NewConfigError("configuration cannot be nil", "", nil)
+	}
+	if config.TokenIBPT == nil || strings.TrimSpace(*config.TokenIBPT) == "" {
+		return nil, errors.NewConfigError("IBPT token is required", "tokenIBPT", nil)
+	}
+
+	timeout := 30 * time.Second
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
+	return &HTTPProvider{
+		BaseURL:    DefaultBaseURL,
+		Token:      strings.TrimSpace(*config.TokenIBPT),
+		CNPJ:       utils.CleanDocument(config.CNPJ),
+		HTTPClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// apiEntry is the JSON returned by the IBPT API
+type apiEntry struct {
+	Codigo         string     `json:"Codigo"`
+	EX             jsonScalar `json:"EX"`
+	Descricao      string     `json:"Descricao"`
+	Nacional       jsonScalar `json:"Nacional"`
+	Estadual       jsonScalar `json:"Estadual"`
+	Importado      jsonScalar `json:"Importado"`
+	Municipal      jsonScalar `json:"Municipal"`
+	VigenciaInicio string     `json:"VigenciaInicio"`
+	VigenciaFim    string     `json:"VigenciaFim"`
+	Chave          string     `json:"Chave"`
+	Versao         string     `json:"Versao"`
+	Fonte          string     `json:"Fonte"`
+}
+
+// jsonScalar keeps the text of a JSON number or string, since the API is not
+// consistent in the type of some fields
+type jsonScalar string
+
+// UnmarshalJSON accepts numbers, strings and null
+func (s *jsonScalar) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		*s = ""
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		text = value
+	}
+	*s = jsonScalar(strings.TrimSpace(text))
+	return nil
+}
+
+// Lookup queries the products endpoint for NCM codes and the services
+// endpoint for NBS and LC 116 codes
+func (p *HTTPProvider) Lookup(ctx context.Context, query Query) (Entry, error) {
+	endpoint := "produtos"
+	params := url.Values{
+		"token":     {p.Token},
+		"cnpj":      {p.CNPJ},
+		"codigo":    {query.Code},
+		"uf":        {query.UF},
+		"descricao": {query.Description},
+		"valor":     {query.Value.StringFixed(decimal.MoneyPlaces)},
+	}
+	if query.Kind == KindNCM {
+		ex := query.Ex
+		if ex == "" {
+			ex = "0"
+		}
+		params.Set("ex", ex)
+		params.Set("unidadeMedida", query.Unit)
+		params.Set("gtin", query.GTIN)
+	} else {
+		endpoint = "servicos"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.BaseURL, "/")+"/"+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return Entry{}, errors.NewNetworkError("failed to create IBPT request", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Entry{}, errors.NewNetworkError("IBPT request failed", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Entry{}, errors.NewNetworkError(fmt.Sprintf("IBPT returned HTTP %d for %s", resp.StatusCode, query.Code), nil)
+	}
+
+	var result apiEntry
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Entry{}, errors.NewNetworkError("invalid IBPT response", err)
+	}
+	return result.entry(query)
+}
+
+// entry converts the API response into an entry
+func (a apiEntry) entry(query Query) (Entry, error) {
+	entry := Entry{
+		Code:        a.Codigo,
+		Ex:          string(a.EX),
+		Kind:        query.Kind,
+		Description: a.Descricao,
+		Key:         a.Chave,
+		Version:     a.Versao,
+		Source:      a.Fonte,
+	}
+	if entry.Code == "" {
+		entry.Code = query.Code
+	}
+	if entry.Ex == "" {
+		entry.Ex = query.Ex
+	}
+
+	rates := []struct {
+		name   string
+		value  jsonScalar
+		target *decimal.Decimal
+	}{
+		{"Nacional", a.Nacional, &entry.National},
+		{"Importado", a.Importado, &entry.Imported},
+		{"Estadual", a.Estadual, &entry.State},
+		{"Municipal", a.Municipal, &entry.Municipal},
+	}
+	for _, rate := range rates {
+		text := strings.Replace(string(rate.value), ",", ".", 1)
+		if text == "" {
+			text = "0"
+		}
+		value, err := decimal.Parse(text)
+		if err != nil || value.Sign() < 0 {
+			return Entry{}, errors.NewValidationError("invalid IBPT rate", rate.name, text)
+		}
+		*rate.target = value
+	}
+
+	var err error
+	if entry.ValidFrom, err = time.ParseInLocation(dateLayout, a.VigenciaInicio, time.Local); err != nil {
+		return Entry{}, errors.NewValidationError("invalid IBPT validity", "VigenciaInicio", a.VigenciaInicio)
+	}
+	if entry.ValidUntil, err = time.ParseInLocation(dateLayout, a.VigenciaFim, time.Local); err != nil {
+		return Entry{}, errors.NewValidationError("invalid IBPT validity", "VigenciaFim", a.VigenciaFim)
+	}
+	return entry, nil
+}
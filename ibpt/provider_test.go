@@ -0,0 +1,65 @@
+package ibpt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/common"
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+)
+
+func TestNewHTTPProvider(t *testing.T) {
+	if _, err := NewHTTPProvider(&common.Config{CNPJ: "11222333000181"}); err == nil {
+		t.Error("expected error without TokenIBPT")
+	}
+
+	token := " abc123 "
+	provider, err := NewHTTPProvider(&common.Config{CNPJ: "11.222.333/0001-81", TokenIBPT: &token})
+	if err != nil {
+		t.Fatalf("NewHTTPProvider should not return error, got: %v", err)
+	}
+	if provider.Token != "abc123" || provider.CNPJ != "11222333000181" || provider.BaseURL != DefaultBaseURL {
+		t.Errorf("unexpected provider %+v", provider)
+	}
+}
+
+func TestHTTPProviderLookup(t *testing.T) {
+	var path string
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.Query()
+		if r.URL.Query().Get("codigo") == "00000000" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"Codigo":"22030000","UF":"SP","EX":0,"Descricao":"Cervejas de malte","Nacional":13.45,
+			"Estadual":"25","Importado":15.45,"Municipal":0,"Tipo":"0","VigenciaInicio":"01/07/2025",
+			"VigenciaFim":"31/12/2025","Chave":"6E0B4B","Versao":"25.2.A","Fonte":"IBPT/empresometro.com.br"}`))
+	}))
+	defer server.Close()
+
+	provider := &HTTPProvider{BaseURL: server.URL, Token: "abc123", CNPJ: "11222333000181", HTTPClient: server.Client()}
+	entry, err := provider.Lookup(context.Background(), Query{
+		UF: "SP", Code: "22030000", Description: "Cerveja", Unit: "UN", Value: decimal.Money(59.9), GTIN: "7891234567895",
+	})
+	if err != nil {
+		t.Fatalf("Lookup should not return error, got: %v", err)
+	}
+
+	if path != "/produtos" || query["token"][0] != "abc123" || query["cnpj"][0] != "11222333000181" ||
+		query["ex"][0] != "0" || query["valor"][0] != "59.90" || query["gtin"][0] != "7891234567895" {
+		t.Errorf("unexpected request %s %v", path, query)
+	}
+	if entry.National != decimal.MustParse("13.45") || entry.State != decimal.MustParse("25") || entry.Key != "6E0B4B" || entry.Ex != "0" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	if _, err := provider.Lookup(context.Background(), Query{UF: "SP", Code: "0107", Kind: KindLC116}); err != nil || path != "/servicos" {
+		t.Errorf("services should use the servicos endpoint, got %s (%v)", path, err)
+	}
+	if _, err := provider.Lookup(context.Background(), Query{UF: "SP", Code: "00000000"}); err == nil {
+		t.Error("expected error for HTTP 404")
+	}
+}
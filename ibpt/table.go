@@ -0,0 +1,215 @@
+package ibpt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// csvColumns are the columns of the IBPT tables used by the loader
+var csvColumns = []string{
+	"codigo", "ex", "tipo", "descricao", "nacionalfederal", "importadosfederal",
+	"estadual", "municipal", "vigenciainicio", "vigenciafim", "chave", "versao", "fonte",
+}
+
+// dateLayout is the format of the validity columns
+const dateLayout = "02/01/2006"
+
+// fileNamePattern matches the names of the published tables, e.g.
+// TabelaIBPTaxSP25.1.A.csv, capturing the UF
+var fileNamePattern = regexp.MustCompile(`(?i)^TabelaIBPTax([A-Z]{2})`)
+
+// entryKey identifies an entry of the table
+type entryKey struct {
+	uf, code, ex string
+}
+
+// Table holds the IBPT entries of one or more UFs. It is safe for concurrent
+// use, so entries refreshed by a Provider can be added while it is read.
+type Table struct {
+	mu      sync.RWMutex
+	entries map[entryKey]Entry
+}
+
+// NewTable creates an empty table
+func NewTable() *Table {
+	return &Table{entries: make(map[entryKey]Entry)}
+}
+
+// Add stores an entry for the UF, replacing any entry with the same code and ex
+func (t *Table) Add(uf string, entry Entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries[newEntryKey(uf, entry.Code, entry.Ex)] = entry
+}
+
+// Lookup returns the entry of a NCM, NBS or LC 116 code in the UF
+func (t *Table) Lookup(uf, code, ex string) (Entry, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	entry, ok := t.entries[newEntryKey(uf, code, ex)]
+	return entry, ok
+}
+
+// Len returns the number of entries in the table
+func (t *Table) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.entries)
+}
+
+// LoadFile loads a table published by the IBPT, taking the UF from the file
+// name (TabelaIBPTaxSP25.1.A.csv)
+func (t *Table) LoadFile(path string) (int, error) {
+	match := fileNamePattern.FindStringSubmatch(filepath.Base(path))
+	if match == nil {
+		return 0, errors.NewValidationError("IBPT file name must start with TabelaIBPTax and the UF", "path", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, errors.NewConfigError("failed to open IBPT table", "path", path)
+	}
+	defer file.Close()
+
+	return t.LoadCSV(file, match[1])
+}
+
+// LoadCSV loads the entries of a UF from a table in the IBPT CSV layout
+// (separated by semicolons, with a header). Tables are published in
+// ISO-8859-1, which is converted to UTF-8. It returns the number of entries loaded.
+func (t *Table) LoadCSV(r io.Reader, uf string) (int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, errors.NewConfigError("failed to read IBPT table", "uf", uf)
+	}
+	if !utf8.Valid(data) {
+		if data, err = charmap.ISO8859_1.NewDecoder().Bytes(data); err != nil {
+			return 0, errors.NewValidationError("IBPT table with invalid encoding", "uf", uf)
+		}
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return 0, errors.NewValidationError("IBPT table without header", "uf", uf)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok {
+			return 0, errors.NewValidationError("IBPT table without column "+name, "uf", uf)
+		}
+	}
+
+	var entries []Entry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, errors.NewValidationError("invalid IBPT table", "line", line)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		entry, err := parseRecord(record, columns)
+		if err != nil {
+			return 0, errors.WrapError(err, errors.ErrValidation, fmt.Sprintf("invalid IBPT entry at line %d", line))
+		}
+		entries = append(entries, entry)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, entry := range entries {
+		t.entries[newEntryKey(uf, entry.Code, entry.Ex)] = entry
+	}
+	return len(entries), nil
+}
+
+// parseRecord converts a CSV record into an entry
+func parseRecord(record []string, columns map[string]int) (Entry, error) {
+	field := func(name string) string {
+		if i := columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	entry := Entry{
+		Code:        field("codigo"),
+		Ex:          field("ex"),
+		Description: field("descricao"),
+		Key:         field("chave"),
+		Version:     field("versao"),
+		Source:      field("fonte"),
+	}
+	if entry.Code == "" {
+		return Entry{}, errors.NewValidationError("IBPT entry without code", "codigo", "")
+	}
+
+	switch field("tipo") {
+	case "0":
+		entry.Kind = KindNCM
+	case "1":
+		entry.Kind = KindNBS
+	case "2":
+		entry.Kind = KindLC116
+	default:
+		return Entry{}, errors.NewValidationError("invalid IBPT entry type", "tipo", field("tipo"))
+	}
+
+	rates := []struct {
+		name   string
+		target *decimal.Decimal
+	}{
+		{"nacionalfederal", &entry.National},
+		{"importadosfederal", &entry.Imported},
+		{"estadual", &entry.State},
+		{"municipal", &entry.Municipal},
+	}
+	for _, rate := range rates {
+		value, err := decimal.Parse(strings.Replace(field(rate.name), ",", ".", 1))
+		if err != nil || value.Sign() < 0 {
+			return Entry{}, errors.NewValidationError("invalid IBPT rate", rate.name, field(rate.name))
+		}
+		*rate.target = value
+	}
+
+	var err error
+	if entry.ValidFrom, err = time.ParseInLocation(dateLayout, field("vigenciainicio"), time.Local); err != nil {
+		return Entry{}, errors.NewValidationError("invalid IBPT validity", "vigenciainicio", field("vigenciainicio"))
+	}
+	if entry.ValidUntil, err = time.ParseInLocation(dateLayout, field("vigenciafim"), time.Local); err != nil {
+		return Entry{}, errors.NewValidationError("invalid IBPT validity", "vigenciafim", field("vigenciafim"))
+	}
+	return entry, nil
+}
+
+// newEntryKey normalizes the parts of an entry key
+func newEntryKey(uf, code, ex string) entryKey {
+	ex = strings.TrimLeft(strings.TrimSpace(ex), "0")
+	return entryKey{uf: strings.ToUpper(strings.TrimSpace(uf)), code: strings.TrimSpace(code), ex: ex}
+}
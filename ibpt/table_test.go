@@ -0,0 +1,100 @@
+package ibpt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+)
+
+const testCSV = `codigo;ex;tipo;descricao;nacionalfederal;importadosfederal;estadual;municipal;vigenciainicio;vigenciafim;chave;versao;fonte
+22030000;;0;Cervejas de malte;13.45;15.45;25.00;0.00;01/01/2025;30/06/2025;5D9A3A;25.1.A;IBPT/empresometro.com.br
+84713012;01;0;Maquinas portateis;9.25;13.25;18.00;0.00;01/01/2025;30/06/2025;5D9A3A;25.1.A;IBPT/empresometro.com.br
+0107;;2;Suporte tecnico em informatica;13.45;0.00;0.00;2.00;01/01/2025;30/06/2025;5D9A3A;25.1.A;IBPT/empresometro.com.br
+`
+
+func TestLoadCSV(t *testing.T) {
+	table := NewTable()
+	count, err := table.LoadCSV(strings.NewReader(testCSV), "sp")
+	if err != nil {
+		t.Fatalf("LoadCSV should not return error, got: %v", err)
+	}
+	if count != 3 || table.Len() != 3 {
+		t.Errorf("LoadCSV() loaded %d entries, table has %d, expected 3", count, table.Len())
+	}
+
+	entry, ok := table.Lookup("SP", "22030000", "")
+	if !ok {
+		t.Fatal("entry 22030000 should be found")
+	}
+	if entry.National != decimal.MustParse("13.45") || entry.Imported != decimal.MustParse("15.45") ||
+		entry.State != decimal.MustParse("25.00") || entry.Municipal != decimal.MustParse("0.00") {
+		t.Errorf("unexpected rates %+v", entry)
+	}
+	if entry.Kind != KindNCM || entry.Key != "5D9A3A" || entry.Version != "25.1.A" || entry.Source != "IBPT/empresometro.com.br" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if !entry.ValidAt(time.Date(2025, 6, 30, 23, 0, 0, 0, time.Local)) || entry.ValidAt(time.Date(2025, 7, 1, 0, 0, 0, 0, time.Local)) {
+		t.Error("entry should be valid until the end of 30/06/2025")
+	}
+
+	if _, ok := table.Lookup("SP", "84713012", "1"); !ok {
+		t.Error("ex should be compared without leading zeros")
+	}
+	if entry, ok := table.Lookup("SP", "0107", ""); !ok || entry.Kind != KindLC116 {
+		t.Errorf("LC 116 entry not loaded: %+v", entry)
+	}
+	if _, ok := table.Lookup("RJ", "22030000", ""); ok {
+		t.Error("entries of other UFs should not be found")
+	}
+}
+
+func TestLoadCSVLatin1(t *testing.T) {
+	data := strings.Replace(testCSV, "Suporte tecnico", "Suporte t\xe9cnico", 1)
+	table := NewTable()
+	if _, err := table.LoadCSV(strings.NewReader(data), "SP"); err != nil {
+		t.Fatalf("LoadCSV should not return error, got: %v", err)
+	}
+	if entry, _ := table.Lookup("SP", "0107", ""); entry.Description != "Suporte técnico em informatica" {
+		t.Errorf("description = %q, expected the ISO-8859-1 text converted", entry.Description)
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	header := strings.SplitN(testCSV, "\n", 2)[0] + "\n"
+	tests := map[string]string{
+		"missing column": "codigo;ex;tipo\n22030000;;0\n",
+		"invalid rate":   header + "22030000;;0;Cerveja;abc;0;0;0;01/01/2025;30/06/2025;A;1;IBPT\n",
+		"invalid type":   header + "22030000;;9;Cerveja;1;0;0;0;01/01/2025;30/06/2025;A;1;IBPT\n",
+		"invalid date":   header + "22030000;;0;Cerveja;1;0;0;0;2025-01-01;30/06/2025;A;1;IBPT\n",
+		"empty":          "",
+	}
+	for name, data := range tests {
+		if _, err := NewTable().LoadCSV(strings.NewReader(data), "SP"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "TabelaIBPTaxMG25.1.A.csv")
+	if err := os.WriteFile(path, []byte(testCSV), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	table := NewTable()
+	if _, err := table.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile should not return error, got: %v", err)
+	}
+	if _, ok := table.Lookup("MG", "22030000", ""); !ok {
+		t.Error("the UF should be taken from the file name")
+	}
+
+	if _, err := table.LoadFile(filepath.Join(dir, "tabela.csv")); err == nil {
+		t.Error("expected error for a file name without the UF")
+	}
+}
@@ -0,0 +1,328 @@
+package nfe
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/soap"
+	"github.com/adrianodrix/sped-nfe-go/types"
+	"github.com/adrianodrix/sped-nfe-go/webservices"
+)
+
+// Receipt polling defaults for asynchronous submissions
+const (
+	defaultReceiptInterval = 2 * time.Second
+	maxReceiptAttempts     = 5
+)
+
+// xmlDeclaration matches a leading XML declaration
+var xmlDeclaration = regexp.MustCompile(`^\s*<\?xml[^>]*\?>\s*`)
+
+// AuthorizationResponse is the outcome of an NFe authorization request
+type AuthorizationResponse struct {
+	ChNFe    string
+	CStat    int
+	XMotivo  string
+	NProt    string
+	Protocol *ProtNFe
+	// NFeProc is the nfeProc document (NFe plus protocol) to store and distribute.
+	// It is set for authorized and denied NFe.
+	NFeProc []byte
+	// Recovered is true when a previous submission had already been authorized
+	// and its protocol was recovered after a duplicate rejection (cStat 204/539)
+	// or a lost response
+	Recovered bool
+}
+
+// IsAuthorized returns true if the NFe was authorized (cStat 100 or 150)
+func (r *AuthorizationResponse) IsAuthorized() bool {
+	return r.CStat == 100 || r.CStat == 150
+}
+
+// signedNFe holds the fields of a signed NFe needed by the authorization flow
+type signedNFe struct {
+	XMLName xml.Name `xml:"NFe"`
+	InfNFe  struct {
+		ID  string `xml:"Id,attr"`
+		Ide struct {
+			Mod   types.ModeloNFe `xml:"mod"`
+			TpAmb int             `xml:"tpAmb"`
+		} `xml:"ide"`
+	} `xml:"infNFe"`
+	Signature struct {
+		SignedInfo struct {
+			Reference struct {
+				DigestValue string `xml:"DigestValue"`
+			} `xml:"Reference"`
+		} `xml:"SignedInfo"`
+	} `xml:"Signature"`
+}
+
+// submission is a signed NFe ready to be sent
+type submission struct {
+	xml    []byte
+	chave  string
+	modelo types.ModeloNFe
+	digest string
+}
+
+// parseSubmission extracts the access key, model and digest of a signed NFe
+func parseSubmission(signedXML []byte) (*submission, error) {
+	if len(bytes.TrimSpace(signedXML)) == 0 {
+		return nil, errors.NewValidationError("NFe XML cannot be empty", "xml", "")
+	}
+
+	var doc signedNFe
+	if err := xml.Unmarshal(signedXML, &doc); err != nil {
+		return nil, errors.NewXMLError("failed to parse signed NFe", "NFe", err)
+	}
+
+	chave := strings.TrimPrefix(doc.InfNFe.ID, "NFe")
+	if len(chave) != 44 {
+		return nil, errors.NewValidationError("infNFe Id does not hold a 44 character access key", "Id", doc.InfNFe.ID)
+	}
+
+	digest := strings.TrimSpace(doc.Signature.SignedInfo.Reference.DigestValue)
+	if digest == "" {
+		return nil, errors.NewValidationError("NFe must be signed before authorization", "DigestValue", "")
+	}
+
+	modelo := doc.InfNFe.Ide.Mod
+	if modelo == 0 {
+		modelo = modeloFromChave(chave)
+	}
+
+	return &submission{
+		xml:    xmlDeclaration.ReplaceAll(bytes.TrimSpace(signedXML), nil),
+		chave:  chave,
+		modelo: modelo,
+		digest: digest,
+	}, nil
+}
+
+// Authorize sends a signed NFe to the authorizer webservice (synchronous lote
+// with a single NFe) and returns the protocol and the nfeProc document.
+//
+// Duplicate rejections (cStat 204 and 539) are resolved by querying the
+// protocol of the NFe SEFAZ already holds: when its digest matches the
+// submitted NFe the original authorization is returned with Recovered set,
+// otherwise a *DuplicateError describes the conflict.
+//
+// When the response is lost after the lote may have been received
+// (*soap.UncertainDeliveryError), the protocol is queried instead of resending.
+//
+// Rejections and denials are returned as errors wrapping *errors.SEFAZError;
+// for denials the response is returned as well, carrying the nfeProc that must be kept.
+func (c *Client) Authorize(ctx context.Context, signedXML []byte) (*AuthorizationResponse, error) {
+	sub, err := parseSubmission(signedXML)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := c.service(sub.modelo, webservices.ServiceAutorizacao)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf(`<enviNFe xmlns="%s" versao="4.00"><idLote>%s</idLote><indSinc>1</indSinc>%s</enviNFe>`,
+		NFeNamespace, newLoteID(), sub.xml)
+
+	response, err := c.requester.Send(ctx, service, message)
+	if err != nil {
+		if soap.IsUncertainDelivery(err) {
+			return c.recoverUncertain(ctx, sub, err)
+		}
+		return nil, err
+	}
+
+	var ret RetEnviNFe
+	if err := decodeResponse(response, "retEnviNFe", &ret); err != nil {
+		return nil, err
+	}
+	attachRaw(response, ret.ProtNFe)
+
+	switch ret.CStat {
+	case 104:
+		if ret.ProtNFe == nil {
+			return nil, errors.NewSEFAZStatusError(ret.CStat, "lote processed without protocol")
+		}
+		return c.handleProtocol(ctx, sub, ret.ProtNFe)
+	case 103:
+		if ret.InfRec == nil || ret.InfRec.NRec == "" {
+			return nil, errors.NewSEFAZStatusError(ret.CStat, "lote received without receipt number")
+		}
+		protocol, err := c.waitReceipt(ctx, sub, ret.InfRec)
+		if err != nil {
+			return nil, err
+		}
+		return c.handleProtocol(ctx, sub, protocol)
+	case 204, 539:
+		return c.recoverDuplicate(ctx, sub, ret.CStat, ret.XMotivo)
+	default:
+		return nil, errors.NewSEFAZStatusError(ret.CStat, ret.XMotivo)
+	}
+}
+
+// recoverUncertain resolves a submission whose response was lost by querying
+// the protocol of the NFe instead of sending it again. When SEFAZ holds no
+// protocol with the submitted digest, the original error is returned and the
+// NFe can be sent again.
+func (c *Client) recoverUncertain(ctx context.Context, sub *submission, sendErr error) (*AuthorizationResponse, error) {
+	ret, err := c.Query(ctx, sub.chave)
+	if err != nil || ret.ProtNFe == nil {
+		return nil, sendErr
+	}
+	if !strings.EqualFold(strings.TrimSpace(ret.ProtNFe.InfProt.DigVal), sub.digest) {
+		return nil, sendErr
+	}
+	if cancelledSituation(ret.CStat) {
+		return nil, errors.NewSEFAZStatusError(ret.CStat, ret.XMotivo)
+	}
+
+	response, err := c.handleProtocol(ctx, sub, ret.ProtNFe)
+	if response != nil {
+		response.Recovered = true
+	}
+	return response, err
+}
+
+// handleProtocol turns the protocol of the submitted NFe into a response
+func (c *Client) handleProtocol(ctx context.Context, sub *submission, protocol *ProtNFe) (*AuthorizationResponse, error) {
+	info := protocol.InfProt
+	response := &AuthorizationResponse{
+		ChNFe:    sub.chave,
+		CStat:    info.CStat,
+		XMotivo:  info.XMotivo,
+		NProt:    info.NProt,
+		Protocol: protocol,
+	}
+
+	// only duplicates of the NFe itself can be recovered; the other codes of
+	// the category (206, 218, 420, ...) are plain rejections
+	switch info.CStat {
+	case 204, 539:
+		return c.recoverDuplicate(ctx, sub, info.CStat, info.XMotivo)
+	}
+
+	switch errors.CStatDetails(info.CStat).Category {
+	case errors.CategoryAuthorized:
+		response.NFeProc = buildNFeProc(sub.xml, protocol)
+		return response, nil
+	case errors.CategoryDenied:
+		response.NFeProc = buildNFeProc(sub.xml, protocol)
+		return response, errors.NewSEFAZStatusError(info.CStat, info.XMotivo)
+	default:
+		return nil, errors.NewSEFAZStatusError(info.CStat, info.XMotivo)
+	}
+}
+
+// waitReceipt polls NfeRetAutorizacao until the lote is processed
+func (c *Client) waitReceipt(ctx context.Context, sub *submission, rec *InfRec) (*ProtNFe, error) {
+	interval := time.Duration(rec.TMed) * time.Second
+	if interval < c.receiptInterval {
+		interval = c.receiptInterval
+	}
+
+	for attempt := 1; attempt <= maxReceiptAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		ret, err := c.QueryReceipt(ctx, sub.modelo, rec.NRec)
+		if err != nil {
+			return nil, err
+		}
+
+		switch ret.CStat {
+		case 105:
+			continue
+		case 104:
+			for _, protocol := range ret.ProtNFe {
+				if protocol.InfProt.ChNFe == sub.chave {
+					return protocol, nil
+				}
+			}
+			return nil, errors.NewSEFAZStatusError(ret.CStat, fmt.Sprintf("receipt does not contain the submitted NFe %s", sub.chave))
+		default:
+			return nil, errors.NewSEFAZStatusError(ret.CStat, ret.XMotivo)
+		}
+	}
+
+	return nil, errors.NewSEFAZStatusError(105,
+		fmt.Sprintf("lote %s still in processing after %d receipt queries", rec.NRec, maxReceiptAttempts))
+}
+
+// QueryReceipt queries the processing result of an asynchronous lote
+func (c *Client) QueryReceipt(ctx context.Context, modelo types.ModeloNFe, nRec string) (*RetConsReciNFe, error) {
+	if nRec == "" {
+		return nil, errors.NewValidationError("receipt number cannot be empty", "nRec", nRec)
+	}
+
+	service, err := c.service(modelo, webservices.ServiceRetAutorizacao)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf(`<consReciNFe xmlns="%s" versao="4.00"><tpAmb>%d</tpAmb><nRec>%s</nRec></consReciNFe>`,
+		NFeNamespace, int(c.config.Environment), nRec)
+
+	response, err := c.requester.Send(ctx, service, message)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret RetConsReciNFe
+	if err := decodeResponse(response, "retConsReciNFe", &ret); err != nil {
+		return nil, err
+	}
+	attachRaw(response, ret.ProtNFe...)
+	return &ret, nil
+}
+
+// service resolves a webservice of the client's UF and environment
+func (c *Client) service(modelo types.ModeloNFe, serviceType webservices.ServiceType) (*webservices.Service, error) {
+	if c.requester == nil {
+		return nil, errors.NewConfigError("no requester configured", "requester", nil)
+	}
+	return webservices.GetWebserviceURL(types.UF(c.config.UF), types.Ambiente(c.config.Environment), modelo, serviceType)
+}
+
+// buildNFeProc wraps the NFe and its protocol in an nfeProc document
+func buildNFeProc(nfeXML []byte, protocol *ProtNFe) []byte {
+	raw := protocol.Raw
+	if len(raw) == 0 {
+		raw, _ = xml.Marshal(protocol)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(&buf, `<nfeProc xmlns="%s" versao="4.00">`, NFeNamespace)
+	buf.Write(xmlDeclaration.ReplaceAll(nfeXML, nil))
+	buf.Write(raw)
+	buf.WriteString(`</nfeProc>`)
+	return buf.Bytes()
+}
+
+// newLoteID returns a 15 digit lote identifier derived from the current time
+func newLoteID() string {
+	return fmt.Sprintf("%015d", time.Now().UnixNano()/int64(time.Microsecond)%1e15)
+}
+
+// modeloFromChave extracts the document model (positions 21-22) from an access key
+func modeloFromChave(chave string) types.ModeloNFe {
+	if len(chave) != 44 {
+		return types.ModeloNFe55
+	}
+	if chave[20:22] == "65" {
+		return types.ModeloNFCe65
+	}
+	return types.ModeloNFe55
+}
@@ -0,0 +1,466 @@
+package nfe
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/soap"
+	"github.com/adrianodrix/sped-nfe-go/webservices"
+)
+
+const (
+	testChave  = "33240511222333000181550010000012341123456784"
+	testDigest = "q1Wn3hZ8mN0Ptf6VXbKQ1+3cR0s="
+)
+
+// fakeRequester answers SEFAZ messages with canned responses keyed by webservice method
+type fakeRequester struct {
+	responses map[string][]string
+	requests  map[string][]string
+}
+
+func newFakeRequester() *fakeRequester {
+	return &fakeRequester{
+		responses: make(map[string][]string),
+		requests:  make(map[string][]string),
+	}
+}
+
+func (f *fakeRequester) on(method string, responses ...string) {
+	f.responses[method] = append(f.responses[method], responses...)
+}
+
+func (f *fakeRequester) Send(ctx context.Context, service *webservices.Service, message string) (string, error) {
+	f.requests[service.Method] = append(f.requests[service.Method], message)
+	queue := f.responses[service.Method]
+	if len(queue) == 0 {
+		return "", fmt.Errorf("unexpected request to %s", service.Method)
+	}
+	f.responses[service.Method] = queue[1:]
+	return queue[0], nil
+}
+
+func protNFeXML(chave string, cStat int, xMotivo, nProt, digVal string) string {
+	return fmt.Sprintf(`<protNFe versao="4.00"><infProt><tpAmb>2</tpAmb><verAplic>SVRS202405</verAplic>`+
+		`<chNFe>%s</chNFe><dhRecbto>2024-05-10T10:00:05-03:00</dhRecbto><nProt>%s</nProt><digVal>%s</digVal>`+
+		`<cStat>%d</cStat><xMotivo>%s</xMotivo></infProt></protNFe>`, chave, nProt, digVal, cStat, xMotivo)
+}
+
+func retEnviNFeXML(cStat int, xMotivo, inner string) string {
+	return fmt.Sprintf(`<nfeResultMsg xmlns="http://www.portalfiscal.inf.br/nfe/wsdl/NFeAutorizacao4">`+
+		`<retEnviNFe xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><verAplic>SVRS202405</verAplic>`+
+		`<cStat>%d</cStat><xMotivo>%s</xMotivo><cUF>33</cUF><dhRecbto>2024-05-10T10:00:05-03:00</dhRecbto>%s</retEnviNFe></nfeResultMsg>`,
+		cStat, xMotivo, inner)
+}
+
+func retConsSitNFeXML(chave, protocol string) string {
+	return fmt.Sprintf(`<nfeResultMsg xmlns="http://www.portalfiscal.inf.br/nfe/wsdl/NFeConsultaProtocolo4">`+
+		`<retConsSitNFe xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><verAplic>SVRS202405</verAplic>`+
+		`<cStat>100</cStat><xMotivo>Autorizado o uso da NF-e</xMotivo><cUF>33</cUF><dhRecbto>2024-05-10T10:05:00-03:00</dhRecbto>`+
+		`<chNFe>%s</chNFe>%s</retConsSitNFe></nfeResultMsg>`, chave, protocol)
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeRequester) {
+	t.Helper()
+	client, err := New(Config{Environment: Homologation, UF: RJ, Timeout: 30})
+	if err != nil {
+		t.Fatalf("New should not return error, got: %v", err)
+	}
+	requester := newFakeRequester()
+	client.SetRequester(requester)
+	client.receiptInterval = time.Millisecond
+	return client, requester
+}
+
+func readSignedNFe(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/nfe_signed.xml")
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+	return data
+}
+
+func TestAuthorizeSynchronous(t *testing.T) {
+	client, requester := newTestClient(t)
+	protocol := protNFeXML(testChave, 100, "Autorizado o uso da NF-e", "333240000012345", testDigest)
+	requester.on("nfeAutorizacaoLote", retEnviNFeXML(104, "Lote processado", protocol))
+
+	response, err := client.Authorize(context.Background(), readSignedNFe(t))
+	if err != nil {
+		t.Fatalf("Authorize should not return error, got: %v", err)
+	}
+
+	if !response.IsAuthorized() || response.NProt != "333240000012345" || response.Recovered {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	sent := requester.requests["nfeAutorizacaoLote"][0]
+	if strings.Contains(sent, "<?xml") || !strings.Contains(sent, "<indSinc>1</indSinc>") {
+		t.Errorf("Unexpected enviNFe message: %s", sent)
+	}
+
+	proc := string(response.NFeProc)
+	if !strings.Contains(proc, `<nfeProc xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><NFe`) ||
+		!strings.HasSuffix(proc, protocol+"</nfeProc>") {
+		t.Errorf("Unexpected nfeProc: %s", proc)
+	}
+}
+
+func TestAuthorizeAsynchronousReceipt(t *testing.T) {
+	client, requester := newTestClient(t)
+	requester.on("nfeAutorizacaoLote", retEnviNFeXML(103, "Lote recebido com sucesso",
+		`<infRec><nRec>331000012345678</nRec><tMed>0</tMed></infRec>`))
+	requester.on("nfeRetAutorizacaoLote",
+		`<retConsReciNFe xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><nRec>331000012345678</nRec><cStat>105</cStat><xMotivo>Lote em processamento</xMotivo></retConsReciNFe>`,
+		`<retConsReciNFe xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><nRec>331000012345678</nRec><cStat>104</cStat><xMotivo>Lote processado</xMotivo>`+
+			protNFeXML(testChave, 100, "Autorizado o uso da NF-e", "333240000012345", testDigest)+`</retConsReciNFe>`)
+
+	response, err := client.Authorize(context.Background(), readSignedNFe(t))
+	if err != nil {
+		t.Fatalf("Authorize should not return error, got: %v", err)
+	}
+
+	if !response.IsAuthorized() {
+		t.Errorf("Expected authorized response, got: %+v", response)
+	}
+	if len(requester.requests["nfeRetAutorizacaoLote"]) != 2 {
+		t.Errorf("Expected 2 receipt queries, got %d", len(requester.requests["nfeRetAutorizacaoLote"]))
+	}
+}
+
+func TestAuthorizeIncompleteResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		lote    string
+		receipt string
+		cStat   int
+	}{
+		{
+			name:  "lote processed without protocol",
+			lote:  retEnviNFeXML(104, "Lote processado", ""),
+			cStat: 104,
+		},
+		{
+			name:  "lote received without receipt",
+			lote:  retEnviNFeXML(103, "Lote recebido com sucesso", ""),
+			cStat: 103,
+		},
+		{
+			name: "receipt without the submitted NFe",
+			lote: retEnviNFeXML(103, "Lote recebido com sucesso", `<infRec><nRec>331000012345678</nRec><tMed>0</tMed></infRec>`),
+			receipt: `<retConsReciNFe xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><nRec>331000012345678</nRec><cStat>104</cStat><xMotivo>Lote processado</xMotivo>` +
+				protNFeXML("33240511222333000181550010000012341876543218", 100, "Autorizado o uso da NF-e", "333240000012345", testDigest) + `</retConsReciNFe>`,
+			cStat: 104,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requester := newTestClient(t)
+			requester.on("nfeAutorizacaoLote", tt.lote)
+			if tt.receipt != "" {
+				requester.on("nfeRetAutorizacaoLote", tt.receipt)
+			}
+
+			_, err := client.Authorize(context.Background(), readSignedNFe(t))
+			sefazErr, ok := errors.AsSEFAZError(err)
+			if !ok || sefazErr.CStat != tt.cStat || sefazErr.Retryable() {
+				t.Errorf("Expected SEFAZError with cStat %d, got: %v", tt.cStat, err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeRejection(t *testing.T) {
+	client, requester := newTestClient(t)
+	requester.on("nfeAutorizacaoLote", retEnviNFeXML(104, "Lote processado",
+		protNFeXML(testChave, 225, "Rejeição: Falha no Schema XML da NFe", "", "")))
+
+	response, err := client.Authorize(context.Background(), readSignedNFe(t))
+	if response != nil {
+		t.Errorf("Expected nil response, got: %+v", response)
+	}
+
+	sefazErr, ok := errors.AsSEFAZError(err)
+	if !ok || sefazErr.CStat != 225 || sefazErr.Category != errors.CategoryRejection {
+		t.Errorf("Expected cStat 225 rejection, got: %v", err)
+	}
+}
+
+func TestAuthorizeDenied(t *testing.T) {
+	client, requester := newTestClient(t)
+	requester.on("nfeAutorizacaoLote", retEnviNFeXML(104, "Lote processado",
+		protNFeXML(testChave, 301, "Uso Denegado: Irregularidade fiscal do emitente", "333240000012346", testDigest)))
+
+	response, err := client.Authorize(context.Background(), readSignedNFe(t))
+	if !stderrors.Is(err, errors.ErrSEFAZDenied) {
+		t.Errorf("Expected denied error, got: %v", err)
+	}
+	if response == nil || len(response.NFeProc) == 0 {
+		t.Errorf("Denied NFe should return the nfeProc to keep")
+	}
+}
+
+func TestAuthorizeDuplicate(t *testing.T) {
+	const (
+		otherChave        = "33240511222333000181550010000012341876543218"
+		alphanumericChave = "332405AB2CD333EF0181550010000012341876543210"
+	)
+
+	tests := []struct {
+		name          string
+		cStat         int
+		xMotivo       string
+		protocol      string
+		queryChave    string
+		wantRecovered bool
+		wantDigest    string
+	}{
+		{
+			name:          "204 with matching digest",
+			cStat:         204,
+			xMotivo:       "Rejeição: Duplicidade de NF-e [nProt:333240000012345][dhAut:2024-05-10T10:00:05-03:00]",
+			protocol:      protNFeXML(testChave, 100, "Autorizado o uso da NF-e", "333240000012345", testDigest),
+			queryChave:    testChave,
+			wantRecovered: true,
+		},
+		{
+			name:       "204 with different digest",
+			cStat:      204,
+			xMotivo:    "Rejeição: Duplicidade de NF-e [nProt:333240000012345]",
+			protocol:   protNFeXML(testChave, 100, "Autorizado o uso da NF-e", "333240000012345", "ZmZmZmZmZmZmZmZmZmZmZmZmZmY="),
+			queryChave: testChave,
+			wantDigest: "ZmZmZmZmZmZmZmZmZmZmZmZmZmY=",
+		},
+		{
+			name:       "539 with another access key",
+			cStat:      539,
+			xMotivo:    "Rejeição: Duplicidade de NF-e, com diferença na Chave de Acesso [chNFe:" + otherChave + "][nRec:331000012345678]",
+			protocol:   protNFeXML(otherChave, 100, "Autorizado o uso da NF-e", "333240000099999", testDigest),
+			queryChave: otherChave,
+			wantDigest: testDigest,
+		},
+		{
+			name:       "539 with alphanumeric access key",
+			cStat:      539,
+			xMotivo:    "Rejeição: Duplicidade de NF-e, com diferença na Chave de Acesso [chNFe:" + alphanumericChave + "][nRec:331000012345678]",
+			protocol:   protNFeXML(alphanumericChave, 100, "Autorizado o uso da NF-e", "333240000099999", testDigest),
+			queryChave: alphanumericChave,
+			wantDigest: testDigest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requester := newTestClient(t)
+			requester.on("nfeAutorizacaoLote", retEnviNFeXML(tt.cStat, tt.xMotivo, ""))
+			requester.on("nfeConsultaNF", retConsSitNFeXML(tt.queryChave, tt.protocol))
+
+			response, err := client.Authorize(context.Background(), readSignedNFe(t))
+
+			queries := requester.requests["nfeConsultaNF"]
+			if len(queries) != 1 || !strings.Contains(queries[0], "<chNFe>"+tt.queryChave+"</chNFe>") {
+				t.Errorf("Expected a protocol query for %s, got: %v", tt.queryChave, queries)
+			}
+
+			if tt.wantRecovered {
+				if err != nil {
+					t.Fatalf("Authorize should recover the protocol, got: %v", err)
+				}
+				if !response.Recovered || !response.IsAuthorized() || !bytes.Contains(response.NFeProc, []byte(tt.protocol)) {
+					t.Errorf("Unexpected recovered response: %+v", response)
+				}
+				return
+			}
+
+			var dupErr *DuplicateError
+			if !stderrors.As(err, &dupErr) {
+				t.Fatalf("Expected DuplicateError, got: %v", err)
+			}
+			if dupErr.ChNFe != tt.queryChave || dupErr.AuthorizedDigest != tt.wantDigest {
+				t.Errorf("Unexpected duplicate error: %+v", dupErr)
+			}
+			if !stderrors.Is(err, errors.ErrSEFAZDuplicate) {
+				t.Errorf("DuplicateError should match ErrSEFAZDuplicate")
+			}
+		})
+	}
+}
+
+func TestAuthorizeDuplicateNotAuthorized(t *testing.T) {
+	const otherChave = "33240511222333000181550010000012341876543218"
+	cancelled := strings.Replace(
+		retConsSitNFeXML(testChave, protNFeXML(testChave, 100, "Autorizado o uso da NF-e", "333240000012345", testDigest)),
+		"<cStat>100</cStat><xMotivo>Autorizado o uso da NF-e</xMotivo><cUF>",
+		"<cStat>101</cStat><xMotivo>Cancelamento de NF-e homologado</xMotivo><cUF>", 1)
+
+	tests := []struct {
+		name       string
+		cStat      int
+		xMotivo    string
+		query      string
+		queryChave string
+	}{
+		{
+			name:       "204 of a cancelled NFe",
+			cStat:      204,
+			xMotivo:    "Rejeição: Duplicidade de NF-e [nProt:333240000012345]",
+			query:      cancelled,
+			queryChave: testChave,
+		},
+		{
+			name:       "539 with a denied access key",
+			cStat:      539,
+			xMotivo:    "Rejeição: Duplicidade de NF-e, com diferença na Chave de Acesso [chNFe:" + otherChave + "]",
+			query:      retConsSitNFeXML(otherChave, protNFeXML(otherChave, 110, "Uso Denegado", "333240000099999", testDigest)),
+			queryChave: otherChave,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requester := newTestClient(t)
+			requester.on("nfeAutorizacaoLote", retEnviNFeXML(104, "Lote processado", protNFeXML(testChave, tt.cStat, tt.xMotivo, "", "")))
+			requester.on("nfeConsultaNF", tt.query)
+
+			response, err := client.Authorize(context.Background(), readSignedNFe(t))
+			if response != nil {
+				t.Errorf("Expected nil response, got: %+v", response)
+			}
+
+			var dupErr *DuplicateError
+			if !stderrors.As(err, &dupErr) {
+				t.Fatalf("Expected DuplicateError, got: %v", err)
+			}
+			if dupErr.ChNFe != tt.queryChave || dupErr.AuthorizedDigest != "" {
+				t.Errorf("Duplicate of an NFe not authorized should have no authorized digest: %+v", dupErr)
+			}
+		})
+	}
+}
+
+func TestAuthorizeAlreadyCancelled(t *testing.T) {
+	client, requester := newTestClient(t)
+	requester.on("nfeAutorizacaoLote", retEnviNFeXML(104, "Lote processado",
+		protNFeXML(testChave, 218, "Rejeição: NF-e já está cancelada na base de dados da SEFAZ", "", "")))
+
+	response, err := client.Authorize(context.Background(), readSignedNFe(t))
+	if response != nil {
+		t.Errorf("Expected nil response, got: %+v", response)
+	}
+	if sefazErr, ok := errors.AsSEFAZError(err); !ok || sefazErr.CStat != 218 {
+		t.Errorf("Expected SEFAZ error 218, got: %v", err)
+	}
+	var dupErr *DuplicateError
+	if stderrors.As(err, &dupErr) {
+		t.Errorf("218 should not be handled as a duplicate: %v", err)
+	}
+	if queries := requester.requests["nfeConsultaNF"]; len(queries) != 0 {
+		t.Errorf("218 should not query the protocol, got %d queries", len(queries))
+	}
+}
+
+func TestAuthorizeDuplicateQueryFailure(t *testing.T) {
+	client, requester := newTestClient(t)
+	requester.on("nfeAutorizacaoLote", retEnviNFeXML(204, "Rejeição: Duplicidade de NF-e [nProt:333240000012345]", ""))
+
+	_, err := client.Authorize(context.Background(), readSignedNFe(t))
+
+	var dupErr *DuplicateError
+	if !stderrors.As(err, &dupErr) || dupErr.Cause == nil {
+		t.Fatalf("Expected DuplicateError with query cause, got: %v", err)
+	}
+	if dupErr.NProt != "333240000012345" {
+		t.Errorf("Expected nProt from xMotivo, got %s", dupErr.NProt)
+	}
+}
+
+// lostResponseRequester fails the authorization as if its response was lost
+type lostResponseRequester struct {
+	*fakeRequester
+}
+
+func (l lostResponseRequester) Send(ctx context.Context, service *webservices.Service, message string) (string, error) {
+	if service.Method == "nfeAutorizacaoLote" {
+		l.requests[service.Method] = append(l.requests[service.Method], message)
+		return "", &soap.UncertainDeliveryError{
+			Operation: soap.OperationAuthorization,
+			Err:       errors.NewNetworkError("HTTP request failed: connection reset by peer", nil),
+		}
+	}
+	return l.fakeRequester.Send(ctx, service, message)
+}
+
+func TestAuthorizeUncertainDelivery(t *testing.T) {
+	protocol := protNFeXML(testChave, 100, "Autorizado o uso da NF-e", "333240000012345", testDigest)
+
+	tests := []struct {
+		name          string
+		protocol      string
+		wantRecovered bool
+	}{
+		{"lote was processed", protocol, true},
+		{"lote was not received", "", false},
+		{"another submission was authorized", protNFeXML(testChave, 100, "Autorizado o uso da NF-e", "333240000012345", "ZmZmZmZmZmZmZmZmZmZmZmZmZmY="), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requester := newTestClient(t)
+			client.SetRequester(lostResponseRequester{requester})
+			requester.on("nfeConsultaNF", retConsSitNFeXML(testChave, tt.protocol))
+
+			response, err := client.Authorize(context.Background(), readSignedNFe(t))
+
+			if len(requester.requests["nfeAutorizacaoLote"]) != 1 {
+				t.Errorf("The lote should be sent once, got %d", len(requester.requests["nfeAutorizacaoLote"]))
+			}
+			if len(requester.requests["nfeConsultaNF"]) != 1 {
+				t.Errorf("Expected a protocol query, got %d", len(requester.requests["nfeConsultaNF"]))
+			}
+
+			if tt.wantRecovered {
+				if err != nil {
+					t.Fatalf("Authorize should recover the protocol, got: %v", err)
+				}
+				if !response.Recovered || !response.IsAuthorized() || response.NProt != "333240000012345" {
+					t.Errorf("Unexpected recovered response: %+v", response)
+				}
+				return
+			}
+			if !soap.IsUncertainDelivery(err) {
+				t.Errorf("Expected the uncertain delivery error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeInvalidXML(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	tests := []struct {
+		name string
+		xml  string
+	}{
+		{"empty", ""},
+		{"malformed", "<NFe><infNFe>"},
+		{"unsigned", `<NFe xmlns="http://www.portalfiscal.inf.br/nfe"><infNFe Id="NFe` + testChave + `"/></NFe>`},
+		{"invalid id", `<NFe xmlns="http://www.portalfiscal.inf.br/nfe"><infNFe Id="NFe123"/></NFe>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.Authorize(context.Background(), []byte(tt.xml)); err == nil {
+				t.Errorf("Expected error for %s XML", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package nfe
+
+import (
+	"encoding/xml"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+)
+
+// Item is an item of the NFe (grupo det)
+type Item struct {
+	XMLName      xml.Name      `xml:"det"`
+	NItem        int           `xml:"nItem,attr"`
+	Prod         Produto       `xml:"prod"`
+	Imposto      Imposto       `xml:"imposto"`
+	ImpostoDevol *ImpostoDevol `xml:"impostoDevol,omitempty"`
+	InfAdProd    string        `xml:"infAdProd,omitempty"`
+}
+
+// Produto holds the product or service data of an item (grupo prod)
+type Produto struct {
+	CProd    string          `xml:"cProd"`
+	CEAN     string          `xml:"cEAN"`
+	XProd    string          `xml:"xProd"`
+	NCM      string          `xml:"NCM"`
+	CEST     string          `xml:"CEST,omitempty"`
+	CBenef   string          `xml:"cBenef,omitempty"`
+	EXTIPI   string          `xml:"EXTIPI,omitempty"`
+	CFOP     string          `xml:"CFOP"`
+	UCom     string          `xml:"uCom"`
+	QCom     decimal.Decimal `xml:"qCom"`
+	VUnCom   decimal.Decimal `xml:"vUnCom"`
+	VProd    decimal.Decimal `xml:"vProd"`
+	CEANTrib string          `xml:"cEANTrib"`
+	UTrib    string          `xml:"uTrib"`
+	QTrib    decimal.Decimal `xml:"qTrib"`
+	VUnTrib  decimal.Decimal `xml:"vUnTrib"`
+	VFrete   decimal.Decimal `xml:"vFrete,omitempty"`
+	VSeg     decimal.Decimal `xml:"vSeg,omitempty"`
+	VDesc    decimal.Decimal `xml:"vDesc,omitempty"`
+	VOutro   decimal.Decimal `xml:"vOutro,omitempty"`
+	// IndTot is 1 when vProd composes the total of the NFe
+	IndTot   int    `xml:"indTot"`
+	XPed     string `xml:"xPed,omitempty"`
+	NItemPed string `xml:"nItemPed,omitempty"`
+}
+
+// ImpostoDevol holds the IPI returned in devolution NFe
+type ImpostoDevol struct {
+	PDevol decimal.Decimal `xml:"pDevol"`
+	IPI    IPIDevol        `xml:"IPI"`
+}
+
+// IPIDevol holds the value of the returned IPI
+type IPIDevol struct {
+	VIPIDevol decimal.Decimal `xml:"vIPIDevol"`
+}
@@ -0,0 +1,359 @@
+package nfe
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+// NFe is the electronic invoice document (layouts 3.10 and 4.00)
+type NFe struct {
+	XMLName    xml.Name    `xml:"NFe"`
+	InfNFe     InfNFe      `xml:"infNFe"`
+	InfNFeSupl *InfNFeSupl `xml:"infNFeSupl,omitempty"`
+	Signature  *Signature  `xml:"http://www.w3.org/2000/09/xmldsig# Signature,omitempty"`
+}
+
+// Chave returns the access key of the document, taken from the infNFe Id
+func (n *NFe) Chave() string {
+	return strings.TrimPrefix(n.InfNFe.ID, "NFe")
+}
+
+// Versao returns the layout version of the document
+func (n *NFe) Versao() types.VersaoLayout {
+	return types.VersaoLayout(n.InfNFe.Versao)
+}
+
+// InfNFe holds the data of the invoice
+type InfNFe struct {
+	Versao      string       `xml:"versao,attr"`
+	ID          string       `xml:"Id,attr"`
+	Ide         Ide          `xml:"ide"`
+	Emit        Emit         `xml:"emit"`
+	Dest        *Dest        `xml:"dest,omitempty"`
+	Retirada    *Local       `xml:"retirada,omitempty"`
+	Entrega     *Local       `xml:"entrega,omitempty"`
+	AutXML      []AutXML     `xml:"autXML,omitempty"`
+	Det         []Item       `xml:"det"`
+	Total       Total        `xml:"total"`
+	Transp      Transp       `xml:"transp"`
+	Cobr        *Cobr        `xml:"cobr,omitempty"`
+	Pag         *Pag         `xml:"pag,omitempty"`
+	InfIntermed *InfIntermed `xml:"infIntermed,omitempty"`
+	InfAdic     *InfAdic     `xml:"infAdic,omitempty"`
+	InfRespTec  *InfRespTec  `xml:"infRespTec,omitempty"`
+}
+
+// Ide holds the identification of the invoice (grupo ide). IndPag only
+// exists in layout 3.10; in 4.00 it moved to detPag.
+type Ide struct {
+	CUF         int                `xml:"cUF"`
+	CNF         string             `xml:"cNF"`
+	NatOp       string             `xml:"natOp"`
+	IndPag      *int               `xml:"indPag,omitempty"`
+	Mod         types.ModeloNFe    `xml:"mod"`
+	Serie       int                `xml:"serie"`
+	NNF         int                `xml:"nNF"`
+	DhEmi       string             `xml:"dhEmi"`
+	DhSaiEnt    string             `xml:"dhSaiEnt,omitempty"`
+	TpNF        int                `xml:"tpNF"`
+	IdDest      int                `xml:"idDest"`
+	CMunFG      string             `xml:"cMunFG"`
+	TpImp       int                `xml:"tpImp"`
+	TpEmis      int                `xml:"tpEmis"`
+	CDV         int                `xml:"cDV"`
+	TpAmb       types.TipoAmbiente `xml:"tpAmb"`
+	FinNFe      int                `xml:"finNFe"`
+	IndFinal    int                `xml:"indFinal"`
+	IndPres     int                `xml:"indPres"`
+	IndIntermed *int               `xml:"indIntermed,omitempty"`
+	ProcEmi     int                `xml:"procEmi"`
+	VerProc     string             `xml:"verProc"`
+	DhCont      string             `xml:"dhCont,omitempty"`
+	XJust       string             `xml:"xJust,omitempty"`
+	NFref       []NFref            `xml:"NFref,omitempty"`
+}
+
+// NFref references another fiscal document
+type NFref struct {
+	RefNFe string  `xml:"refNFe,omitempty"`
+	RefNF  *RefNF  `xml:"refNF,omitempty"`
+	RefCTe string  `xml:"refCTe,omitempty"`
+	RefECF *RefECF `xml:"refECF,omitempty"`
+}
+
+// RefNF references a model 1/1A invoice
+type RefNF struct {
+	CUF   int    `xml:"cUF"`
+	AAMM  string `xml:"AAMM"`
+	CNPJ  string `xml:"CNPJ"`
+	Mod   string `xml:"mod"`
+	Serie int    `xml:"serie"`
+	NNF   int    `xml:"nNF"`
+}
+
+// RefECF references a fiscal coupon
+type RefECF struct {
+	Mod  string `xml:"mod"`
+	NECF string `xml:"nECF"`
+	NCOO string `xml:"nCOO"`
+}
+
+// Endereco is the address of the issuer or recipient
+type Endereco struct {
+	XLgr    string `xml:"xLgr"`
+	Nro     string `xml:"nro"`
+	XCpl    string `xml:"xCpl,omitempty"`
+	XBairro string `xml:"xBairro"`
+	CMun    string `xml:"cMun"`
+	XMun    string `xml:"xMun"`
+	UF      string `xml:"UF"`
+	CEP     string `xml:"CEP,omitempty"`
+	CPais   string `xml:"cPais,omitempty"`
+	XPais   string `xml:"xPais,omitempty"`
+	Fone    string `xml:"fone,omitempty"`
+}
+
+// Emit holds the issuer of the invoice
+type Emit struct {
+	CNPJ      string   `xml:"CNPJ,omitempty"`
+	CPF       string   `xml:"CPF,omitempty"`
+	XNome     string   `xml:"xNome"`
+	XFant     string   `xml:"xFant,omitempty"`
+	EnderEmit Endereco `xml:"enderEmit"`
+	IE        string   `xml:"IE"`
+	IEST      string   `xml:"IEST,omitempty"`
+	IM        string   `xml:"IM,omitempty"`
+	CNAE      string   `xml:"CNAE,omitempty"`
+	CRT       int      `xml:"CRT"`
+}
+
+// Dest holds the recipient of the invoice
+type Dest struct {
+	CNPJ          string    `xml:"CNPJ,omitempty"`
+	CPF           string    `xml:"CPF,omitempty"`
+	IDEstrangeiro string    `xml:"idEstrangeiro,omitempty"`
+	XNome         string    `xml:"xNome,omitempty"`
+	EnderDest     *Endereco `xml:"enderDest,omitempty"`
+	IndIEDest     int       `xml:"indIEDest"`
+	IE            string    `xml:"IE,omitempty"`
+	ISUF          string    `xml:"ISUF,omitempty"`
+	IM            string    `xml:"IM,omitempty"`
+	Email         string    `xml:"email,omitempty"`
+}
+
+// Local is a pickup (retirada) or delivery (entrega) place
+type Local struct {
+	CNPJ    string `xml:"CNPJ,omitempty"`
+	CPF     string `xml:"CPF,omitempty"`
+	XNome   string `xml:"xNome,omitempty"`
+	XLgr    string `xml:"xLgr"`
+	Nro     string `xml:"nro"`
+	XCpl    string `xml:"xCpl,omitempty"`
+	XBairro string `xml:"xBairro"`
+	CMun    string `xml:"cMun"`
+	XMun    string `xml:"xMun"`
+	UF      string `xml:"UF"`
+	CEP     string `xml:"CEP,omitempty"`
+	Fone    string `xml:"fone,omitempty"`
+	Email   string `xml:"email,omitempty"`
+	IE      string `xml:"IE,omitempty"`
+}
+
+// AutXML authorizes a third party to download the XML
+type AutXML struct {
+	CNPJ string `xml:"CNPJ,omitempty"`
+	CPF  string `xml:"CPF,omitempty"`
+}
+
+// Transp holds the transport data
+type Transp struct {
+	ModFrete   int         `xml:"modFrete"`
+	Transporta *Transporta `xml:"transporta,omitempty"`
+	VeicTransp *Veiculo    `xml:"veicTransp,omitempty"`
+	Reboque    []Veiculo   `xml:"reboque,omitempty"`
+	Vol        []Vol       `xml:"vol,omitempty"`
+}
+
+// Transporta identifies the carrier
+type Transporta struct {
+	CNPJ   string `xml:"CNPJ,omitempty"`
+	CPF    string `xml:"CPF,omitempty"`
+	XNome  string `xml:"xNome,omitempty"`
+	IE     string `xml:"IE,omitempty"`
+	XEnder string `xml:"xEnder,omitempty"`
+	XMun   string `xml:"xMun,omitempty"`
+	UF     string `xml:"UF,omitempty"`
+}
+
+// Veiculo identifies a vehicle or trailer
+type Veiculo struct {
+	Placa string `xml:"placa"`
+	UF    string `xml:"UF,omitempty"`
+	RNTC  string `xml:"RNTC,omitempty"`
+}
+
+// Vol describes the transported volumes
+type Vol struct {
+	QVol  int             `xml:"qVol,omitempty"`
+	Esp   string          `xml:"esp,omitempty"`
+	Marca string          `xml:"marca,omitempty"`
+	NVol  string          `xml:"nVol,omitempty"`
+	PesoL decimal.Decimal `xml:"pesoL,omitempty"`
+	PesoB decimal.Decimal `xml:"pesoB,omitempty"`
+}
+
+// Cobr holds the invoice and installments
+type Cobr struct {
+	Fat *Fat  `xml:"fat,omitempty"`
+	Dup []Dup `xml:"dup,omitempty"`
+}
+
+// Fat is the commercial invoice
+type Fat struct {
+	NFat  string          `xml:"nFat,omitempty"`
+	VOrig decimal.Decimal `xml:"vOrig,omitempty"`
+	VDesc decimal.Decimal `xml:"vDesc,omitempty"`
+	VLiq  decimal.Decimal `xml:"vLiq,omitempty"`
+}
+
+// Dup is an installment
+type Dup struct {
+	NDup  string          `xml:"nDup,omitempty"`
+	DVenc string          `xml:"dVenc,omitempty"`
+	VDup  decimal.Decimal `xml:"vDup"`
+}
+
+// Pag holds the payments (grupo pag). Layout 3.10 repeats pag with tPag and
+// vPag directly inside it; those are read as detPag entries.
+type Pag struct {
+	DetPag []DetPag        `xml:"detPag"`
+	VTroco decimal.Decimal `xml:"vTroco,omitempty"`
+}
+
+// DetPag is a payment
+type DetPag struct {
+	IndPag *int            `xml:"indPag,omitempty"`
+	TPag   string          `xml:"tPag"`
+	XPag   string          `xml:"xPag,omitempty"`
+	VPag   decimal.Decimal `xml:"vPag"`
+	Card   *Card           `xml:"card,omitempty"`
+}
+
+// Card holds the card payment data
+type Card struct {
+	TpIntegra int    `xml:"tpIntegra"`
+	CNPJ      string `xml:"CNPJ,omitempty"`
+	TBand     string `xml:"tBand,omitempty"`
+	CAut      string `xml:"cAut,omitempty"`
+}
+
+// UnmarshalXML reads both the 4.00 group and the repeated 3.10 groups
+func (p *Pag) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var pag struct {
+		DetPag []DetPag        `xml:"detPag"`
+		VTroco decimal.Decimal `xml:"vTroco"`
+		TPag   string          `xml:"tPag"`
+		VPag   decimal.Decimal `xml:"vPag"`
+		Card   *Card           `xml:"card"`
+	}
+	if err := d.DecodeElement(&pag, &start); err != nil {
+		return err
+	}
+
+	p.DetPag = append(p.DetPag, pag.DetPag...)
+	if pag.TPag != "" {
+		p.DetPag = append(p.DetPag, DetPag{TPag: pag.TPag, VPag: pag.VPag, Card: pag.Card})
+	}
+	if pag.VTroco.IsSet() {
+		p.VTroco = pag.VTroco
+	}
+	return nil
+}
+
+// InfIntermed identifies the intermediary platform of the sale
+type InfIntermed struct {
+	CNPJ         string `xml:"CNPJ"`
+	IDCadIntTran string `xml:"idCadIntTran"`
+}
+
+// InfAdic holds the additional information
+type InfAdic struct {
+	InfAdFisco string    `xml:"infAdFisco,omitempty"`
+	InfCpl     string    `xml:"infCpl,omitempty"`
+	ObsCont    []Obs     `xml:"obsCont,omitempty"`
+	ObsFisco   []Obs     `xml:"obsFisco,omitempty"`
+	ProcRef    []ProcRef `xml:"procRef,omitempty"`
+}
+
+// Obs is a free observation identified by its field name
+type Obs struct {
+	XCampo string `xml:"xCampo,attr"`
+	XTexto string `xml:"xTexto"`
+}
+
+// ProcRef references an administrative or judicial process
+type ProcRef struct {
+	NProc   string `xml:"nProc"`
+	IndProc int    `xml:"indProc"`
+}
+
+// InfRespTec identifies the technical responsible for the issuing software
+type InfRespTec struct {
+	CNPJ     string `xml:"CNPJ"`
+	XContato string `xml:"xContato"`
+	Email    string `xml:"email"`
+	Fone     string `xml:"fone"`
+	IDCSRT   string `xml:"idCSRT,omitempty"`
+	HashCSRT string `xml:"hashCSRT,omitempty"`
+}
+
+// InfNFeSupl holds the NFC-e QR Code data
+type InfNFeSupl struct {
+	QrCode   string `xml:"qrCode"`
+	URLChave string `xml:"urlChave,omitempty"`
+}
+
+// Signature holds the XMLDSig signature of the document
+type Signature struct {
+	SignedInfo struct {
+		Reference struct {
+			URI         string `xml:"URI,attr"`
+			DigestValue string `xml:"DigestValue"`
+		} `xml:"Reference"`
+	} `xml:"SignedInfo"`
+	SignatureValue string `xml:"SignatureValue"`
+	KeyInfo        struct {
+		X509Data struct {
+			X509Certificate string `xml:"X509Certificate"`
+		} `xml:"X509Data"`
+	} `xml:"KeyInfo"`
+}
+
+// NFeProc is the authorized NFe distributed with its protocol
+type NFeProc struct {
+	XMLName xml.Name `xml:"nfeProc"`
+	Versao  string   `xml:"versao,attr"`
+	NFe     NFe      `xml:"NFe"`
+	ProtNFe ProtNFe  `xml:"protNFe"`
+}
+
+// ResNFe is the summary of an NFe distributed to the recipient (DistribuicaoDFe)
+type ResNFe struct {
+	XMLName  xml.Name        `xml:"resNFe"`
+	Versao   string          `xml:"versao,attr"`
+	ChNFe    string          `xml:"chNFe"`
+	CNPJ     string          `xml:"CNPJ,omitempty"`
+	CPF      string          `xml:"CPF,omitempty"`
+	XNome    string          `xml:"xNome"`
+	IE       string          `xml:"IE"`
+	DhEmi    string          `xml:"dhEmi"`
+	TpNF     int             `xml:"tpNF"`
+	VNF      decimal.Decimal `xml:"vNF"`
+	DigVal   string          `xml:"digVal"`
+	DhRecbto string          `xml:"dhRecbto"`
+	NProt    string          `xml:"nProt"`
+	CSitNFe  int             `xml:"cSitNFe"`
+}
@@ -0,0 +1,163 @@
+package nfe
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// Patterns of the references SEFAZ appends to duplicate rejections, e.g.
+// "Rejeição: Duplicidade de NF-e [nProt:135240000012345][dhAut:...]" and
+// "Rejeição: Duplicidade de NF-e, com diferença na Chave de Acesso [chNFe:...][nRec:...]".
+// The emitente CNPJ in the chave (positions 7-20) may be alphanumeric.
+var (
+	duplicateChNFe = regexp.MustCompile(`\[\s*(?i:chNFe)\s*:\s*(\d{6}[0-9A-Z]{14}\d{24})\s*\]`)
+	duplicateNProt = regexp.MustCompile(`(?i)\[\s*nProt\s*:\s*(\d{15})\s*\]`)
+	duplicateNRec  = regexp.MustCompile(`(?i)\[\s*nRec\s*:\s*(\d{15})\s*\]`)
+)
+
+// DuplicateReference holds the identification of the original NFe found in
+// the xMotivo of a duplicate rejection
+type DuplicateReference struct {
+	ChNFe string
+	NProt string
+	NRec  string
+}
+
+// ParseDuplicateMotivo extracts the original access key, protocol and receipt
+// from the xMotivo of a duplicate rejection (cStat 204 or 539). Missing
+// references are left empty.
+func ParseDuplicateMotivo(xMotivo string) DuplicateReference {
+	var ref DuplicateReference
+	if match := duplicateChNFe.FindStringSubmatch(xMotivo); match != nil {
+		ref.ChNFe = match[1]
+	}
+	if match := duplicateNProt.FindStringSubmatch(xMotivo); match != nil {
+		ref.NProt = match[1]
+	}
+	if match := duplicateNRec.FindStringSubmatch(xMotivo); match != nil {
+		ref.NRec = match[1]
+	}
+	return ref
+}
+
+// DuplicateError is returned when SEFAZ reports a duplicate NFe that could not
+// be matched to the submitted document
+type DuplicateError struct {
+	CStat   int
+	XMotivo string
+	// ChNFe and NProt identify the NFe already registered at SEFAZ
+	ChNFe string
+	NProt string
+	// Digest is the digest of the submitted NFe and AuthorizedDigest the one
+	// registered at SEFAZ, empty when the protocol could not be queried
+	Digest           string
+	AuthorizedDigest string
+	// Cause is the error of the protocol query, if any
+	Cause error
+}
+
+// Error implements the error interface
+func (e *DuplicateError) Error() string {
+	switch {
+	case e.Cause != nil:
+		return fmt.Sprintf("[%s] cStat %d: duplicate NFe %s could not be verified: %v",
+			errors.ErrSEFAZ.Code, e.CStat, e.ChNFe, e.Cause)
+	case e.AuthorizedDigest != "":
+		return fmt.Sprintf("[%s] cStat %d: NFe %s is already authorized (nProt %s) with a different digest: submitted %s, authorized %s",
+			errors.ErrSEFAZ.Code, e.CStat, e.ChNFe, e.NProt, e.Digest, e.AuthorizedDigest)
+	default:
+		return fmt.Sprintf("[%s] cStat %d: NFe %s is already registered and is not authorized: %s",
+			errors.ErrSEFAZ.Code, e.CStat, e.ChNFe, e.XMotivo)
+	}
+}
+
+// Unwrap exposes the SEFAZ rejection and the query error, so that
+// errors.Is(err, errors.ErrSEFAZDuplicate) holds
+func (e *DuplicateError) Unwrap() []error {
+	unwrapped := []error{errors.NewSEFAZStatusError(e.CStat, e.XMotivo)}
+	if e.Cause != nil {
+		unwrapped = append(unwrapped, e.Cause)
+	}
+	return unwrapped
+}
+
+// recoverDuplicate resolves a duplicate rejection by querying the protocol of
+// the NFe registered at SEFAZ and comparing its digest with the submitted one
+func (c *Client) recoverDuplicate(ctx context.Context, sub *submission, cStat int, xMotivo string) (*AuthorizationResponse, error) {
+	ref := ParseDuplicateMotivo(xMotivo)
+	chave := ref.ChNFe
+	if chave == "" {
+		chave = sub.chave
+	}
+
+	dupErr := &DuplicateError{
+		CStat:   cStat,
+		XMotivo: xMotivo,
+		ChNFe:   chave,
+		NProt:   ref.NProt,
+		Digest:  sub.digest,
+	}
+
+	// 539 means the number is registered under another access key, so the
+	// submitted NFe can never be the registered one
+	if chave != sub.chave {
+		ret, err := c.Query(ctx, chave)
+		if err != nil {
+			dupErr.Cause = err
+		} else if authorizedSituation(ret) {
+			dupErr.NProt = ret.ProtNFe.InfProt.NProt
+			dupErr.AuthorizedDigest = ret.ProtNFe.InfProt.DigVal
+		}
+		return nil, dupErr
+	}
+
+	ret, err := c.Query(ctx, chave)
+	if err != nil {
+		dupErr.Cause = err
+		return nil, dupErr
+	}
+	if !authorizedSituation(ret) {
+		return nil, dupErr
+	}
+
+	info := ret.ProtNFe.InfProt
+	dupErr.NProt = info.NProt
+	dupErr.AuthorizedDigest = info.DigVal
+	if !strings.EqualFold(strings.TrimSpace(info.DigVal), sub.digest) {
+		return nil, dupErr
+	}
+
+	return &AuthorizationResponse{
+		ChNFe:     chave,
+		CStat:     info.CStat,
+		XMotivo:   info.XMotivo,
+		NProt:     info.NProt,
+		Protocol:  ret.ProtNFe,
+		NFeProc:   buildNFeProc(sub.xml, ret.ProtNFe),
+		Recovered: true,
+	}, nil
+}
+
+// authorizedSituation reports whether a protocol query shows the NFe as
+// authorized: its protocol authorizes it and it was not cancelled since
+func authorizedSituation(ret *RetConsSitNFe) bool {
+	if ret.ProtNFe == nil || errors.CStatDetails(ret.ProtNFe.InfProt.CStat).Category != errors.CategoryAuthorized {
+		return false
+	}
+	return !cancelledSituation(ret.CStat)
+}
+
+// cancelledSituation reports whether the cStat of a retConsSitNFe means the
+// NFe is cancelled (101, 151 or 155). The query still returns the original
+// protocol, with cStat 100, so the protocol alone does not tell.
+func cancelledSituation(cStat int) bool {
+	switch cStat {
+	case 101, 151, 155:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package nfe
+
+import "testing"
+
+func TestParseDuplicateMotivo(t *testing.T) {
+	tests := []struct {
+		name    string
+		xMotivo string
+		want    DuplicateReference
+	}{
+		{
+			name:    "204 with protocol",
+			xMotivo: "Rejeição: Duplicidade de NF-e [nProt:333240000012345][dhAut:2024-05-10T10:00:05-03:00]",
+			want:    DuplicateReference{NProt: "333240000012345"},
+		},
+		{
+			name:    "539 with access key and receipt",
+			xMotivo: "Rejeição: Duplicidade de NF-e, com diferença na Chave de Acesso [chNFe:33240511222333000181550010000012341876543218][nRec:331000012345678]",
+			want:    DuplicateReference{ChNFe: "33240511222333000181550010000012341876543218", NRec: "331000012345678"},
+		},
+		{
+			name:    "539 with alphanumeric access key",
+			xMotivo: "Rejeição: Duplicidade de NF-e, com diferença na Chave de Acesso [chNFe:332405AB2CD333EF0181550010000012341876543210]",
+			want:    DuplicateReference{ChNFe: "332405AB2CD333EF0181550010000012341876543210"},
+		},
+		{
+			name:    "spacing and case",
+			xMotivo: "Duplicidade de NF-e [ NPROT: 333240000012345 ] [chnfe:33240511222333000181550010000012341876543218]",
+			want:    DuplicateReference{ChNFe: "33240511222333000181550010000012341876543218", NProt: "333240000012345"},
+		},
+		{
+			name:    "without references",
+			xMotivo: "Rejeição: Duplicidade de NF-e",
+			want:    DuplicateReference{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseDuplicateMotivo(tt.xMotivo); got != tt.want {
+				t.Errorf("ParseDuplicateMotivo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
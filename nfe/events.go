@@ -0,0 +1,129 @@
+package nfe
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+// ProcEventoNFe represents a registered fiscal event (evento + retEvento)
+// as returned by SEFAZ and distributed to the parties of the operation
+type ProcEventoNFe struct {
+	XMLName   xml.Name  `xml:"procEventoNFe"`
+	Versao    string    `xml:"versao,attr"`
+	Evento    Evento    `xml:"evento"`
+	RetEvento RetEvento `xml:"retEvento"`
+}
+
+// Evento represents the event request signed by the author
+type Evento struct {
+	XMLName   xml.Name  `xml:"evento"`
+	Versao    string    `xml:"versao,attr"`
+	InfEvento InfEvento `xml:"infEvento"`
+}
+
+// InfEvento holds the event identification and details
+type InfEvento struct {
+	ID         string             `xml:"Id,attr"`
+	COrgao     int                `xml:"cOrgao"`
+	TpAmb      types.TipoAmbiente `xml:"tpAmb"`
+	CNPJ       string             `xml:"CNPJ,omitempty"`
+	CPF        string             `xml:"CPF,omitempty"`
+	ChNFe      string             `xml:"chNFe"`
+	DhEvento   string             `xml:"dhEvento"`
+	TpEvento   types.TipoEvento   `xml:"tpEvento"`
+	NSeqEvento int                `xml:"nSeqEvento"`
+	VerEvento  string             `xml:"verEvento"`
+	DetEvento  DetEvento          `xml:"detEvento"`
+}
+
+// DetEvento holds the event specific details (CC-e, cancelamento, etc.)
+type DetEvento struct {
+	Versao     string `xml:"versao,attr"`
+	DescEvento string `xml:"descEvento"`
+	NProt      string `xml:"nProt,omitempty"`
+	XJust      string `xml:"xJust,omitempty"`
+	XCorrecao  string `xml:"xCorrecao,omitempty"`
+	XCondUso   string `xml:"xCondUso,omitempty"`
+	ChNFeRef   string `xml:"chNFeRef,omitempty"`
+}
+
+// RetEvento represents the SEFAZ answer to an event request
+type RetEvento struct {
+	XMLName   xml.Name     `xml:"retEvento"`
+	Versao    string       `xml:"versao,attr"`
+	InfEvento InfEventoRet `xml:"infEvento"`
+}
+
+// InfEventoRet holds the event registration result
+type InfEventoRet struct {
+	ID          string             `xml:"Id,attr,omitempty"`
+	TpAmb       types.TipoAmbiente `xml:"tpAmb"`
+	VerAplic    string             `xml:"verAplic"`
+	COrgao      int                `xml:"cOrgao"`
+	CStat       int                `xml:"cStat"`
+	XMotivo     string             `xml:"xMotivo"`
+	ChNFe       string             `xml:"chNFe,omitempty"`
+	TpEvento    types.TipoEvento   `xml:"tpEvento,omitempty"`
+	XEvento     string             `xml:"xEvento,omitempty"`
+	NSeqEvento  int                `xml:"nSeqEvento,omitempty"`
+	CNPJDest    string             `xml:"CNPJDest,omitempty"`
+	CPFDest     string             `xml:"CPFDest,omitempty"`
+	EmailDest   string             `xml:"emailDest,omitempty"`
+	DhRegEvento string             `xml:"dhRegEvento,omitempty"`
+	NProt       string             `xml:"nProt,omitempty"`
+}
+
+// ParseProcEventoNFe parses a procEventoNFe XML document
+func ParseProcEventoNFe(data []byte) (*ProcEventoNFe, error) {
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, errors.NewValidationError("event XML cannot be empty", "procEventoNFe", "")
+	}
+
+	var proc ProcEventoNFe
+	if err := xml.Unmarshal(data, &proc); err != nil {
+		return nil, errors.NewXMLError("failed to parse procEventoNFe", "procEventoNFe", err)
+	}
+
+	if proc.Evento.InfEvento.ChNFe == "" {
+		return nil, errors.NewValidationError("event has no chNFe", "chNFe", "")
+	}
+
+	return &proc, nil
+}
+
+// IsRegistered returns true if SEFAZ registered the event (cStat 135, 136 or 155)
+func (p *ProcEventoNFe) IsRegistered() bool {
+	switch p.RetEvento.InfEvento.CStat {
+	case 135, 136, 155:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventName returns a human readable name for the event type
+func EventName(tpEvento types.TipoEvento) string {
+	switch tpEvento {
+	case types.EvtCCe:
+		return "Carta de Correção Eletrônica"
+	case types.EvtCancela:
+		return "Cancelamento"
+	case types.EvtCancelaSubstituicao:
+		return "Cancelamento por Substituição"
+	case types.EvtEPEC:
+		return "EPEC"
+	case types.EvtConfirmacao:
+		return "Confirmação da Operação"
+	case types.EvtCiencia:
+		return "Ciência da Operação"
+	case types.EvtDesconhecimento:
+		return "Desconhecimento da Operação"
+	case types.EvtNaoRealizada:
+		return "Operação não Realizada"
+	default:
+		return "Evento"
+	}
+}
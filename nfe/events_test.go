@@ -0,0 +1,74 @@
+package nfe
+
+import (
+	"os"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+func TestParseProcEventoNFe(t *testing.T) {
+	data, err := os.ReadFile("testdata/procEventoNFe_cce.xml")
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+
+	proc, err := ParseProcEventoNFe(data)
+	if err != nil {
+		t.Fatalf("ParseProcEventoNFe should not return error, got: %v", err)
+	}
+
+	inf := proc.Evento.InfEvento
+	if inf.TpEvento != types.EvtCCe {
+		t.Errorf("Expected tpEvento %d, got %d", types.EvtCCe, inf.TpEvento)
+	}
+
+	if inf.ChNFe != "35240511222333000181550010000012341123456789" {
+		t.Errorf("Unexpected chNFe %s", inf.ChNFe)
+	}
+
+	if inf.TpAmb != types.TaHomologacao {
+		t.Errorf("Expected homologation environment, got %d", inf.TpAmb)
+	}
+
+	if inf.DetEvento.XCorrecao == "" {
+		t.Error("xCorrecao should be parsed")
+	}
+
+	if proc.RetEvento.InfEvento.NProt != "135240000012345" {
+		t.Errorf("Unexpected nProt %s", proc.RetEvento.InfEvento.NProt)
+	}
+
+	if !proc.IsRegistered() {
+		t.Error("Event with cStat 135 should be registered")
+	}
+}
+
+func TestParseProcEventoNFeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+	}{
+		{"empty", ""},
+		{"malformed", "<procEventoNFe><evento>"},
+		{"missing chNFe", `<procEventoNFe versao="1.00"><evento><infEvento/></evento></procEventoNFe>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseProcEventoNFe([]byte(tt.xml)); err == nil {
+				t.Error("ParseProcEventoNFe should return error")
+			}
+		})
+	}
+}
+
+func TestEventName(t *testing.T) {
+	if EventName(types.EvtCCe) != "Carta de Correção Eletrônica" {
+		t.Errorf("Unexpected CC-e name: %s", EventName(types.EvtCCe))
+	}
+
+	if EventName(types.EvtCancela) != "Cancelamento" {
+		t.Errorf("Unexpected cancel name: %s", EventName(types.EvtCancela))
+	}
+}
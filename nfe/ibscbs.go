@@ -0,0 +1,218 @@
+package nfe
+
+import "github.com/adrianodrix/sped-nfe-go/decimal"
+
+// IS holds the Imposto Seletivo of an item (NT 2025.002, grupo UB01)
+type IS struct {
+	CSTIS        string          `xml:"CSTIS"`
+	CClassTribIS string          `xml:"cClassTribIS"`
+	VBCIS        decimal.Decimal `xml:"vBCIS"`
+	PIS          decimal.Decimal `xml:"pIS"`
+	PISEspec     decimal.Decimal `xml:"pISEspec,omitempty"`
+	UTrib        string          `xml:"uTrib,omitempty"`
+	QTrib        decimal.Decimal `xml:"qTrib,omitempty"`
+	VIS          decimal.Decimal `xml:"vIS"`
+}
+
+// IBSCBS holds the IBS and CBS of an item (NT 2025.002, grupo UB12). The
+// cClassTrib starts with the CST; GIBSCBS is nil for CSTs without taxation,
+// such as isenção (400) and imunidade (410).
+type IBSCBS struct {
+	CST        string   `xml:"CST"`
+	CClassTrib string   `xml:"cClassTrib"`
+	GIBSCBS    *GIBSCBS `xml:"gIBSCBS,omitempty"`
+}
+
+// GIBSCBS holds the base and the values of the IBS and CBS
+type GIBSCBS struct {
+	VBC            decimal.Decimal `xml:"vBC"`
+	GIBSUF         GIBSUF          `xml:"gIBSUF"`
+	GIBSMun        GIBSMun         `xml:"gIBSMun"`
+	VIBS           decimal.Decimal `xml:"vIBS"`
+	GCBS           GCBS            `xml:"gCBS"`
+	GTribRegular   *GTribRegular   `xml:"gTribRegular,omitempty"`
+	GTribCompraGov *GTribCompraGov `xml:"gTribCompraGov,omitempty"`
+}
+
+// GIBSUF - IBS de competência da UF
+type GIBSUF struct {
+	PIBSUF   decimal.Decimal `xml:"pIBSUF"`
+	GDif     *GDif           `xml:"gDif,omitempty"`
+	GDevTrib *GDevTrib       `xml:"gDevTrib,omitempty"`
+	GRed     *GRed           `xml:"gRed,omitempty"`
+	VIBSUF   decimal.Decimal `xml:"vIBSUF"`
+}
+
+// GIBSMun - IBS de competência do município
+type GIBSMun struct {
+	PIBSMun  decimal.Decimal `xml:"pIBSMun"`
+	GDif     *GDif           `xml:"gDif,omitempty"`
+	GDevTrib *GDevTrib       `xml:"gDevTrib,omitempty"`
+	GRed     *GRed           `xml:"gRed,omitempty"`
+	VIBSMun  decimal.Decimal `xml:"vIBSMun"`
+}
+
+// GCBS - Contribuição sobre Bens e Serviços
+type GCBS struct {
+	PCBS     decimal.Decimal `xml:"pCBS"`
+	GDif     *GDif           `xml:"gDif,omitempty"`
+	GDevTrib *GDevTrib       `xml:"gDevTrib,omitempty"`
+	GRed     *GRed           `xml:"gRed,omitempty"`
+	VCBS     decimal.Decimal `xml:"vCBS"`
+}
+
+// GDif - diferimento: the deferred share of the tax
+type GDif struct {
+	PDif decimal.Decimal `xml:"pDif"`
+	VDif decimal.Decimal `xml:"vDif"`
+}
+
+// GDevTrib - devolução de tributos (cashback) to the consumer
+type GDevTrib struct {
+	VDevTrib decimal.Decimal `xml:"vDevTrib"`
+}
+
+// GRed - redução de alíquota: the reduction and the resulting effective rate
+type GRed struct {
+	PRedAliq  decimal.Decimal `xml:"pRedAliq"`
+	PAliqEfet decimal.Decimal `xml:"pAliqEfet"`
+}
+
+// GTribRegular - the taxation that would apply without the special regime
+// informed in CST and cClassTrib (e.g. suspensão)
+type GTribRegular struct {
+	CSTReg             string          `xml:"CSTReg"`
+	CClassTribReg      string          `xml:"cClassTribReg"`
+	PAliqEfetRegIBSUF  decimal.Decimal `xml:"pAliqEfetRegIBSUF"`
+	VTribRegIBSUF      decimal.Decimal `xml:"vTribRegIBSUF"`
+	PAliqEfetRegIBSMun decimal.Decimal `xml:"pAliqEfetRegIBSMun"`
+	VTribRegIBSMun     decimal.Decimal `xml:"vTribRegIBSMun"`
+	PAliqEfetRegCBS    decimal.Decimal `xml:"pAliqEfetRegCBS"`
+	VTribRegCBS        decimal.Decimal `xml:"vTribRegCBS"`
+}
+
+// GTribCompraGov - the rates and values of purchases by public entities
+type GTribCompraGov struct {
+	PAliqIBSUF  decimal.Decimal `xml:"pAliqIBSUF"`
+	VTribIBSUF  decimal.Decimal `xml:"vTribIBSUF"`
+	PAliqIBSMun decimal.Decimal `xml:"pAliqIBSMun"`
+	VTribIBSMun decimal.Decimal `xml:"vTribIBSMun"`
+	PAliqCBS    decimal.Decimal `xml:"pAliqCBS"`
+	VTribCBS    decimal.Decimal `xml:"vTribCBS"`
+}
+
+// ISTot holds the total of the Imposto Seletivo
+type ISTot struct {
+	VIS decimal.Decimal `xml:"vIS"`
+}
+
+// IBSCBSTot holds the totals of the IBS and CBS
+type IBSCBSTot struct {
+	VBCIBSCBS decimal.Decimal `xml:"vBCIBSCBS"`
+	GIBS      GIBSTot         `xml:"gIBS"`
+	GCBS      GCBSTot         `xml:"gCBS"`
+}
+
+// GIBSTot holds the IBS totals of the UF and the municipality
+type GIBSTot struct {
+	GIBSUF           GIBSUFTot       `xml:"gIBSUF"`
+	GIBSMun          GIBSMunTot      `xml:"gIBSMun"`
+	VIBS             decimal.Decimal `xml:"vIBS"`
+	VCredPres        decimal.Decimal `xml:"vCredPres"`
+	VCredPresCondSus decimal.Decimal `xml:"vCredPresCondSus"`
+}
+
+// GIBSUFTot holds the IBS totals of the UF
+type GIBSUFTot struct {
+	VDif     decimal.Decimal `xml:"vDif"`
+	VDevTrib decimal.Decimal `xml:"vDevTrib"`
+	VIBSUF   decimal.Decimal `xml:"vIBSUF"`
+}
+
+// GIBSMunTot holds the IBS totals of the municipality
+type GIBSMunTot struct {
+	VDif     decimal.Decimal `xml:"vDif"`
+	VDevTrib decimal.Decimal `xml:"vDevTrib"`
+	VIBSMun  decimal.Decimal `xml:"vIBSMun"`
+}
+
+// GCBSTot holds the CBS totals
+type GCBSTot struct {
+	VDif             decimal.Decimal `xml:"vDif"`
+	VDevTrib         decimal.Decimal `xml:"vDevTrib"`
+	VCBS             decimal.Decimal `xml:"vCBS"`
+	VCredPres        decimal.Decimal `xml:"vCredPres"`
+	VCredPresCondSus decimal.Decimal `xml:"vCredPresCondSus"`
+}
+
+// deferredAndReturned returns the deferred and returned values of a tax group
+func deferredAndReturned(dif *GDif, dev *GDevTrib) (vDif, vDevTrib decimal.Decimal) {
+	if dif != nil {
+		vDif = dif.VDif
+	}
+	if dev != nil {
+		vDevTrib = dev.VDevTrib
+	}
+	return vDif, vDevTrib
+}
+
+// CalculateReformTotals computes the IBSCBSTot and ISTot groups from the
+// items. Each is nil when no item has the respective group.
+func CalculateReformTotals(items []Item) (*IBSCBSTot, *ISTot) {
+	var ibscbs *IBSCBSTot
+	var is *ISTot
+
+	for _, item := range items {
+		if item.Imposto.IS != nil {
+			if is == nil {
+				is = &ISTot{}
+			}
+			is.VIS = is.VIS.Add(item.Imposto.IS.VIS)
+		}
+
+		if item.Imposto.IBSCBS == nil {
+			continue
+		}
+		if ibscbs == nil {
+			ibscbs = &IBSCBSTot{}
+		}
+		g := item.Imposto.IBSCBS.GIBSCBS
+		if g == nil {
+			continue
+		}
+
+		ibscbs.VBCIBSCBS = ibscbs.VBCIBSCBS.Add(g.VBC)
+
+		uf := &ibscbs.GIBS.GIBSUF
+		vDif, vDevTrib := deferredAndReturned(g.GIBSUF.GDif, g.GIBSUF.GDevTrib)
+		uf.VDif, uf.VDevTrib, uf.VIBSUF = uf.VDif.Add(vDif), uf.VDevTrib.Add(vDevTrib), uf.VIBSUF.Add(g.GIBSUF.VIBSUF)
+
+		mun := &ibscbs.GIBS.GIBSMun
+		vDif, vDevTrib = deferredAndReturned(g.GIBSMun.GDif, g.GIBSMun.GDevTrib)
+		mun.VDif, mun.VDevTrib, mun.VIBSMun = mun.VDif.Add(vDif), mun.VDevTrib.Add(vDevTrib), mun.VIBSMun.Add(g.GIBSMun.VIBSMun)
+
+		cbs := &ibscbs.GCBS
+		vDif, vDevTrib = deferredAndReturned(g.GCBS.GDif, g.GCBS.GDevTrib)
+		cbs.VDif, cbs.VDevTrib, cbs.VCBS = cbs.VDif.Add(vDif), cbs.VDevTrib.Add(vDevTrib), cbs.VCBS.Add(g.GCBS.VCBS)
+	}
+
+	if is != nil {
+		is.VIS = total(is.VIS)
+	}
+	if ibscbs != nil {
+		t := ibscbs
+		t.VBCIBSCBS = total(t.VBCIBSCBS)
+		t.GIBS.GIBSUF = GIBSUFTot{VDif: total(t.GIBS.GIBSUF.VDif), VDevTrib: total(t.GIBS.GIBSUF.VDevTrib), VIBSUF: total(t.GIBS.GIBSUF.VIBSUF)}
+		t.GIBS.GIBSMun = GIBSMunTot{VDif: total(t.GIBS.GIBSMun.VDif), VDevTrib: total(t.GIBS.GIBSMun.VDevTrib), VIBSMun: total(t.GIBS.GIBSMun.VIBSMun)}
+		t.GIBS.VIBS = total(t.GIBS.GIBSUF.VIBSUF.Add(t.GIBS.GIBSMun.VIBSMun))
+		t.GIBS.VCredPres, t.GIBS.VCredPresCondSus = decimal.Money(0), decimal.Money(0)
+		t.GCBS = GCBSTot{
+			VDif:             total(t.GCBS.VDif),
+			VDevTrib:         total(t.GCBS.VDevTrib),
+			VCBS:             total(t.GCBS.VCBS),
+			VCredPres:        decimal.Money(0),
+			VCredPresCondSus: decimal.Money(0),
+		}
+	}
+	return ibscbs, is
+}
@@ -0,0 +1,78 @@
+package nfe
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func reformTestItems() []Item {
+	items := totalsTestItems()[:2]
+	items[0].Imposto.IBSCBS = &IBSCBS{CST: "000", CClassTrib: "000001", GIBSCBS: &GIBSCBS{
+		VBC:     money(110),
+		GIBSUF:  GIBSUF{PIBSUF: rate(0.1), VIBSUF: money(0.11)},
+		GIBSMun: GIBSMun{PIBSMun: rate(0), VIBSMun: money(0)},
+		VIBS:    money(0.11),
+		GCBS:    GCBS{PCBS: rate(0.9), VCBS: money(0.99)},
+	}}
+	items[1].Imposto.IS = &IS{CSTIS: "000", CClassTribIS: "000001", VBCIS: money(1000), PIS: rate(2), VIS: money(20)}
+	items[1].Imposto.IBSCBS = &IBSCBS{CST: "510", CClassTrib: "510001", GIBSCBS: &GIBSCBS{
+		VBC:     money(1020),
+		GIBSUF:  GIBSUF{PIBSUF: rate(0.1), GDif: &GDif{PDif: rate(50), VDif: money(0.51)}, VIBSUF: money(0.51)},
+		GIBSMun: GIBSMun{PIBSMun: rate(0), VIBSMun: money(0)},
+		VIBS:    money(0.51),
+		GCBS:    GCBS{PCBS: rate(0.9), GDif: &GDif{PDif: rate(50), VDif: money(4.59)}, VCBS: money(4.59)},
+	}}
+	return items
+}
+
+func TestCalculateReformTotals(t *testing.T) {
+	ibscbs, is := CalculateReformTotals(reformTestItems())
+
+	expected := &IBSCBSTot{
+		VBCIBSCBS: money(1130),
+		GIBS: GIBSTot{
+			GIBSUF:           GIBSUFTot{VDif: money(0.51), VDevTrib: money(0), VIBSUF: money(0.62)},
+			GIBSMun:          GIBSMunTot{VDif: money(0), VDevTrib: money(0), VIBSMun: money(0)},
+			VIBS:             money(0.62),
+			VCredPres:        money(0),
+			VCredPresCondSus: money(0),
+		},
+		GCBS: GCBSTot{VDif: money(4.59), VDevTrib: money(0), VCBS: money(5.58), VCredPres: money(0), VCredPresCondSus: money(0)},
+	}
+	if !reflect.DeepEqual(ibscbs, expected) {
+		t.Errorf("CalculateReformTotals() IBSCBSTot = %+v, expected %+v", ibscbs, expected)
+	}
+	if is == nil || is.VIS != money(20) {
+		t.Errorf("CalculateReformTotals() ISTot = %+v, expected vIS 20.00", is)
+	}
+
+	if ibscbs, is := CalculateReformTotals(totalsTestItems()); ibscbs != nil || is != nil {
+		t.Errorf("items without reform groups should have no totals, got %+v %+v", ibscbs, is)
+	}
+}
+
+func TestIBSCBSMarshal(t *testing.T) {
+	group := IBSCBS{CST: "410", CClassTrib: "410001"}
+	data, err := xml.Marshal(group)
+	if err != nil {
+		t.Fatalf("Marshal should not return error, got: %v", err)
+	}
+	if expected := `<IBSCBS><CST>410</CST><cClassTrib>410001</cClassTrib></IBSCBS>`; string(data) != expected {
+		t.Errorf("Marshal() = %s, expected %s", data, expected)
+	}
+
+	data, err = xml.Marshal(reformTestItems()[1].Imposto)
+	if err != nil {
+		t.Fatalf("Marshal should not return error, got: %v", err)
+	}
+	for _, fragment := range []string{
+		`<IS><CSTIS>000</CSTIS><cClassTribIS>000001</cClassTribIS><vBCIS>1000.00</vBCIS><pIS>2.0000</pIS><vIS>20.00</vIS></IS>`,
+		`<gIBSUF><pIBSUF>0.1000</pIBSUF><gDif><pDif>50.0000</pDif><vDif>0.51</vDif></gDif><vIBSUF>0.51</vIBSUF></gIBSUF>`,
+	} {
+		if !strings.Contains(string(data), fragment) {
+			t.Errorf("Marshal() = %s, expected to contain %s", data, fragment)
+		}
+	}
+}
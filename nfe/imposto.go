@@ -0,0 +1,481 @@
+package nfe
+
+import "github.com/adrianodrix/sped-nfe-go/decimal"
+
+// Imposto holds the taxes of an item (grupo imposto)
+type Imposto struct {
+	VTotTrib   decimal.Decimal `xml:"vTotTrib,omitempty"`
+	ICMS       ICMS            `xml:"ICMS"`
+	IPI        *IPI            `xml:"IPI,omitempty"`
+	II         *II             `xml:"II,omitempty"`
+	PIS        PIS             `xml:"PIS"`
+	PISST      *PISST          `xml:"PISST,omitempty"`
+	COFINS     COFINS          `xml:"COFINS"`
+	COFINSST   *COFINSST       `xml:"COFINSST,omitempty"`
+	ICMSUFDest *ICMSUFDest     `xml:"ICMSUFDest,omitempty"`
+	// IS and IBSCBS are the groups of the tax reform, only in LayoutPL010
+	IS     *IS     `xml:"IS,omitempty"`
+	IBSCBS *IBSCBS `xml:"IBSCBS,omitempty"`
+}
+
+// ICMS holds exactly one of the ICMS groups, chosen by CST (regime normal)
+// or CSOSN (Simples Nacional)
+type ICMS struct {
+	ICMS00    *ICMS00    `xml:"ICMS00,omitempty"`
+	ICMS10    *ICMS10    `xml:"ICMS10,omitempty"`
+	ICMS20    *ICMS20    `xml:"ICMS20,omitempty"`
+	ICMS30    *ICMS30    `xml:"ICMS30,omitempty"`
+	ICMS40    *ICMS40    `xml:"ICMS40,omitempty"`
+	ICMS51    *ICMS51    `xml:"ICMS51,omitempty"`
+	ICMS60    *ICMS60    `xml:"ICMS60,omitempty"`
+	ICMS70    *ICMS70    `xml:"ICMS70,omitempty"`
+	ICMS90    *ICMS90    `xml:"ICMS90,omitempty"`
+	ICMSSN101 *ICMSSN101 `xml:"ICMSSN101,omitempty"`
+	ICMSSN102 *ICMSSN102 `xml:"ICMSSN102,omitempty"`
+	ICMSSN201 *ICMSSN201 `xml:"ICMSSN201,omitempty"`
+	ICMSSN202 *ICMSSN202 `xml:"ICMSSN202,omitempty"`
+	ICMSSN500 *ICMSSN500 `xml:"ICMSSN500,omitempty"`
+	ICMSSN900 *ICMSSN900 `xml:"ICMSSN900,omitempty"`
+}
+
+// Origin returns the origin of the goods (orig) of the group set in icms
+func (icms ICMS) Origin() int {
+	switch {
+	case icms.ICMS00 != nil:
+		return icms.ICMS00.Orig
+	case icms.ICMS10 != nil:
+		return icms.ICMS10.Orig
+	case icms.ICMS20 != nil:
+		return icms.ICMS20.Orig
+	case icms.ICMS30 != nil:
+		return icms.ICMS30.Orig
+	case icms.ICMS40 != nil:
+		return icms.ICMS40.Orig
+	case icms.ICMS51 != nil:
+		return icms.ICMS51.Orig
+	case icms.ICMS60 != nil:
+		return icms.ICMS60.Orig
+	case icms.ICMS70 != nil:
+		return icms.ICMS70.Orig
+	case icms.ICMS90 != nil:
+		return icms.ICMS90.Orig
+	case icms.ICMSSN101 != nil:
+		return icms.ICMSSN101.Orig
+	case icms.ICMSSN102 != nil:
+		return icms.ICMSSN102.Orig
+	case icms.ICMSSN201 != nil:
+		return icms.ICMSSN201.Orig
+	case icms.ICMSSN202 != nil:
+		return icms.ICMSSN202.Orig
+	case icms.ICMSSN500 != nil:
+		return icms.ICMSSN500.Orig
+	case icms.ICMSSN900 != nil:
+		return icms.ICMSSN900.Orig
+	}
+	return 0
+}
+
+// ICMS00 - tributada integralmente
+type ICMS00 struct {
+	Orig  int             `xml:"orig"`
+	CST   string          `xml:"CST"`
+	ModBC int             `xml:"modBC"`
+	VBC   decimal.Decimal `xml:"vBC"`
+	PICMS decimal.Decimal `xml:"pICMS"`
+	VICMS decimal.Decimal `xml:"vICMS"`
+	PFCP  decimal.Decimal `xml:"pFCP,omitempty"`
+	VFCP  decimal.Decimal `xml:"vFCP,omitempty"`
+}
+
+// ICMS10 - tributada e com cobrança do ICMS por substituição tributária
+type ICMS10 struct {
+	Orig         int             `xml:"orig"`
+	CST          string          `xml:"CST"`
+	ModBC        int             `xml:"modBC"`
+	VBC          decimal.Decimal `xml:"vBC"`
+	PICMS        decimal.Decimal `xml:"pICMS"`
+	VICMS        decimal.Decimal `xml:"vICMS"`
+	VBCFCP       decimal.Decimal `xml:"vBCFCP,omitempty"`
+	PFCP         decimal.Decimal `xml:"pFCP,omitempty"`
+	VFCP         decimal.Decimal `xml:"vFCP,omitempty"`
+	ModBCST      int             `xml:"modBCST"`
+	PMVAST       decimal.Decimal `xml:"pMVAST,omitempty"`
+	PRedBCST     decimal.Decimal `xml:"pRedBCST,omitempty"`
+	VBCST        decimal.Decimal `xml:"vBCST"`
+	PICMSST      decimal.Decimal `xml:"pICMSST"`
+	VICMSST      decimal.Decimal `xml:"vICMSST"`
+	VBCFCPST     decimal.Decimal `xml:"vBCFCPST,omitempty"`
+	PFCPST       decimal.Decimal `xml:"pFCPST,omitempty"`
+	VFCPST       decimal.Decimal `xml:"vFCPST,omitempty"`
+	VICMSSTDeson decimal.Decimal `xml:"vICMSSTDeson,omitempty"`
+	MotDesICMSST int             `xml:"motDesICMSST,omitempty"`
+}
+
+// ICMS20 - com redução de base de cálculo
+type ICMS20 struct {
+	Orig          int             `xml:"orig"`
+	CST           string          `xml:"CST"`
+	ModBC         int             `xml:"modBC"`
+	PRedBC        decimal.Decimal `xml:"pRedBC"`
+	VBC           decimal.Decimal `xml:"vBC"`
+	PICMS         decimal.Decimal `xml:"pICMS"`
+	VICMS         decimal.Decimal `xml:"vICMS"`
+	VBCFCP        decimal.Decimal `xml:"vBCFCP,omitempty"`
+	PFCP          decimal.Decimal `xml:"pFCP,omitempty"`
+	VFCP          decimal.Decimal `xml:"vFCP,omitempty"`
+	VICMSDeson    decimal.Decimal `xml:"vICMSDeson,omitempty"`
+	MotDesICMS    int             `xml:"motDesICMS,omitempty"`
+	IndDeduzDeson int             `xml:"indDeduzDeson,omitempty"`
+}
+
+// ICMS30 - isenta ou não tributada e com cobrança do ICMS por substituição tributária
+type ICMS30 struct {
+	Orig          int             `xml:"orig"`
+	CST           string          `xml:"CST"`
+	ModBCST       int             `xml:"modBCST"`
+	PMVAST        decimal.Decimal `xml:"pMVAST,omitempty"`
+	PRedBCST      decimal.Decimal `xml:"pRedBCST,omitempty"`
+	VBCST         decimal.Decimal `xml:"vBCST"`
+	PICMSST       decimal.Decimal `xml:"pICMSST"`
+	VICMSST       decimal.Decimal `xml:"vICMSST"`
+	VBCFCPST      decimal.Decimal `xml:"vBCFCPST,omitempty"`
+	PFCPST        decimal.Decimal `xml:"pFCPST,omitempty"`
+	VFCPST        decimal.Decimal `xml:"vFCPST,omitempty"`
+	VICMSDeson    decimal.Decimal `xml:"vICMSDeson,omitempty"`
+	MotDesICMS    int             `xml:"motDesICMS,omitempty"`
+	IndDeduzDeson int             `xml:"indDeduzDeson,omitempty"`
+}
+
+// ICMS40 - isenta (40), não tributada (41) ou com suspensão (50)
+type ICMS40 struct {
+	Orig          int             `xml:"orig"`
+	CST           string          `xml:"CST"`
+	VICMSDeson    decimal.Decimal `xml:"vICMSDeson,omitempty"`
+	MotDesICMS    int             `xml:"motDesICMS,omitempty"`
+	IndDeduzDeson int             `xml:"indDeduzDeson,omitempty"`
+}
+
+// ICMS51 - diferimento
+type ICMS51 struct {
+	Orig     int             `xml:"orig"`
+	CST      string          `xml:"CST"`
+	ModBC    int             `xml:"modBC"`
+	PRedBC   decimal.Decimal `xml:"pRedBC,omitempty"`
+	VBC      decimal.Decimal `xml:"vBC"`
+	PICMS    decimal.Decimal `xml:"pICMS"`
+	VICMSOp  decimal.Decimal `xml:"vICMSOp"`
+	PDif     decimal.Decimal `xml:"pDif"`
+	VICMSDif decimal.Decimal `xml:"vICMSDif"`
+	VICMS    decimal.Decimal `xml:"vICMS"`
+	VBCFCP   decimal.Decimal `xml:"vBCFCP,omitempty"`
+	PFCP     decimal.Decimal `xml:"pFCP,omitempty"`
+	VFCP     decimal.Decimal `xml:"vFCP,omitempty"`
+	PFCPDif  decimal.Decimal `xml:"pFCPDif,omitempty"`
+	VFCPDif  decimal.Decimal `xml:"vFCPDif,omitempty"`
+	VFCPEfet decimal.Decimal `xml:"vFCPEfet,omitempty"`
+}
+
+// ICMS60 - ICMS cobrado anteriormente por substituição tributária
+type ICMS60 struct {
+	Orig            int             `xml:"orig"`
+	CST             string          `xml:"CST"`
+	VBCSTRet        decimal.Decimal `xml:"vBCSTRet,omitempty"`
+	PST             decimal.Decimal `xml:"pST,omitempty"`
+	VICMSSubstituto decimal.Decimal `xml:"vICMSSubstituto,omitempty"`
+	VICMSSTRet      decimal.Decimal `xml:"vICMSSTRet,omitempty"`
+	VBCFCPSTRet     decimal.Decimal `xml:"vBCFCPSTRet,omitempty"`
+	PFCPSTRet       decimal.Decimal `xml:"pFCPSTRet,omitempty"`
+	VFCPSTRet       decimal.Decimal `xml:"vFCPSTRet,omitempty"`
+	PRedBCEfet      decimal.Decimal `xml:"pRedBCEfet,omitempty"`
+	VBCEfet         decimal.Decimal `xml:"vBCEfet,omitempty"`
+	PICMSEfet       decimal.Decimal `xml:"pICMSEfet,omitempty"`
+	VICMSEfet       decimal.Decimal `xml:"vICMSEfet,omitempty"`
+}
+
+// ICMS70 - com redução de base de cálculo e cobrança do ICMS por substituição tributária
+type ICMS70 struct {
+	Orig          int             `xml:"orig"`
+	CST           string          `xml:"CST"`
+	ModBC         int             `xml:"modBC"`
+	PRedBC        decimal.Decimal `xml:"pRedBC"`
+	VBC           decimal.Decimal `xml:"vBC"`
+	PICMS         decimal.Decimal `xml:"pICMS"`
+	VICMS         decimal.Decimal `xml:"vICMS"`
+	VBCFCP        decimal.Decimal `xml:"vBCFCP,omitempty"`
+	PFCP          decimal.Decimal `xml:"pFCP,omitempty"`
+	VFCP          decimal.Decimal `xml:"vFCP,omitempty"`
+	ModBCST       int             `xml:"modBCST"`
+	PMVAST        decimal.Decimal `xml:"pMVAST,omitempty"`
+	PRedBCST      decimal.Decimal `xml:"pRedBCST,omitempty"`
+	VBCST         decimal.Decimal `xml:"vBCST"`
+	PICMSST       decimal.Decimal `xml:"pICMSST"`
+	VICMSST       decimal.Decimal `xml:"vICMSST"`
+	VBCFCPST      decimal.Decimal `xml:"vBCFCPST,omitempty"`
+	PFCPST        decimal.Decimal `xml:"pFCPST,omitempty"`
+	VFCPST        decimal.Decimal `xml:"vFCPST,omitempty"`
+	VICMSDeson    decimal.Decimal `xml:"vICMSDeson,omitempty"`
+	MotDesICMS    int             `xml:"motDesICMS,omitempty"`
+	IndDeduzDeson int             `xml:"indDeduzDeson,omitempty"`
+}
+
+// ICMS90 - outras. The own operation and ST blocks are optional; ModBC and
+// ModBCST are nil when the respective block is absent.
+type ICMS90 struct {
+	Orig          int             `xml:"orig"`
+	CST           string          `xml:"CST"`
+	ModBC         *int            `xml:"modBC,omitempty"`
+	VBC           decimal.Decimal `xml:"vBC,omitempty"`
+	PRedBC        decimal.Decimal `xml:"pRedBC,omitempty"`
+	PICMS         decimal.Decimal `xml:"pICMS,omitempty"`
+	VICMS         decimal.Decimal `xml:"vICMS,omitempty"`
+	VBCFCP        decimal.Decimal `xml:"vBCFCP,omitempty"`
+	PFCP          decimal.Decimal `xml:"pFCP,omitempty"`
+	VFCP          decimal.Decimal `xml:"vFCP,omitempty"`
+	ModBCST       *int            `xml:"modBCST,omitempty"`
+	PMVAST        decimal.Decimal `xml:"pMVAST,omitempty"`
+	PRedBCST      decimal.Decimal `xml:"pRedBCST,omitempty"`
+	VBCST         decimal.Decimal `xml:"vBCST,omitempty"`
+	PICMSST       decimal.Decimal `xml:"pICMSST,omitempty"`
+	VICMSST       decimal.Decimal `xml:"vICMSST,omitempty"`
+	VBCFCPST      decimal.Decimal `xml:"vBCFCPST,omitempty"`
+	PFCPST        decimal.Decimal `xml:"pFCPST,omitempty"`
+	VFCPST        decimal.Decimal `xml:"vFCPST,omitempty"`
+	VICMSDeson    decimal.Decimal `xml:"vICMSDeson,omitempty"`
+	MotDesICMS    int             `xml:"motDesICMS,omitempty"`
+	IndDeduzDeson int             `xml:"indDeduzDeson,omitempty"`
+}
+
+// ICMSSN101 - Simples Nacional tributada com permissão de crédito
+type ICMSSN101 struct {
+	Orig        int             `xml:"orig"`
+	CSOSN       string          `xml:"CSOSN"`
+	PCredSN     decimal.Decimal `xml:"pCredSN"`
+	VCredICMSSN decimal.Decimal `xml:"vCredICMSSN"`
+}
+
+// ICMSSN102 - Simples Nacional sem permissão de crédito (102), isenção por
+// faixa de receita (103), imune (300) ou não tributada (400)
+type ICMSSN102 struct {
+	Orig  int    `xml:"orig"`
+	CSOSN string `xml:"CSOSN"`
+}
+
+// ICMSSN201 - Simples Nacional com permissão de crédito e cobrança do ICMS por substituição tributária
+type ICMSSN201 struct {
+	Orig        int             `xml:"orig"`
+	CSOSN       string          `xml:"CSOSN"`
+	ModBCST     int             `xml:"modBCST"`
+	PMVAST      decimal.Decimal `xml:"pMVAST,omitempty"`
+	PRedBCST    decimal.Decimal `xml:"pRedBCST,omitempty"`
+	VBCST       decimal.Decimal `xml:"vBCST"`
+	PICMSST     decimal.Decimal `xml:"pICMSST"`
+	VICMSST     decimal.Decimal `xml:"vICMSST"`
+	VBCFCPST    decimal.Decimal `xml:"vBCFCPST,omitempty"`
+	PFCPST      decimal.Decimal `xml:"pFCPST,omitempty"`
+	VFCPST      decimal.Decimal `xml:"vFCPST,omitempty"`
+	PCredSN     decimal.Decimal `xml:"pCredSN"`
+	VCredICMSSN decimal.Decimal `xml:"vCredICMSSN"`
+}
+
+// ICMSSN202 - Simples Nacional sem permissão de crédito (202) ou com isenção
+// por faixa de receita (203) e cobrança do ICMS por substituição tributária
+type ICMSSN202 struct {
+	Orig     int             `xml:"orig"`
+	CSOSN    string          `xml:"CSOSN"`
+	ModBCST  int             `xml:"modBCST"`
+	PMVAST   decimal.Decimal `xml:"pMVAST,omitempty"`
+	PRedBCST decimal.Decimal `xml:"pRedBCST,omitempty"`
+	VBCST    decimal.Decimal `xml:"vBCST"`
+	PICMSST  decimal.Decimal `xml:"pICMSST"`
+	VICMSST  decimal.Decimal `xml:"vICMSST"`
+	VBCFCPST decimal.Decimal `xml:"vBCFCPST,omitempty"`
+	PFCPST   decimal.Decimal `xml:"pFCPST,omitempty"`
+	VFCPST   decimal.Decimal `xml:"vFCPST,omitempty"`
+}
+
+// ICMSSN500 - Simples Nacional com ICMS cobrado anteriormente por substituição tributária
+type ICMSSN500 struct {
+	Orig            int             `xml:"orig"`
+	CSOSN           string          `xml:"CSOSN"`
+	VBCSTRet        decimal.Decimal `xml:"vBCSTRet,omitempty"`
+	PST             decimal.Decimal `xml:"pST,omitempty"`
+	VICMSSubstituto decimal.Decimal `xml:"vICMSSubstituto,omitempty"`
+	VICMSSTRet      decimal.Decimal `xml:"vICMSSTRet,omitempty"`
+	VBCFCPSTRet     decimal.Decimal `xml:"vBCFCPSTRet,omitempty"`
+	PFCPSTRet       decimal.Decimal `xml:"pFCPSTRet,omitempty"`
+	VFCPSTRet       decimal.Decimal `xml:"vFCPSTRet,omitempty"`
+	PRedBCEfet      decimal.Decimal `xml:"pRedBCEfet,omitempty"`
+	VBCEfet         decimal.Decimal `xml:"vBCEfet,omitempty"`
+	PICMSEfet       decimal.Decimal `xml:"pICMSEfet,omitempty"`
+	VICMSEfet       decimal.Decimal `xml:"vICMSEfet,omitempty"`
+}
+
+// ICMSSN900 - Simples Nacional outros. As in ICMS90, the own operation and
+// ST blocks are optional.
+type ICMSSN900 struct {
+	Orig        int             `xml:"orig"`
+	CSOSN       string          `xml:"CSOSN"`
+	ModBC       *int            `xml:"modBC,omitempty"`
+	VBC         decimal.Decimal `xml:"vBC,omitempty"`
+	PRedBC      decimal.Decimal `xml:"pRedBC,omitempty"`
+	PICMS       decimal.Decimal `xml:"pICMS,omitempty"`
+	VICMS       decimal.Decimal `xml:"vICMS,omitempty"`
+	ModBCST     *int            `xml:"modBCST,omitempty"`
+	PMVAST      decimal.Decimal `xml:"pMVAST,omitempty"`
+	PRedBCST    decimal.Decimal `xml:"pRedBCST,omitempty"`
+	VBCST       decimal.Decimal `xml:"vBCST,omitempty"`
+	PICMSST     decimal.Decimal `xml:"pICMSST,omitempty"`
+	VICMSST     decimal.Decimal `xml:"vICMSST,omitempty"`
+	VBCFCPST    decimal.Decimal `xml:"vBCFCPST,omitempty"`
+	PFCPST      decimal.Decimal `xml:"pFCPST,omitempty"`
+	VFCPST      decimal.Decimal `xml:"vFCPST,omitempty"`
+	PCredSN     decimal.Decimal `xml:"pCredSN,omitempty"`
+	VCredICMSSN decimal.Decimal `xml:"vCredICMSSN,omitempty"`
+}
+
+// ICMSUFDest holds the ICMS due to the destination UF in interstate sales to
+// final consumers that are not ICMS contributors (DIFAL, EC 87/2015)
+type ICMSUFDest struct {
+	VBCUFDest      decimal.Decimal `xml:"vBCUFDest"`
+	VBCFCPUFDest   decimal.Decimal `xml:"vBCFCPUFDest,omitempty"`
+	PFCPUFDest     decimal.Decimal `xml:"pFCPUFDest,omitempty"`
+	PICMSUFDest    decimal.Decimal `xml:"pICMSUFDest"`
+	PICMSInter     decimal.Decimal `xml:"pICMSInter"`
+	PICMSInterPart decimal.Decimal `xml:"pICMSInterPart"`
+	VFCPUFDest     decimal.Decimal `xml:"vFCPUFDest,omitempty"`
+	VICMSUFDest    decimal.Decimal `xml:"vICMSUFDest"`
+	VICMSUFRemet   decimal.Decimal `xml:"vICMSUFRemet"`
+}
+
+// IPI holds the Imposto sobre Produtos Industrializados of an item, with
+// either IPITrib or IPINT set
+type IPI struct {
+	CNPJProd string   `xml:"CNPJProd,omitempty"`
+	CSelo    string   `xml:"cSelo,omitempty"`
+	QSelo    int      `xml:"qSelo,omitempty"`
+	CEnq     string   `xml:"cEnq"`
+	IPITrib  *IPITrib `xml:"IPITrib,omitempty"`
+	IPINT    *IPINT   `xml:"IPINT,omitempty"`
+}
+
+// IPITrib - IPI tributado (CST 00, 49, 50 e 99), by percentage (vBC and
+// pIPI) or per unit (qUnid and vUnid)
+type IPITrib struct {
+	CST   string          `xml:"CST"`
+	VBC   decimal.Decimal `xml:"vBC,omitempty"`
+	PIPI  decimal.Decimal `xml:"pIPI,omitempty"`
+	QUnid decimal.Decimal `xml:"qUnid,omitempty"`
+	VUnid decimal.Decimal `xml:"vUnid,omitempty"`
+	VIPI  decimal.Decimal `xml:"vIPI"`
+}
+
+// IPINT - IPI não tributado (CST 01 a 05 e 51 a 55)
+type IPINT struct {
+	CST string `xml:"CST"`
+}
+
+// II holds the Imposto de Importação of an item
+type II struct {
+	VBC      decimal.Decimal `xml:"vBC"`
+	VDespAdu decimal.Decimal `xml:"vDespAdu"`
+	VII      decimal.Decimal `xml:"vII"`
+	VIOF     decimal.Decimal `xml:"vIOF"`
+}
+
+// PIS holds exactly one of the PIS groups, chosen by CST
+type PIS struct {
+	PISAliq *PISAliq `xml:"PISAliq,omitempty"`
+	PISQtde *PISQtde `xml:"PISQtde,omitempty"`
+	PISNT   *PISNT   `xml:"PISNT,omitempty"`
+	PISOutr *PISOutr `xml:"PISOutr,omitempty"`
+}
+
+// PISAliq - tributado pela alíquota (CST 01 e 02)
+type PISAliq struct {
+	CST  string          `xml:"CST"`
+	VBC  decimal.Decimal `xml:"vBC"`
+	PPIS decimal.Decimal `xml:"pPIS"`
+	VPIS decimal.Decimal `xml:"vPIS"`
+}
+
+// PISQtde - tributado por quantidade (CST 03)
+type PISQtde struct {
+	CST       string          `xml:"CST"`
+	QBCProd   decimal.Decimal `xml:"qBCProd"`
+	VAliqProd decimal.Decimal `xml:"vAliqProd"`
+	VPIS      decimal.Decimal `xml:"vPIS"`
+}
+
+// PISNT - não tributado (CST 04 a 09)
+type PISNT struct {
+	CST string `xml:"CST"`
+}
+
+// PISOutr - outras operações (CST 49 a 99), by percentage or per unit
+type PISOutr struct {
+	CST       string          `xml:"CST"`
+	VBC       decimal.Decimal `xml:"vBC,omitempty"`
+	PPIS      decimal.Decimal `xml:"pPIS,omitempty"`
+	QBCProd   decimal.Decimal `xml:"qBCProd,omitempty"`
+	VAliqProd decimal.Decimal `xml:"vAliqProd,omitempty"`
+	VPIS      decimal.Decimal `xml:"vPIS"`
+}
+
+// PISST - PIS substituição tributária
+type PISST struct {
+	VBC          decimal.Decimal `xml:"vBC,omitempty"`
+	PPIS         decimal.Decimal `xml:"pPIS,omitempty"`
+	QBCProd      decimal.Decimal `xml:"qBCProd,omitempty"`
+	VAliqProd    decimal.Decimal `xml:"vAliqProd,omitempty"`
+	VPIS         decimal.Decimal `xml:"vPIS"`
+	IndSomaPISST int             `xml:"indSomaPISST,omitempty"`
+}
+
+// COFINS holds exactly one of the COFINS groups, chosen by CST
+type COFINS struct {
+	COFINSAliq *COFINSAliq `xml:"COFINSAliq,omitempty"`
+	COFINSQtde *COFINSQtde `xml:"COFINSQtde,omitempty"`
+	COFINSNT   *COFINSNT   `xml:"COFINSNT,omitempty"`
+	COFINSOutr *COFINSOutr `xml:"COFINSOutr,omitempty"`
+}
+
+// COFINSAliq - tributado pela alíquota (CST 01 e 02)
+type COFINSAliq struct {
+	CST     string          `xml:"CST"`
+	VBC     decimal.Decimal `xml:"vBC"`
+	PCOFINS decimal.Decimal `xml:"pCOFINS"`
+	VCOFINS decimal.Decimal `xml:"vCOFINS"`
+}
+
+// COFINSQtde - tributado por quantidade (CST 03)
+type COFINSQtde struct {
+	CST       string          `xml:"CST"`
+	QBCProd   decimal.Decimal `xml:"qBCProd"`
+	VAliqProd decimal.Decimal `xml:"vAliqProd"`
+	VCOFINS   decimal.Decimal `xml:"vCOFINS"`
+}
+
+// COFINSNT - não tributado (CST 04 a 09)
+type COFINSNT struct {
+	CST string `xml:"CST"`
+}
+
+// COFINSOutr - outras operações (CST 49 a 99), by percentage or per unit
+type COFINSOutr struct {
+	CST       string          `xml:"CST"`
+	VBC       decimal.Decimal `xml:"vBC,omitempty"`
+	PCOFINS   decimal.Decimal `xml:"pCOFINS,omitempty"`
+	QBCProd   decimal.Decimal `xml:"qBCProd,omitempty"`
+	VAliqProd decimal.Decimal `xml:"vAliqProd,omitempty"`
+	VCOFINS   decimal.Decimal `xml:"vCOFINS"`
+}
+
+// COFINSST - COFINS substituição tributária
+type COFINSST struct {
+	VBC             decimal.Decimal `xml:"vBC,omitempty"`
+	PCOFINS         decimal.Decimal `xml:"pCOFINS,omitempty"`
+	QBCProd         decimal.Decimal `xml:"qBCProd,omitempty"`
+	VAliqProd       decimal.Decimal `xml:"vAliqProd,omitempty"`
+	VCOFINS         decimal.Decimal `xml:"vCOFINS"`
+	IndSomaCOFINSST int             `xml:"indSomaCOFINSST,omitempty"`
+}
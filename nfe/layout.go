@@ -0,0 +1,66 @@
+package nfe
+
+// Layout identifies the schema package (PL) of the NFe 4.00 layout, so
+// documents before and after the tax reform can be produced by the same code
+type Layout int
+
+const (
+	// LayoutPL009 is the layout before the tax reform, without IBS, CBS and IS
+	LayoutPL009 Layout = iota
+	// LayoutPL010 is the layout of NT 2025.002 with the IBS, CBS and IS groups
+	LayoutPL010
+)
+
+// String returns the name of the schema package of the layout
+func (l Layout) String() string {
+	switch l {
+	case LayoutPL009:
+		return "PL_009_V4"
+	case LayoutPL010:
+		return "PL_010_V1"
+	default:
+		return "Unknown"
+	}
+}
+
+// SupportsReform reports whether the layout has the IBS, CBS and IS groups
+func (l Layout) SupportsReform() bool {
+	return l >= LayoutPL010
+}
+
+// ApplyLayout removes from the items the groups the layout does not define,
+// so items computed with the reform taxes can still be sent in PL_009
+func ApplyLayout(items []Item, layout Layout) {
+	if layout.SupportsReform() {
+		return
+	}
+	for i := range items {
+		items[i].Imposto.IS = nil
+		items[i].Imposto.IBSCBS = nil
+	}
+}
+
+// CalculateTotal computes the total group of the layout. ICMSTot is always
+// computed; in LayoutPL010 the ISTot and IBSCBSTot groups are added when the
+// items have them, with vNFTot = vNF + vIS + vIBS + vCBS.
+func CalculateTotal(items []Item, layout Layout) Total {
+	t := Total{ICMSTot: CalculateTotals(items)}
+	if !layout.SupportsReform() {
+		return t
+	}
+
+	t.IBSCBSTot, t.ISTot = CalculateReformTotals(items)
+	if t.IBSCBSTot == nil && t.ISTot == nil {
+		return t
+	}
+
+	vNFTot := t.ICMSTot.VNF
+	if t.ISTot != nil {
+		vNFTot = vNFTot.Add(t.ISTot.VIS)
+	}
+	if t.IBSCBSTot != nil {
+		vNFTot = vNFTot.Add(t.IBSCBSTot.GIBS.VIBS).Add(t.IBSCBSTot.GCBS.VCBS)
+	}
+	t.VNFTot = total(vNFTot)
+	return t
+}
@@ -0,0 +1,44 @@
+package nfe
+
+import "testing"
+
+func TestLayout(t *testing.T) {
+	if LayoutPL009.String() != "PL_009_V4" || LayoutPL010.String() != "PL_010_V1" {
+		t.Errorf("unexpected layout names %s, %s", LayoutPL009, LayoutPL010)
+	}
+	if LayoutPL009.SupportsReform() || !LayoutPL010.SupportsReform() {
+		t.Error("only PL_010 should support the reform groups")
+	}
+}
+
+func TestCalculateTotal(t *testing.T) {
+	items := reformTestItems()
+
+	post := CalculateTotal(items, LayoutPL010)
+	if post.IBSCBSTot == nil || post.ISTot == nil {
+		t.Fatalf("PL_010 total should have the reform groups, got %+v", post)
+	}
+	// vNF 1398.00 + vIS 20.00 + vIBS 0.62 + vCBS 5.58
+	if expected := money(1424.2); post.VNFTot != expected {
+		t.Errorf("vNFTot = %s, expected %s", post.VNFTot, expected)
+	}
+	if post.ICMSTot != CalculateTotals(items) {
+		t.Error("ICMSTot should not change with the layout")
+	}
+
+	pre := CalculateTotal(items, LayoutPL009)
+	if pre.IBSCBSTot != nil || pre.ISTot != nil || pre.VNFTot.IsSet() {
+		t.Errorf("PL_009 total should not have the reform groups, got %+v", pre)
+	}
+
+	ApplyLayout(items, LayoutPL009)
+	for _, item := range items {
+		if item.Imposto.IS != nil || item.Imposto.IBSCBS != nil {
+			t.Errorf("ApplyLayout(PL_009) should remove the reform groups of item %d", item.NItem)
+		}
+	}
+
+	if total := CalculateTotal(totalsTestItems(), LayoutPL010); total.VNFTot.IsSet() {
+		t.Errorf("vNFTot should be omitted without reform groups, got %s", total.VNFTot)
+	}
+}
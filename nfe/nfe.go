@@ -10,7 +10,13 @@ package nfe
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/types"
+	"github.com/adrianodrix/sped-nfe-go/utils"
 )
 
 // Version represents the current version of the sped-nfe-go package
@@ -45,7 +51,11 @@ type Config struct {
 
 // Client represents the main NFe client
 type Client struct {
-	config Config
+	config    Config
+	requester Requester
+
+	// receiptInterval is the minimum wait between receipt queries of an asynchronous lote
+	receiptInterval time.Duration
 }
 
 // New creates a new NFe client with the given configuration
@@ -55,10 +65,17 @@ func New(config Config) (*Client, error) {
 	}
 
 	return &Client{
-		config: config,
+		config:          config,
+		requester:       NewSOAPRequester(time.Duration(config.Timeout) * time.Second),
+		receiptInterval: defaultReceiptInterval,
 	}, nil
 }
 
+// SetRequester replaces the transport used to reach SEFAZ webservices
+func (c *Client) SetRequester(requester Requester) {
+	c.requester = requester
+}
+
 // GetVersion returns the current package version
 func GetVersion() string {
 	return Version
@@ -74,18 +91,65 @@ func (c *Client) GetConfig() Config {
 	return c.config
 }
 
-// GenerateAccessKey generates a 44-digit access key for NFe
-// This is a simplified version - will be enhanced later
-func (c *Client) GenerateAccessKey(cnpj string, modelo, serie, numero int, tipoEmissao int) string {
-	// Simplified implementation for now
-	uf := int(c.config.UF)
-	year := time.Now().Year() % 100 // last 2 digits
-	month := int(time.Now().Month())
-
-	// Format: UF + YYMM + CNPJ + MODELO + SERIE + NUMERO + TIPO + COD + DV
-	// This is a placeholder implementation
-	return fmt.Sprintf("%02d%02d%02d%s%02d%03d%09d%d%08d%d",
-		uf, year, month, cnpj[:14], modelo, serie, numero, tipoEmissao, 12345678, 9)
+// GenerateAccessKey builds the access key of a document from its ide (cUF,
+// dhEmi, mod, serie, nNF and tpEmis) and the CNPJ or CPF of the emitter. The
+// cNF already in ide is kept, otherwise a random one is generated; cNF, cDV
+// and the infNFe Id are written back into the document. When cUF is not set
+// the UF of the client configuration is used.
+func (c *Client) GenerateAccessKey(nfe *NFe) (string, error) {
+	if nfe == nil {
+		return "", errors.NewValidationError("NFe cannot be nil", "NFe", nil)
+	}
+	ide := &nfe.InfNFe.Ide
+
+	uf := types.UF(ide.CUF)
+	if ide.CUF == 0 {
+		uf = types.UF(c.config.UF)
+	}
+
+	dhEmi, err := time.Parse(time.RFC3339, strings.TrimSpace(ide.DhEmi))
+	if err != nil {
+		return "", errors.NewValidationError("dhEmi must be a date and time with offset (AAAA-MM-DDThh:mm:ssTZD)", "dhEmi", ide.DhEmi)
+	}
+
+	document := nfe.InfNFe.Emit.CNPJ
+	if document == "" {
+		document = nfe.InfNFe.Emit.CPF
+	}
+	if document == "" {
+		return "", errors.NewValidationError("emitter CNPJ or CPF is required", "emit", "")
+	}
+
+	components := utils.NFEKeyComponents{
+		UF:       uf,
+		DateTime: dhEmi,
+		CNPJ:     document,
+		Model:    ide.Mod,
+		Series:   ide.Serie,
+		Number:   ide.NNF,
+		EmitType: types.TipoEmissao(ide.TpEmis),
+	}
+	if cnf := strings.TrimSpace(ide.CNF); cnf != "" {
+		code, err := strconv.Atoi(cnf)
+		if err != nil || len(cnf) != 8 {
+			return "", errors.NewValidationError("cNF must have 8 digits", "cNF", ide.CNF)
+		}
+		if code == ide.NNF {
+			return "", errors.NewValidationError("cNF cannot be equal to nNF", "cNF", ide.CNF)
+		}
+		components.Code = &code
+	}
+
+	key, err := utils.GenerateAccessKey(components)
+	if err != nil {
+		return "", err
+	}
+
+	ide.CUF = int(uf)
+	ide.CNF = key[35:43]
+	ide.CDV = int(key[43] - '0')
+	nfe.InfNFe.ID = "NFe" + key
+	return key, nil
 }
 
 // ValidateConfig validates the client configuration
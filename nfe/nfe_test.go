@@ -2,6 +2,9 @@ package nfe
 
 import (
 	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/types"
+	"github.com/adrianodrix/sped-nfe-go/utils"
 )
 
 func TestNew(t *testing.T) {
@@ -129,31 +132,106 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+func accessKeyTestNFe(cnpj, cpf, cnf string) *NFe {
+	return &NFe{InfNFe: InfNFe{
+		Ide: Ide{
+			CUF:    35,
+			CNF:    cnf,
+			Mod:    types.ModeloNFe55,
+			Serie:  1,
+			NNF:    1234,
+			DhEmi:  "2024-05-10T23:30:00-03:00",
+			TpEmis: 1,
+		},
+		Emit: Emit{CNPJ: cnpj, CPF: cpf},
+	}}
+}
+
 func TestGenerateAccessKey(t *testing.T) {
-	config := Config{
-		Environment: Homologation,
-		UF:          SP,
-		Timeout:     30,
+	client, _ := New(Config{Environment: Homologation, UF: SP, Timeout: 30})
+
+	tests := []struct {
+		name string
+		nfe  *NFe
+		want string
+	}{
+		{"CNPJ", accessKeyTestNFe("11.222.333/0001-81", "", "12345678"), "35240511222333000181550010000012341123456789"},
+		{"CPF", accessKeyTestNFe("", "123.456.789-09", "12345678"), "35240500012345678909550010000012341123456782"},
+		{"alphanumeric CNPJ", accessKeyTestNFe("12.ABC.345/01DE-35", "", "12345678"), "35240512ABC34501DE35550010000012341123456780"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := client.GenerateAccessKey(tt.nfe)
+			if err != nil {
+				t.Fatalf("GenerateAccessKey failed: %v", err)
+			}
+			if key != tt.want {
+				t.Errorf("expected key %s, got %s", tt.want, key)
+			}
+			ide := tt.nfe.InfNFe.Ide
+			if ide.CNF != "12345678" || ide.CDV != int(tt.want[43]-'0') {
+				t.Errorf("expected cNF 12345678 and cDV %c, got %s and %d", tt.want[43], ide.CNF, ide.CDV)
+			}
+			if tt.nfe.Chave() != key {
+				t.Errorf("expected infNFe Id NFe%s, got %s", key, tt.nfe.InfNFe.ID)
+			}
+			if err := utils.ValidateAccessKey(key); err != nil {
+				t.Errorf("generated key is invalid: %v", err)
+			}
+		})
 	}
+}
 
-	client, _ := New(config)
+func TestGenerateAccessKeyRandomCode(t *testing.T) {
+	client, _ := New(Config{Environment: Homologation, UF: SP})
 
-	cnpj := "12345678000190"
-	modelo := 55
-	serie := 1
-	numero := 123456
-	tipoEmissao := 1
+	nfe := accessKeyTestNFe("11222333000181", "", "")
+	nfe.InfNFe.Ide.CUF = 0
+	key, err := client.GenerateAccessKey(nfe)
+	if err != nil {
+		t.Fatalf("GenerateAccessKey failed: %v", err)
+	}
+
+	ide := nfe.InfNFe.Ide
+	if len(ide.CNF) != 8 || ide.CNF == "000001234" {
+		t.Errorf("expected a random 8 digit cNF, got %q", ide.CNF)
+	}
+	if key[35:43] != ide.CNF || ide.CUF != 35 || key[:6] != "352405" {
+		t.Errorf("key %s does not match ide %+v", key, ide)
+	}
+}
 
-	accessKey := client.GenerateAccessKey(cnpj, modelo, serie, numero, tipoEmissao)
+func TestGenerateAccessKeyErrors(t *testing.T) {
+	client, _ := New(Config{Environment: Homologation, UF: SP})
 
-	// Access key should be 44 characters
-	if len(accessKey) != 44 {
-		t.Errorf("Expected access key length 44, got %d", len(accessKey))
+	tests := []struct {
+		name   string
+		modify func(*NFe)
+	}{
+		{"short CNPJ", func(n *NFe) { n.InfNFe.Emit.CNPJ = "123" }},
+		{"no emitter", func(n *NFe) { n.InfNFe.Emit.CNPJ = "" }},
+		{"empty dhEmi", func(n *NFe) { n.InfNFe.Ide.DhEmi = "" }},
+		{"dhEmi without offset", func(n *NFe) { n.InfNFe.Ide.DhEmi = "2024-05-10T10:00:00" }},
+		{"cNF equal to nNF", func(n *NFe) { n.InfNFe.Ide.CNF = "00001234" }},
+		{"short cNF", func(n *NFe) { n.InfNFe.Ide.CNF = "1234" }},
+		{"invalid model", func(n *NFe) { n.InfNFe.Ide.Mod = 0 }},
+		{"invalid tpEmis", func(n *NFe) { n.InfNFe.Ide.TpEmis = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nfe := accessKeyTestNFe("11222333000181", "", "12345678")
+			tt.modify(nfe)
+			if _, err := client.GenerateAccessKey(nfe); err == nil {
+				t.Error("expected error")
+			}
+			if nfe.InfNFe.ID != "" {
+				t.Errorf("document should not be changed on error, got Id %s", nfe.InfNFe.ID)
+			}
+		})
 	}
 
-	// Should start with UF code (35 for SP)
-	if accessKey[:2] != "35" {
-		t.Errorf("Expected access key to start with '35', got '%s'", accessKey[:2])
+	if _, err := client.GenerateAccessKey(nil); err == nil {
+		t.Error("expected error for nil NFe")
 	}
 }
 
@@ -175,6 +253,8 @@ func BenchmarkGenerateAccessKey(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		client.GenerateAccessKey("12345678000190", 55, 1, i+1, 1)
+		nfe := accessKeyTestNFe("11222333000181", "", "")
+		nfe.InfNFe.Ide.NNF = i + 1
+		_, _ = client.GenerateAccessKey(nfe)
 	}
 }
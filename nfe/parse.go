@@ -0,0 +1,193 @@
+package nfe
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+// Document is a parsed NFe XML of any of the supported kinds. NFe is set
+// for NFe and nfeProc documents, Proc for nfeProc and Summary for resNFe.
+type Document struct {
+	NFe     *NFe
+	Proc    *NFeProc
+	Summary *ResNFe
+}
+
+// ParseDocument parses an NFe, nfeProc or resNFe XML, detected by its root
+// element. Namespace prefixes, indentation and whitespace around values are
+// accepted, as well as documents encoded in ISO-8859-1.
+func ParseDocument(data []byte) (*Document, error) {
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "NFe":
+		nfe, err := ParseNFe(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{NFe: nfe}, nil
+	case "nfeProc":
+		proc, err := ParseNFeProc(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{NFe: &proc.NFe, Proc: proc}, nil
+	case "resNFe":
+		summary, err := ParseResNFe(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Summary: summary}, nil
+	}
+	return nil, errors.NewValidationError("unsupported document", "root", root)
+}
+
+// ParseNFe parses an NFe document; for an nfeProc the NFe inside it is returned
+func ParseNFe(data []byte) (*NFe, error) {
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+	if root == "nfeProc" {
+		proc, err := ParseNFeProc(data)
+		if err != nil {
+			return nil, err
+		}
+		return &proc.NFe, nil
+	}
+
+	var nfe NFe
+	if err := decodeDocument(data, "NFe", &nfe); err != nil {
+		return nil, err
+	}
+	if err := validateParsedNFe(&nfe); err != nil {
+		return nil, err
+	}
+	return &nfe, nil
+}
+
+// ParseNFeProc parses an authorized NFe with its protocol
+func ParseNFeProc(data []byte) (*NFeProc, error) {
+	var proc NFeProc
+	if err := decodeDocument(data, "nfeProc", &proc); err != nil {
+		return nil, err
+	}
+	if err := validateParsedNFe(&proc.NFe); err != nil {
+		return nil, err
+	}
+	if raws := rawElements(data, "protNFe"); len(raws) > 0 {
+		proc.ProtNFe.Raw = raws[0]
+	}
+	return &proc, nil
+}
+
+// ParseResNFe parses the summary of an NFe received through DistribuicaoDFe
+func ParseResNFe(data []byte) (*ResNFe, error) {
+	var summary ResNFe
+	if err := decodeDocument(data, "resNFe", &summary); err != nil {
+		return nil, err
+	}
+	if len(summary.ChNFe) != 44 {
+		return nil, errors.NewValidationError("resNFe does not hold a 44 character access key", "chNFe", summary.ChNFe)
+	}
+	return &summary, nil
+}
+
+// rootElement returns the local name of the root element of data
+func rootElement(data []byte) (string, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "", errors.NewValidationError("NFe XML cannot be empty", "xml", "")
+	}
+
+	decoder := newDecoder(data)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", errors.NewXMLError("failed to parse NFe XML", "xml", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// decodeDocument decodes data into v, whose root element is local, and trims
+// the whitespace around its text values
+func decodeDocument(data []byte, local string, v interface{}) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return errors.NewValidationError("NFe XML cannot be empty", local, "")
+	}
+	if err := newDecoder(data).Decode(v); err != nil {
+		return errors.NewXMLError(fmt.Sprintf("failed to parse %s", local), local, err)
+	}
+	trimStrings(reflect.ValueOf(v))
+	return nil
+}
+
+// newDecoder creates a decoder that accepts the ISO-8859-1 and Windows-1252
+// encodings still used by some issuers
+func newDecoder(data []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(label) {
+		case "iso-8859-1", "iso8859-1", "latin1":
+			return charmap.ISO8859_1.NewDecoder().Reader(input), nil
+		case "windows-1252", "cp1252":
+			return charmap.Windows1252.NewDecoder().Reader(input), nil
+		}
+		return nil, fmt.Errorf("unsupported encoding %s", label)
+	}
+	return decoder
+}
+
+// validateParsedNFe checks the layout version and the access key
+func validateParsedNFe(nfe *NFe) error {
+	switch nfe.Versao() {
+	case types.Versao310, types.Versao400:
+	default:
+		return errors.NewValidationError("unsupported NFe layout version", "versao", nfe.InfNFe.Versao)
+	}
+	if len(nfe.Chave()) != 44 {
+		return errors.NewValidationError("infNFe Id does not hold a 44 character access key", "Id", nfe.InfNFe.ID)
+	}
+	return nil
+}
+
+// trimStrings removes the whitespace around every string reachable from v
+func trimStrings(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			trimStrings(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Field(i); field.CanSet() {
+				trimStrings(field)
+			}
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			trimStrings(v.Index(i))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(strings.TrimSpace(v.String()))
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package nfe
+
+import (
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+func readTestData(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+	return data
+}
+
+func TestParseNFeProc(t *testing.T) {
+	proc, err := ParseNFeProc(readTestData(t, "nfeProc_v400.xml"))
+	if err != nil {
+		t.Fatalf("ParseNFeProc should not return error, got: %v", err)
+	}
+
+	nfe := proc.NFe
+	if nfe.Chave() != "35240511222333000181550010000012341123456789" || nfe.Versao() != types.Versao400 {
+		t.Errorf("unexpected chave %s or version %s", nfe.Chave(), nfe.Versao())
+	}
+
+	inf := nfe.InfNFe
+	if inf.Ide.Mod != types.ModeloNFe55 || inf.Ide.NNF != 1234 || inf.Ide.TpAmb != types.TaHomologacao || inf.Ide.NatOp != "VENDA DE MERCADORIA" {
+		t.Errorf("unexpected ide %+v", inf.Ide)
+	}
+	if inf.Emit.XNome != "FORNECEDOR TESTE LTDA" {
+		t.Errorf("xNome = %q, expected the whitespace trimmed", inf.Emit.XNome)
+	}
+	if inf.Emit.EnderEmit.XMun != "SAO PAULO" || inf.Dest == nil || inf.Dest.CNPJ != "44555666000109" {
+		t.Errorf("unexpected emit %+v or dest %+v", inf.Emit, inf.Dest)
+	}
+
+	if len(inf.Det) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(inf.Det))
+	}
+	item := inf.Det[0]
+	if item.NItem != 1 || item.Prod.NCM != "73181500" || item.Prod.VUnCom != decimal.MustParse("1.5000000000") {
+		t.Errorf("unexpected prod %+v", item.Prod)
+	}
+	icms := item.Imposto.ICMS.ICMS00
+	if icms == nil || icms.VBC != decimal.Money(150) || icms.VICMS != decimal.Money(27) {
+		t.Errorf("unexpected ICMS00 %+v", icms)
+	}
+	if item.Imposto.PIS.PISAliq == nil || item.Imposto.COFINS.COFINSAliq.VCOFINS != decimal.Money(11.4) {
+		t.Errorf("unexpected PIS/COFINS %+v %+v", item.Imposto.PIS, item.Imposto.COFINS)
+	}
+
+	if inf.Total.ICMSTot.VNF != decimal.Money(150) {
+		t.Errorf("vNF = %s, expected 150.00", inf.Total.ICMSTot.VNF)
+	}
+	if mismatches := ValidateTotals(inf.Total.ICMSTot, inf.Det); len(mismatches) != 0 {
+		t.Errorf("parsed totals should match the items, got %v", mismatches)
+	}
+	if len(inf.Transp.Vol) != 1 || inf.Transp.Vol[0].PesoB.String() != "11.000" {
+		t.Errorf("unexpected volumes %+v", inf.Transp.Vol)
+	}
+	if inf.Cobr == nil || len(inf.Cobr.Dup) != 1 || inf.Cobr.Dup[0].DVenc != "2024-06-10" {
+		t.Errorf("unexpected cobr %+v", inf.Cobr)
+	}
+	if inf.Pag == nil || len(inf.Pag.DetPag) != 1 || inf.Pag.DetPag[0].TPag != "15" || *inf.Pag.DetPag[0].IndPag != 1 {
+		t.Errorf("unexpected pag %+v", inf.Pag)
+	}
+	if inf.InfAdic == nil || inf.InfAdic.InfCpl != "PEDIDO 4567" {
+		t.Errorf("unexpected infAdic %+v", inf.InfAdic)
+	}
+
+	if nfe.Signature == nil || nfe.Signature.SignedInfo.Reference.DigestValue != "q1Wn3hZ8mN0Ptf6VXbKQ1+3cR0s=" {
+		t.Errorf("unexpected signature %+v", nfe.Signature)
+	}
+	if proc.ProtNFe.InfProt.NProt != "135240000012345" || proc.ProtNFe.InfProt.CStat != 100 {
+		t.Errorf("unexpected protocol %+v", proc.ProtNFe.InfProt)
+	}
+	if !strings.Contains(string(proc.ProtNFe.Raw), "135240000012345") {
+		t.Error("the raw protocol should be kept")
+	}
+}
+
+func TestParseNFe310(t *testing.T) {
+	nfe, err := ParseNFe(readTestData(t, "nfe_v310.xml"))
+	if err != nil {
+		t.Fatalf("ParseNFe should not return error, got: %v", err)
+	}
+
+	inf := nfe.InfNFe
+	if nfe.Versao() != types.Versao310 || inf.Ide.IndPag == nil || *inf.Ide.IndPag != 0 {
+		t.Errorf("unexpected version %s or indPag %v", nfe.Versao(), inf.Ide.IndPag)
+	}
+	if inf.Emit.XNome != "INDÚSTRIA MINEIRA LTDA" || inf.Emit.EnderEmit.XLgr != "RUA SÃO JOÃO" {
+		t.Errorf("ISO-8859-1 text not decoded: %q, %q", inf.Emit.XNome, inf.Emit.EnderEmit.XLgr)
+	}
+	if ipi := inf.Det[0].Imposto.IPI; ipi == nil || ipi.IPITrib.VIPI != decimal.Money(105) {
+		t.Errorf("unexpected IPI %+v", ipi)
+	}
+	if inf.Total.ICMSTot.VFCP.IsSet() {
+		t.Error("fields absent from layout 3.10 should stay unset")
+	}
+
+	if inf.Pag == nil || len(inf.Pag.DetPag) != 2 {
+		t.Fatalf("the repeated 3.10 pag groups should be read as detPag, got %+v", inf.Pag)
+	}
+	if inf.Pag.DetPag[0].TPag != "01" || inf.Pag.DetPag[1].VPag != decimal.Money(1205) {
+		t.Errorf("unexpected payments %+v", inf.Pag.DetPag)
+	}
+}
+
+func TestParseResNFe(t *testing.T) {
+	summary, err := ParseResNFe(readTestData(t, "resNFe.xml"))
+	if err != nil {
+		t.Fatalf("ParseResNFe should not return error, got: %v", err)
+	}
+	if summary.ChNFe != "35240511222333000181550010000012341123456789" || summary.VNF != decimal.Money(150) || summary.CSitNFe != 1 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		file               string
+		nfe, proc, summary bool
+	}{
+		{"nfeProc_v400.xml", true, true, false},
+		{"nfe_v310.xml", true, false, false},
+		{"nfe_signed.xml", true, false, false},
+		{"resNFe.xml", false, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			doc, err := ParseDocument(readTestData(t, test.file))
+			if err != nil {
+				t.Fatalf("ParseDocument should not return error, got: %v", err)
+			}
+			if (doc.NFe != nil) != test.nfe || (doc.Proc != nil) != test.proc || (doc.Summary != nil) != test.summary {
+				t.Errorf("unexpected document kinds %+v", doc)
+			}
+		})
+	}
+
+	nfe, err := ParseNFe(readTestData(t, "nfeProc_v400.xml"))
+	if err != nil || nfe.Chave() != "35240511222333000181550010000012341123456789" {
+		t.Errorf("ParseNFe should return the NFe inside an nfeProc, got %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	valid := string(readTestData(t, "nfe_signed.xml"))
+	invalid := map[string]string{
+		"empty":        "  ",
+		"malformed":    "<NFe><infNFe>",
+		"unsupported":  `<CTe xmlns="http://www.portalfiscal.inf.br/cte"/>`,
+		"version 2.00": strings.Replace(valid, `versao="4.00"`, `versao="2.00"`, 1),
+		"short chave":  strings.Replace(valid, `Id="NFe33240511222333000181550010000012341123456784"`, `Id="NFe3324"`, 1),
+		"short resNFe": `<resNFe versao="1.01"><chNFe>123</chNFe></resNFe>`,
+	}
+	for name, data := range invalid {
+		if _, err := ParseDocument([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNFeMarshalRoundTrip(t *testing.T) {
+	nfe, err := ParseNFe(readTestData(t, "nfeProc_v400.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := xml.Marshal(nfe)
+	if err != nil {
+		t.Fatalf("Marshal should not return error, got: %v", err)
+	}
+	parsed, err := ParseNFe(data)
+	if err != nil {
+		t.Fatalf("ParseNFe of the marshalled document should not return error, got: %v", err)
+	}
+	if parsed.InfNFe.Total.ICMSTot != nfe.InfNFe.Total.ICMSTot || parsed.InfNFe.Emit.XNome != nfe.InfNFe.Emit.XNome {
+		t.Error("marshalled document should parse back to the same values")
+	}
+}
@@ -0,0 +1,37 @@
+package nfe
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adrianodrix/sped-nfe-go/utils"
+	"github.com/adrianodrix/sped-nfe-go/webservices"
+)
+
+// Query queries the situation of an NFe by its access key (NfeConsultaProtocolo).
+// A response is returned for any cStat; callers inspect CStat and ProtNFe.
+func (c *Client) Query(ctx context.Context, chave string) (*RetConsSitNFe, error) {
+	if err := utils.ValidateAccessKey(chave); err != nil {
+		return nil, err
+	}
+
+	service, err := c.service(modeloFromChave(chave), webservices.ServiceConsultaProtocolo)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf(`<consSitNFe xmlns="%s" versao="4.00"><tpAmb>%d</tpAmb><xServ>CONSULTAR</xServ><chNFe>%s</chNFe></consSitNFe>`,
+		NFeNamespace, int(c.config.Environment), chave)
+
+	response, err := c.requester.Send(ctx, service, message)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret RetConsSitNFe
+	if err := decodeResponse(response, "retConsSitNFe", &ret); err != nil {
+		return nil, err
+	}
+	attachRaw(response, ret.ProtNFe)
+	return &ret, nil
+}
@@ -0,0 +1,185 @@
+package nfe
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/soap"
+	"github.com/adrianodrix/sped-nfe-go/types"
+	"github.com/adrianodrix/sped-nfe-go/webservices"
+)
+
+// Namespaces used in SEFAZ messages
+const (
+	NFeNamespace  = "http://www.portalfiscal.inf.br/nfe"
+	WSDLNamespace = webservices.WSDLNamespace
+)
+
+// Requester delivers a SEFAZ message to a webservice and returns the response
+// message extracted from the SOAP envelope
+type Requester interface {
+	Send(ctx context.Context, service *webservices.Service, message string) (string, error)
+}
+
+// SOAPRequester is the default Requester, sending messages in the SOAP 1.2
+// SEFAZ envelope through a soap.SOAPClient
+type SOAPRequester struct {
+	Client *soap.SOAPClient
+}
+
+// NewSOAPRequester creates a requester using a SOAP client with the given timeout
+func NewSOAPRequester(timeout time.Duration) *SOAPRequester {
+	config := soap.DefaultConfig()
+	if timeout > 0 {
+		config.Timeout = timeout
+	}
+	return &SOAPRequester{Client: soap.NewSOAPClient(config)}
+}
+
+// Send implements Requester
+func (r *SOAPRequester) Send(ctx context.Context, service *webservices.Service, message string) (string, error) {
+	if service == nil || service.URL == "" {
+		return "", errors.NewConfigError("webservice URL not configured", "service", service)
+	}
+
+	request, err := soap.CreateSEFAZSOAPRequest(service, message)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := r.Client.Call(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	return soap.ExtractBodyContent(response.Body)
+}
+
+// ProtNFe is the authorization protocol of an NFe
+type ProtNFe struct {
+	XMLName xml.Name `xml:"protNFe"`
+	Versao  string   `xml:"versao,attr"`
+	InfProt InfProt  `xml:"infProt"`
+
+	// Raw holds the protocol exactly as returned by SEFAZ, used to build nfeProc
+	Raw []byte `xml:"-"`
+}
+
+// InfProt holds the authorization protocol data
+type InfProt struct {
+	ID       string             `xml:"Id,attr,omitempty"`
+	TpAmb    types.TipoAmbiente `xml:"tpAmb"`
+	VerAplic string             `xml:"verAplic"`
+	ChNFe    string             `xml:"chNFe"`
+	DhRecbto string             `xml:"dhRecbto"`
+	NProt    string             `xml:"nProt,omitempty"`
+	DigVal   string             `xml:"digVal,omitempty"`
+	CStat    int                `xml:"cStat"`
+	XMotivo  string             `xml:"xMotivo"`
+}
+
+// InfRec holds the receipt of an asynchronous submission
+type InfRec struct {
+	NRec string `xml:"nRec"`
+	TMed int    `xml:"tMed"`
+}
+
+// RetEnviNFe is the response of NfeAutorizacao
+type RetEnviNFe struct {
+	XMLName  xml.Name           `xml:"retEnviNFe"`
+	Versao   string             `xml:"versao,attr"`
+	TpAmb    types.TipoAmbiente `xml:"tpAmb"`
+	VerAplic string             `xml:"verAplic"`
+	CStat    int                `xml:"cStat"`
+	XMotivo  string             `xml:"xMotivo"`
+	CUF      int                `xml:"cUF"`
+	DhRecbto string             `xml:"dhRecbto"`
+	InfRec   *InfRec            `xml:"infRec"`
+	ProtNFe  *ProtNFe           `xml:"protNFe"`
+}
+
+// RetConsReciNFe is the response of NfeRetAutorizacao
+type RetConsReciNFe struct {
+	XMLName  xml.Name           `xml:"retConsReciNFe"`
+	Versao   string             `xml:"versao,attr"`
+	TpAmb    types.TipoAmbiente `xml:"tpAmb"`
+	VerAplic string             `xml:"verAplic"`
+	NRec     string             `xml:"nRec"`
+	CStat    int                `xml:"cStat"`
+	XMotivo  string             `xml:"xMotivo"`
+	CUF      int                `xml:"cUF"`
+	DhRecbto string             `xml:"dhRecbto"`
+	ProtNFe  []*ProtNFe         `xml:"protNFe"`
+}
+
+// RetConsSitNFe is the response of NfeConsultaProtocolo
+type RetConsSitNFe struct {
+	XMLName  xml.Name           `xml:"retConsSitNFe"`
+	Versao   string             `xml:"versao,attr"`
+	TpAmb    types.TipoAmbiente `xml:"tpAmb"`
+	VerAplic string             `xml:"verAplic"`
+	CStat    int                `xml:"cStat"`
+	XMotivo  string             `xml:"xMotivo"`
+	CUF      int                `xml:"cUF"`
+	DhRecbto string             `xml:"dhRecbto"`
+	ChNFe    string             `xml:"chNFe"`
+	ProtNFe  *ProtNFe           `xml:"protNFe"`
+	// ProcEventoNFe lists the events registered for the NFe
+	ProcEventoNFe []*ProcEventoNFe `xml:"procEventoNFe"`
+}
+
+// decodeResponse finds the first element named local in a SEFAZ response,
+// ignoring the nfeResultMsg wrapper and namespace prefixes, and decodes it into v
+func decodeResponse(data string, local string, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader([]byte(data)))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return errors.NewXMLError(fmt.Sprintf("element %s not found in SEFAZ response", local), local, nil)
+		}
+		if err != nil {
+			return errors.NewXMLError("failed to parse SEFAZ response", local, err)
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == local {
+			if err := decoder.DecodeElement(v, &start); err != nil {
+				return errors.NewXMLError(fmt.Sprintf("failed to decode %s", local), local, err)
+			}
+			return nil
+		}
+	}
+}
+
+// rawElements returns the raw bytes of every element named local in data,
+// in document order
+func rawElements(data []byte, local string) [][]byte {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	elements := make([][]byte, 0)
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return elements
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == local {
+			if err := decoder.Skip(); err != nil {
+				return elements
+			}
+			elements = append(elements, data[offset:decoder.InputOffset()])
+		}
+	}
+}
+
+// attachRaw fills the Raw field of protocols from the response they were decoded from
+func attachRaw(data string, protocols ...*ProtNFe) {
+	raws := rawElements([]byte(data), "protNFe")
+	for i, protocol := range protocols {
+		if protocol != nil && i < len(raws) {
+			protocol.Raw = raws[i]
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package nfe
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/types"
+	"github.com/adrianodrix/sped-nfe-go/webservices"
+)
+
+// defaultProbeConcurrency is the number of status requests a StatusProber runs at once
+const defaultProbeConcurrency = 4
+
+// RetConsStatServ is the response of NfeStatusServico
+type RetConsStatServ struct {
+	XMLName   xml.Name           `xml:"retConsStatServ"`
+	Versao    string             `xml:"versao,attr"`
+	TpAmb     types.TipoAmbiente `xml:"tpAmb"`
+	VerAplic  string             `xml:"verAplic"`
+	CStat     int                `xml:"cStat"`
+	XMotivo   string             `xml:"xMotivo"`
+	CUF       int                `xml:"cUF"`
+	DhRecbto  string             `xml:"dhRecbto"`
+	TMed      int                `xml:"tMed"`
+	DhRetorno string             `xml:"dhRetorno"`
+	XObs      string             `xml:"xObs"`
+}
+
+// IsOnline returns true if the service is in operation (cStat 107)
+func (r *RetConsStatServ) IsOnline() bool {
+	return r.CStat == 107
+}
+
+// Status queries the situation of the NfeStatusServico of the client UF
+func (c *Client) Status(ctx context.Context, modelo types.ModeloNFe) (*RetConsStatServ, error) {
+	service, err := c.service(modelo, webservices.ServiceStatusServico)
+	if err != nil {
+		return nil, err
+	}
+	return queryStatus(ctx, c.requester, service, c.config.Environment, types.UF(c.config.UF))
+}
+
+// queryStatus sends consStatServ for a UF to a status service
+func queryStatus(ctx context.Context, requester Requester, service *webservices.Service, environment Environment, uf types.UF) (*RetConsStatServ, error) {
+	message := fmt.Sprintf(`<consStatServ xmlns="%s" versao="4.00"><tpAmb>%d</tpAmb><cUF>%d</cUF><xServ>STATUS</xServ></consStatServ>`,
+		NFeNamespace, int(environment), int(uf))
+
+	response, err := requester.Send(ctx, service, message)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret RetConsStatServ
+	if err := decodeResponse(response, "retConsStatServ", &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// ServiceStatus is the outcome of probing the status service of a UF
+type ServiceStatus struct {
+	UF         types.UF
+	Authorizer string
+	// Online is true when the authorizer answered cStat 107
+	Online bool
+	// CStat, XMotivo and TMed are those of the last response; CStat is zero
+	// when the authorizer could not be reached
+	CStat   int
+	XMotivo string
+	TMed    int
+	// Latency is the round trip time of the request
+	Latency   time.Duration
+	CheckedAt time.Time
+	// Err holds the failure to reach the authorizer or read its response
+	Err error
+}
+
+// StatusProber queries NfeStatusServico of several UFs in parallel, e.g. to
+// decide when to switch to contingency
+type StatusProber struct {
+	requester   Requester
+	environment Environment
+	modelo      types.ModeloNFe
+
+	// Concurrency is the maximum number of requests in flight
+	Concurrency int
+	// Timeout bounds each request; zero leaves it to the requester
+	Timeout time.Duration
+}
+
+// NewStatusProber creates a prober sending requests through requester
+func NewStatusProber(requester Requester, environment Environment, modelo types.ModeloNFe) *StatusProber {
+	return &StatusProber{
+		requester:   requester,
+		environment: environment,
+		modelo:      modelo,
+		Concurrency: defaultProbeConcurrency,
+	}
+}
+
+// Probe queries the status service of each UF, or of every state served by an
+// authorizer of the model when no UF is given, and returns the status by UF.
+// Failures are reported in ServiceStatus.Err rather than aborting the probe.
+func (p *StatusProber) Probe(ctx context.Context, ufs ...types.UF) map[types.UF]*ServiceStatus {
+	if len(ufs) == 0 {
+		ufs = probeUFs(p.modelo)
+	}
+
+	concurrency := p.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultProbeConcurrency
+	}
+
+	results := make(map[types.UF]*ServiceStatus, len(ufs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+
+	for _, uf := range ufs {
+		wg.Add(1)
+		go func(uf types.UF) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				mu.Lock()
+				results[uf] = &ServiceStatus{UF: uf, CheckedAt: time.Now(), Err: ctx.Err()}
+				mu.Unlock()
+				return
+			}
+
+			status := p.probe(ctx, uf)
+			mu.Lock()
+			results[uf] = status
+			mu.Unlock()
+		}(uf)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// probe queries the status service of a single UF
+func (p *StatusProber) probe(ctx context.Context, uf types.UF) *ServiceStatus {
+	status := &ServiceStatus{UF: uf, CheckedAt: time.Now()}
+
+	authorizer, err := webservices.GetAuthorizer(uf, p.modelo)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Authorizer = authorizer
+
+	env, err := webservices.GetAllServices(uf, types.Ambiente(p.environment), p.modelo)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	if env == nil || env.NfeStatusServico == nil {
+		status.Err = errors.NewConfigError(fmt.Sprintf("NfeStatusServico not configured for %s", authorizer), "authorizer", authorizer)
+		return status
+	}
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	ret, err := queryStatus(ctx, p.requester, env.NfeStatusServico, p.environment, uf)
+	status.Latency = time.Since(start)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+
+	status.Online = ret.IsOnline()
+	status.CStat = ret.CStat
+	status.XMotivo = ret.XMotivo
+	status.TMed = ret.TMed
+	return status
+}
+
+// probeUFs returns the states of a model, leaving out the virtual authorizers
+// (AN, SVRS, SVAN and SVC), which are reached through the states they serve
+func probeUFs(modelo types.ModeloNFe) []types.UF {
+	ufs := make([]types.UF, 0, len(webservices.AuthorizeMapping[modelo]))
+	for uf := range webservices.AuthorizeMapping[modelo] {
+		if uf < types.AN {
+			ufs = append(ufs, uf)
+		}
+	}
+	sort.Slice(ufs, func(i, j int) bool { return ufs[i] < ufs[j] })
+	return ufs
+}
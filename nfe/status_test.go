@@ -0,0 +1,197 @@
+package nfe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/soap"
+	"github.com/adrianodrix/sped-nfe-go/types"
+	"github.com/adrianodrix/sped-nfe-go/webservices"
+)
+
+func retConsStatServXML(cUF, cStat int, xMotivo string) string {
+	return fmt.Sprintf(`<nfeResultMsg xmlns="http://www.portalfiscal.inf.br/nfe/wsdl/NFeStatusServico4">`+
+		`<retConsStatServ xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><verAplic>SP_NFE_PL009_V4</verAplic>`+
+		`<cStat>%d</cStat><xMotivo>%s</xMotivo><cUF>%d</cUF><dhRecbto>2024-05-10T10:00:00-03:00</dhRecbto><tMed>1</tMed></retConsStatServ></nfeResultMsg>`,
+		cStat, xMotivo, cUF)
+}
+
+func TestClientStatus(t *testing.T) {
+	client, _ := New(Config{Environment: Homologation, UF: SP})
+	fake := newFakeRequester()
+	fake.on("nfeStatusServicoNF", retConsStatServXML(35, 107, "Servico em Operacao"))
+	client.SetRequester(fake)
+
+	ret, err := client.Status(context.Background(), types.ModeloNFe55)
+	if err != nil {
+		t.Fatalf("Status should not return error, got: %v", err)
+	}
+	if !ret.IsOnline() || ret.CUF != 35 || ret.TMed != 1 {
+		t.Errorf("unexpected response: %+v", ret)
+	}
+
+	want := `<consStatServ xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><cUF>35</cUF><xServ>STATUS</xServ></consStatServ>`
+	if got := fake.requests["nfeStatusServicoNF"][0]; got != want {
+		t.Errorf("unexpected request\n got: %s\nwant: %s", got, want)
+	}
+}
+
+// fakeStatusServer answers NfeStatusServico like SEFAZ: SP is paralyzed, MG
+// fails with HTTP 500 and every other UF is in operation
+func fakeStatusServer() *httptest.Server {
+	cUFPattern := regexp.MustCompile(`<cUF>(\d+)</cUF>`)
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		match := cUFPattern.FindSubmatch(body)
+		if match == nil {
+			http.Error(w, "missing cUF", http.StatusBadRequest)
+			return
+		}
+		var cUF int
+		fmt.Sscan(string(match[1]), &cUF)
+
+		if types.UF(cUF) == types.MG {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		cStat, xMotivo := 107, "Servico em Operacao"
+		if types.UF(cUF) == types.SP {
+			cStat, xMotivo = 108, "Servico Paralisado Momentaneamente"
+		}
+		w.Header().Set("Content-Type", "application/soap+xml; charset=utf-8")
+		fmt.Fprintf(w, `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body>%s</soap:Body></soap:Envelope>`,
+			retConsStatServXML(cUF, cStat, xMotivo))
+	}))
+}
+
+func TestStatusProberFakeServer(t *testing.T) {
+	server := fakeStatusServer()
+	defer server.Close()
+
+	// Point the status service of every authorizer to the fake server
+	override := webservices.WebserviceConfig{}
+	for authorizer := range webservices.NFe55Config {
+		override[authorizer] = &webservices.StateWebservices{
+			Homologacao: &webservices.Environment{
+				NfeStatusServico: &webservices.Service{
+					Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+					URL: server.URL + "/" + authorizer,
+				},
+			},
+		}
+	}
+	if err := webservices.SetWebserviceConfig(types.ModeloNFe55, webservices.NFe55Config.Merge(override)); err != nil {
+		t.Fatalf("SetWebserviceConfig failed: %v", err)
+	}
+	defer webservices.ResetWebserviceConfig(types.ModeloNFe55)
+
+	config := soap.DefaultConfig()
+	config.MaxRetries = 0
+	config.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	requester := &SOAPRequester{Client: soap.NewSOAPClient(config)}
+
+	prober := NewStatusProber(requester, Homologation, types.ModeloNFe55)
+	prober.Timeout = 5 * time.Second
+	results := prober.Probe(context.Background())
+
+	if len(results) != 27 {
+		t.Fatalf("expected the 27 states, got %d", len(results))
+	}
+
+	sp := results[types.SP]
+	if sp.Err != nil || sp.Online || sp.CStat != 108 || sp.Authorizer != "SP" {
+		t.Errorf("unexpected SP status: %+v", sp)
+	}
+	mg := results[types.MG]
+	if mg.Err == nil || mg.Online || mg.CStat != 0 {
+		t.Errorf("MG should report the HTTP failure: %+v", mg)
+	}
+	ac := results[types.AC]
+	if ac.Err != nil || !ac.Online || ac.CStat != 107 || ac.Authorizer != "SVRS" || ac.TMed != 1 {
+		t.Errorf("unexpected AC status: %+v", ac)
+	}
+	if ac.Latency <= 0 || ac.CheckedAt.IsZero() {
+		t.Errorf("AC status should record latency and time: %+v", ac)
+	}
+}
+
+// countingRequester answers every status request after a delay, recording the
+// largest number of requests in flight
+type countingRequester struct {
+	delay    time.Duration
+	inFlight int32
+	max      int32
+}
+
+func (c *countingRequester) Send(ctx context.Context, service *webservices.Service, message string) (string, error) {
+	current := atomic.AddInt32(&c.inFlight, 1)
+	defer atomic.AddInt32(&c.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&c.max)
+		if current <= max || atomic.CompareAndSwapInt32(&c.max, max, current) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	return retConsStatServXML(35, 107, "Servico em Operacao"), nil
+}
+
+func TestStatusProberConcurrency(t *testing.T) {
+	requester := &countingRequester{delay: 10 * time.Millisecond}
+	prober := NewStatusProber(requester, Homologation, types.ModeloNFCe65)
+	prober.Concurrency = 3
+
+	results := prober.Probe(context.Background(), types.SP, types.MG, types.RS, types.PR, types.AC, types.BA, types.GO)
+	if len(results) != 7 {
+		t.Fatalf("expected 7 results, got %d", len(results))
+	}
+	if requester.max > 3 {
+		t.Errorf("expected at most 3 requests in flight, got %d", requester.max)
+	}
+	if requester.max < 2 {
+		t.Errorf("expected requests to run in parallel, got %d", requester.max)
+	}
+	for uf, status := range results {
+		if status.Err != nil || !status.Online {
+			t.Errorf("unexpected status for %s: %+v", uf.String(), status)
+		}
+	}
+}
+
+func TestStatusProberErrors(t *testing.T) {
+	requester := &countingRequester{delay: time.Second}
+	prober := NewStatusProber(requester, Homologation, types.ModeloNFe55)
+	prober.Timeout = 10 * time.Millisecond
+
+	results := prober.Probe(context.Background(), types.SP, types.AN, types.UF(999))
+	if results[types.SP].Err == nil {
+		t.Error("SP should report the timeout")
+	}
+	if results[types.AN].Err == nil {
+		t.Error("AN has no status service and should report an error")
+	}
+	if results[types.UF(999)].Err == nil {
+		t.Error("unknown UF should report an error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for uf, status := range prober.Probe(ctx, types.SP, types.MG) {
+		if status.Err == nil {
+			t.Errorf("%s should report the cancelled context", uf.String())
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package nfe
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// Total holds the totals of the NFe (grupo total)
+type Total struct {
+	ICMSTot ICMSTot `xml:"ICMSTot"`
+	// ISTot, IBSCBSTot and VNFTot are the totals of the tax reform, only in LayoutPL010
+	ISTot     *ISTot          `xml:"ISTot,omitempty"`
+	IBSCBSTot *IBSCBSTot      `xml:"IBSCBSTot,omitempty"`
+	VNFTot    decimal.Decimal `xml:"vNFTot,omitempty"`
+}
+
+// ICMSTot holds the totals of the items values and taxes
+type ICMSTot struct {
+	VBC          decimal.Decimal `xml:"vBC"`
+	VICMS        decimal.Decimal `xml:"vICMS"`
+	VICMSDeson   decimal.Decimal `xml:"vICMSDeson"`
+	VFCPUFDest   decimal.Decimal `xml:"vFCPUFDest,omitempty"`
+	VICMSUFDest  decimal.Decimal `xml:"vICMSUFDest,omitempty"`
+	VICMSUFRemet decimal.Decimal `xml:"vICMSUFRemet,omitempty"`
+	VFCP         decimal.Decimal `xml:"vFCP"`
+	VBCST        decimal.Decimal `xml:"vBCST"`
+	VST          decimal.Decimal `xml:"vST"`
+	VFCPST       decimal.Decimal `xml:"vFCPST"`
+	VFCPSTRet    decimal.Decimal `xml:"vFCPSTRet"`
+	VProd        decimal.Decimal `xml:"vProd"`
+	VFrete       decimal.Decimal `xml:"vFrete"`
+	VSeg         decimal.Decimal `xml:"vSeg"`
+	VDesc        decimal.Decimal `xml:"vDesc"`
+	VII          decimal.Decimal `xml:"vII"`
+	VIPI         decimal.Decimal `xml:"vIPI"`
+	VIPIDevol    decimal.Decimal `xml:"vIPIDevol"`
+	VPIS         decimal.Decimal `xml:"vPIS"`
+	VCOFINS      decimal.Decimal `xml:"vCOFINS"`
+	VOutro       decimal.Decimal `xml:"vOutro"`
+	VNF          decimal.Decimal `xml:"vNF"`
+	VTotTrib     decimal.Decimal `xml:"vTotTrib,omitempty"`
+}
+
+// cents converts a value to an exact integer amount of cents. Values have at
+// most 15 digits (TDec_1302), so the amount fits an int64.
+func cents(v decimal.Decimal) int64 {
+	return v.Round(decimal.MoneyPlaces, decimal.RoundHalfUp).Coefficient().Int64()
+}
+
+// total returns a required total field, always written with 2 decimals
+func total(v decimal.Decimal) decimal.Decimal {
+	return v.Round(decimal.MoneyPlaces, decimal.RoundHalfUp)
+}
+
+// optionalTotal returns an optional total field, left unset when zero
+func optionalTotal(v decimal.Decimal) decimal.Decimal {
+	if v.IsZero() {
+		return decimal.Decimal{}
+	}
+	return total(v)
+}
+
+// icmsAmounts holds the ICMS values of an item that compose the totals
+type icmsAmounts struct {
+	vBC, vICMS, vICMSDeson, vFCP  decimal.Decimal
+	vBCST, vST, vFCPST, vFCPSTRet decimal.Decimal
+	deductedDeson                 decimal.Decimal
+}
+
+// amounts returns the values of the ICMS group set in icms
+func (icms ICMS) amounts() icmsAmounts {
+	var a icmsAmounts
+	deson := func(value decimal.Decimal, deduz int) {
+		a.vICMSDeson = value
+		if deduz == 1 {
+			a.deductedDeson = a.vICMSDeson
+		}
+	}
+
+	switch {
+	case icms.ICMS00 != nil:
+		g := icms.ICMS00
+		a.vBC, a.vICMS, a.vFCP = g.VBC, g.VICMS, g.VFCP
+	case icms.ICMS10 != nil:
+		g := icms.ICMS10
+		a.vBC, a.vICMS, a.vFCP = g.VBC, g.VICMS, g.VFCP
+		a.vBCST, a.vST, a.vFCPST = g.VBCST, g.VICMSST, g.VFCPST
+	case icms.ICMS20 != nil:
+		g := icms.ICMS20
+		a.vBC, a.vICMS, a.vFCP = g.VBC, g.VICMS, g.VFCP
+		deson(g.VICMSDeson, g.IndDeduzDeson)
+	case icms.ICMS30 != nil:
+		g := icms.ICMS30
+		a.vBCST, a.vST, a.vFCPST = g.VBCST, g.VICMSST, g.VFCPST
+		deson(g.VICMSDeson, g.IndDeduzDeson)
+	case icms.ICMS40 != nil:
+		deson(icms.ICMS40.VICMSDeson, icms.ICMS40.IndDeduzDeson)
+	case icms.ICMS51 != nil:
+		g := icms.ICMS51
+		a.vBC, a.vICMS, a.vFCP = g.VBC, g.VICMS, g.VFCP
+		if g.PFCPDif.Sign() > 0 {
+			a.vFCP = g.VFCPEfet
+		}
+	case icms.ICMS60 != nil:
+		a.vFCPSTRet = icms.ICMS60.VFCPSTRet
+	case icms.ICMS70 != nil:
+		g := icms.ICMS70
+		a.vBC, a.vICMS, a.vFCP = g.VBC, g.VICMS, g.VFCP
+		a.vBCST, a.vST, a.vFCPST = g.VBCST, g.VICMSST, g.VFCPST
+		deson(g.VICMSDeson, g.IndDeduzDeson)
+	case icms.ICMS90 != nil:
+		g := icms.ICMS90
+		a.vBC, a.vICMS, a.vFCP = g.VBC, g.VICMS, g.VFCP
+		a.vBCST, a.vST, a.vFCPST = g.VBCST, g.VICMSST, g.VFCPST
+		deson(g.VICMSDeson, g.IndDeduzDeson)
+	case icms.ICMSSN201 != nil:
+		g := icms.ICMSSN201
+		a.vBCST, a.vST, a.vFCPST = g.VBCST, g.VICMSST, g.VFCPST
+	case icms.ICMSSN202 != nil:
+		g := icms.ICMSSN202
+		a.vBCST, a.vST, a.vFCPST = g.VBCST, g.VICMSST, g.VFCPST
+	case icms.ICMSSN500 != nil:
+		a.vFCPSTRet = icms.ICMSSN500.VFCPSTRet
+	case icms.ICMSSN900 != nil:
+		g := icms.ICMSSN900
+		a.vBC, a.vICMS = g.VBC, g.VICMS
+		a.vBCST, a.vST, a.vFCPST = g.VBCST, g.VICMSST, g.VFCPST
+	}
+	return a
+}
+
+// value returns the PIS of the group set in pis
+func (pis PIS) value() decimal.Decimal {
+	switch {
+	case pis.PISAliq != nil:
+		return pis.PISAliq.VPIS
+	case pis.PISQtde != nil:
+		return pis.PISQtde.VPIS
+	case pis.PISOutr != nil:
+		return pis.PISOutr.VPIS
+	}
+	return decimal.Decimal{}
+}
+
+// value returns the COFINS of the group set in cofins
+func (cofins COFINS) value() decimal.Decimal {
+	switch {
+	case cofins.COFINSAliq != nil:
+		return cofins.COFINSAliq.VCOFINS
+	case cofins.COFINSQtde != nil:
+		return cofins.COFINSQtde.VCOFINS
+	case cofins.COFINSOutr != nil:
+		return cofins.COFINSOutr.VCOFINS
+	}
+	return decimal.Decimal{}
+}
+
+// CalculateTotals computes every ICMSTot field from the items.
+//
+// vNF = vProd − vDesc − vICMSDeson (when indDeduzDeson = 1) + vST + vFCPST +
+// vFrete + vSeg + vOutro + vII + vIPI + vIPIDevol + PISST and COFINSST that
+// compose the total. vProd only sums items with indTot = 1.
+func CalculateTotals(items []Item) ICMSTot {
+	var t struct {
+		vBC, vICMS, vICMSDeson, deductedDeson, vFCP             decimal.Decimal
+		vFCPUFDest, vICMSUFDest, vICMSUFRemet                   decimal.Decimal
+		vBCST, vST, vFCPST, vFCPSTRet                           decimal.Decimal
+		vProd, vFrete, vSeg, vDesc, vOutro                      decimal.Decimal
+		vII, vIPI, vIPIDevol, vPIS, vCOFINS, vSTContribs, vTrib decimal.Decimal
+	}
+
+	for _, item := range items {
+		prod, imposto := item.Prod, item.Imposto
+
+		if prod.IndTot == 1 {
+			t.vProd = t.vProd.Add(prod.VProd)
+		}
+		t.vFrete = t.vFrete.Add(prod.VFrete)
+		t.vSeg = t.vSeg.Add(prod.VSeg)
+		t.vDesc = t.vDesc.Add(prod.VDesc)
+		t.vOutro = t.vOutro.Add(prod.VOutro)
+		t.vTrib = t.vTrib.Add(imposto.VTotTrib)
+
+		icms := imposto.ICMS.amounts()
+		t.vBC = t.vBC.Add(icms.vBC)
+		t.vICMS = t.vICMS.Add(icms.vICMS)
+		t.vICMSDeson = t.vICMSDeson.Add(icms.vICMSDeson)
+		t.deductedDeson = t.deductedDeson.Add(icms.deductedDeson)
+		t.vFCP = t.vFCP.Add(icms.vFCP)
+		t.vBCST = t.vBCST.Add(icms.vBCST)
+		t.vST = t.vST.Add(icms.vST)
+		t.vFCPST = t.vFCPST.Add(icms.vFCPST)
+		t.vFCPSTRet = t.vFCPSTRet.Add(icms.vFCPSTRet)
+
+		if dest := imposto.ICMSUFDest; dest != nil {
+			t.vFCPUFDest = t.vFCPUFDest.Add(dest.VFCPUFDest)
+			t.vICMSUFDest = t.vICMSUFDest.Add(dest.VICMSUFDest)
+			t.vICMSUFRemet = t.vICMSUFRemet.Add(dest.VICMSUFRemet)
+		}
+		if imposto.IPI != nil && imposto.IPI.IPITrib != nil {
+			t.vIPI = t.vIPI.Add(imposto.IPI.IPITrib.VIPI)
+		}
+		if imposto.II != nil {
+			t.vII = t.vII.Add(imposto.II.VII)
+		}
+		if item.ImpostoDevol != nil {
+			t.vIPIDevol = t.vIPIDevol.Add(item.ImpostoDevol.IPI.VIPIDevol)
+		}
+		t.vPIS = t.vPIS.Add(imposto.PIS.value())
+		t.vCOFINS = t.vCOFINS.Add(imposto.COFINS.value())
+		if st := imposto.PISST; st != nil && st.IndSomaPISST == 1 {
+			t.vSTContribs = t.vSTContribs.Add(st.VPIS)
+		}
+		if st := imposto.COFINSST; st != nil && st.IndSomaCOFINSST == 1 {
+			t.vSTContribs = t.vSTContribs.Add(st.VCOFINS)
+		}
+	}
+
+	vNF := t.vProd.Sub(t.vDesc).Sub(t.deductedDeson).Add(t.vST).Add(t.vFCPST).Add(t.vFrete).Add(t.vSeg).
+		Add(t.vOutro).Add(t.vII).Add(t.vIPI).Add(t.vIPIDevol).Add(t.vSTContribs)
+
+	return ICMSTot{
+		VBC:          total(t.vBC),
+		VICMS:        total(t.vICMS),
+		VICMSDeson:   total(t.vICMSDeson),
+		VFCPUFDest:   optionalTotal(t.vFCPUFDest),
+		VICMSUFDest:  optionalTotal(t.vICMSUFDest),
+		VICMSUFRemet: optionalTotal(t.vICMSUFRemet),
+		VFCP:         total(t.vFCP),
+		VBCST:        total(t.vBCST),
+		VST:          total(t.vST),
+		VFCPST:       total(t.vFCPST),
+		VFCPSTRet:    total(t.vFCPSTRet),
+		VProd:        total(t.vProd),
+		VFrete:       total(t.vFrete),
+		VSeg:         total(t.vSeg),
+		VDesc:        total(t.vDesc),
+		VII:          total(t.vII),
+		VIPI:         total(t.vIPI),
+		VIPIDevol:    total(t.vIPIDevol),
+		VPIS:         total(t.vPIS),
+		VCOFINS:      total(t.vCOFINS),
+		VOutro:       total(t.vOutro),
+		VNF:          total(vNF),
+		VTotTrib:     optionalTotal(t.vTrib),
+	}
+}
+
+// TotalMismatch is an ICMSTot field whose declared value differs from the
+// value computed from the items
+type TotalMismatch struct {
+	Field    string
+	Declared decimal.Decimal
+	Computed decimal.Decimal
+	// CStat is the rejection SEFAZ returns for the mismatch, 0 when unknown
+	CStat int
+}
+
+// String returns a readable description of the mismatch
+func (m TotalMismatch) String() string {
+	message := fmt.Sprintf("%s declared %s, computed %s", m.Field, m.Declared.StringFixed(decimal.MoneyPlaces), m.Computed.StringFixed(decimal.MoneyPlaces))
+	if m.CStat != 0 {
+		message += fmt.Sprintf(" (cStat %d)", m.CStat)
+	}
+	return message
+}
+
+// totalFields lists the validated ICMSTot fields with their rejection codes
+var totalFields = []struct {
+	name  string
+	cStat int
+	get   func(ICMSTot) decimal.Decimal
+}{
+	{"vBC", 531, func(t ICMSTot) decimal.Decimal { return t.VBC }},
+	{"vICMS", 532, func(t ICMSTot) decimal.Decimal { return t.VICMS }},
+	{"vICMSDeson", 795, func(t ICMSTot) decimal.Decimal { return t.VICMSDeson }},
+	{"vFCPUFDest", 797, func(t ICMSTot) decimal.Decimal { return t.VFCPUFDest }},
+	{"vICMSUFDest", 798, func(t ICMSTot) decimal.Decimal { return t.VICMSUFDest }},
+	{"vICMSUFRemet", 799, func(t ICMSTot) decimal.Decimal { return t.VICMSUFRemet }},
+	{"vFCP", 0, func(t ICMSTot) decimal.Decimal { return t.VFCP }},
+	{"vBCST", 533, func(t ICMSTot) decimal.Decimal { return t.VBCST }},
+	{"vST", 534, func(t ICMSTot) decimal.Decimal { return t.VST }},
+	{"vFCPST", 0, func(t ICMSTot) decimal.Decimal { return t.VFCPST }},
+	{"vFCPSTRet", 0, func(t ICMSTot) decimal.Decimal { return t.VFCPSTRet }},
+	{"vProd", 564, func(t ICMSTot) decimal.Decimal { return t.VProd }},
+	{"vFrete", 535, func(t ICMSTot) decimal.Decimal { return t.VFrete }},
+	{"vSeg", 536, func(t ICMSTot) decimal.Decimal { return t.VSeg }},
+	{"vDesc", 537, func(t ICMSTot) decimal.Decimal { return t.VDesc }},
+	{"vII", 601, func(t ICMSTot) decimal.Decimal { return t.VII }},
+	{"vIPI", 538, func(t ICMSTot) decimal.Decimal { return t.VIPI }},
+	{"vIPIDevol", 0, func(t ICMSTot) decimal.Decimal { return t.VIPIDevol }},
+	{"vPIS", 602, func(t ICMSTot) decimal.Decimal { return t.VPIS }},
+	{"vCOFINS", 603, func(t ICMSTot) decimal.Decimal { return t.VCOFINS }},
+	{"vOutro", 604, func(t ICMSTot) decimal.Decimal { return t.VOutro }},
+	{"vNF", 610, func(t ICMSTot) decimal.Decimal { return t.VNF }},
+	{"vTotTrib", 685, func(t ICMSTot) decimal.Decimal { return t.VTotTrib }},
+}
+
+// ValidateTotals compares the declared totals with the totals computed from
+// the items and returns every mismatching field, in layout order
+func ValidateTotals(declared ICMSTot, items []Item) []TotalMismatch {
+	computed := CalculateTotals(items)
+	mismatches := make([]TotalMismatch, 0)
+	for _, field := range totalFields {
+		d, c := field.get(declared), field.get(computed)
+		if cents(d) != cents(c) {
+			mismatches = append(mismatches, TotalMismatch{Field: field.name, Declared: d, Computed: c, CStat: field.cStat})
+		}
+	}
+	return mismatches
+}
+
+// Charges are header-level values distributed across the items
+type Charges struct {
+	VFrete decimal.Decimal
+	VSeg   decimal.Decimal
+	VDesc  decimal.Decimal
+	VOutro decimal.Decimal
+}
+
+// ProrateCharges distributes freight, insurance, discount and other charges
+// across the items with indTot = 1, proportionally to vProd, replacing the
+// item values. Charges left at zero are not prorated and keep the values
+// already in the items. Cents left over by rounding go to the items with the
+// largest remainders, so the item values always add up to the charges.
+func ProrateCharges(items []Item, charges Charges) error {
+	weights := make([]int64, len(items))
+	var totalWeight int64
+	for i, item := range items {
+		if item.Prod.IndTot == 1 {
+			weights[i] = cents(item.Prod.VProd)
+			totalWeight += weights[i]
+		}
+	}
+
+	fields := []struct {
+		name   string
+		amount decimal.Decimal
+		set    func(*Produto, decimal.Decimal)
+	}{
+		{"vFrete", charges.VFrete, func(p *Produto, v decimal.Decimal) { p.VFrete = v }},
+		{"vSeg", charges.VSeg, func(p *Produto, v decimal.Decimal) { p.VSeg = v }},
+		{"vDesc", charges.VDesc, func(p *Produto, v decimal.Decimal) { p.VDesc = v }},
+		{"vOutro", charges.VOutro, func(p *Produto, v decimal.Decimal) { p.VOutro = v }},
+	}
+
+	for _, field := range fields {
+		if field.amount.Sign() < 0 {
+			return errors.NewValidationError(fmt.Sprintf("%s cannot be negative", field.name), field.name, field.amount)
+		}
+		if field.amount.Sign() > 0 && totalWeight <= 0 {
+			return errors.NewValidationError("no item with vProd composing the total to prorate charges", field.name, field.amount)
+		}
+	}
+
+	for _, field := range fields {
+		if field.amount.Sign() == 0 {
+			continue
+		}
+		shares := prorate(cents(field.amount), weights, totalWeight)
+		for i := range items {
+			share := decimal.Decimal{}
+			if shares[i] != 0 {
+				share = decimal.New(shares[i], decimal.MoneyPlaces)
+			}
+			field.set(&items[i].Prod, share)
+		}
+	}
+	return nil
+}
+
+// prorate splits amount proportionally to weights using the largest remainder method
+func prorate(amount int64, weights []int64, totalWeight int64) []int64 {
+	shares := make([]int64, len(weights))
+	if amount == 0 || totalWeight == 0 {
+		return shares
+	}
+
+	type remainder struct {
+		index int
+		value *big.Int
+	}
+	remainders := make([]remainder, 0, len(weights))
+	distributed := int64(0)
+	total := big.NewInt(totalWeight)
+
+	for i, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		product := new(big.Int).Mul(big.NewInt(amount), big.NewInt(weight))
+		quo, rem := new(big.Int).QuoRem(product, total, new(big.Int))
+		shares[i] = quo.Int64()
+		distributed += shares[i]
+		remainders = append(remainders, remainder{index: i, value: rem})
+	}
+
+	sort.SliceStable(remainders, func(a, b int) bool {
+		return remainders[a].value.Cmp(remainders[b].value) > 0
+	})
+	for i := int64(0); i < amount-distributed; i++ {
+		shares[remainders[i].index]++
+	}
+	return shares
+}
@@ -0,0 +1,209 @@
+package nfe
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+)
+
+var (
+	money = decimal.Money
+	rate  = decimal.Rate
+)
+
+func totalsTestItems() []Item {
+	return []Item{
+		{
+			NItem: 1,
+			Prod:  Produto{VProd: money(100), VFrete: money(10), IndTot: 1},
+			Imposto: Imposto{
+				VTotTrib: money(27.25),
+				ICMS:     ICMS{ICMS00: &ICMS00{CST: "00", VBC: money(100), PICMS: rate(18), VICMS: money(18)}},
+				PIS:      PIS{PISAliq: &PISAliq{CST: "01", VBC: money(100), PPIS: rate(1.65), VPIS: money(1.65)}},
+				COFINS:   COFINS{COFINSAliq: &COFINSAliq{CST: "01", VBC: money(100), PCOFINS: rate(7.6), VCOFINS: money(7.6)}},
+			},
+		},
+		{
+			NItem: 2,
+			Prod:  Produto{VProd: money(1000), IndTot: 1},
+			Imposto: Imposto{
+				ICMS: ICMS{ICMS10: &ICMS10{
+					CST: "10", VBC: money(1000), PICMS: rate(12), VICMS: money(120),
+					ModBCST: 4, VBCST: money(1540), PICMSST: rate(18), VICMSST: money(157.2), VBCFCPST: money(1540), PFCPST: rate(2), VFCPST: money(30.8),
+				}},
+				IPI: &IPI{CEnq: "999", IPITrib: &IPITrib{CST: "50", VBC: money(1000), PIPI: rate(10), VIPI: money(100)}},
+			},
+		},
+		{
+			NItem: 3,
+			Prod:  Produto{VProd: money(1000), IndTot: 1},
+			Imposto: Imposto{
+				ICMS: ICMS{ICMS20: &ICMS20{
+					CST: "20", PRedBC: rate(33.33), VBC: money(666.7), PICMS: rate(18), VICMS: money(120.01),
+					VICMSDeson: money(59.99), MotDesICMS: 9, IndDeduzDeson: 1,
+				}},
+			},
+		},
+		{
+			NItem:   4,
+			Prod:    Produto{VProd: money(50), IndTot: 0},
+			Imposto: Imposto{ICMS: ICMS{ICMSSN102: &ICMSSN102{CSOSN: "102"}}},
+		},
+	}
+}
+
+func TestCalculateTotals(t *testing.T) {
+	expected := ICMSTot{
+		VBC:        money(1766.7),
+		VICMS:      money(258.01),
+		VICMSDeson: money(59.99),
+		VFCP:       money(0),
+		VBCST:      money(1540),
+		VST:        money(157.2),
+		VFCPST:     money(30.8),
+		VFCPSTRet:  money(0),
+		VProd:      money(2100),
+		VFrete:     money(10),
+		VSeg:       money(0),
+		VDesc:      money(0),
+		VII:        money(0),
+		VIPI:       money(100),
+		VIPIDevol:  money(0),
+		VPIS:       money(1.65),
+		VCOFINS:    money(7.6),
+		VOutro:     money(0),
+		VNF:        money(2338.01),
+		VTotTrib:   money(27.25),
+	}
+
+	if result := CalculateTotals(totalsTestItems()); result != expected {
+		t.Errorf("CalculateTotals() = %+v, expected %+v", result, expected)
+	}
+}
+
+func TestCalculateTotalsWithoutDrift(t *testing.T) {
+	items := make([]Item, 1000)
+	for i := range items {
+		items[i] = Item{NItem: i + 1, Prod: Produto{VProd: money(0.1), VDesc: money(0.01), IndTot: 1}}
+	}
+
+	result := CalculateTotals(items)
+	if result.VProd != money(100) || result.VDesc != money(10) || result.VNF != money(90) {
+		t.Errorf("Unexpected totals: vProd %v, vDesc %v, vNF %v", result.VProd, result.VDesc, result.VNF)
+	}
+}
+
+func TestCalculateTotalsContributions(t *testing.T) {
+	items := []Item{{
+		Prod: Produto{VProd: money(100), IndTot: 1},
+		Imposto: Imposto{
+			II:         &II{VBC: money(100), VII: money(14)},
+			PISST:      &PISST{VBC: money(100), PPIS: rate(1.65), VPIS: money(1.65), IndSomaPISST: 1},
+			COFINSST:   &COFINSST{VBC: money(100), PCOFINS: rate(7.6), VCOFINS: money(7.6)},
+			ICMSUFDest: &ICMSUFDest{VBCUFDest: money(100), VFCPUFDest: money(2), VICMSUFDest: money(6)},
+		},
+		ImpostoDevol: &ImpostoDevol{PDevol: rate(100), IPI: IPIDevol{VIPIDevol: money(5)}},
+	}}
+
+	result := CalculateTotals(items)
+	if result.VII != money(14) || result.VIPIDevol != money(5) || result.VFCPUFDest != money(2) || result.VICMSUFDest != money(6) {
+		t.Errorf("Unexpected totals: %+v", result)
+	}
+	if result.VNF != money(120.65) {
+		t.Errorf("Expected vNF 120.65 (COFINSST not composing the total), got %v", result.VNF)
+	}
+}
+
+func TestValidateTotals(t *testing.T) {
+	items := totalsTestItems()
+
+	if mismatches := ValidateTotals(CalculateTotals(items), items); len(mismatches) != 0 {
+		t.Errorf("Expected no mismatches, got %v", mismatches)
+	}
+
+	declared := CalculateTotals(items)
+	declared.VICMS = money(258)
+	declared.VNF = money(2398)
+
+	expected := []TotalMismatch{
+		{Field: "vICMS", Declared: money(258), Computed: money(258.01), CStat: 532},
+		{Field: "vNF", Declared: money(2398), Computed: money(2338.01), CStat: 610},
+	}
+	mismatches := ValidateTotals(declared, items)
+	if !reflect.DeepEqual(mismatches, expected) {
+		t.Errorf("ValidateTotals() = %v, expected %v", mismatches, expected)
+	}
+
+	if message := mismatches[0].String(); message != "vICMS declared 258.00, computed 258.01 (cStat 532)" {
+		t.Errorf("Unexpected message: %s", message)
+	}
+}
+
+func TestProrateCharges(t *testing.T) {
+	items := []Item{
+		{Prod: Produto{VProd: money(100), IndTot: 1}},
+		{Prod: Produto{VProd: money(200), IndTot: 1}},
+		{Prod: Produto{VProd: money(300), IndTot: 1}},
+		{Prod: Produto{VProd: money(999), VFrete: money(3), IndTot: 0}},
+	}
+
+	err := ProrateCharges(items, Charges{VFrete: money(10), VSeg: money(6), VDesc: money(0.01)})
+	if err != nil {
+		t.Fatalf("ProrateCharges should not return error, got: %v", err)
+	}
+
+	tests := []struct {
+		field    string
+		get      func(Produto) decimal.Decimal
+		expected []decimal.Decimal
+	}{
+		{"vFrete", func(p Produto) decimal.Decimal { return p.VFrete }, []decimal.Decimal{money(1.67), money(3.33), money(5), {}}},
+		{"vSeg", func(p Produto) decimal.Decimal { return p.VSeg }, []decimal.Decimal{money(1), money(2), money(3), {}}},
+		{"vDesc", func(p Produto) decimal.Decimal { return p.VDesc }, []decimal.Decimal{{}, {}, money(0.01), {}}},
+		{"vOutro", func(p Produto) decimal.Decimal { return p.VOutro }, []decimal.Decimal{{}, {}, {}, {}}},
+	}
+
+	for _, test := range tests {
+		for i, item := range items {
+			if got := test.get(item.Prod); got != test.expected[i] {
+				t.Errorf("%s of item %d = %v, expected %v", test.field, i+1, got, test.expected[i])
+			}
+		}
+	}
+
+	totals := CalculateTotals(items)
+	if totals.VFrete != money(10) || totals.VSeg != money(6) || totals.VDesc != money(0.01) {
+		t.Errorf("Prorated values should add up to the charges, got %+v", totals)
+	}
+}
+
+func TestProrateChargesKeepsUnsetFields(t *testing.T) {
+	items := []Item{
+		{Prod: Produto{VProd: money(100), VDesc: money(5), VOutro: money(2), IndTot: 1}},
+		{Prod: Produto{VProd: money(300), VDesc: money(1), IndTot: 1}},
+	}
+
+	if err := ProrateCharges(items, Charges{VFrete: money(8)}); err != nil {
+		t.Fatalf("ProrateCharges should not return error, got: %v", err)
+	}
+
+	if items[0].Prod.VFrete != money(2) || items[1].Prod.VFrete != money(6) {
+		t.Errorf("Unexpected prorated freight %v and %v", items[0].Prod.VFrete, items[1].Prod.VFrete)
+	}
+	if items[0].Prod.VDesc != money(5) || items[1].Prod.VDesc != money(1) || items[0].Prod.VOutro != money(2) {
+		t.Errorf("Charges not prorated should keep the item values, got %+v and %+v", items[0].Prod, items[1].Prod)
+	}
+}
+
+func TestProrateChargesErrors(t *testing.T) {
+	items := []Item{{Prod: Produto{VProd: money(100), IndTot: 0}}}
+	if err := ProrateCharges(items, Charges{VFrete: money(10)}); err == nil {
+		t.Errorf("Expected error without items composing the total")
+	}
+
+	items[0].Prod.IndTot = 1
+	if err := ProrateCharges(items, Charges{VDesc: money(-1)}); err == nil {
+		t.Errorf("Expected error for negative charge")
+	}
+}
@@ -0,0 +1,875 @@
+package schemas
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Well known namespaces
+const (
+	xsdNS = "http://www.w3.org/2001/XMLSchema"
+	xsiNS = "http://www.w3.org/2001/XMLSchema-instance"
+	xmlNS = "http://www.w3.org/XML/1998/namespace"
+)
+
+// unbounded is the maxOccurs value for maxOccurs="unbounded"
+const unbounded = -1
+
+// elementDecl is a compiled element declaration
+type elementDecl struct {
+	name     xml.Name
+	typ      *typeDef
+	fixed    *string
+	nillable bool
+}
+
+// typeDef is a compiled type: exactly one of simple and complex is set
+type typeDef struct {
+	name    string
+	simple  *simpleType
+	complex *complexType
+}
+
+// complexType is a compiled complex type definition
+type complexType struct {
+	name          string
+	content       *particle
+	mixed         bool
+	simpleContent *simpleType
+	attributes    []*attributeDecl
+	anyAttribute  bool
+
+	// elements and wildcards index the content model for child validation
+	elements  map[xml.Name]*elementDecl
+	wildcards []*particle
+}
+
+// particleKind identifies the kind of a content model particle
+type particleKind int
+
+const (
+	particleElement particleKind = iota
+	particleSequence
+	particleChoice
+	particleAll
+	particleAny
+)
+
+// particle is a node of a content model with its occurrence bounds
+type particle struct {
+	kind     particleKind
+	element  *elementDecl
+	children []*particle
+	min      int
+	max      int
+
+	// wildcard constraints, used by particleAny
+	namespaces      []string
+	targetNS        string
+	processContents string
+}
+
+// attributeDecl is a compiled attribute use
+type attributeDecl struct {
+	name     xml.Name
+	typ      *simpleType
+	required bool
+	fixed    *string
+}
+
+// schemaDoc is a loaded schema document
+type schemaDoc struct {
+	file               string
+	root               *node
+	targetNS           string
+	chameleon          bool
+	elementQualified   bool
+	attributeQualified bool
+}
+
+// component is a global schema component together with its document
+type component struct {
+	node *node
+	doc  *schemaDoc
+}
+
+// loader reads schema documents and compiles their components
+type loader struct {
+	fsys       fs.FS
+	docs       map[string]*schemaDoc
+	elements   map[xml.Name]component
+	types      map[xml.Name]component
+	attributes map[xml.Name]component
+
+	compiledElements   map[xml.Name]*elementDecl
+	compiledTypes      map[xml.Name]*typeDef
+	compiledAttributes map[xml.Name]*attributeDecl
+}
+
+// compileSchema loads file and everything it includes or imports and
+// compiles every global element declaration
+func compileSchema(fsys fs.FS, file string) (map[xml.Name]*elementDecl, error) {
+	l := &loader{
+		fsys:               fsys,
+		docs:               make(map[string]*schemaDoc),
+		elements:           make(map[xml.Name]component),
+		types:              make(map[xml.Name]component),
+		attributes:         make(map[xml.Name]component),
+		compiledElements:   make(map[xml.Name]*elementDecl),
+		compiledTypes:      make(map[xml.Name]*typeDef),
+		compiledAttributes: make(map[xml.Name]*attributeDecl),
+	}
+
+	if err := l.load(path.Clean(file), "", false); err != nil {
+		return nil, err
+	}
+
+	for name := range l.types {
+		if _, err := l.typeByName(name); err != nil {
+			return nil, err
+		}
+	}
+	for name := range l.elements {
+		if _, err := l.elementByName(name); err != nil {
+			return nil, err
+		}
+	}
+	return l.compiledElements, nil
+}
+
+// load reads a schema document and registers its global components.
+// Included documents without a target namespace take the includer's one.
+func (l *loader) load(file, includerNS string, include bool) error {
+	key := file + "|" + includerNS
+	if _, ok := l.docs[key]; ok {
+		return nil
+	}
+
+	data, err := fs.ReadFile(l.fsys, file)
+	if err != nil {
+		return fmt.Errorf("reading schema %s: %w", file, err)
+	}
+	root, err := parseTree(data)
+	if err != nil {
+		return fmt.Errorf("parsing schema %s: %w", file, err)
+	}
+	if root.name.Space != xsdNS || root.name.Local != "schema" {
+		return fmt.Errorf("%s is not an XML Schema document", file)
+	}
+
+	doc := &schemaDoc{
+		file:               file,
+		root:               root,
+		elementQualified:   root.attrDefault("elementFormDefault", "unqualified") == "qualified",
+		attributeQualified: root.attrDefault("attributeFormDefault", "unqualified") == "qualified",
+	}
+	doc.targetNS, _ = root.attr("targetNamespace")
+	if include && doc.targetNS == "" {
+		doc.targetNS = includerNS
+		doc.chameleon = includerNS != ""
+	}
+	l.docs[key] = doc
+
+	for _, child := range root.xsdChildren() {
+		switch child.name.Local {
+		case "include", "import":
+			location, ok := child.attr("schemaLocation")
+			if !ok {
+				if child.name.Local == "import" {
+					if ns, _ := child.attr("namespace"); ns == xmlNS {
+						continue
+					}
+				}
+				return fmt.Errorf("%s: %s without schemaLocation is not supported", file, child.name.Local)
+			}
+			if strings.Contains(location, "://") {
+				return fmt.Errorf("%s: remote schema %s is not available offline", file, location)
+			}
+			target := path.Join(path.Dir(file), location)
+			if err := l.load(target, doc.targetNS, child.name.Local == "include"); err != nil {
+				return err
+			}
+		case "redefine", "override":
+			return fmt.Errorf("%s: xs:%s is not supported", file, child.name.Local)
+		case "element", "complexType", "simpleType", "attribute":
+			name, ok := child.attr("name")
+			if !ok {
+				return fmt.Errorf("%s line %d: global xs:%s without name", file, child.line, child.name.Local)
+			}
+			qname := xml.Name{Space: doc.targetNS, Local: name}
+			registry := l.types
+			switch child.name.Local {
+			case "element":
+				registry = l.elements
+			case "attribute":
+				registry = l.attributes
+			}
+			if _, exists := registry[qname]; !exists {
+				registry[qname] = component{node: child, doc: doc}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve resolves a QName attribute value in the context of a schema document
+func (d *schemaDoc) resolve(n *node, value string) (xml.Name, error) {
+	name, err := n.resolveQName(value)
+	if err != nil {
+		return xml.Name{}, fmt.Errorf("%s line %d: %w", d.file, n.line, err)
+	}
+	if d.chameleon && name.Space == "" {
+		name.Space = d.targetNS
+	}
+	return name, nil
+}
+
+// errorf formats an error located at a schema node
+func (d *schemaDoc) errorf(n *node, format string, args ...interface{}) error {
+	return fmt.Errorf("%s line %d: %s", d.file, n.line, fmt.Sprintf(format, args...))
+}
+
+// typeByName returns the compiled type for a QName, compiling it on first use
+func (l *loader) typeByName(name xml.Name) (*typeDef, error) {
+	if name.Space == xsdNS {
+		return builtinTypeDef(name.Local), nil
+	}
+	if td, ok := l.compiledTypes[name]; ok {
+		return td, nil
+	}
+
+	c, ok := l.types[name]
+	if !ok {
+		return nil, fmt.Errorf("type {%s}%s is not declared", name.Space, name.Local)
+	}
+
+	// Register before compiling so recursive references resolve to this definition
+	td := &typeDef{name: name.Local}
+	l.compiledTypes[name] = td
+
+	if c.node.name.Local == "simpleType" {
+		simple, err := l.compileSimpleType(c.node, c.doc, name.Local)
+		if err != nil {
+			return nil, err
+		}
+		td.simple = simple
+		return td, nil
+	}
+
+	complexType, err := l.compileComplexType(c.node, c.doc, name.Local)
+	if err != nil {
+		return nil, err
+	}
+	td.complex = complexType
+	return td, nil
+}
+
+// elementByName returns the compiled global element declaration for a QName
+func (l *loader) elementByName(name xml.Name) (*elementDecl, error) {
+	if decl, ok := l.compiledElements[name]; ok {
+		return decl, nil
+	}
+
+	c, ok := l.elements[name]
+	if !ok {
+		return nil, fmt.Errorf("element {%s}%s is not declared", name.Space, name.Local)
+	}
+
+	decl := &elementDecl{name: name}
+	l.compiledElements[name] = decl
+	if err := l.fillElement(decl, c.node, c.doc); err != nil {
+		return nil, err
+	}
+	return decl, nil
+}
+
+// builtinTypeDef returns the type definition of an XML Schema built-in type
+func builtinTypeDef(local string) *typeDef {
+	if local == "anyType" {
+		return anyTypeDef()
+	}
+	return &typeDef{name: "xs:" + local, simple: builtinType(local)}
+}
+
+// anyTypeDef returns the ur-type, which accepts any attributes and content
+func anyTypeDef() *typeDef {
+	wildcard := &particle{kind: particleAny, min: 0, max: unbounded, namespaces: []string{"##any"}, processContents: "lax"}
+	return &typeDef{
+		name: "xs:anyType",
+		complex: &complexType{
+			name:         "xs:anyType",
+			content:      wildcard,
+			mixed:        true,
+			anyAttribute: true,
+			wildcards:    []*particle{wildcard},
+			elements:     map[xml.Name]*elementDecl{},
+		},
+	}
+}
+
+// fillElement compiles the type, fixed value and nillable flag of an element
+func (l *loader) fillElement(decl *elementDecl, n *node, doc *schemaDoc) error {
+	if fixed, ok := n.attr("fixed"); ok {
+		decl.fixed = &fixed
+	}
+	decl.nillable = n.attrDefault("nillable", "false") == "true"
+
+	if typeName, ok := n.attr("type"); ok {
+		qname, err := doc.resolve(n, typeName)
+		if err != nil {
+			return err
+		}
+		td, err := l.typeByName(qname)
+		if err != nil {
+			return doc.errorf(n, "%v", err)
+		}
+		decl.typ = td
+		return nil
+	}
+
+	for _, child := range n.xsdChildren() {
+		switch child.name.Local {
+		case "simpleType":
+			simple, err := l.compileSimpleType(child, doc, decl.name.Local)
+			if err != nil {
+				return err
+			}
+			decl.typ = &typeDef{name: decl.name.Local, simple: simple}
+			return nil
+		case "complexType":
+			complexType, err := l.compileComplexType(child, doc, decl.name.Local)
+			if err != nil {
+				return err
+			}
+			decl.typ = &typeDef{name: decl.name.Local, complex: complexType}
+			return nil
+		}
+	}
+
+	decl.typ = anyTypeDef()
+	return nil
+}
+
+// compileSimpleType compiles an xs:simpleType node
+func (l *loader) compileSimpleType(n *node, doc *schemaDoc, name string) (*simpleType, error) {
+	for _, child := range n.xsdChildren() {
+		switch child.name.Local {
+		case "restriction":
+			base, err := l.simpleBase(child, doc)
+			if err != nil {
+				return nil, err
+			}
+			f, err := parseFacets(child, doc)
+			if err != nil {
+				return nil, err
+			}
+			return &simpleType{name: name, base: base, facets: f}, nil
+
+		case "list":
+			if itemType, ok := child.attr("itemType"); ok {
+				item, err := l.simpleByQName(child, doc, itemType)
+				if err != nil {
+					return nil, err
+				}
+				return &simpleType{name: name, itemType: item}, nil
+			}
+			var item *simpleType
+			for _, inline := range child.xsdChildren() {
+				if inline.name.Local == "simpleType" {
+					compiled, err := l.compileSimpleType(inline, doc, name)
+					if err != nil {
+						return nil, err
+					}
+					item = compiled
+				}
+			}
+			if item == nil {
+				return nil, doc.errorf(child, "xs:list without item type")
+			}
+			return &simpleType{name: name, itemType: item}, nil
+
+		case "union":
+			union := &simpleType{name: name}
+			for _, member := range strings.Fields(child.attrDefault("memberTypes", "")) {
+				compiled, err := l.simpleByQName(child, doc, member)
+				if err != nil {
+					return nil, err
+				}
+				union.members = append(union.members, compiled)
+			}
+			for _, inline := range child.xsdChildren() {
+				if inline.name.Local == "simpleType" {
+					compiled, err := l.compileSimpleType(inline, doc, name)
+					if err != nil {
+						return nil, err
+					}
+					union.members = append(union.members, compiled)
+				}
+			}
+			return union, nil
+		}
+	}
+	return nil, doc.errorf(n, "xs:simpleType %q has no restriction, list or union", name)
+}
+
+// simpleBase returns the base type of a simple restriction, given by the base
+// attribute or an inline xs:simpleType
+func (l *loader) simpleBase(n *node, doc *schemaDoc) (*simpleType, error) {
+	if base, ok := n.attr("base"); ok {
+		return l.simpleByQName(n, doc, base)
+	}
+	for _, child := range n.xsdChildren() {
+		if child.name.Local == "simpleType" {
+			return l.compileSimpleType(child, doc, "")
+		}
+	}
+	return nil, doc.errorf(n, "xs:restriction without base type")
+}
+
+// simpleByQName resolves a QName that must denote a simple type
+func (l *loader) simpleByQName(n *node, doc *schemaDoc, value string) (*simpleType, error) {
+	qname, err := doc.resolve(n, value)
+	if err != nil {
+		return nil, err
+	}
+	td, err := l.typeByName(qname)
+	if err != nil {
+		return nil, doc.errorf(n, "%v", err)
+	}
+	if td.complex != nil {
+		if td.complex.simpleContent != nil {
+			return td.complex.simpleContent, nil
+		}
+		return nil, doc.errorf(n, "type %s is not a simple type", value)
+	}
+	if td.simple == nil {
+		return nil, doc.errorf(n, "type %s is defined recursively", value)
+	}
+	return td.simple, nil
+}
+
+// parseFacets reads the constraining facets of an xs:restriction node
+func parseFacets(n *node, doc *schemaDoc) (facets, error) {
+	var f facets
+	for _, child := range n.xsdChildren() {
+		value := child.attrDefault("value", "")
+		switch child.name.Local {
+		case "simpleType", "attribute", "anyAttribute", "sequence", "choice", "all":
+			// inline base type or complex restriction content, handled by the caller
+		case "enumeration":
+			f.enumeration = append(f.enumeration, value)
+		case "pattern":
+			compiled, err := compilePattern(value)
+			if err != nil {
+				return f, doc.errorf(child, "invalid pattern: %v", err)
+			}
+			f.patterns = append(f.patterns, compiled)
+			f.patternSources = append(f.patternSources, value)
+		case "whiteSpace":
+			f.whiteSpace = value
+		case "length", "minLength", "maxLength", "totalDigits", "fractionDigits":
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return f, doc.errorf(child, "invalid %s value %q", child.name.Local, value)
+			}
+			switch child.name.Local {
+			case "length":
+				f.length = &number
+			case "minLength":
+				f.minLength = &number
+			case "maxLength":
+				f.maxLength = &number
+			case "totalDigits":
+				f.totalDigits = &number
+			case "fractionDigits":
+				f.fractionDigits = &number
+			}
+		case "minInclusive", "maxInclusive", "minExclusive", "maxExclusive":
+			bound, ok := new(big.Rat).SetString(value)
+			if !ok {
+				return f, doc.errorf(child, "invalid %s value %q", child.name.Local, value)
+			}
+			switch child.name.Local {
+			case "minInclusive":
+				f.minInclusive = bound
+			case "maxInclusive":
+				f.maxInclusive = bound
+			case "minExclusive":
+				f.minExclusive = bound
+			case "maxExclusive":
+				f.maxExclusive = bound
+			}
+		default:
+			return f, doc.errorf(child, "facet xs:%s is not supported", child.name.Local)
+		}
+	}
+	return f, nil
+}
+
+// compileComplexType compiles an xs:complexType node
+func (l *loader) compileComplexType(n *node, doc *schemaDoc, name string) (*complexType, error) {
+	ct := &complexType{
+		name:  name,
+		mixed: n.attrDefault("mixed", "false") == "true",
+	}
+
+	if err := l.compileComplexChildren(ct, n, doc); err != nil {
+		return nil, err
+	}
+
+	ct.index()
+	return ct, nil
+}
+
+// compileComplexChildren compiles the content model and attributes declared
+// directly under a complexType, extension or restriction node
+func (l *loader) compileComplexChildren(ct *complexType, n *node, doc *schemaDoc) error {
+	for _, child := range n.xsdChildren() {
+		switch child.name.Local {
+		case "sequence", "choice", "all":
+			content, err := l.compileParticle(child, doc)
+			if err != nil {
+				return err
+			}
+			ct.content = content
+		case "attribute":
+			attribute, err := l.compileAttribute(child, doc)
+			if err != nil {
+				return err
+			}
+			ct.setAttribute(attribute)
+		case "anyAttribute":
+			ct.anyAttribute = true
+		case "simpleContent":
+			if err := l.compileSimpleContent(ct, child, doc); err != nil {
+				return err
+			}
+		case "complexContent":
+			if child.attrDefault("mixed", "false") == "true" {
+				ct.mixed = true
+			}
+			if err := l.compileComplexContent(ct, child, doc); err != nil {
+				return err
+			}
+		case "group", "attributeGroup":
+			return doc.errorf(child, "xs:%s is not supported", child.name.Local)
+		case "simpleType", "enumeration", "pattern", "length", "minLength", "maxLength",
+			"totalDigits", "fractionDigits", "minInclusive", "maxInclusive",
+			"minExclusive", "maxExclusive", "whiteSpace":
+			// facets of a simpleContent restriction, handled by compileSimpleContent
+		default:
+			return doc.errorf(child, "unexpected xs:%s in complex type", child.name.Local)
+		}
+	}
+	return nil
+}
+
+// compileSimpleContent compiles an xs:simpleContent derivation
+func (l *loader) compileSimpleContent(ct *complexType, n *node, doc *schemaDoc) error {
+	for _, derivation := range n.xsdChildren() {
+		baseName, ok := derivation.attr("base")
+		if !ok {
+			return doc.errorf(derivation, "xs:%s without base type", derivation.name.Local)
+		}
+		qname, err := doc.resolve(derivation, baseName)
+		if err != nil {
+			return err
+		}
+		td, err := l.typeByName(qname)
+		if err != nil {
+			return doc.errorf(derivation, "%v", err)
+		}
+
+		var base *simpleType
+		switch {
+		case td.simple != nil:
+			base = td.simple
+		case td.complex != nil && td.complex.simpleContent != nil:
+			base = td.complex.simpleContent
+			ct.attributes = append(ct.attributes, td.complex.attributes...)
+			ct.anyAttribute = td.complex.anyAttribute
+		default:
+			return doc.errorf(derivation, "base type %s has no simple content", baseName)
+		}
+
+		if derivation.name.Local == "restriction" {
+			f, err := parseFacets(derivation, doc)
+			if err != nil {
+				return err
+			}
+			base = &simpleType{name: ct.name, base: base, facets: f}
+		}
+		ct.simpleContent = base
+
+		if err := l.compileComplexChildren(ct, derivation, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileComplexContent compiles an xs:complexContent extension or restriction
+func (l *loader) compileComplexContent(ct *complexType, n *node, doc *schemaDoc) error {
+	for _, derivation := range n.xsdChildren() {
+		baseName, ok := derivation.attr("base")
+		if !ok {
+			return doc.errorf(derivation, "xs:%s without base type", derivation.name.Local)
+		}
+		qname, err := doc.resolve(derivation, baseName)
+		if err != nil {
+			return err
+		}
+		td, err := l.typeByName(qname)
+		if err != nil {
+			return doc.errorf(derivation, "%v", err)
+		}
+		if td.complex == nil {
+			return doc.errorf(derivation, "base type %s is not a complex type", baseName)
+		}
+
+		ct.attributes = append(ct.attributes, td.complex.attributes...)
+		ct.anyAttribute = td.complex.anyAttribute
+
+		if err := l.compileComplexChildren(ct, derivation, doc); err != nil {
+			return err
+		}
+
+		if derivation.name.Local == "extension" && td.complex.content != nil {
+			if ct.content == nil {
+				ct.content = td.complex.content
+			} else {
+				ct.content = &particle{
+					kind:     particleSequence,
+					children: []*particle{td.complex.content, ct.content},
+					min:      1,
+					max:      1,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// compileParticle compiles an element, sequence, choice, all or any node
+func (l *loader) compileParticle(n *node, doc *schemaDoc) (*particle, error) {
+	minOccurs, err := strconv.Atoi(n.attrDefault("minOccurs", "1"))
+	if err != nil {
+		return nil, doc.errorf(n, "invalid minOccurs")
+	}
+	maxOccurs := unbounded
+	if value := n.attrDefault("maxOccurs", "1"); value != "unbounded" {
+		if maxOccurs, err = strconv.Atoi(value); err != nil {
+			return nil, doc.errorf(n, "invalid maxOccurs")
+		}
+	}
+
+	p := &particle{min: minOccurs, max: maxOccurs}
+
+	switch n.name.Local {
+	case "element":
+		p.kind = particleElement
+		p.element, err = l.elementParticle(n, doc)
+		if err != nil {
+			return nil, err
+		}
+	case "any":
+		p.kind = particleAny
+		p.namespaces = strings.Fields(n.attrDefault("namespace", "##any"))
+		p.targetNS = doc.targetNS
+		p.processContents = n.attrDefault("processContents", "strict")
+	case "sequence", "choice", "all":
+		p.kind = map[string]particleKind{
+			"sequence": particleSequence,
+			"choice":   particleChoice,
+			"all":      particleAll,
+		}[n.name.Local]
+		for _, child := range n.xsdChildren() {
+			compiled, err := l.compileParticle(child, doc)
+			if err != nil {
+				return nil, err
+			}
+			p.children = append(p.children, compiled)
+		}
+	default:
+		return nil, doc.errorf(n, "xs:%s is not supported", n.name.Local)
+	}
+	return p, nil
+}
+
+// elementParticle returns the declaration of a local element or element reference
+func (l *loader) elementParticle(n *node, doc *schemaDoc) (*elementDecl, error) {
+	if ref, ok := n.attr("ref"); ok {
+		qname, err := doc.resolve(n, ref)
+		if err != nil {
+			return nil, err
+		}
+		decl, err := l.elementByName(qname)
+		if err != nil {
+			return nil, doc.errorf(n, "%v", err)
+		}
+		return decl, nil
+	}
+
+	name, ok := n.attr("name")
+	if !ok {
+		return nil, doc.errorf(n, "xs:element without name or ref")
+	}
+	decl := &elementDecl{name: xml.Name{Local: name}}
+	if form := n.attrDefault("form", ""); form == "qualified" || (form == "" && doc.elementQualified) {
+		decl.name.Space = doc.targetNS
+	}
+	if err := l.fillElement(decl, n, doc); err != nil {
+		return nil, err
+	}
+	return decl, nil
+}
+
+// compileAttribute compiles a local attribute or attribute reference
+func (l *loader) compileAttribute(n *node, doc *schemaDoc) (*attributeDecl, error) {
+	attribute := &attributeDecl{required: n.attrDefault("use", "optional") == "required"}
+	if fixed, ok := n.attr("fixed"); ok {
+		attribute.fixed = &fixed
+	}
+
+	if ref, ok := n.attr("ref"); ok {
+		qname, err := doc.resolve(n, ref)
+		if err != nil {
+			return nil, err
+		}
+		global, err := l.attributeByName(qname)
+		if err != nil {
+			return nil, doc.errorf(n, "%v", err)
+		}
+		attribute.name = global.name
+		attribute.typ = global.typ
+		if attribute.fixed == nil {
+			attribute.fixed = global.fixed
+		}
+		return attribute, nil
+	}
+
+	name, ok := n.attr("name")
+	if !ok {
+		return nil, doc.errorf(n, "xs:attribute without name or ref")
+	}
+	attribute.name = xml.Name{Local: name}
+	if form := n.attrDefault("form", ""); form == "qualified" || (form == "" && doc.attributeQualified) {
+		attribute.name.Space = doc.targetNS
+	}
+
+	typ, err := l.attributeType(n, doc)
+	if err != nil {
+		return nil, err
+	}
+	attribute.typ = typ
+	return attribute, nil
+}
+
+// attributeByName returns the compiled global attribute for a QName
+func (l *loader) attributeByName(name xml.Name) (*attributeDecl, error) {
+	if name.Space == xmlNS {
+		return &attributeDecl{name: name, typ: builtinType("string")}, nil
+	}
+	if attribute, ok := l.compiledAttributes[name]; ok {
+		return attribute, nil
+	}
+
+	c, ok := l.attributes[name]
+	if !ok {
+		return nil, fmt.Errorf("attribute {%s}%s is not declared", name.Space, name.Local)
+	}
+
+	attribute := &attributeDecl{name: name}
+	if fixed, ok := c.node.attr("fixed"); ok {
+		attribute.fixed = &fixed
+	}
+	typ, err := l.attributeType(c.node, c.doc)
+	if err != nil {
+		return nil, err
+	}
+	attribute.typ = typ
+	l.compiledAttributes[name] = attribute
+	return attribute, nil
+}
+
+// attributeType returns the simple type of an attribute declaration
+func (l *loader) attributeType(n *node, doc *schemaDoc) (*simpleType, error) {
+	if typeName, ok := n.attr("type"); ok {
+		return l.simpleByQName(n, doc, typeName)
+	}
+	for _, child := range n.xsdChildren() {
+		if child.name.Local == "simpleType" {
+			return l.compileSimpleType(child, doc, "")
+		}
+	}
+	return builtinType("anySimpleType"), nil
+}
+
+// setAttribute adds an attribute use, replacing an inherited one with the same name
+func (ct *complexType) setAttribute(attribute *attributeDecl) {
+	for i, existing := range ct.attributes {
+		if existing.name == attribute.name {
+			ct.attributes[i] = attribute
+			return
+		}
+	}
+	ct.attributes = append(ct.attributes, attribute)
+}
+
+// index records the element declarations and wildcards of the content model.
+// XML Schema requires same-named elements in one content model to share a
+// type, so a child element can be validated by name alone.
+func (ct *complexType) index() {
+	ct.elements = make(map[xml.Name]*elementDecl)
+	var walk func(p *particle)
+	walk = func(p *particle) {
+		if p == nil {
+			return
+		}
+		switch p.kind {
+		case particleElement:
+			if _, ok := ct.elements[p.element.name]; !ok {
+				ct.elements[p.element.name] = p.element
+			}
+		case particleAny:
+			ct.wildcards = append(ct.wildcards, p)
+		default:
+			for _, child := range p.children {
+				walk(child)
+			}
+		}
+	}
+	walk(ct.content)
+}
+
+// allows reports whether a wildcard accepts elements from namespace ns
+func (p *particle) allows(ns string) bool {
+	for _, allowed := range p.namespaces {
+		switch allowed {
+		case "##any":
+			return true
+		case "##other":
+			if ns != p.targetNS && ns != "" {
+				return true
+			}
+		case "##targetNamespace":
+			if ns == p.targetNS {
+				return true
+			}
+		case "##local":
+			if ns == "" {
+				return true
+			}
+		default:
+			if ns == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
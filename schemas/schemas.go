@@ -0,0 +1,198 @@
+// Package schemas validates NFe XML documents against the official XSD
+// schemas (PL_009_V4 package) before they are sent to SEFAZ.
+//
+// Schemas are read from a directory, usually common.Config.Schemes, or from
+// any fs.FS so applications can ship an embedded copy of the package:
+//
+//	//go:embed PL_009_V4/*.xsd
+//	var pl009 embed.FS
+//
+//	sub, _ := fs.Sub(pl009, "PL_009_V4")
+//	validator := schemas.NewValidatorFS(sub)
+//	violations, err := validator.Validate(schemas.DocNFe, xmlData)
+//
+// The package is not distributed with the module. Its tests run against
+// trimmed copies in testdata; set PL009_DIR to the extracted package to also
+// compile every official schema with go test.
+//
+// The validator implements the subset of XML Schema used by the SEFAZ
+// schemas: global and local elements, named and anonymous types, sequence,
+// choice, all, any, simple and complex content derivation, attributes and
+// every constraining facet. Schemas using other constructs fail to load
+// instead of being silently accepted.
+package schemas
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/adrianodrix/sped-nfe-go/common"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+// DocumentType identifies a SEFAZ message validated by its own root schema
+type DocumentType string
+
+const (
+	DocNFe          DocumentType = "NFe"          // NFe document
+	DocEnviNFe      DocumentType = "enviNFe"      // Lote de envio de NFe
+	DocInutNFe      DocumentType = "inutNFe"      // Inutilização de numeração
+	DocConsStatServ DocumentType = "consStatServ" // Consulta status do serviço
+	DocConsSitNFe   DocumentType = "consSitNFe"   // Consulta situação da NFe
+	DocConsCad      DocumentType = "ConsCad"      // Consulta cadastro
+	DocDistDFeInt   DocumentType = "distDFeInt"   // Distribuição DFe
+)
+
+// SchemaFiles maps each document type to its root schema file in PL_009_V4
+var SchemaFiles = map[DocumentType]string{
+	DocNFe:          "nfe_v4.00.xsd",
+	DocEnviNFe:      "enviNFe_v4.00.xsd",
+	DocInutNFe:      "inutNFe_v4.00.xsd",
+	DocConsStatServ: "consStatServ_v4.00.xsd",
+	DocConsSitNFe:   "consSitNFe_v4.00.xsd",
+	DocConsCad:      "consCad_v2.00.xsd",
+	DocDistDFeInt:   "distDFeInt_v1.01.xsd",
+}
+
+// EventSchemaFiles maps each event type to the envEvento schema of its package
+var EventSchemaFiles = map[types.TipoEvento]string{
+	types.EvtCCe:                 "envCCe_v1.00.xsd",
+	types.EvtCancela:             "envEventoCancNFe_v1.00.xsd",
+	types.EvtCancelaSubstituicao: "envEventoCancSubst_v1.00.xsd",
+	types.EvtEPEC:                "envEPEC_v1.00.xsd",
+	types.EvtConfirmacao:         "envConfRecebto_v1.00.xsd",
+	types.EvtCiencia:             "envConfRecebto_v1.00.xsd",
+	types.EvtDesconhecimento:     "envConfRecebto_v1.00.xsd",
+	types.EvtNaoRealizada:        "envConfRecebto_v1.00.xsd",
+}
+
+// Violation describes a schema violation found in an instance document
+type Violation struct {
+	XPath   string `json:"xpath"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// String returns a human-readable representation of the violation
+func (v Violation) String() string {
+	return fmt.Sprintf("%s (line %d): %s", v.XPath, v.Line, v.Message)
+}
+
+// Schema is a compiled root schema together with everything it includes and imports
+type Schema struct {
+	file     string
+	elements map[xml.Name]*elementDecl
+}
+
+// Validator loads schemas from a file system and caches them after compilation.
+// It is safe for concurrent use.
+type Validator struct {
+	fsys  fs.FS
+	mutex sync.Mutex
+	cache map[string]*Schema
+}
+
+// NewValidator creates a validator reading schemas from a directory
+func NewValidator(dir string) (*Validator, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, errors.NewConfigError("schemes path cannot be empty", "schemes", dir)
+	}
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, errors.NewConfigError("schemes path is not a directory", "schemes", dir)
+	}
+	return NewValidatorFS(os.DirFS(dir)), nil
+}
+
+// NewValidatorFS creates a validator reading schemas from fsys, such as an embed.FS
+func NewValidatorFS(fsys fs.FS) *Validator {
+	return &Validator{
+		fsys:  fsys,
+		cache: make(map[string]*Schema),
+	}
+}
+
+// NewValidatorFromConfig creates a validator for the schema directory in config
+func NewValidatorFromConfig(config *common.Config) (*Validator, error) {
+	if config == nil {
+		return nil, errors.NewConfigError("config cannot be nil", "config", nil)
+	}
+	return NewValidator(config.Schemes)
+}
+
+// Load compiles a root schema file, returning the cached copy when already loaded
+func (v *Validator) Load(file string) (*Schema, error) {
+	file = path.Clean(file)
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if schema, ok := v.cache[file]; ok {
+		return schema, nil
+	}
+
+	elements, err := compileSchema(v.fsys, file)
+	if err != nil {
+		return nil, errors.NewXMLError(fmt.Sprintf("failed to load schema %s: %v", file, err), "schema", err)
+	}
+
+	schema := &Schema{file: file, elements: elements}
+	v.cache[file] = schema
+	return schema, nil
+}
+
+// Validate validates data against the root schema of a document type.
+// A nil slice means the document is valid; the error reports problems loading
+// the schema or parsing data.
+func (v *Validator) Validate(doc DocumentType, data []byte) ([]Violation, error) {
+	file, ok := SchemaFiles[doc]
+	if !ok {
+		return nil, errors.NewValidationError("unknown document type", "documentType", doc)
+	}
+	return v.ValidateFile(file, data)
+}
+
+// ValidateEvento validates an envEvento message against the schema of its event type
+func (v *Validator) ValidateEvento(tpEvento types.TipoEvento, data []byte) ([]Violation, error) {
+	file, ok := EventSchemaFiles[tpEvento]
+	if !ok {
+		return nil, errors.NewValidationError("no schema registered for event type", "tpEvento", tpEvento)
+	}
+	return v.ValidateFile(file, data)
+}
+
+// ValidateFile validates data against an arbitrary root schema file
+func (v *Validator) ValidateFile(file string, data []byte) ([]Violation, error) {
+	schema, err := v.Load(file)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Validate(data)
+}
+
+// File returns the root schema file the schema was loaded from
+func (s *Schema) File() string {
+	return s.file
+}
+
+// Validate validates an XML document and returns every violation found
+func (s *Schema) Validate(data []byte) ([]Violation, error) {
+	if len(data) == 0 {
+		return nil, errors.NewValidationError("XML data cannot be empty", "xml", "")
+	}
+
+	root, err := parseTree(data)
+	if err != nil {
+		return nil, errors.NewXMLError("failed to parse XML document", "xml", err)
+	}
+
+	v := &validation{elements: s.elements}
+	v.root(root)
+	return v.violations, nil
+}
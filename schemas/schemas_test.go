@@ -0,0 +1,429 @@
+package schemas
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adrianodrix/sped-nfe-go/common"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+const validNFe = `<?xml version="1.0" encoding="UTF-8"?>
+<NFe xmlns="http://www.portalfiscal.inf.br/nfe">
+	<infNFe versao="4.00" Id="NFe35240511222333000181550010000012341123456789">
+		<ide>
+			<cUF>35</cUF>
+			<natOp>VENDA DE MERCADORIA</natOp>
+			<mod>55</mod>
+			<dhEmi>2024-05-10T14:30:00-03:00</dhEmi>
+			<tpAmb>2</tpAmb>
+		</ide>
+		<emit>
+			<CNPJ>11222333000181</CNPJ>
+			<xNome>EMPRESA TESTE LTDA</xNome>
+		</emit>
+		<det nItem="1">
+			<prod>
+				<cProd>001</cProd>
+				<qCom>1.0000</qCom>
+				<vProd>10.00</vProd>
+			</prod>
+		</det>
+		<det nItem="2">
+			<prod>
+				<cProd>002</cProd>
+				<qCom>2</qCom>
+				<vProd>5.50</vProd>
+			</prod>
+			<infAdProd>LOTE 42</infAdProd>
+		</det>
+		<total>
+			<vNF>15.50</vNF>
+			<vProd>15.50</vProd>
+		</total>
+	</infNFe>
+	<Signature xmlns="http://www.w3.org/2000/09/xmldsig#">
+		<SignedInfo><CanonicalizationMethod Algorithm="http://www.w3.org/TR/2001/REC-xml-c14n-20010315"/></SignedInfo>
+		<SignatureValue>c2lnbmF0dXJl</SignatureValue>
+		<KeyInfo><X509Data><X509Certificate>Y2VydGlmaWNhdGU=</X509Certificate></X509Data></KeyInfo>
+	</Signature>
+</NFe>`
+
+func newTestValidator(t *testing.T) *Validator {
+	t.Helper()
+	validator, err := NewValidator("testdata")
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	return validator
+}
+
+func TestValidateValidDocument(t *testing.T) {
+	validator := newTestValidator(t)
+
+	violations, err := validator.Validate(DocNFe, []byte(validNFe))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Validate() violations = %v, want none", violations)
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	validator := newTestValidator(t)
+
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		xpath   string
+		message string
+	}{
+		{
+			name:    "pattern",
+			old:     "<CNPJ>11222333000181</CNPJ>",
+			new:     "<CNPJ>11.222.333/0001-81</CNPJ>",
+			xpath:   "/NFe/infNFe/emit/CNPJ",
+			message: "does not match pattern",
+		},
+		{
+			name:    "enumeration",
+			old:     "<cUF>35</cUF>",
+			new:     "<cUF>99</cUF>",
+			xpath:   "/NFe/infNFe/ide/cUF",
+			message: "not one of the allowed values",
+		},
+		{
+			name:    "max length",
+			old:     "<natOp>VENDA DE MERCADORIA</natOp>",
+			new:     "<natOp>" + strings.Repeat("X", 61) + "</natOp>",
+			xpath:   "/NFe/infNFe/ide/natOp",
+			message: "at most 60 characters",
+		},
+		{
+			name:    "missing element",
+			old:     "<tpAmb>2</tpAmb>",
+			new:     "",
+			xpath:   "/NFe/infNFe/ide",
+			message: "incomplete; expected tpAmb",
+		},
+		{
+			name:    "unexpected element",
+			old:     "<mod>55</mod>",
+			new:     "<serie>1</serie><mod>55</mod>",
+			xpath:   "/NFe/infNFe/ide/serie",
+			message: "serie is not expected; expected mod",
+		},
+		{
+			name:    "choice",
+			old:     "<CNPJ>11222333000181</CNPJ>",
+			new:     "",
+			xpath:   "/NFe/infNFe/emit/xNome",
+			message: "expected CNPJ, CPF",
+		},
+		{
+			name:    "repeated sibling index",
+			old:     "<vProd>5.50</vProd>",
+			new:     "<vProd>5.5</vProd>",
+			xpath:   "/NFe/infNFe/det[2]/prod/vProd",
+			message: "does not match pattern",
+		},
+		{
+			name:    "missing required attribute",
+			old:     `<det nItem="1">`,
+			new:     `<det>`,
+			xpath:   "/NFe/infNFe/det[1]",
+			message: "missing required attribute nItem",
+		},
+		{
+			name:    "undeclared attribute",
+			old:     `<ide>`,
+			new:     `<ide foo="bar">`,
+			xpath:   "/NFe/infNFe/ide",
+			message: "attribute foo is not allowed",
+		},
+		{
+			name:    "attribute pattern",
+			old:     `versao="4.00"`,
+			new:     `versao="3.10"`,
+			xpath:   "/NFe/infNFe",
+			message: "attribute versao",
+		},
+		{
+			name:    "builtin decimal",
+			old:     "<qCom>2</qCom>",
+			new:     "<qCom>dois</qCom>",
+			xpath:   "/NFe/infNFe/det[2]/prod/qCom",
+			message: "not a valid decimal",
+		},
+		{
+			name:    "all group",
+			old:     "<vNF>15.50</vNF>",
+			new:     "",
+			xpath:   "/NFe/infNFe/total",
+			message: "incomplete; expected vNF",
+		},
+		{
+			name:    "imported namespace",
+			old:     "<SignatureValue>c2lnbmF0dXJl</SignatureValue>",
+			new:     "",
+			xpath:   "/NFe/Signature/KeyInfo",
+			message: "KeyInfo is not expected; expected SignatureValue",
+		},
+		{
+			name:    "simple content",
+			old:     "<SignatureValue>c2lnbmF0dXJl</SignatureValue>",
+			new:     "<SignatureValue>%%%</SignatureValue>",
+			xpath:   "/NFe/Signature/SignatureValue",
+			message: "not a valid base64Binary",
+		},
+		{
+			name:    "text in element content",
+			old:     "<emit>",
+			new:     "<emit>texto",
+			xpath:   "/NFe/infNFe/emit",
+			message: "must not contain text",
+		},
+		{
+			name:    "missing namespace",
+			old:     `<NFe xmlns="http://www.portalfiscal.inf.br/nfe">`,
+			new:     `<NFe>`,
+			xpath:   "/NFe",
+			message: "no global declaration for element NFe (no namespace)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := strings.Replace(validNFe, tt.old, tt.new, 1)
+			if data == validNFe {
+				t.Fatalf("test mutation %q did not apply", tt.old)
+			}
+
+			violations, err := validator.Validate(DocNFe, []byte(data))
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if len(violations) != 1 {
+				t.Fatalf("Validate() violations = %v, want exactly one", violations)
+			}
+			if violations[0].XPath != tt.xpath {
+				t.Errorf("XPath = %q, want %q", violations[0].XPath, tt.xpath)
+			}
+			if !strings.Contains(violations[0].Message, tt.message) {
+				t.Errorf("Message = %q, want it to contain %q", violations[0].Message, tt.message)
+			}
+			if violations[0].Line == 0 {
+				t.Errorf("Line = 0, want the line of the offending element")
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	validator := newTestValidator(t)
+
+	data := strings.Replace(validNFe, "<cUF>35</cUF>", "<cUF>99</cUF>", 1)
+	data = strings.Replace(data, "<vProd>10.00</vProd>", "<vProd>10,00</vProd>", 1)
+
+	violations, err := validator.Validate(DocNFe, []byte(data))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("Validate() violations = %v, want 2", violations)
+	}
+	if violations[0].XPath != "/NFe/infNFe/ide/cUF" || violations[1].XPath != "/NFe/infNFe/det[1]/prod/vProd" {
+		t.Errorf("violations not reported in document order: %v", violations)
+	}
+}
+
+func TestValidateConsStatServ(t *testing.T) {
+	validator := newTestValidator(t)
+
+	tests := []struct {
+		name       string
+		xml        string
+		violations int
+	}{
+		{
+			name:       "valid",
+			xml:        `<consStatServ xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><cUF>35</cUF><xServ>STATUS</xServ></consStatServ>`,
+			violations: 0,
+		},
+		{
+			name:       "token whitespace is collapsed",
+			xml:        `<consStatServ xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><cUF>35</cUF><xServ> STATUS </xServ></consStatServ>`,
+			violations: 0,
+		},
+		{
+			name:       "wrong service",
+			xml:        `<consStatServ xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><cUF>35</cUF><xServ>CONSULTAR</xServ></consStatServ>`,
+			violations: 1,
+		},
+		{
+			name:       "missing version",
+			xml:        `<consStatServ xmlns="http://www.portalfiscal.inf.br/nfe"><tpAmb>3</tpAmb><cUF>35</cUF><xServ>STATUS</xServ></consStatServ>`,
+			violations: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := validator.Validate(DocConsStatServ, []byte(tt.xml))
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if len(violations) != tt.violations {
+				t.Errorf("Validate() violations = %v, want %d", violations, tt.violations)
+			}
+		})
+	}
+}
+
+// TestOfficialPackage compiles every schema of the official PL_009_V4 package,
+// which is not distributed with the module. Set PL009_DIR to the directory of
+// the extracted package to run it.
+func TestOfficialPackage(t *testing.T) {
+	dir := os.Getenv("PL009_DIR")
+	if dir == "" {
+		t.Skip("PL009_DIR not set")
+	}
+	validator, err := NewValidator(dir)
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, SchemaFiles[DocEnviNFe])); err != nil {
+		t.Fatalf("PL009_DIR does not hold the PL_009_V4 package: %v", err)
+	}
+
+	// consulta cadastro, distribuição DF-e and the events ship in packages of
+	// their own, which may be extracted to the same directory
+	files := make(map[string]bool)
+	for _, file := range SchemaFiles {
+		files[file] = true
+	}
+	for _, file := range EventSchemaFiles {
+		files[file] = true
+	}
+	for file := range files {
+		t.Run(file, func(t *testing.T) {
+			if _, err := os.Stat(filepath.Join(dir, file)); os.IsNotExist(err) {
+				t.Skipf("%s not in the package", file)
+			}
+			if _, err := validator.Load(file); err != nil {
+				t.Errorf("Load() error = %v", err)
+			}
+		})
+	}
+
+	violations, err := validator.Validate(DocConsStatServ, []byte(`<consStatServ xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><cUF>35</cUF><xServ>STATUS</xServ></consStatServ>`))
+	if err != nil || len(violations) != 0 {
+		t.Errorf("Validate() = %v, %v, want no violations", violations, err)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	validator := newTestValidator(t)
+
+	if _, err := validator.Validate(DocNFe, nil); err == nil {
+		t.Error("Validate() with empty data should fail")
+	}
+	if _, err := validator.Validate(DocNFe, []byte("<NFe><infNFe></NFe>")); err == nil {
+		t.Error("Validate() with malformed XML should fail")
+	}
+	if _, err := validator.Validate(DocumentType("unknown"), []byte(validNFe)); err == nil {
+		t.Error("Validate() with unknown document type should fail")
+	}
+	if _, err := validator.Validate(DocInutNFe, []byte(validNFe)); err == nil {
+		t.Error("Validate() with a missing schema file should fail")
+	}
+	if _, err := validator.ValidateEvento(types.EvtConciliacao, []byte(validNFe)); err == nil {
+		t.Error("ValidateEvento() with an event without schema should fail")
+	}
+	if _, err := validator.Load("unsupported_group.xsd"); err == nil || !strings.Contains(err.Error(), "xs:group is not supported") {
+		t.Errorf("Load() error = %v, want unsupported xs:group", err)
+	}
+}
+
+func TestNewValidator(t *testing.T) {
+	if _, err := NewValidator(""); err == nil {
+		t.Error("NewValidator() with empty path should fail")
+	}
+	if _, err := NewValidator("testdata/nfe_v4.00.xsd"); err == nil {
+		t.Error("NewValidator() with a file path should fail")
+	}
+	if _, err := NewValidatorFromConfig(nil); err == nil {
+		t.Error("NewValidatorFromConfig() with nil config should fail")
+	}
+	if _, err := NewValidatorFromConfig(&common.Config{Schemes: "testdata"}); err != nil {
+		t.Errorf("NewValidatorFromConfig() error = %v", err)
+	}
+}
+
+func TestValidatorFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/root.xsd": {Data: []byte(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:test" xmlns="urn:test" elementFormDefault="qualified">
+	<xs:include schemaLocation="types/chameleon.xsd"/>
+	<xs:element name="root">
+		<xs:complexType>
+			<xs:sequence>
+				<xs:element name="code" type="TCode" maxOccurs="unbounded"/>
+			</xs:sequence>
+		</xs:complexType>
+	</xs:element>
+</xs:schema>`)},
+		"schemas/types/chameleon.xsd": {Data: []byte(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+	<xs:simpleType name="TCode">
+		<xs:restriction base="xs:unsignedByte"/>
+	</xs:simpleType>
+</xs:schema>`)},
+	}
+
+	validator := NewValidatorFS(fsys)
+
+	violations, err := validator.ValidateFile("schemas/root.xsd", []byte(`<t:root xmlns:t="urn:test"><t:code>1</t:code><t:code>300</t:code></t:root>`))
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(violations) != 1 || violations[0].XPath != "/root/code[2]" {
+		t.Errorf("ValidateFile() violations = %v, want out of range at /root/code[2]", violations)
+	}
+}
+
+func TestValidatorConcurrentUse(t *testing.T) {
+	validator := newTestValidator(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			violations, err := validator.Validate(DocNFe, []byte(validNFe))
+			if err != nil || len(violations) != 0 {
+				t.Errorf("Validate() = %v, %v", violations, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	schema, err := validator.Load("./nfe_v4.00.xsd")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if schema.File() != "nfe_v4.00.xsd" {
+		t.Errorf("File() = %q", schema.File())
+	}
+}
+
+func TestViolationString(t *testing.T) {
+	v := Violation{XPath: "/NFe/infNFe/ide/cUF", Line: 5, Message: "invalid"}
+	if got := v.String(); got != "/NFe/infNFe/ide/cUF (line 5): invalid" {
+		t.Errorf("String() = %q", got)
+	}
+}
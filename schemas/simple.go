@@ -0,0 +1,362 @@
+package schemas
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// simpleType is a compiled XSD simple type: a built-in primitive or a
+// restriction, list or union derived from other simple types
+type simpleType struct {
+	name     string
+	builtin  string
+	base     *simpleType
+	itemType *simpleType
+	members  []*simpleType
+	facets   facets
+}
+
+// facets holds the constraining facets of one restriction step
+type facets struct {
+	enumeration    []string
+	patterns       []*regexp.Regexp
+	patternSources []string
+	length         *int
+	minLength      *int
+	maxLength      *int
+	totalDigits    *int
+	fractionDigits *int
+	minInclusive   *big.Rat
+	maxInclusive   *big.Rat
+	minExclusive   *big.Rat
+	maxExclusive   *big.Rat
+	whiteSpace     string
+}
+
+// Built-in type patterns
+var (
+	decimalPattern  = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	integerPattern  = regexp.MustCompile(`^[+-]?\d+$`)
+	dateTimePattern = regexp.MustCompile(`^-?\d{4,}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?$`)
+	datePattern     = regexp.MustCompile(`^-?\d{4,}-\d{2}-\d{2}(Z|[+-]\d{2}:\d{2})?$`)
+	timePattern     = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?$`)
+	doublePattern   = regexp.MustCompile(`^([+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?|INF|-INF|NaN)$`)
+)
+
+// integerRanges holds the value space limits of the derived integer types
+var integerRanges = map[string][2]string{
+	"nonNegativeInteger": {"0", ""},
+	"positiveInteger":    {"1", ""},
+	"nonPositiveInteger": {"", "0"},
+	"negativeInteger":    {"", "-1"},
+	"long":               {"-9223372036854775808", "9223372036854775807"},
+	"int":                {"-2147483648", "2147483647"},
+	"short":              {"-32768", "32767"},
+	"byte":               {"-128", "127"},
+	"unsignedLong":       {"0", "18446744073709551615"},
+	"unsignedInt":        {"0", "4294967295"},
+	"unsignedShort":      {"0", "65535"},
+	"unsignedByte":       {"0", "255"},
+	"integer":            {"", ""},
+}
+
+// builtinType returns the simple type for an XML Schema built-in type
+func builtinType(local string) *simpleType {
+	return &simpleType{name: "xs:" + local, builtin: local}
+}
+
+// primitive returns the built-in type at the root of the derivation chain
+func (t *simpleType) primitive() string {
+	for current := t; current != nil; current = current.base {
+		if current.builtin != "" {
+			return current.builtin
+		}
+	}
+	return "string"
+}
+
+// whiteSpace returns the effective whiteSpace facet
+func (t *simpleType) whiteSpace() string {
+	for current := t; current != nil; current = current.base {
+		if current.facets.whiteSpace != "" {
+			return current.facets.whiteSpace
+		}
+	}
+	switch t.primitive() {
+	case "string", "anySimpleType":
+		return "preserve"
+	case "normalizedString":
+		return "replace"
+	default:
+		return "collapse"
+	}
+}
+
+// validate checks a lexical value against the type and returns a description
+// of the first constraint it violates, or an empty string when valid
+func (t *simpleType) validate(value string) string {
+	switch t.whiteSpace() {
+	case "replace":
+		value = strings.Map(replaceWhiteSpace, value)
+	case "collapse":
+		value = strings.Join(strings.Fields(value), " ")
+	}
+	return t.check(value)
+}
+
+// check validates an already whitespace-normalized value
+func (t *simpleType) check(value string) string {
+	if t.itemType != nil {
+		for _, item := range strings.Fields(value) {
+			if problem := t.itemType.validate(item); problem != "" {
+				return problem
+			}
+		}
+		return t.facets.check(value, "list")
+	}
+
+	if len(t.members) > 0 {
+		for _, member := range t.members {
+			if member.validate(value) == "" {
+				return t.facets.check(value, "union")
+			}
+		}
+		return fmt.Sprintf("value %q does not match any member of union %s", value, t.name)
+	}
+
+	if t.builtin != "" {
+		if problem := checkBuiltin(t.builtin, value); problem != "" {
+			return problem
+		}
+	} else if t.base != nil {
+		if problem := t.base.check(value); problem != "" {
+			return problem
+		}
+	}
+
+	return t.facets.check(value, t.primitive())
+}
+
+// check validates a value against the facets of one restriction step
+func (f *facets) check(value, primitive string) string {
+	if len(f.enumeration) > 0 {
+		found := false
+		for _, allowed := range f.enumeration {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("value %q is not one of the allowed values [%s]", value, strings.Join(f.enumeration, ", "))
+		}
+	}
+
+	if len(f.patterns) > 0 {
+		matched := false
+		for _, pattern := range f.patterns {
+			if pattern.MatchString(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Sprintf("value %q does not match pattern %s", value, strings.Join(f.patternSources, " | "))
+		}
+	}
+
+	length := valueLength(value, primitive)
+	if f.length != nil && length != *f.length {
+		return fmt.Sprintf("value %q must have length %d, got %d", value, *f.length, length)
+	}
+	if f.minLength != nil && length < *f.minLength {
+		return fmt.Sprintf("value %q must have at least %d characters, got %d", value, *f.minLength, length)
+	}
+	if f.maxLength != nil && length > *f.maxLength {
+		return fmt.Sprintf("value %q must have at most %d characters, got %d", value, *f.maxLength, length)
+	}
+
+	if f.totalDigits != nil || f.fractionDigits != nil {
+		total, fraction := countDigits(value)
+		if f.totalDigits != nil && total > *f.totalDigits {
+			return fmt.Sprintf("value %q must have at most %d digits, got %d", value, *f.totalDigits, total)
+		}
+		if f.fractionDigits != nil && fraction > *f.fractionDigits {
+			return fmt.Sprintf("value %q must have at most %d fraction digits, got %d", value, *f.fractionDigits, fraction)
+		}
+	}
+
+	if f.minInclusive != nil || f.maxInclusive != nil || f.minExclusive != nil || f.maxExclusive != nil {
+		number, ok := new(big.Rat).SetString(value)
+		if !ok {
+			return fmt.Sprintf("value %q is not numeric", value)
+		}
+		if f.minInclusive != nil && number.Cmp(f.minInclusive) < 0 {
+			return fmt.Sprintf("value %q must be >= %s", value, f.minInclusive.RatString())
+		}
+		if f.maxInclusive != nil && number.Cmp(f.maxInclusive) > 0 {
+			return fmt.Sprintf("value %q must be <= %s", value, f.maxInclusive.RatString())
+		}
+		if f.minExclusive != nil && number.Cmp(f.minExclusive) <= 0 {
+			return fmt.Sprintf("value %q must be > %s", value, f.minExclusive.RatString())
+		}
+		if f.maxExclusive != nil && number.Cmp(f.maxExclusive) >= 0 {
+			return fmt.Sprintf("value %q must be < %s", value, f.maxExclusive.RatString())
+		}
+	}
+
+	return ""
+}
+
+// checkBuiltin validates the lexical space of a built-in type
+func checkBuiltin(builtin, value string) string {
+	valid := true
+	switch builtin {
+	case "decimal":
+		valid = decimalPattern.MatchString(value)
+	case "float", "double":
+		valid = doublePattern.MatchString(value)
+	case "boolean":
+		valid = value == "true" || value == "false" || value == "1" || value == "0"
+	case "dateTime":
+		valid = dateTimePattern.MatchString(value)
+	case "date":
+		valid = datePattern.MatchString(value)
+	case "time":
+		valid = timePattern.MatchString(value)
+	case "base64Binary":
+		_, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+		valid = err == nil
+	case "hexBinary":
+		_, err := hex.DecodeString(value)
+		valid = err == nil
+	default:
+		if limits, ok := integerRanges[builtin]; ok {
+			return checkInteger(builtin, value, limits)
+		}
+	}
+
+	if !valid {
+		return fmt.Sprintf("value %q is not a valid %s", value, builtin)
+	}
+	return ""
+}
+
+// checkInteger validates integer types and their value ranges
+func checkInteger(builtin, value string, limits [2]string) string {
+	if !integerPattern.MatchString(value) {
+		return fmt.Sprintf("value %q is not a valid %s", value, builtin)
+	}
+
+	number, _ := new(big.Int).SetString(strings.TrimPrefix(value, "+"), 10)
+	if limits[0] != "" {
+		lower, _ := new(big.Int).SetString(limits[0], 10)
+		if number.Cmp(lower) < 0 {
+			return fmt.Sprintf("value %q is out of range for %s", value, builtin)
+		}
+	}
+	if limits[1] != "" {
+		upper, _ := new(big.Int).SetString(limits[1], 10)
+		if number.Cmp(upper) > 0 {
+			return fmt.Sprintf("value %q is out of range for %s", value, builtin)
+		}
+	}
+	return ""
+}
+
+// valueLength measures a value for the length facets: octets for binary
+// types, items for lists and characters otherwise
+func valueLength(value, primitive string) int {
+	switch primitive {
+	case "base64Binary":
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+		if err == nil {
+			return len(decoded)
+		}
+	case "hexBinary":
+		return len(value) / 2
+	case "list":
+		return len(strings.Fields(value))
+	}
+	return utf8.RuneCountInString(value)
+}
+
+// countDigits returns the total and fraction digits of a decimal value,
+// ignoring leading and trailing zeros as the XSD value space does
+func countDigits(value string) (int, int) {
+	value = strings.TrimLeft(value, "+-")
+	integer, fraction := value, ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		integer, fraction = value[:i], value[i+1:]
+	}
+	integer = strings.TrimLeft(integer, "0")
+	fraction = strings.TrimRight(fraction, "0")
+	return len(integer) + len(fraction), len(fraction)
+}
+
+// replaceWhiteSpace implements the whiteSpace="replace" facet
+func replaceWhiteSpace(r rune) rune {
+	if r == '\t' || r == '\n' || r == '\r' {
+		return ' '
+	}
+	return r
+}
+
+// compilePattern translates an XSD regular expression into an anchored Go regexp.
+// XSD expressions are implicitly anchored and treat ^ and $ as literals.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	inClass := 0
+	runes := []rune(pattern)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			next := runes[i+1]
+			switch next {
+			case 'i':
+				b.WriteString(classOrSet(inClass, `A-Za-z_:`))
+			case 'I':
+				b.WriteString(`[^A-Za-z_:]`)
+			case 'c':
+				b.WriteString(classOrSet(inClass, `\-.0-9A-Za-z_:`))
+			case 'C':
+				b.WriteString(`[^\-.0-9A-Za-z_:]`)
+			default:
+				b.WriteRune(r)
+				b.WriteRune(next)
+			}
+			i++
+		case r == '[':
+			if inClass > 0 && i > 0 && runes[i-1] == '-' {
+				return nil, fmt.Errorf("character class subtraction is not supported in pattern %q", pattern)
+			}
+			inClass++
+			b.WriteRune(r)
+		case r == ']' && inClass > 0:
+			inClass--
+			b.WriteRune(r)
+		case (r == '^' || r == '$') && inClass == 0:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return regexp.Compile("^(?:" + b.String() + ")$")
+}
+
+// classOrSet returns a character set usable inside or outside a bracket expression
+func classOrSet(inClass int, set string) string {
+	if inClass > 0 {
+		return set
+	}
+	return "[" + set + "]"
+}
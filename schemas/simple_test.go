@@ -0,0 +1,113 @@
+package schemas
+
+import "testing"
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{`[0-9]{14}`, "11222333000181", true},
+		{`[0-9]{14}`, "1122233300018", false},
+		{`[0-9]{14}`, "x11222333000181", false},
+		{`0|0\.[0-9]{2}|[1-9]{1}[0-9]{0,12}(\.[0-9]{2})?`, "0.50", true},
+		{`0|0\.[0-9]{2}|[1-9]{1}[0-9]{0,12}(\.[0-9]{2})?`, "10.5", false},
+		{`a$b`, "a$b", true},
+		{`^ab`, "^ab", true},
+		{`[^0-9]+`, "abc", true},
+		{`\i\c*`, "nItem1", true},
+		{`\i\c*`, "1nItem", false},
+		{`[!-ÿ]{1}[ -ÿ]{0,}[!-ÿ]{1}|[!-ÿ]{1}`, "VENDA DE MERCADORIA", true},
+		{`[!-ÿ]{1}[ -ÿ]{0,}[!-ÿ]{1}|[!-ÿ]{1}`, " VENDA", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.value, func(t *testing.T) {
+			re, err := compilePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("compilePattern() error = %v", err)
+			}
+			if got := re.MatchString(tt.value); got != tt.want {
+				t.Errorf("match %q = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := compilePattern(`[a-z-[aeiou]]`); err == nil {
+		t.Error("compilePattern() should reject character class subtraction")
+	}
+}
+
+func TestSimpleTypeValidate(t *testing.T) {
+	two, four := 2, 4
+
+	tests := []struct {
+		name  string
+		typ   *simpleType
+		value string
+		valid bool
+	}{
+		{"integer", builtinType("integer"), "-42", true},
+		{"integer invalid", builtinType("integer"), "4.2", false},
+		{"unsignedByte range", builtinType("unsignedByte"), "256", false},
+		{"positiveInteger", builtinType("positiveInteger"), "0", false},
+		{"boolean", builtinType("boolean"), "true", true},
+		{"date", builtinType("date"), "2024-05-10", true},
+		{"dateTime", builtinType("dateTime"), "2024-05-10T10:00:00-03:00", true},
+		{"dateTime invalid", builtinType("dateTime"), "10/05/2024", false},
+		{"hexBinary", builtinType("hexBinary"), "0aFF", true},
+		{"collapse", builtinType("int"), "  42\n", true},
+		{
+			"total digits",
+			&simpleType{base: builtinType("decimal"), facets: facets{totalDigits: &four, fractionDigits: &two}},
+			"12.34",
+			true,
+		},
+		{
+			"total digits exceeded",
+			&simpleType{base: builtinType("decimal"), facets: facets{totalDigits: &four, fractionDigits: &two}},
+			"123.45",
+			false,
+		},
+		{
+			"fraction digits ignore trailing zeros",
+			&simpleType{base: builtinType("decimal"), facets: facets{fractionDigits: &two}},
+			"1.2300",
+			true,
+		},
+		{
+			"list",
+			&simpleType{itemType: builtinType("int"), facets: facets{length: &two}},
+			"1 2",
+			true,
+		},
+		{
+			"list length",
+			&simpleType{itemType: builtinType("int"), facets: facets{length: &two}},
+			"1 2 3",
+			false,
+		},
+		{
+			"union",
+			&simpleType{members: []*simpleType{builtinType("int"), builtinType("boolean")}},
+			"false",
+			true,
+		},
+		{
+			"union invalid",
+			&simpleType{members: []*simpleType{builtinType("int"), builtinType("boolean")}},
+			"maybe",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := tt.typ.validate(tt.value)
+			if (problem == "") != tt.valid {
+				t.Errorf("validate(%q) = %q, want valid=%v", tt.value, problem, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package schemas
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// node is a generic XML element used for both schema documents and the
+// instance documents being validated
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	parent   *node
+	text     string
+	line     int
+	ns       map[string]string
+}
+
+// parseTree parses data into a node tree keeping in-scope namespace
+// declarations, which are needed to resolve QName values in schemas
+func parseTree(data []byte) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	var root, current *node
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			line, _ := decoder.InputPos()
+			n := &node{
+				name:   t.Name,
+				attrs:  t.Attr,
+				parent: current,
+				line:   line,
+				ns:     make(map[string]string),
+			}
+			if current != nil {
+				for prefix, uri := range current.ns {
+					n.ns[prefix] = uri
+				}
+				current.children = append(current.children, n)
+			} else if root != nil {
+				return nil, fmt.Errorf("multiple root elements")
+			} else {
+				root = n
+			}
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					n.ns[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					n.ns[""] = attr.Value
+				}
+			}
+			current = n
+		case xml.CharData:
+			if current != nil {
+				current.text += string(t)
+			}
+		case xml.EndElement:
+			current = current.parent
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("document has no root element")
+	}
+	return root, nil
+}
+
+// attr returns the value of an unqualified attribute
+func (n *node) attr(local string) (string, bool) {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// attrDefault returns the value of an unqualified attribute or def when absent
+func (n *node) attrDefault(local, def string) string {
+	if value, ok := n.attr(local); ok {
+		return value
+	}
+	return def
+}
+
+// xsdChildren returns the XML Schema children of a schema node, skipping annotations
+func (n *node) xsdChildren() []*node {
+	children := make([]*node, 0, len(n.children))
+	for _, child := range n.children {
+		if child.name.Space == xsdNS && child.name.Local != "annotation" {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// resolveQName resolves a prefixed name using the namespaces in scope
+func (n *node) resolveQName(value string) (xml.Name, error) {
+	prefix, local := "", value
+	if i := strings.IndexByte(value, ':'); i >= 0 {
+		prefix, local = value[:i], value[i+1:]
+	}
+	uri, ok := n.ns[prefix]
+	if !ok && prefix != "" {
+		return xml.Name{}, fmt.Errorf("undeclared namespace prefix %q in %q", prefix, value)
+	}
+	return xml.Name{Space: uri, Local: local}, nil
+}
+
+// xpath returns the location path of the node, indexing repeated siblings
+func (n *node) xpath() string {
+	if n.parent == nil {
+		return "/" + n.name.Local
+	}
+
+	index, count := 0, 0
+	for _, sibling := range n.parent.children {
+		if sibling.name == n.name {
+			count++
+			if sibling == n {
+				index = count
+			}
+		}
+	}
+
+	step := n.name.Local
+	if count > 1 {
+		step = fmt.Sprintf("%s[%d]", step, index)
+	}
+	return n.parent.xpath() + "/" + step
+}
@@ -0,0 +1,370 @@
+package schemas
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// validation collects the violations found while walking an instance document
+type validation struct {
+	elements   map[xml.Name]*elementDecl
+	violations []Violation
+}
+
+// report records a violation located at n
+func (v *validation) report(n *node, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		XPath:   n.xpath(),
+		Line:    n.line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// root validates the document element against the global declarations
+func (v *validation) root(n *node) {
+	decl, ok := v.elements[n.name]
+	if !ok {
+		v.report(n, "no global declaration for element %s", qualifiedName(n.name))
+		return
+	}
+	v.element(n, decl)
+}
+
+// element validates an element and its subtree against a declaration
+func (v *validation) element(n *node, decl *elementDecl) {
+	if decl.nillable {
+		for _, attr := range n.attrs {
+			if attr.Name.Space == xsiNS && attr.Name.Local == "nil" && attr.Value == "true" {
+				if len(n.children) > 0 || strings.TrimSpace(n.text) != "" {
+					v.report(n, "element marked xsi:nil must be empty")
+				}
+				return
+			}
+		}
+	}
+
+	td := decl.typ
+	if td == nil {
+		return
+	}
+
+	if td.simple != nil {
+		v.attributes(n, nil)
+		if len(n.children) > 0 {
+			v.report(n, "element %s must not have child elements", n.name.Local)
+			return
+		}
+		v.simpleValue(n, td.simple, decl.fixed)
+		return
+	}
+
+	if td.complex == nil {
+		return
+	}
+	ct := td.complex
+	v.attributes(n, ct)
+
+	if ct.simpleContent != nil {
+		if len(n.children) > 0 {
+			v.report(n, "element %s must not have child elements", n.name.Local)
+			return
+		}
+		v.simpleValue(n, ct.simpleContent, decl.fixed)
+		return
+	}
+
+	if !ct.mixed && strings.TrimSpace(n.text) != "" {
+		v.report(n, "element %s must not contain text", n.name.Local)
+	}
+
+	v.content(n, ct)
+}
+
+// simpleValue validates the text of an element with simple content
+func (v *validation) simpleValue(n *node, typ *simpleType, fixed *string) {
+	if problem := typ.validate(n.text); problem != "" {
+		v.report(n, "%s", problem)
+		return
+	}
+	if fixed != nil && strings.TrimSpace(n.text) != *fixed {
+		v.report(n, "value %q must be %q", n.text, *fixed)
+	}
+}
+
+// attributes validates the attributes of an element against a complex type;
+// ct is nil for elements with a simple type, which accept no attributes
+func (v *validation) attributes(n *node, ct *complexType) {
+	seen := make(map[xml.Name]bool)
+
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") || attr.Name.Space == xsiNS {
+			continue
+		}
+		seen[attr.Name] = true
+
+		var decl *attributeDecl
+		if ct != nil {
+			for _, candidate := range ct.attributes {
+				if candidate.name == attr.Name {
+					decl = candidate
+					break
+				}
+			}
+		}
+		if decl == nil {
+			if ct == nil || !ct.anyAttribute {
+				v.report(n, "attribute %s is not allowed", displayName(attr.Name))
+			}
+			continue
+		}
+
+		if problem := decl.typ.validate(attr.Value); problem != "" {
+			v.report(n, "attribute %s: %s", displayName(attr.Name), problem)
+			continue
+		}
+		if decl.fixed != nil && attr.Value != *decl.fixed {
+			v.report(n, "attribute %s must be %q, got %q", displayName(attr.Name), *decl.fixed, attr.Value)
+		}
+	}
+
+	if ct == nil {
+		return
+	}
+	for _, decl := range ct.attributes {
+		if decl.required && !seen[decl.name] {
+			v.report(n, "missing required attribute %s", displayName(decl.name))
+		}
+	}
+}
+
+// content validates the child elements of n against the content model of ct
+func (v *validation) content(n *node, ct *complexType) {
+	if ct.content == nil {
+		if len(n.children) > 0 {
+			v.report(n.children[0], "element %s is not expected: %s must be empty", displayName(n.children[0].name), n.name.Local)
+		}
+		return
+	}
+
+	m := &matcher{children: n.children, expected: make(map[string]bool)}
+	ends := m.repeat(ct.content, positions{0: true})
+
+	if !ends[len(n.children)] {
+		expected := m.expectedNames()
+		if m.furthest < len(n.children) {
+			child := n.children[m.furthest]
+			if len(expected) > 0 {
+				v.report(child, "element %s is not expected; expected %s", displayName(child.name), strings.Join(expected, ", "))
+			} else {
+				v.report(child, "element %s is not expected", displayName(child.name))
+			}
+		} else {
+			v.report(n, "element %s is incomplete; expected %s", n.name.Local, strings.Join(expected, ", "))
+		}
+	}
+
+	for _, child := range n.children {
+		if decl, ok := ct.elements[child.name]; ok {
+			v.element(child, decl)
+			continue
+		}
+		for _, wildcard := range ct.wildcards {
+			if !wildcard.allows(child.name.Space) {
+				continue
+			}
+			if wildcard.processContents == "skip" {
+				break
+			}
+			if decl, ok := v.elements[child.name]; ok {
+				v.element(child, decl)
+			} else if wildcard.processContents == "strict" {
+				v.report(child, "no global declaration for element %s", qualifiedName(child.name))
+			}
+			break
+		}
+	}
+}
+
+// positions is a set of child indexes reachable while matching a content model
+type positions map[int]bool
+
+// matcher matches a sequence of child elements against a content model by
+// tracking every reachable position, so optional particles never need
+// backtracking. It remembers the furthest position reached and which element
+// names would have been accepted there, for error reporting.
+type matcher struct {
+	children      []*node
+	furthest      int
+	expected      map[string]bool
+	expectedOrder []string
+}
+
+// repeat matches a particle between its minimum and maximum occurrences
+func (m *matcher) repeat(p *particle, from positions) positions {
+	result := make(positions)
+	if p.min == 0 {
+		for pos := range from {
+			result[pos] = true
+		}
+	}
+
+	seen := make(positions)
+	for pos := range from {
+		seen[pos] = true
+	}
+
+	current := from
+	for count := 1; p.max == unbounded || count <= p.max; count++ {
+		next := m.once(p, current)
+		if len(next) == 0 {
+			break
+		}
+
+		progressed := false
+		for pos := range next {
+			if !seen[pos] {
+				seen[pos] = true
+				progressed = true
+			}
+		}
+		if count >= p.min {
+			for pos := range next {
+				result[pos] = true
+			}
+			if !progressed {
+				break
+			}
+		}
+		current = next
+	}
+	return result
+}
+
+// once matches a single occurrence of a particle
+func (m *matcher) once(p *particle, from positions) positions {
+	result := make(positions)
+
+	switch p.kind {
+	case particleElement:
+		for pos := range from {
+			if pos < len(m.children) && m.children[pos].name == p.element.name {
+				m.advance(pos + 1)
+				result[pos+1] = true
+			} else {
+				m.expect(pos, displayName(p.element.name))
+			}
+		}
+
+	case particleAny:
+		for pos := range from {
+			if pos < len(m.children) && p.allows(m.children[pos].name.Space) {
+				m.advance(pos + 1)
+				result[pos+1] = true
+			} else {
+				m.expect(pos, "any element")
+			}
+		}
+
+	case particleSequence:
+		current := from
+		for _, child := range p.children {
+			current = m.repeat(child, current)
+			if len(current) == 0 {
+				break
+			}
+		}
+		result = current
+
+	case particleChoice:
+		for _, child := range p.children {
+			for pos := range m.repeat(child, from) {
+				result[pos] = true
+			}
+		}
+
+	case particleAll:
+		for pos := range from {
+			for end := range m.all(p, pos) {
+				result[end] = true
+			}
+		}
+	}
+	return result
+}
+
+// all matches an xs:all group, whose elements may appear in any order
+func (m *matcher) all(p *particle, start int) positions {
+	type state struct {
+		pos  int
+		used uint64
+	}
+
+	result := make(positions)
+	visited := map[state]bool{{pos: start}: true}
+	queue := []state{{pos: start}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		complete := true
+		for i, child := range p.children {
+			if current.used&(1<<uint(i)) != 0 {
+				continue
+			}
+			if child.min > 0 {
+				complete = false
+			}
+			for end := range m.once(child, positions{current.pos: true}) {
+				next := state{pos: end, used: current.used | 1<<uint(i)}
+				if !visited[next] {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+		if complete {
+			result[current.pos] = true
+		}
+	}
+	return result
+}
+
+// advance records that the matcher consumed children up to pos
+func (m *matcher) advance(pos int) {
+	if pos > m.furthest {
+		m.furthest = pos
+		m.expected = make(map[string]bool)
+		m.expectedOrder = nil
+	}
+}
+
+// expect records a name that would have been accepted at pos
+func (m *matcher) expect(pos int, name string) {
+	m.advance(pos)
+	if pos == m.furthest && !m.expected[name] {
+		m.expected[name] = true
+		m.expectedOrder = append(m.expectedOrder, name)
+	}
+}
+
+// expectedNames returns the names accepted at the furthest position reached,
+// in content model order
+func (m *matcher) expectedNames() []string {
+	return m.expectedOrder
+}
+
+// displayName formats an element or attribute name for messages
+func displayName(name xml.Name) string {
+	return name.Local
+}
+
+// qualifiedName formats a name with its namespace, for messages where a
+// missing or wrong xmlns is the likely cause
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local + " (no namespace)"
+	}
+	return name.Local + " in namespace " + name.Space
+}
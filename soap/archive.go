@@ -0,0 +1,269 @@
+package soap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// ArchiveOutcome classifies how an exchange ended
+type ArchiveOutcome string
+
+const (
+	// OutcomeSuccess is a response received with a successful HTTP status
+	OutcomeSuccess ArchiveOutcome = "success"
+	// OutcomeFault is a SOAP fault returned by the webservice
+	OutcomeFault ArchiveOutcome = "fault"
+	// OutcomeFailure is a request that got no usable response: network
+	// failure, timeout or HTTP error
+	OutcomeFailure ArchiveOutcome = "failure"
+)
+
+// ArchivedExchange is a single HTTP attempt sent to a SEFAZ webservice, with
+// everything needed to reproduce it during an audit
+type ArchivedExchange struct {
+	Service string   `json:"service"`
+	UF      string   `json:"uf,omitempty"`
+	CNPJ    string   `json:"cnpj,omitempty"`
+	Chaves  []string `json:"chaves,omitempty"`
+	URL     string   `json:"url"`
+	Action  string   `json:"action,omitempty"`
+	Attempt int      `json:"attempt"`
+
+	// Request is the SOAP envelope as sent and Response the body as
+	// received, empty when no response arrived
+	Request  string `json:"-"`
+	Response string `json:"-"`
+
+	StatusCode int            `json:"statusCode,omitempty"`
+	CStat      int            `json:"cStat,omitempty"`
+	Outcome    ArchiveOutcome `json:"outcome"`
+	Error      string         `json:"error,omitempty"`
+	SentAt     time.Time      `json:"sentAt"`
+	ReceivedAt time.Time      `json:"receivedAt"`
+}
+
+// Archiver keeps every exchange with SEFAZ, as required for fiscal audit.
+// Archive is called after each attempt, including failed ones; an error is
+// logged but does not change the result of the call, as the webservice has
+// already processed the request.
+type Archiver interface {
+	Archive(ctx context.Context, exchange *ArchivedExchange) error
+}
+
+// newArchivedExchange builds the record of an attempt whose request was sent
+// with body, as left by the middlewares
+func newArchivedExchange(info messageInfo, exchange *Exchange, body string) *ArchivedExchange {
+	archived := &ArchivedExchange{
+		Service:    info.Service,
+		UF:         info.UF,
+		CNPJ:       info.CNPJ,
+		Chaves:     info.Chaves,
+		URL:        exchange.HTTPRequest.URL.String(),
+		Action:     exchange.Request.Action,
+		Attempt:    exchange.Attempt,
+		Request:    body,
+		Outcome:    OutcomeSuccess,
+		SentAt:     exchange.Start,
+		ReceivedAt: exchange.Start.Add(exchange.Duration),
+	}
+
+	if exchange.Response != nil {
+		archived.Chaves = responseChaves(archived.Chaves, exchange.Response.Body)
+		if archived.CNPJ == "" && len(archived.Chaves) > 0 {
+			archived.CNPJ = archived.Chaves[0][6:20]
+		}
+		archived.Response = exchange.Response.Body
+		archived.StatusCode = exchange.Response.StatusCode
+		archived.CStat = responseCStat(exchange.Response.Body)
+	}
+	if exchange.Err != nil {
+		archived.Error = exchange.Err.Error()
+		archived.Outcome = OutcomeFailure
+		if _, ok := AsFaultError(exchange.Err); ok {
+			archived.Outcome = OutcomeFault
+		}
+	}
+	return archived
+}
+
+// docZipPattern matches the documents of a distribuição DF-e response,
+// compressed with gzip and encoded in base64
+var docZipPattern = regexp.MustCompile(`<(?:[\w.-]+:)?docZip[^>]*>([^<]+)<`)
+
+// responseChaves adds to the chaves of the request those of the response: the
+// protocols of a recibo or the documents of a distribuição DF-e, so that the
+// exchange is also filed under the NFe it returns
+func responseChaves(chaves []string, body string) []string {
+	chaves = appendChaves(slices.Clip(chaves), body)
+	for _, match := range docZipPattern.FindAllStringSubmatch(body, -1) {
+		if doc, err := unzipDocument(match[1]); err == nil {
+			chaves = appendChaves(chaves, doc)
+		}
+	}
+	return chaves
+}
+
+// unzipDocument decodes a docZip
+func unzipDocument(encoded string) (string, error) {
+	compressed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	doc, err := io.ReadAll(reader)
+	return string(doc), err
+}
+
+// archive hands an attempt to the archiver. It runs even when the call was
+// canceled, since the request may have reached the webservice.
+func (c *SOAPClient) archive(ctx context.Context, info messageInfo, exchange *Exchange, body string) {
+	archived := newArchivedExchange(info, exchange, body)
+	if err := c.archiver.Archive(context.WithoutCancel(ctx), archived); err != nil {
+		attrs := append(info.attrs(), slog.Int("attempt", exchange.Attempt), slog.String("error", err.Error()))
+		c.logger.LogAttrs(ctx, slog.LevelError, "Failed to archive SOAP exchange", attrs...)
+	}
+}
+
+// unknownCNPJ is the directory of exchanges without CNPJ when FileArchiver has none
+const unknownCNPJ = "00000000000000"
+
+// safeNamePattern matches the characters kept in file names
+var safeNamePattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// FileArchiver stores exchanges on disk, laid out as
+//
+//	Dir/CNPJ/YYYY/MM/chave/20240115T103000.123-nfeAutorizacaoLote-1-request.xml
+//	                                         ...-response.xml
+//	                                         ...-meta.json
+//
+// An exchange is stored under each chave of its request and of its response,
+// so a lote, the recibo carrying its protocols and the documents returned by
+// distribuição DF-e are all found under the chave. Exchanges without chave
+// (status, inutilização, cadastro) go to a directory named after the service.
+// Files are never overwritten: exchanges sent in the same millisecond get a
+// numeric suffix.
+type FileArchiver struct {
+	// Dir is the root directory of the archive
+	Dir string
+	// CNPJ is used for exchanges whose message carries none, such as
+	// NfeStatusServico; usually the CNPJ of the certificate
+	CNPJ string
+}
+
+// NewFileArchiver creates an archiver writing under dir
+func NewFileArchiver(dir, cnpj string) *FileArchiver {
+	return &FileArchiver{Dir: dir, CNPJ: cnpj}
+}
+
+// Archive implements Archiver
+func (a *FileArchiver) Archive(ctx context.Context, exchange *ArchivedExchange) error {
+	if a.Dir == "" {
+		return errors.NewConfigError("archive directory not configured", "dir", "")
+	}
+
+	meta, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return errors.WrapError(err, errors.ErrConfig, "failed to encode archived exchange")
+	}
+
+	for _, dir := range a.Directories(exchange) {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return errors.WrapError(err, errors.ErrConfig, "failed to create archive directory")
+		}
+
+		prefix, err := createRequestFile(filepath.Join(dir, fmt.Sprintf("%s-%s-%d",
+			exchange.SentAt.Format("20060102T150405.000"), safeName(exchange.Service, "soap"), exchange.Attempt)),
+			[]byte(exchange.Request))
+		if err != nil {
+			return err
+		}
+
+		if exchange.Response != "" {
+			if err := writeNewFile(prefix+"-response.xml", []byte(exchange.Response)); err != nil {
+				return err
+			}
+		}
+		if err := writeNewFile(prefix+"-meta.json", meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createRequestFile writes the request of an exchange under prefix, or under
+// prefix-2, prefix-3... when exchanges sent in the same millisecond already
+// took it, and returns the prefix used. Archived files are never overwritten.
+func createRequestFile(prefix string, data []byte) (string, error) {
+	for n := 1; ; n++ {
+		candidate := prefix
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", prefix, n)
+		}
+		err := writeNewFile(candidate+"-request.xml", data)
+		if !stderrors.Is(err, fs.ErrExist) {
+			return candidate, err
+		}
+	}
+}
+
+// writeNewFile creates path and writes data to it, failing if it exists
+func writeNewFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrConfig, "failed to create archived exchange")
+	}
+	if _, err = file.Write(data); err == nil {
+		err = file.Close()
+	} else {
+		file.Close()
+	}
+	if err != nil {
+		return errors.WrapError(err, errors.ErrConfig, "failed to write archived exchange")
+	}
+	return nil
+}
+
+// Directories returns the directories an exchange is stored in
+func (a *FileArchiver) Directories(exchange *ArchivedExchange) []string {
+	cnpj := safeName(exchange.CNPJ, safeName(a.CNPJ, unknownCNPJ))
+	month := filepath.Join(a.Dir, cnpj, exchange.SentAt.Format("2006"), exchange.SentAt.Format("01"))
+
+	if len(exchange.Chaves) == 0 {
+		return []string{filepath.Join(month, safeName(exchange.Service, "soap"))}
+	}
+
+	dirs := make([]string, len(exchange.Chaves))
+	for i, chave := range exchange.Chaves {
+		dirs[i] = filepath.Join(month, safeName(chave, "soap"))
+	}
+	return dirs
+}
+
+// safeName removes path separators and other unexpected characters from a
+// file name, returning fallback when nothing is left
+func safeName(name, fallback string) string {
+	if name = safeNamePattern.ReplaceAllString(name, ""); name == "" {
+		return fallback
+	}
+	return name
+}
@@ -0,0 +1,289 @@
+package soap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryArchiver keeps the archived exchanges
+type memoryArchiver struct {
+	exchanges []*ArchivedExchange
+	err       error
+}
+
+func (m *memoryArchiver) Archive(ctx context.Context, exchange *ArchivedExchange) error {
+	m.exchanges = append(m.exchanges, exchange)
+	return m.err
+}
+
+func TestSOAPClientArchivesEveryAttempt(t *testing.T) {
+	chave := "35240112345678000195550010000000011000000010"
+	fault := `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><soap:Fault><soap:Code><soap:Value>soap:Receiver</soap:Value></soap:Code><soap:Reason><soap:Text xml:lang="pt">Erro interno</soap:Text></soap:Reason></soap:Fault></soap:Body></soap:Envelope>`
+
+	archiver := &memoryArchiver{err: fmt.Errorf("disk full")}
+	config := DefaultConfig()
+	config.MaxRetries = 1
+	config.RetryDelay = time.Millisecond
+	config.Transport = &recordingTransport{status: http.StatusInternalServerError, body: fault}
+	config.Archiver = archiver
+	client := NewSOAPClient(config)
+
+	request := &SOAPRequest{
+		URL:    "https://nfe.example.gov.br/ws",
+		Action: "http://www.portalfiscal.inf.br/nfe/wsdl/NFeConsultaProtocolo4/nfeConsultaNF",
+		Body:   `<consSitNFe><chNFe>` + chave + `</chNFe></consSitNFe>`,
+	}
+	if _, err := client.Call(context.Background(), request); err == nil {
+		t.Fatal("Expected fault error")
+	}
+
+	if len(archiver.exchanges) != 2 {
+		t.Fatalf("Expected 2 archived attempts, got %d", len(archiver.exchanges))
+	}
+	for i, exchange := range archiver.exchanges {
+		if exchange.Attempt != i+1 {
+			t.Errorf("Expected attempt %d, got %d", i+1, exchange.Attempt)
+		}
+		if exchange.Service != "nfeConsultaNF" || exchange.UF != "35" || exchange.CNPJ != "12345678000195" {
+			t.Errorf("Unexpected identification %+v", exchange)
+		}
+		if len(exchange.Chaves) != 1 || exchange.Chaves[0] != chave {
+			t.Errorf("Unexpected chaves %v", exchange.Chaves)
+		}
+		if exchange.Request != request.Body || exchange.Response != fault {
+			t.Error("Exchange should keep the raw request and response")
+		}
+		if exchange.Outcome != OutcomeFault || exchange.StatusCode != http.StatusInternalServerError || exchange.Error == "" {
+			t.Errorf("Unexpected outcome %s %d %q", exchange.Outcome, exchange.StatusCode, exchange.Error)
+		}
+		if exchange.SentAt.IsZero() || exchange.ReceivedAt.Before(exchange.SentAt) {
+			t.Errorf("Unexpected timestamps %v %v", exchange.SentAt, exchange.ReceivedAt)
+		}
+	}
+}
+
+func TestSOAPClientArchivesRequestAsSent(t *testing.T) {
+	chave := "35240112ABC345000195550010000000011000000010"
+	dir := t.TempDir()
+
+	config := DefaultConfig()
+	config.Transport = &recordingTransport{status: http.StatusOK, body: `<retConsSitNFe><cStat>100</cStat></retConsSitNFe>`}
+	config.Archiver = NewFileArchiver(dir, "99999999000191")
+	config.Middlewares = []Middleware{{
+		Name: "envelope",
+		Before: func(exchange *Exchange) error {
+			exchange.HTTPRequest.Body = io.NopCloser(strings.NewReader("<soap:Envelope>" + exchange.Request.Body + "</soap:Envelope>"))
+			return nil
+		},
+	}}
+	client := NewSOAPClient(config)
+
+	request := &SOAPRequest{
+		URL:    "https://nfe.example.gov.br/ws",
+		Action: "http://www.portalfiscal.inf.br/nfe/wsdl/NFeConsultaProtocolo4/nfeConsultaNF",
+		Body:   `<consSitNFe><chNFe>` + chave + `</chNFe></consSitNFe>`,
+	}
+	if _, err := client.Call(context.Background(), request); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "12ABC345000195", "*", "*", chave, "*-request.xml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected the request under the alphanumeric CNPJ and chave, got %v %v", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("Failed to read archived request: %v", err)
+	}
+	if string(data) != "<soap:Envelope>"+request.Body+"</soap:Envelope>" {
+		t.Errorf("Archive should keep the body rewritten by the middleware, got %s", data)
+	}
+}
+
+func TestSOAPClientArchivesResponseChaves(t *testing.T) {
+	chaves := []string{"35240112345678000195550010000000011000000010", "35240112345678000195550010000000021000000020"}
+	var resNFe bytes.Buffer
+	zw := gzip.NewWriter(&resNFe)
+	zw.Write([]byte(`<resNFe xmlns="http://www.portalfiscal.inf.br/nfe" versao="1.01"><chNFe>` + chaves[1] + `</chNFe></resNFe>`))
+	zw.Close()
+
+	tests := []struct {
+		name     string
+		action   string
+		body     string
+		response string
+	}{
+		{
+			name:   "recibo",
+			action: "http://www.portalfiscal.inf.br/nfe/wsdl/NFeRetAutorizacao4/nfeRetAutorizacaoLote",
+			body:   `<consReciNFe versao="4.00"><tpAmb>2</tpAmb><nRec>351000012345678</nRec></consReciNFe>`,
+			response: `<retConsReciNFe><cStat>104</cStat>` +
+				`<protNFe><infProt Id="ID135240000000001"><chNFe>` + chaves[0] + `</chNFe><cStat>100</cStat></infProt></protNFe>` +
+				`<protNFe><infProt Id="ID135240000000002"><chNFe>` + chaves[1] + `</chNFe><cStat>100</cStat></infProt></protNFe></retConsReciNFe>`,
+		},
+		{
+			name:   "distribuição DF-e",
+			action: "http://www.portalfiscal.inf.br/nfe/wsdl/NFeDistribuicaoDFe/nfeDistDFeInteresse",
+			body:   `<distDFeInt versao="1.01"><tpAmb>2</tpAmb><cUFAutor>35</cUFAutor><CNPJ>12345678000195</CNPJ><distNSU><ultNSU>000000000000000</ultNSU></distNSU></distDFeInt>`,
+			response: `<retDistDFeInt><cStat>138</cStat><loteDistDFeInt>` +
+				`<docZip NSU="000000000000001" schema="procNFe_v4.00.xsd">` + base64.StdEncoding.EncodeToString([]byte("not gzip")) + `</docZip>` +
+				`<docZip NSU="000000000000002" schema="resNFe_v1.01.xsd">` + base64.StdEncoding.EncodeToString(resNFe.Bytes()) + `</docZip>` +
+				`</loteDistDFeInt></retDistDFeInt>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archiver := &memoryArchiver{}
+			config := DefaultConfig()
+			config.Transport = &recordingTransport{status: http.StatusOK, body: tt.response}
+			config.Archiver = archiver
+			client := NewSOAPClient(config)
+
+			if _, err := client.Call(context.Background(), &SOAPRequest{URL: "https://nfe.example.gov.br/ws", Action: tt.action, Body: tt.body}); err != nil {
+				t.Fatalf("Call failed: %v", err)
+			}
+			if len(archiver.exchanges) != 1 {
+				t.Fatalf("Expected 1 archived exchange, got %d", len(archiver.exchanges))
+			}
+
+			exchange := archiver.exchanges[0]
+			want := chaves
+			if tt.name != "recibo" {
+				want = chaves[1:]
+			}
+			if fmt.Sprint(exchange.Chaves) != fmt.Sprint(want) || exchange.CNPJ != "12345678000195" {
+				t.Errorf("Expected chaves %v of CNPJ 12345678000195, got %v of %q", want, exchange.Chaves, exchange.CNPJ)
+			}
+		})
+	}
+}
+
+func TestFileArchiverNeverOverwrites(t *testing.T) {
+	dir := t.TempDir()
+	archiver := NewFileArchiver(dir, "12345678000195")
+	sentAt := time.Date(2024, 1, 15, 10, 30, 0, 123000000, time.UTC)
+
+	for _, body := range []string{"<first/>", "<second/>", "<third/>"} {
+		exchange := &ArchivedExchange{
+			Service: "nfeConsultaNF",
+			Chaves:  []string{"35240112345678000195550010000000011000000010"},
+			Attempt: 1,
+			Request: body,
+			SentAt:  sentAt,
+		}
+		if err := archiver.Archive(context.Background(), exchange); err != nil {
+			t.Fatalf("Archive failed: %v", err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(archiver.Directories(&ArchivedExchange{
+		Chaves: []string{"35240112345678000195550010000000011000000010"},
+		SentAt: sentAt,
+	})[0], "*-request.xml"))
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 archived requests, got %v", files)
+	}
+	var bodies []string
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		bodies = append(bodies, string(data))
+	}
+	sort.Strings(bodies)
+	if fmt.Sprint(bodies) != "[<first/> <second/> <third/>]" {
+		t.Errorf("Archived requests should be kept, got %v", bodies)
+	}
+	for _, file := range files {
+		if _, err := os.Stat(strings.TrimSuffix(file, "-request.xml") + "-meta.json"); err != nil {
+			t.Errorf("Each request should have its meta: %v", err)
+		}
+	}
+}
+
+func TestFileArchiver(t *testing.T) {
+	dir := t.TempDir()
+	archiver := NewFileArchiver(dir, "99999999000191")
+	sentAt := time.Date(2024, 1, 15, 10, 30, 0, 123000000, time.UTC)
+
+	lote := &ArchivedExchange{
+		Service:    "nfeAutorizacaoLote",
+		CNPJ:       "12345678000195",
+		Chaves:     []string{"35240112345678000195550010000000011000000010", "35240112345678000195550010000000021000000020"},
+		Attempt:    1,
+		Request:    "<enviNFe/>",
+		Response:   "<retEnviNFe><cStat>104</cStat></retEnviNFe>",
+		CStat:      104,
+		Outcome:    OutcomeSuccess,
+		SentAt:     sentAt,
+		ReceivedAt: sentAt.Add(time.Second),
+	}
+	if err := archiver.Archive(context.Background(), lote); err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+
+	for _, chave := range lote.Chaves {
+		prefix := filepath.Join(dir, "12345678000195", "2024", "01", chave, "20240115T103000.123-nfeAutorizacaoLote-1")
+		request, err := os.ReadFile(prefix + "-request.xml")
+		if err != nil || string(request) != lote.Request {
+			t.Errorf("Request not archived under %s: %v", chave, err)
+		}
+		response, err := os.ReadFile(prefix + "-response.xml")
+		if err != nil || string(response) != lote.Response {
+			t.Errorf("Response not archived under %s: %v", chave, err)
+		}
+
+		var meta ArchivedExchange
+		data, err := os.ReadFile(prefix + "-meta.json")
+		if err != nil || json.Unmarshal(data, &meta) != nil {
+			t.Fatalf("Metadata not archived under %s: %v", chave, err)
+		}
+		if meta.CStat != 104 || meta.Outcome != OutcomeSuccess || !meta.SentAt.Equal(sentAt) || len(meta.Chaves) != 2 {
+			t.Errorf("Unexpected metadata %+v", meta)
+		}
+	}
+
+	status := &ArchivedExchange{Service: "nfeStatusServicoNF", Attempt: 1, Request: "<consStatServ/>", Outcome: OutcomeFailure, SentAt: sentAt}
+	if err := archiver.Archive(context.Background(), status); err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	prefix := filepath.Join(dir, "99999999000191", "2024", "01", "nfeStatusServicoNF", "20240115T103000.123-nfeStatusServicoNF-1")
+	if _, err := os.Stat(prefix + "-request.xml"); err != nil {
+		t.Errorf("Exchange without chave should be archived by service: %v", err)
+	}
+	if _, err := os.Stat(prefix + "-response.xml"); !os.IsNotExist(err) {
+		t.Error("No response file should be written without response")
+	}
+}
+
+func TestFileArchiverDirectories(t *testing.T) {
+	archiver := &FileArchiver{Dir: "/arquivo"}
+	sentAt := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	dirs := archiver.Directories(&ArchivedExchange{Service: "../../etc", CNPJ: "../x", SentAt: sentAt})
+	expected := filepath.Join("/arquivo", "x", "2024", "12", "etc")
+	if len(dirs) != 1 || dirs[0] != expected {
+		t.Errorf("Expected %s, got %v", expected, dirs)
+	}
+
+	dirs = archiver.Directories(&ArchivedExchange{SentAt: sentAt})
+	expected = filepath.Join("/arquivo", unknownCNPJ, "2024", "12", "soap")
+	if len(dirs) != 1 || dirs[0] != expected {
+		t.Errorf("Expected %s, got %v", expected, dirs)
+	}
+
+	if err := (&FileArchiver{}).Archive(context.Background(), &ArchivedExchange{}); err == nil {
+		t.Error("Archive without directory should fail")
+	}
+}
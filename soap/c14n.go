@@ -0,0 +1,378 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// Canonicalization and transform algorithms of XMLDSig
+const (
+	C14N10Algorithm              = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	C14N10WithCommentsAlgorithm  = C14N10Algorithm + "#WithComments"
+	ExcC14NWithCommentsAlgorithm = C14NAlgorithm + "WithComments"
+	EnvelopedSignatureTransform  = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+
+	xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+)
+
+// xmlNode is an element of a parsed document that keeps the prefixes and
+// namespace declarations as written, as required by canonicalization
+type xmlNode struct {
+	name     xml.Name // Space holds the prefix
+	attrs    []xml.Attr
+	children []interface{} // *xmlNode, xml.CharData, xml.Comment or xml.ProcInst
+	parent   *xmlNode
+}
+
+// parseXMLTree parses data into a tree of elements and returns its root
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(label) {
+		case "iso-8859-1", "iso8859-1", "latin1":
+			return charmap.ISO8859_1.NewDecoder().Reader(input), nil
+		case "windows-1252", "cp1252":
+			return charmap.Windows1252.NewDecoder().Reader(input), nil
+		}
+		return nil, fmt.Errorf("unsupported encoding %s", label)
+	}
+
+	var root, current *xmlNode
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewXMLError("failed to parse signed XML", "xml", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name, attrs: t.Attr, parent: current}
+			if current == nil {
+				if root != nil {
+					return nil, errors.NewXMLError("signed XML has more than one root element", "xml", nil)
+				}
+				root = node
+			} else {
+				current.children = append(current.children, node)
+			}
+			current = node
+		case xml.EndElement:
+			if current == nil || current.name != t.Name {
+				return nil, errors.NewXMLError("unexpected closing tag in signed XML", qualifiedName(t.Name), nil)
+			}
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, t.Copy())
+			}
+		case xml.Comment:
+			if current != nil {
+				current.children = append(current.children, t.Copy())
+			}
+		case xml.ProcInst:
+			if current != nil {
+				current.children = append(current.children, t.Copy())
+			}
+		}
+	}
+
+	if root == nil || current != nil {
+		return nil, errors.NewXMLError("signed XML is incomplete", "xml", nil)
+	}
+	return root, nil
+}
+
+// qualifiedName returns prefix:local
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// isNamespaceDecl reports whether attr declares a namespace, returning its prefix
+func isNamespaceDecl(attr xml.Attr) (string, bool) {
+	switch {
+	case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+		return "", true
+	case attr.Name.Space == "xmlns":
+		return attr.Name.Local, true
+	}
+	return "", false
+}
+
+// namespace returns the URI bound to prefix in the scope of n
+func (n *xmlNode) namespace(prefix string) string {
+	if prefix == "xml" {
+		return xmlNamespace
+	}
+	for node := n; node != nil; node = node.parent {
+		for _, attr := range node.attrs {
+			if p, ok := isNamespaceDecl(attr); ok && p == prefix {
+				return attr.Value
+			}
+		}
+	}
+	return ""
+}
+
+// inScopeNamespaces returns every namespace declared on n or its ancestors,
+// the nearest declaration of each prefix winning
+func (n *xmlNode) inScopeNamespaces() map[string]string {
+	namespaces := make(map[string]string)
+	for node := n; node != nil; node = node.parent {
+		for _, attr := range node.attrs {
+			if prefix, ok := isNamespaceDecl(attr); ok {
+				if _, seen := namespaces[prefix]; !seen {
+					namespaces[prefix] = attr.Value
+				}
+			}
+		}
+	}
+	return namespaces
+}
+
+// attr returns the value of the unprefixed attribute local
+func (n *xmlNode) attr(local string) (string, bool) {
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// child returns the first child element named local in the namespace uri;
+// an empty uri matches any namespace
+func (n *xmlNode) child(uri, local string) *xmlNode {
+	for _, c := range n.children {
+		if node, ok := c.(*xmlNode); ok && node.name.Local == local && (uri == "" || node.namespace(node.name.Space) == uri) {
+			return node
+		}
+	}
+	return nil
+}
+
+// childrenNamed returns the child elements named local in the namespace uri
+func (n *xmlNode) childrenNamed(uri, local string) []*xmlNode {
+	var nodes []*xmlNode
+	for _, c := range n.children {
+		if node, ok := c.(*xmlNode); ok && node.name.Local == local && (uri == "" || node.namespace(node.name.Space) == uri) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// text returns the character data directly inside n
+func (n *xmlNode) text() string {
+	var text strings.Builder
+	for _, c := range n.children {
+		if data, ok := c.(xml.CharData); ok {
+			text.Write(data)
+		}
+	}
+	return text.String()
+}
+
+// walk calls fn for n and every element below it, in document order
+func (n *xmlNode) walk(fn func(*xmlNode)) {
+	fn(n)
+	for _, c := range n.children {
+		if node, ok := c.(*xmlNode); ok {
+			node.walk(fn)
+		}
+	}
+}
+
+// canonicalizer serializes a subtree in the canonical form of C14N 1.0 or
+// Exclusive C14N 1.0, optionally leaving out one element (the enveloped signature)
+type canonicalizer struct {
+	exclusive bool
+	comments  bool
+	exclude   *xmlNode
+	buf       bytes.Buffer
+}
+
+// newCanonicalizer returns the canonicalizer of an algorithm URI
+func newCanonicalizer(algorithm string) (*canonicalizer, error) {
+	switch algorithm {
+	case C14N10Algorithm:
+		return &canonicalizer{}, nil
+	case C14N10WithCommentsAlgorithm:
+		return &canonicalizer{comments: true}, nil
+	case C14NAlgorithm:
+		return &canonicalizer{exclusive: true}, nil
+	case ExcC14NWithCommentsAlgorithm:
+		return &canonicalizer{exclusive: true, comments: true}, nil
+	}
+	return nil, errors.NewValidationError("unsupported canonicalization algorithm", "Algorithm", algorithm)
+}
+
+// canonicalize returns the canonical form of the subtree rooted at apex
+func (c *canonicalizer) canonicalize(apex *xmlNode) []byte {
+	c.buf.Reset()
+	c.element(apex, map[string]string{}, true)
+	return append([]byte(nil), c.buf.Bytes()...)
+}
+
+// element writes n and its content; rendered holds the namespaces already
+// written by the output ancestors
+func (c *canonicalizer) element(n *xmlNode, rendered map[string]string, apex bool) {
+	if n == c.exclude {
+		return
+	}
+
+	namespaces := c.namespaces(n, rendered, apex)
+	scope := rendered
+	if len(namespaces) > 0 {
+		scope = make(map[string]string, len(rendered)+len(namespaces))
+		for prefix, uri := range rendered {
+			scope[prefix] = uri
+		}
+		for _, ns := range namespaces {
+			scope[ns.Name.Local] = ns.Value
+		}
+	}
+
+	name := qualifiedName(n.name)
+	c.buf.WriteByte('<')
+	c.buf.WriteString(name)
+	for _, ns := range namespaces {
+		if ns.Name.Local == "" {
+			c.buf.WriteString(` xmlns="`)
+		} else {
+			c.buf.WriteString(" xmlns:" + ns.Name.Local + `="`)
+		}
+		c.buf.WriteString(escapeCanonicalAttr(ns.Value))
+		c.buf.WriteByte('"')
+	}
+	for _, attr := range c.attributes(n, apex) {
+		c.buf.WriteString(" " + qualifiedName(attr.Name) + `="`)
+		c.buf.WriteString(escapeCanonicalAttr(attr.Value))
+		c.buf.WriteByte('"')
+	}
+	c.buf.WriteByte('>')
+
+	for _, child := range n.children {
+		switch t := child.(type) {
+		case *xmlNode:
+			c.element(t, scope, false)
+		case xml.CharData:
+			c.buf.WriteString(escapeCanonicalText(string(t)))
+		case xml.Comment:
+			if c.comments {
+				c.buf.WriteString("<!--" + string(t) + "-->")
+			}
+		case xml.ProcInst:
+			c.buf.WriteString("<?" + t.Target)
+			if len(t.Inst) > 0 {
+				c.buf.WriteString(" " + string(t.Inst))
+			}
+			c.buf.WriteString("?>")
+		}
+	}
+	c.buf.WriteString("</" + name + ">")
+}
+
+// namespaces returns the namespace declarations n must render, sorted by prefix
+func (c *canonicalizer) namespaces(n *xmlNode, rendered map[string]string, apex bool) []xml.Attr {
+	candidates := make(map[string]string)
+	if c.exclusive {
+		// only the namespaces visibly used by the element and its attributes
+		candidates[n.name.Space] = n.namespace(n.name.Space)
+		for _, attr := range n.attrs {
+			if _, ok := isNamespaceDecl(attr); !ok && attr.Name.Space != "" && attr.Name.Space != "xml" {
+				candidates[attr.Name.Space] = n.namespace(attr.Name.Space)
+			}
+		}
+	} else if apex {
+		candidates = n.inScopeNamespaces()
+	} else {
+		for _, attr := range n.attrs {
+			if prefix, ok := isNamespaceDecl(attr); ok {
+				candidates[prefix] = attr.Value
+			}
+		}
+	}
+
+	var namespaces []xml.Attr
+	for prefix, uri := range candidates {
+		if prefix == "xml" {
+			continue
+		}
+		if current, ok := rendered[prefix]; ok && current == uri {
+			continue
+		}
+		if _, ok := rendered[prefix]; !ok && prefix == "" && uri == "" {
+			// an empty default namespace is only written to undeclare a rendered one
+			continue
+		}
+		namespaces = append(namespaces, xml.Attr{Name: xml.Name{Local: prefix}, Value: uri})
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name.Local < namespaces[j].Name.Local })
+	return namespaces
+}
+
+// attributes returns the attributes of n sorted by namespace URI and local
+// name. The apex of an inclusive canonicalization also carries the xml:*
+// attributes of its ancestors.
+func (c *canonicalizer) attributes(n *xmlNode, apex bool) []xml.Attr {
+	var attrs []xml.Attr
+	seen := make(map[string]bool)
+	for _, attr := range n.attrs {
+		if _, ok := isNamespaceDecl(attr); ok {
+			continue
+		}
+		attrs = append(attrs, attr)
+		if attr.Name.Space == "xml" {
+			seen[attr.Name.Local] = true
+		}
+	}
+	if apex && !c.exclusive {
+		for node := n.parent; node != nil; node = node.parent {
+			for _, attr := range node.attrs {
+				if attr.Name.Space == "xml" && !seen[attr.Name.Local] {
+					seen[attr.Name.Local] = true
+					attrs = append(attrs, attr)
+				}
+			}
+		}
+	}
+
+	uri := func(attr xml.Attr) string {
+		if attr.Name.Space == "" {
+			return ""
+		}
+		return n.namespace(attr.Name.Space)
+	}
+	sort.SliceStable(attrs, func(i, j int) bool {
+		ui, uj := uri(attrs[i]), uri(attrs[j])
+		if ui != uj {
+			return ui < uj
+		}
+		return attrs[i].Name.Local < attrs[j].Name.Local
+	})
+	return attrs
+}
+
+// escapeCanonicalText escapes character data as required by C14N
+func escapeCanonicalText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;").Replace(s)
+}
+
+// escapeCanonicalAttr escapes an attribute value as required by C14N
+func escapeCanonicalAttr(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;").Replace(s)
+}
@@ -0,0 +1,100 @@
+package soap
+
+import (
+	"testing"
+)
+
+const c14nTestDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<nfe:nfeProc xmlns:nfe="http://www.portalfiscal.inf.br/nfe" xmlns:x="urn:x" versao="4.00" xml:lang="pt">
+  <!-- c -->
+  <nfe:NFe b="2" a='1 &amp; "q"' x:z="3">
+    <nfe:infNFe Id="NFe1" versao="4.00"><nfe:x/>A &lt; B &gt; C&#13;&amp;</nfe:infNFe>
+    <Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo><inner xmlns=""/></SignedInfo></Signature>
+  </nfe:NFe>
+</nfe:nfeProc>`
+
+func TestCanonicalize(t *testing.T) {
+	root, err := parseXMLTree([]byte(c14nTestDocument))
+	if err != nil {
+		t.Fatalf("parseXMLTree failed: %v", err)
+	}
+	find := func(local string) *xmlNode {
+		var found *xmlNode
+		root.walk(func(n *xmlNode) {
+			if found == nil && n.name.Local == local {
+				found = n
+			}
+		})
+		return found
+	}
+
+	tests := []struct {
+		name      string
+		algorithm string
+		apex      *xmlNode
+		exclude   *xmlNode
+		want      string
+	}{
+		{
+			"inclusive document", C14N10Algorithm, root, nil,
+			`<nfe:nfeProc xmlns:nfe="http://www.portalfiscal.inf.br/nfe" xmlns:x="urn:x" versao="4.00" xml:lang="pt">` + "\n  \n" +
+				`  <nfe:NFe a="1 &amp; &quot;q&quot;" b="2" x:z="3">` + "\n" +
+				`    <nfe:infNFe Id="NFe1" versao="4.00"><nfe:x></nfe:x>A &lt; B &gt; C&#xD;&amp;</nfe:infNFe>` + "\n" +
+				`    <Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo><inner xmlns=""></inner></SignedInfo></Signature>` + "\n" +
+				`  </nfe:NFe>` + "\n</nfe:nfeProc>",
+		},
+		{
+			"exclusive with comments", ExcC14NWithCommentsAlgorithm, root, nil,
+			`<nfe:nfeProc xmlns:nfe="http://www.portalfiscal.inf.br/nfe" versao="4.00" xml:lang="pt">` + "\n  <!-- c -->\n" +
+				`  <nfe:NFe xmlns:x="urn:x" a="1 &amp; &quot;q&quot;" b="2" x:z="3">` + "\n" +
+				`    <nfe:infNFe Id="NFe1" versao="4.00"><nfe:x></nfe:x>A &lt; B &gt; C&#xD;&amp;</nfe:infNFe>` + "\n" +
+				`    <Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo><inner xmlns=""></inner></SignedInfo></Signature>` + "\n" +
+				`  </nfe:NFe>` + "\n</nfe:nfeProc>",
+		},
+		{
+			"inclusive subtree inherits namespaces", C14N10Algorithm, find("SignedInfo"), nil,
+			`<SignedInfo xmlns="http://www.w3.org/2000/09/xmldsig#" xmlns:nfe="http://www.portalfiscal.inf.br/nfe" xmlns:x="urn:x" xml:lang="pt"><inner xmlns=""></inner></SignedInfo>`,
+		},
+		{
+			"exclusive subtree", C14NAlgorithm, find("SignedInfo"), nil,
+			`<SignedInfo xmlns="http://www.w3.org/2000/09/xmldsig#"><inner xmlns=""></inner></SignedInfo>`,
+		},
+		{
+			"enveloped signature", C14N10Algorithm, find("NFe"), find("Signature"),
+			`<nfe:NFe xmlns:nfe="http://www.portalfiscal.inf.br/nfe" xmlns:x="urn:x" a="1 &amp; &quot;q&quot;" b="2" xml:lang="pt" x:z="3">` + "\n" +
+				`    <nfe:infNFe Id="NFe1" versao="4.00"><nfe:x></nfe:x>A &lt; B &gt; C&#xD;&amp;</nfe:infNFe>` + "\n    \n  </nfe:NFe>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newCanonicalizer(tt.algorithm)
+			if err != nil {
+				t.Fatalf("newCanonicalizer failed: %v", err)
+			}
+			c.exclude = tt.exclude
+			if got := string(c.canonicalize(tt.apex)); got != tt.want {
+				t.Errorf("unexpected canonical form\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCanonicalizerUnsupported(t *testing.T) {
+	if _, err := newCanonicalizer("http://www.w3.org/2006/12/xml-c14n11"); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+}
+
+func TestParseXMLTreeErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"<a><b></a>",
+		"<a></a><b></b>",
+		"<a>",
+	}
+	for _, data := range tests {
+		if _, err := parseXMLTree([]byte(data)); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
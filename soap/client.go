@@ -8,10 +8,11 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
-	"math"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/adrianodrix/sped-nfe-go/common"
 	"github.com/adrianodrix/sped-nfe-go/errors"
 )
 
@@ -24,6 +25,11 @@ type SOAPClient struct {
 	userAgent     string
 	tlsConfig     *tls.Config
 	enableLogging bool
+	logger        *slog.Logger
+	archiver      Archiver
+	retryPolicies map[Operation]*RetryPolicy
+	middlewares   []Middleware
+	proxyErr      error
 }
 
 // SOAPClientConfig holds configuration for SOAP client
@@ -34,6 +40,28 @@ type SOAPClientConfig struct {
 	UserAgent     string        `json:"userAgent"`
 	EnableLogging bool          `json:"enableLogging"`
 	TLSConfig     *tls.Config   `json:"-"`
+	// Logger receives the request and response records, with personal and
+	// certificate data redacted; it enables logging and defaults to slog.Default()
+	Logger *slog.Logger `json:"-"`
+	// Archiver receives every request and response exchanged with SEFAZ
+	Archiver Archiver `json:"-"`
+	// RetryPolicies replaces the retry policy of an operation; the others
+	// follow DefaultRetryPolicy with MaxRetries and RetryDelay
+	RetryPolicies map[Operation]*RetryPolicy `json:"-"`
+
+	// Transport replaces the default http.Transport, e.g. to go through an
+	// egress proxy or record exchanges in tests. TLSConfig is not applied to it.
+	Transport http.RoundTripper `json:"-"`
+	// Middlewares run around every HTTP attempt, in order
+	Middlewares []Middleware `json:"-"`
+
+	// Proxy routes requests through an HTTP proxy, tunneling HTTPS with
+	// CONNECT and authenticating with the proxy user and password. It is not
+	// applied to a custom Transport.
+	Proxy *common.ProxyConfig `json:"proxy,omitempty"`
+	// NoProxy lists the hosts reached directly, in the NO_PROXY format; when
+	// empty, the NO_PROXY environment variable is used
+	NoProxy string `json:"noProxy,omitempty"`
 }
 
 // SOAPRequest represents a SOAP request
@@ -42,6 +70,12 @@ type SOAPRequest struct {
 	Action  string
 	Body    string
 	Headers map[string]string
+	// Version selects the HTTP headers of the envelope; when empty it is
+	// detected from the envelope namespace
+	Version SOAPVersion
+	// Operation selects the retry policy; when empty it is derived from the
+	// method in Action
+	Operation Operation
 }
 
 // SOAPResponse represents a SOAP response
@@ -85,25 +119,54 @@ func NewSOAPClient(config *SOAPClientConfig) *SOAPClient {
 	}
 
 	// Create HTTP client with proper configuration
-	httpClient := &http.Client{
-		Timeout: config.Timeout,
-		Transport: &http.Transport{
+	var proxyErr error
+	transport := config.Transport
+	if transport == nil {
+		httpTransport := &http.Transport{
 			TLSClientConfig:     config.TLSConfig,
 			MaxIdleConns:        10,
 			MaxIdleConnsPerHost: 2,
 			IdleConnTimeout:     90 * time.Second,
 			DisableCompression:  false,
-		},
+		}
+		if config.Proxy != nil {
+			noProxy := config.NoProxy
+			if noProxy == "" {
+				noProxy = NoProxyFromEnvironment()
+			}
+			httpTransport.Proxy, proxyErr = ProxyFunc(config.Proxy, noProxy)
+		}
+		transport = httpTransport
+	}
+
+	httpClient := &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+	}
+
+	retryPolicies := make(map[Operation]*RetryPolicy, len(config.RetryPolicies))
+	for operation, policy := range config.RetryPolicies {
+		retryPolicies[operation] = policy
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
 	}
 
 	return &SOAPClient{
+		proxyErr:      proxyErr,
 		httpClient:    httpClient,
 		timeout:       config.Timeout,
 		maxRetries:    config.MaxRetries,
 		retryDelay:    config.RetryDelay,
 		userAgent:     config.UserAgent,
 		tlsConfig:     config.TLSConfig,
-		enableLogging: config.EnableLogging,
+		enableLogging: config.EnableLogging || config.Logger != nil,
+		logger:        logger,
+		archiver:      config.Archiver,
+		retryPolicies: retryPolicies,
+		middlewares:   append([]Middleware(nil), config.Middlewares...),
 	}
 }
 
@@ -121,45 +184,66 @@ func (c *SOAPClient) Call(ctx context.Context, request *SOAPRequest) (*SOAPRespo
 		return nil, errors.NewValidationError("SOAP request body cannot be empty", "body", "")
 	}
 
-	var lastError error
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		if attempt > 0 {
-			// Exponential backoff with jitter
-			delay := time.Duration(float64(c.retryDelay) * math.Pow(2, float64(attempt-1)))
-			if delay > 30*time.Second {
-				delay = 30 * time.Second
-			}
-			
-			if c.enableLogging {
-				logDebug("Retrying SOAP call (attempt %d/%d) after %v", attempt+1, c.maxRetries+1, delay)
-			}
-			
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(delay):
-				// Continue with retry
-			}
+	// Never fall back to a direct connection when the proxy is misconfigured
+	if c.proxyErr != nil {
+		return nil, c.proxyErr
+	}
+
+	var info messageInfo
+	if c.enableLogging || c.archiver != nil {
+		info = describeRequest(request)
+	}
+
+	operation := request.operation()
+	policy := c.GetRetryPolicy(operation)
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		response, err := c.performRequest(ctx, request, attempt, info)
+		duration := time.Since(start)
+
+		retry, delay := false, time.Duration(0)
+		if err != nil && ctx.Err() == nil {
+			retry, delay = policy.next(attempt, err, response)
+		}
+
+		report := AttemptReport{Operation: operation, Attempt: attempt, Duration: duration, Err: err, Retry: retry, Delay: delay}
+		if response != nil {
+			report.StatusCode = response.StatusCode
 		}
+		policy.report(report)
 
-		response, err := c.performRequest(ctx, request, attempt+1)
 		if err == nil {
 			return response, nil
 		}
+		if !retry {
+			// A lote or event that may have been registered is never resent
+			// blindly; the caller must query its result
+			if !policy.Idempotent && possiblyProcessed(err, response) {
+				return nil, &UncertainDeliveryError{Operation: operation, Err: err}
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
 
-		lastError = err
+		if c.enableLogging {
+			attrs := append(info.attrs(), slog.Int("attempt", attempt+1), slog.Int("maxAttempts", policy.MaxRetries+1), slog.Duration("delay", delay))
+			c.logger.LogAttrs(ctx, slog.LevelInfo, "Retrying SOAP call", attrs...)
+		}
 
-		// Don't retry on certain types of errors
-		if !c.shouldRetry(err, response) {
-			break
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+			// Continue with retry
 		}
 	}
-
-	return nil, lastError
 }
 
 // performRequest executes a single SOAP HTTP request
-func (c *SOAPClient) performRequest(ctx context.Context, request *SOAPRequest, attempt int) (*SOAPResponse, error) {
+func (c *SOAPClient) performRequest(ctx context.Context, request *SOAPRequest, attempt int, info messageInfo) (*SOAPResponse, error) {
 	startTime := time.Now()
 
 	// Create HTTP request
@@ -176,12 +260,66 @@ func (c *SOAPClient) performRequest(ctx context.Context, request *SOAPRequest, a
 		httpReq.Header.Set(key, value)
 	}
 
+	exchange := &Exchange{Request: request, HTTPRequest: httpReq, Attempt: attempt, Start: startTime}
+	if ran, err := c.runBefore(exchange); err != nil {
+		exchange.Err = err
+		exchange.Duration = time.Since(startTime)
+		c.runAfter(exchange, ran)
+		return nil, exchange.Err
+	}
+
+	// the body as sent, after the Before hooks, is the one logged and archived
+	var body string
+	if c.enableLogging || c.archiver != nil {
+		if body, err = sentBody(exchange.HTTPRequest); err != nil {
+			exchange.Err = err
+			exchange.Duration = time.Since(startTime)
+			c.runAfter(exchange, len(c.middlewares))
+			return nil, exchange.Err
+		}
+	}
+
 	if c.enableLogging {
-		logDebug("SOAP Request (attempt %d): %s %s", attempt, httpReq.Method, httpReq.URL.String())
-		logDebug("SOAP Headers: %+v", httpReq.Header)
-		logDebug("SOAP Body: %s", request.Body)
+		c.logRequest(ctx, info, exchange.HTTPRequest, body, attempt)
+	}
+
+	exchange.Response, exchange.Err = c.roundTrip(exchange.HTTPRequest, startTime)
+	exchange.Duration = time.Since(startTime)
+
+	if c.enableLogging {
+		c.logResponse(ctx, info, attempt, exchange.Duration, exchange.Response, exchange.Err)
+	}
+	if c.archiver != nil {
+		c.archive(ctx, info, exchange, body)
+	}
+	c.runAfter(exchange, len(c.middlewares))
+
+	return exchange.Response, exchange.Err
+}
+
+// sentBody reads the body of a request about to be sent and rewinds it, so
+// that it can still be sent and retried by the transport
+func sentBody(httpReq *http.Request) (string, error) {
+	if httpReq.Body == nil || httpReq.Body == http.NoBody {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(httpReq.Body)
+	httpReq.Body.Close()
+	if err != nil {
+		return "", errors.NewNetworkError(fmt.Sprintf("failed to read HTTP request body: %v", err), err)
+	}
+
+	httpReq.ContentLength = int64(len(data))
+	httpReq.Body = io.NopCloser(bytes.NewReader(data))
+	httpReq.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
 	}
+	return string(data), nil
+}
 
+// roundTrip sends an HTTP request and reads the SOAP response
+func (c *SOAPClient) roundTrip(httpReq *http.Request, startTime time.Time) (*SOAPResponse, error) {
 	// Perform the HTTP request
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -204,14 +342,11 @@ func (c *SOAPClient) performRequest(ctx context.Context, request *SOAPRequest, a
 		Duration:   duration,
 	}
 
-	if c.enableLogging {
-		logDebug("SOAP Response (attempt %d): Status %d, Duration %v", attempt, httpResp.StatusCode, duration)
-		logDebug("SOAP Response Headers: %+v", httpResp.Header)
-		logDebug("SOAP Response Body: %s", response.Body)
-	}
-
-	// Check for HTTP errors
+	// Check for HTTP errors; SOAP faults are sent with status 500
 	if httpResp.StatusCode >= 400 {
+		if fault := faultFromResponse(response.Body); fault != nil {
+			return response, fault
+		}
 		return response, errors.NewNetworkError(
 			fmt.Sprintf("HTTP error: %d %s", httpResp.StatusCode, http.StatusText(httpResp.StatusCode)),
 			fmt.Errorf("status_%d", httpResp.StatusCode),
@@ -221,14 +356,31 @@ func (c *SOAPClient) performRequest(ctx context.Context, request *SOAPRequest, a
 	return response, nil
 }
 
-// setDefaultHeaders sets the required headers for SOAP requests
+// setDefaultHeaders sets the required headers for SOAP requests.
+// SOAP 1.1 sends the action in the SOAPAction header, SOAP 1.2 as a parameter
+// of the application/soap+xml content type.
 func (c *SOAPClient) setDefaultHeaders(req *http.Request, soapReq *SOAPRequest) {
-	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
 	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("Accept", "text/xml")
 	req.Header.Set("Accept-Encoding", "gzip, deflate")
 	req.Header.Set("Connection", "keep-alive")
-	
+
+	version := soapReq.Version
+	if version == "" {
+		version = GetSOAPVersion(soapReq.Body)
+	}
+
+	if version == SOAP12 {
+		contentType := "application/soap+xml; charset=utf-8"
+		if soapReq.Action != "" {
+			contentType += fmt.Sprintf("; action=\"%s\"", soapReq.Action)
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", "application/soap+xml, text/xml")
+		return
+	}
+
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("Accept", "text/xml")
 	if soapReq.Action != "" {
 		req.Header.Set("SOAPAction", fmt.Sprintf("\"%s\"", soapReq.Action))
 	}
@@ -236,38 +388,26 @@ func (c *SOAPClient) setDefaultHeaders(req *http.Request, soapReq *SOAPRequest)
 	// Content-Length is set automatically by Go's HTTP client
 }
 
-// shouldRetry determines if a request should be retried based on the error and response
-func (c *SOAPClient) shouldRetry(err error, response *SOAPResponse) bool {
-	// Don't retry on context cancellation or timeout
-	if err == context.Canceled || err == context.DeadlineExceeded {
-		return false
-	}
-
-	// Don't retry on validation errors
-	if netErr, ok := err.(*errors.NFError); ok && netErr.Type == errors.ErrValidation {
-		return false
-	}
-
-	// Retry on network errors
-	if _, ok := err.(*errors.NFError); ok {
-		return true
+// SetRetryPolicy replaces the retry policy of an operation, or restores the
+// default one when policy is nil
+func (c *SOAPClient) SetRetryPolicy(operation Operation, policy *RetryPolicy) {
+	if policy == nil {
+		delete(c.retryPolicies, operation)
+		return
 	}
+	c.retryPolicies[operation] = policy
+}
 
-	// Retry on certain HTTP status codes
-	if response != nil {
-		switch response.StatusCode {
-		case 500, 502, 503, 504: // Server errors
-			return true
-		case 408, 429: // Timeout, Too Many Requests
-			return true
-		case 401, 403, 404: // Authorization/Not Found errors
-			return false
-		default:
-			return false
-		}
+// GetRetryPolicy returns the retry policy applied to an operation
+func (c *SOAPClient) GetRetryPolicy(operation Operation) *RetryPolicy {
+	if policy, ok := c.retryPolicies[operation]; ok {
+		return policy
 	}
 
-	return false
+	policy := DefaultRetryPolicy(operation)
+	policy.MaxRetries = c.maxRetries
+	policy.BaseDelay = c.retryDelay
+	return policy
 }
 
 // SetTimeout updates the client timeout
@@ -295,6 +435,25 @@ func (c *SOAPClient) EnableLogging(enable bool) {
 	c.enableLogging = enable
 }
 
+// SetLogger replaces the logger and enables logging, or disables it when logger is nil
+func (c *SOAPClient) SetLogger(logger *slog.Logger) {
+	c.enableLogging = logger != nil
+	if logger == nil {
+		logger = slog.Default()
+	}
+	c.logger = logger
+}
+
+// GetLogger returns the logger receiving the request and response records
+func (c *SOAPClient) GetLogger() *slog.Logger {
+	return c.logger
+}
+
+// SetArchiver sets the archiver of the exchanges, or disables archiving when nil
+func (c *SOAPClient) SetArchiver(archiver Archiver) {
+	c.archiver = archiver
+}
+
 // SetTLSConfig updates the TLS configuration
 func (c *SOAPClient) SetTLSConfig(tlsConfig *tls.Config) {
 	c.tlsConfig = tlsConfig
@@ -303,6 +462,32 @@ func (c *SOAPClient) SetTLSConfig(tlsConfig *tls.Config) {
 	}
 }
 
+// SetProxy routes requests through proxy, or directly when proxy is nil.
+// Hosts matching noProxy bypass the proxy. It has no effect on a custom
+// transport.
+func (c *SOAPClient) SetProxy(proxy *common.ProxyConfig, noProxy string) error {
+	proxyFunc, err := ProxyFunc(proxy, noProxy)
+	if err != nil {
+		return err
+	}
+	if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
+		transport.Proxy = proxyFunc
+		transport.CloseIdleConnections()
+	}
+	c.proxyErr = nil
+	return nil
+}
+
+// SetTransport replaces the transport used to send requests
+func (c *SOAPClient) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
+// GetTransport returns the transport used to send requests
+func (c *SOAPClient) GetTransport() http.RoundTripper {
+	return c.httpClient.Transport
+}
+
 // GetTimeout returns the current timeout setting
 func (c *SOAPClient) GetTimeout() time.Duration {
 	return c.timeout
@@ -332,13 +517,6 @@ func (c *SOAPClient) Close() error {
 	return nil
 }
 
-// logDebug logs debug messages if logging is enabled
-func logDebug(format string, args ...interface{}) {
-	// For now, this is a simple implementation
-	// In a real implementation, you might want to use a proper logger
-	fmt.Printf("[SOAP DEBUG] "+format+"\n", args...)
-}
-
 // ValidateRequest validates a SOAP request
 func ValidateRequest(request *SOAPRequest) error {
 	if request == nil {
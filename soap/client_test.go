@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -441,34 +443,46 @@ func TestSOAPResponseMethods(t *testing.T) {
 
 func TestSOAPClientShouldRetry(t *testing.T) {
 	client := NewSOAPClient(DefaultConfig())
+	policy := client.GetRetryPolicy(OperationUnknown)
 	
 	// Test with context cancellation - should not retry
-	if client.shouldRetry(context.Canceled, nil) {
+	if policy.Retryable(context.Canceled, nil) {
 		t.Error("Should not retry on context cancellation")
 	}
 	
 	// Test with timeout - should not retry
-	if client.shouldRetry(context.DeadlineExceeded, nil) {
+	if policy.Retryable(context.DeadlineExceeded, nil) {
 		t.Error("Should not retry on timeout")
 	}
 	
 	// Test with server error response - should retry
 	response := &SOAPResponse{StatusCode: 500}
-	if !client.shouldRetry(nil, response) {
+	if !policy.Retryable(nil, response) {
 		t.Error("Should retry on server error")
 	}
 	
 	// Test with client error response - should not retry
 	response = &SOAPResponse{StatusCode: 404}
-	if client.shouldRetry(nil, response) {
+	if policy.Retryable(nil, response) {
 		t.Error("Should not retry on client error")
 	}
 	
 	// Test with success response - should not retry
 	response = &SOAPResponse{StatusCode: 200}
-	if client.shouldRetry(nil, response) {
+	if policy.Retryable(nil, response) {
 		t.Error("Should not retry on success")
 	}
+
+	// SEFAZ return codes decide by themselves
+	if !policy.Retryable(errors.NewSEFAZStatusError(108, ""), nil) {
+		t.Error("Should retry when the service is momentarily paralyzed")
+	}
+	if policy.Retryable(errors.NewSEFAZStatusError(204, ""), nil) {
+		t.Error("Should not retry on duplicate submission")
+	}
+	if policy.Retryable(errors.NewSEFAZStatusError(215, ""), nil) {
+		t.Error("Should not retry on schema rejection")
+	}
 }
 
 func TestSOAPClientClose(t *testing.T) {
@@ -478,4 +492,49 @@ func TestSOAPClientClose(t *testing.T) {
 	if err != nil {
 		t.Errorf("Close should not return error, got: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestSOAPClientHeadersByVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *SOAPRequest
+		contentType string
+		soapAction  string
+	}{
+		{
+			"SOAP 1.2 request",
+			&SOAPRequest{Action: "urn:status", Version: SOAP12, Body: "<env/>"},
+			`application/soap+xml; charset=utf-8; action="urn:status"`, "",
+		},
+		{
+			"SOAP 1.2 detected from the envelope",
+			&SOAPRequest{Action: "urn:status", Body: `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"/>`},
+			`application/soap+xml; charset=utf-8; action="urn:status"`, "",
+		},
+		{
+			"SOAP 1.1 request",
+			&SOAPRequest{Action: "urn:status", Body: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"/>`},
+			"text/xml; charset=utf-8", `"urn:status"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Content-Type"); got != test.contentType {
+					t.Errorf("Expected Content-Type %s, got %s", test.contentType, got)
+				}
+				if got := r.Header.Get("SOAPAction"); got != test.soapAction {
+					t.Errorf("Expected SOAPAction %q, got %q", test.soapAction, got)
+				}
+				w.Write([]byte("<ok/>"))
+			}))
+			defer server.Close()
+
+			test.request.URL = server.URL
+			if _, err := NewSOAPClient(DefaultConfig()).Call(context.Background(), test.request); err != nil {
+				t.Errorf("Call should not return error, got: %v", err)
+			}
+		})
+	}
+}
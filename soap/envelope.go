@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/webservices"
 )
 
 // SOAPVersion represents the SOAP protocol version
@@ -52,7 +53,7 @@ type SOAPEnvelope struct {
 // SOAPHeader represents the SOAP header section
 type SOAPHeader struct {
 	XMLName  xml.Name           `xml:"Header"`
-	Security *SecurityHeader    `xml:"Security,omitempty"`
+	Security *SecurityHeader    `xml:"wsse:Security,omitempty"`
 	Custom   []CustomHeaderElement `xml:",omitempty"`
 }
 
@@ -63,13 +64,20 @@ type SOAPBody struct {
 	Fault   *SOAPFault `xml:"Fault,omitempty"`
 }
 
-// SOAPFault represents a SOAP fault
+// SOAPFault represents a SOAP fault. SOAP 1.2 faults are mapped onto the same
+// fields: Code/Value into Code, Reason/Text into String and Detail into Detail.
 type SOAPFault struct {
 	XMLName xml.Name `xml:"Fault"`
 	Code    string   `xml:"faultcode"`
 	String  string   `xml:"faultstring"`
 	Actor   string   `xml:"faultactor,omitempty"`
 	Detail  string   `xml:"detail,omitempty"`
+
+	// SOAP 1.2 only: the Subcode values from outermost to innermost, Node and Role
+	Version  SOAPVersion `xml:"-"`
+	Subcodes []string    `xml:"-"`
+	Node     string      `xml:"-"`
+	Role     string      `xml:"-"`
 }
 
 // SecurityHeader represents WS-Security header
@@ -297,22 +305,13 @@ func ParseSOAPEnvelope(xmlData string) (*SOAPEnvelope, error) {
 		return nil, errors.NewValidationError("failed to parse SOAP envelope", "xml", xmlData[:min(len(xmlData), 100)])
 	}
 
-	// Post-process to handle fault vs content correctly
+	// Faults are parsed separately so SOAP 1.2 elements and any prefix are understood
 	if envelope.Body != nil {
-		// Check if we have a fault by looking for fault elements in the content
-		if strings.Contains(envelope.Body.Content, "<soap:Fault>") || 
-		   strings.Contains(envelope.Body.Content, "<faultcode>") {
-			// Parse fault separately if it wasn't parsed correctly
-			if envelope.Body.Fault == nil {
-				var faultBody struct {
-					XMLName xml.Name   `xml:"soap:Body"`
-					Fault   *SOAPFault `xml:"soap:Fault"`
-				}
-				if faultErr := xml.Unmarshal([]byte(xmlData), &faultBody); faultErr == nil && faultBody.Fault != nil {
-					envelope.Body.Fault = faultBody.Fault
-				}
-			}
+		fault, err := ParseSOAPFault(xmlData)
+		if err != nil {
+			return nil, err
 		}
+		envelope.Body.Fault = fault
 	}
 
 	return &envelope, nil
@@ -435,6 +434,52 @@ func CreateNFeSOAPRequest(url, action, bodyContent string) (*SOAPRequest, error)
 	return request, nil
 }
 
+// CreateSEFAZSOAPEnvelope creates the SOAP 1.2 envelope expected by the SEFAZ
+// 4.00 webservices: the message wrapped in nfeDadosMsg with the namespace of
+// the service, and no header
+func CreateSEFAZSOAPEnvelope(service *webservices.Service, message string) (*SOAPEnvelope, error) {
+	if service == nil || service.Operation == "" {
+		return nil, errors.NewValidationError("webservice operation cannot be empty", "service", service)
+	}
+
+	message = CleanXMLContent(message)
+	if message == "" {
+		return nil, errors.NewValidationError("message cannot be empty", "message", "")
+	}
+
+	builder := NewSOAP12EnvelopeBuilder()
+	builder.SetBodyContent(fmt.Sprintf(`<nfeDadosMsg xmlns="%s">%s</nfeDadosMsg>`, service.Namespace(), message))
+
+	return builder.Build()
+}
+
+// CreateSEFAZSOAPRequest creates a SOAP 1.2 request for a SEFAZ webservice,
+// sent with the application/soap+xml content type carrying the service action
+func CreateSEFAZSOAPRequest(service *webservices.Service, message string) (*SOAPRequest, error) {
+	if service == nil || service.URL == "" {
+		return nil, errors.NewValidationError("URL cannot be empty", "url", "")
+	}
+
+	envelope, err := CreateSEFAZSOAPEnvelope(service, message)
+	if err != nil {
+		return nil, err
+	}
+
+	envelopeXML, err := envelope.ToXML()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SOAPRequest{
+		URL:       service.URL,
+		Action:    service.Action(),
+		Body:      envelopeXML,
+		Headers:   make(map[string]string),
+		Version:   SOAP12,
+		Operation: OperationForMethod(service.Method),
+	}, nil
+}
+
 // ExtractBodyContent extracts the body content from a SOAP response
 func ExtractBodyContent(soapResponse string) (string, error) {
 	envelope, err := ParseSOAPEnvelope(soapResponse)
@@ -443,12 +488,7 @@ func ExtractBodyContent(soapResponse string) (string, error) {
 	}
 
 	if envelope.HasFault() {
-		fault := envelope.GetFault()
-		return "", errors.NewSEFAZError(
-			fmt.Sprintf("SOAP Fault: %s", fault.String),
-			fault.Code,
-			fmt.Errorf("%s", fault.Detail),
-		)
+		return "", NewFaultError(envelope.GetFault())
 	}
 
 	return envelope.GetBodyContent(), nil
@@ -456,10 +496,7 @@ func ExtractBodyContent(soapResponse string) (string, error) {
 
 // IsSOAPFaultResponse checks if a response contains a SOAP fault
 func IsSOAPFaultResponse(responseBody string) bool {
-	return strings.Contains(responseBody, "<soap:Fault>") || 
-		   strings.Contains(responseBody, "<Fault>") ||
-		   strings.Contains(responseBody, "faultcode") ||
-		   strings.Contains(responseBody, "faultstring")
+	return faultFromResponse(responseBody) != nil
 }
 
 // GetSOAPVersion detects the SOAP version from XML content
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/webservices"
 )
 
 func TestNewEnvelopeBuilder(t *testing.T) {
@@ -550,4 +552,51 @@ func TestValidateSOAPEnvelope(t *testing.T) {
 	if err != nil {
 		t.Errorf("ValidateSOAPEnvelope with valid envelope should not return error, got: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestCreateSEFAZSOAPRequest(t *testing.T) {
+	service := &webservices.Service{
+		Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+		URL: "https://nfe.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+	}
+	message := `<?xml version="1.0" encoding="UTF-8"?><consStatServ xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><cUF>43</cUF><xServ>STATUS</xServ></consStatServ>`
+
+	request, err := CreateSEFAZSOAPRequest(service, message)
+	if err != nil {
+		t.Fatalf("CreateSEFAZSOAPRequest should not return error, got: %v", err)
+	}
+
+	if request.URL != service.URL {
+		t.Errorf("Expected URL %s, got %s", service.URL, request.URL)
+	}
+	if request.Action != "http://www.portalfiscal.inf.br/nfe/wsdl/NFeStatusServico4/nfeStatusServicoNF" {
+		t.Errorf("Unexpected action %s", request.Action)
+	}
+	if request.Version != SOAP12 {
+		t.Errorf("Expected SOAP 1.2, got %s", request.Version)
+	}
+	if request.Operation != OperationStatus {
+		t.Errorf("Expected status operation, got %q", request.Operation)
+	}
+
+	want := `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">` +
+		`<soap:Body><nfeDadosMsg xmlns="http://www.portalfiscal.inf.br/nfe/wsdl/NFeStatusServico4">` +
+		`<consStatServ xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><tpAmb>2</tpAmb><cUF>43</cUF><xServ>STATUS</xServ></consStatServ>` +
+		`</nfeDadosMsg></soap:Body></soap:Envelope>`
+	if request.Body != xml.Header+want {
+		t.Errorf("Unexpected envelope\n got: %s\nwant: %s", request.Body, xml.Header+want)
+	}
+	if strings.Contains(request.Body, "Security") || strings.Contains(request.Body, "Header") {
+		t.Error("SEFAZ envelope should not have a header")
+	}
+
+	if _, err := CreateSEFAZSOAPRequest(nil, message); err == nil {
+		t.Error("CreateSEFAZSOAPRequest should return error for nil service")
+	}
+	if _, err := CreateSEFAZSOAPRequest(&webservices.Service{URL: service.URL}, message); err == nil {
+		t.Error("CreateSEFAZSOAPRequest should return error without operation")
+	}
+	if _, err := CreateSEFAZSOAPRequest(service, " "); err == nil {
+		t.Error("CreateSEFAZSOAPRequest should return error for empty message")
+	}
+}
@@ -0,0 +1,226 @@
+package soap
+
+import (
+	"encoding/xml"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// Fault codes of SOAP 1.2, with their SOAP 1.1 equivalents
+const (
+	FaultCodeSender              = "Sender"
+	FaultCodeReceiver            = "Receiver"
+	FaultCodeVersionMismatch     = "VersionMismatch"
+	FaultCodeMustUnderstand      = "MustUnderstand"
+	FaultCodeDataEncodingUnknown = "DataEncodingUnknown"
+	FaultCodeClient              = "Client"
+	FaultCodeServer              = "Server"
+)
+
+// rawFault holds the elements of a SOAP 1.1 or 1.2 fault. Tags carry only local
+// names, so they match whatever prefix the server binds to the envelope namespace.
+type rawFault struct {
+	FaultCode   string   `xml:"faultcode"`
+	FaultString string   `xml:"faultstring"`
+	FaultActor  string   `xml:"faultactor"`
+	FaultDetail innerXML `xml:"detail"`
+
+	Code   rawFaultCode `xml:"Code"`
+	Reason []string     `xml:"Reason>Text"`
+	Node   string       `xml:"Node"`
+	Role   string       `xml:"Role"`
+	Detail innerXML     `xml:"Detail"`
+}
+
+// rawFaultCode is a SOAP 1.2 Code or Subcode, which nest
+type rawFaultCode struct {
+	Value   string        `xml:"Value"`
+	Subcode *rawFaultCode `xml:"Subcode"`
+}
+
+// innerXML captures the raw content of an element
+type innerXML struct {
+	Content string `xml:",innerxml"`
+}
+
+// ParseSOAPFault returns the fault carried in the body of a SOAP 1.1 or 1.2
+// envelope, or nil when the body holds a regular response. Fault codes are
+// returned without their namespace prefix.
+func ParseSOAPFault(xmlData string) (*SOAPFault, error) {
+	decoder := xml.NewDecoder(strings.NewReader(xmlData))
+	version := SOAP11
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.NewXMLError("failed to parse SOAP envelope", "Envelope", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch depth {
+			case 0:
+				if t.Name.Local != "Envelope" {
+					return nil, errors.NewXMLError(fmt.Sprintf("expected SOAP Envelope, found %s", t.Name.Local), "Envelope", nil)
+				}
+				if t.Name.Space == SOAP12EnvelopeNS {
+					version = SOAP12
+				}
+			case 2:
+				// First element of the Body
+				if t.Name.Local != "Fault" {
+					return nil, nil
+				}
+				var raw rawFault
+				if err := decoder.DecodeElement(&raw, &t); err != nil {
+					return nil, errors.NewXMLError("failed to parse SOAP fault", "Fault", err)
+				}
+				return raw.toFault(version), nil
+			}
+			if depth == 1 && t.Name.Local != "Body" {
+				if err := decoder.Skip(); err != nil {
+					return nil, errors.NewXMLError("failed to parse SOAP envelope", t.Name.Local, err)
+				}
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// toFault converts the parsed elements into a SOAPFault
+func (r *rawFault) toFault(version SOAPVersion) *SOAPFault {
+	if version == SOAP11 && r.FaultCode == "" && r.Code.Value != "" {
+		version = SOAP12
+	}
+
+	if version == SOAP11 {
+		return &SOAPFault{
+			Version: SOAP11,
+			Code:    localName(r.FaultCode),
+			String:  strings.TrimSpace(r.FaultString),
+			Actor:   strings.TrimSpace(r.FaultActor),
+			Detail:  strings.TrimSpace(r.FaultDetail.Content),
+		}
+	}
+
+	fault := &SOAPFault{
+		Version: SOAP12,
+		Code:    localName(r.Code.Value),
+		Node:    strings.TrimSpace(r.Node),
+		Role:    strings.TrimSpace(r.Role),
+		Detail:  strings.TrimSpace(r.Detail.Content),
+	}
+	for subcode := r.Code.Subcode; subcode != nil; subcode = subcode.Subcode {
+		fault.Subcodes = append(fault.Subcodes, localName(subcode.Value))
+	}
+	for _, text := range r.Reason {
+		if text = strings.TrimSpace(text); text != "" {
+			fault.String = text
+			break
+		}
+	}
+	return fault
+}
+
+// localName removes the namespace prefix of a qualified name such as soap:Receiver
+func localName(qname string) string {
+	qname = strings.TrimSpace(qname)
+	if i := strings.LastIndex(qname, ":"); i >= 0 {
+		return qname[i+1:]
+	}
+	return qname
+}
+
+// FaultError is a SOAP fault returned by a webservice
+type FaultError struct {
+	SOAPFault
+}
+
+// NewFaultError creates the error of a parsed fault
+func NewFaultError(fault *SOAPFault) *FaultError {
+	return &FaultError{SOAPFault: *fault}
+}
+
+// Error implements the error interface
+func (e *FaultError) Error() string {
+	code := e.Code
+	if len(e.Subcodes) > 0 {
+		code += "/" + strings.Join(e.Subcodes, "/")
+	}
+	message := fmt.Sprintf("[%s] SOAP fault %s: %s", errors.ErrSEFAZ.Code, code, e.String)
+	if e.Detail != "" {
+		message += " (" + e.Detail + ")"
+	}
+	return message
+}
+
+// baseCode returns the fault code without SOAP 1.1 dotted subcodes (Client.Authentication)
+func (e *FaultError) baseCode() string {
+	if i := strings.Index(e.Code, "."); i >= 0 {
+		return e.Code[:i]
+	}
+	return e.Code
+}
+
+// IsServerFault reports whether the webservice failed to process a valid
+// message (Receiver, or Server in SOAP 1.1), so the request may be resent
+func (e *FaultError) IsServerFault() bool {
+	code := e.baseCode()
+	return code == FaultCodeReceiver || code == FaultCodeServer
+}
+
+// IsSchemaRejection reports whether the webservice rejected the message itself
+// (Sender, or Client in SOAP 1.1), typically a body that does not match the
+// WSDL or schema; resending it unchanged fails again
+func (e *FaultError) IsSchemaRejection() bool {
+	code := e.baseCode()
+	return code == FaultCodeSender || code == FaultCodeClient
+}
+
+// Retryable reports whether the request can be resent unchanged after a delay
+func (e *FaultError) Retryable() bool {
+	return e.IsServerFault()
+}
+
+// Is matches another FaultError with the same code, or any NFError of type ErrSEFAZ
+func (e *FaultError) Is(target error) bool {
+	switch t := target.(type) {
+	case *FaultError:
+		return t.Code == "" || t.baseCode() == e.baseCode()
+	case *errors.NFError:
+		return t.Type != nil && t.Type.Code == errors.ErrSEFAZ.Code
+	}
+	return false
+}
+
+// AsFaultError finds the first FaultError in the chain of err
+func AsFaultError(err error) (*FaultError, bool) {
+	var fault *FaultError
+	if stderrors.As(err, &fault) {
+		return fault, true
+	}
+	return nil, false
+}
+
+// faultFromResponse returns the fault carried in a response body, if any
+func faultFromResponse(body string) *FaultError {
+	if !strings.Contains(body, "Fault") {
+		return nil
+	}
+	fault, err := ParseSOAPFault(body)
+	if err != nil || fault == nil {
+		return nil
+	}
+	return NewFaultError(fault)
+}
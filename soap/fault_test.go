@@ -0,0 +1,170 @@
+package soap
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+const soap12ServerFault = `<?xml version="1.0" encoding="utf-8"?>
+<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope">
+  <env:Header><Trace xmlns="urn:x">1</Trace></env:Header>
+  <env:Body>
+    <env:Fault>
+      <env:Code>
+        <env:Value>env:Receiver</env:Value>
+        <env:Subcode><env:Value xmlns:s="urn:sefaz">s:Indisponivel</env:Value>
+          <env:Subcode><env:Value>s:Timeout</env:Value></env:Subcode>
+        </env:Subcode>
+      </env:Code>
+      <env:Reason><env:Text xml:lang="pt-BR">Servidor indisponível</env:Text><env:Text xml:lang="en">Server unavailable</env:Text></env:Reason>
+      <env:Node>https://nfe.svrs.rs.gov.br</env:Node>
+      <env:Detail><erro xmlns="urn:sefaz">banco de dados</erro></env:Detail>
+    </env:Fault>
+  </env:Body>
+</env:Envelope>`
+
+const soap12SenderFault = `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body>` +
+	`<soap:Fault><soap:Code><soap:Value>soap:Sender</soap:Value></soap:Code>` +
+	`<soap:Reason><soap:Text xml:lang="en">Server was unable to read request. ---&gt; There is an error in XML document (1, 120).</soap:Text></soap:Reason>` +
+	`<soap:Detail /></soap:Fault></soap:Body></soap:Envelope>`
+
+func TestParseSOAPFault(t *testing.T) {
+	fault, err := ParseSOAPFault(soap12ServerFault)
+	if err != nil {
+		t.Fatalf("ParseSOAPFault should not return error, got: %v", err)
+	}
+	if fault == nil {
+		t.Fatal("ParseSOAPFault should return fault")
+	}
+	if fault.Version != SOAP12 || fault.Code != FaultCodeReceiver {
+		t.Errorf("Unexpected version/code: %s %s", fault.Version, fault.Code)
+	}
+	if len(fault.Subcodes) != 2 || fault.Subcodes[0] != "Indisponivel" || fault.Subcodes[1] != "Timeout" {
+		t.Errorf("Unexpected subcodes: %v", fault.Subcodes)
+	}
+	if fault.String != "Servidor indisponível" {
+		t.Errorf("Unexpected reason: %s", fault.String)
+	}
+	if fault.Node != "https://nfe.svrs.rs.gov.br" {
+		t.Errorf("Unexpected node: %s", fault.Node)
+	}
+	if fault.Detail != `<erro xmlns="urn:sefaz">banco de dados</erro>` {
+		t.Errorf("Unexpected detail: %s", fault.Detail)
+	}
+
+	soap11 := `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>` +
+		`<faultcode>s:Client.Authentication</faultcode><faultstring>Certificado invalido</faultstring>` +
+		`<detail><x>1</x></detail></s:Fault></s:Body></s:Envelope>`
+	fault, err = ParseSOAPFault(soap11)
+	if err != nil || fault == nil {
+		t.Fatalf("ParseSOAPFault should parse SOAP 1.1 fault, got: %v, %v", fault, err)
+	}
+	if fault.Version != SOAP11 || fault.Code != "Client.Authentication" || fault.String != "Certificado invalido" || fault.Detail != "<x>1</x>" {
+		t.Errorf("Unexpected SOAP 1.1 fault: %+v", fault)
+	}
+
+	// A regular response, and a fault-like element outside the Body
+	for _, data := range []string{
+		`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><nfeResultMsg/></soap:Body></soap:Envelope>`,
+		`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Header><Fault/></soap:Header><soap:Body><ok/></soap:Body></soap:Envelope>`,
+	} {
+		fault, err := ParseSOAPFault(data)
+		if err != nil || fault != nil {
+			t.Errorf("ParseSOAPFault(%s) = %v, %v, expected no fault", data, fault, err)
+		}
+	}
+
+	for _, data := range []string{"<Envelope><Body>", "<html><body>502</body></html>"} {
+		if _, err := ParseSOAPFault(data); err == nil {
+			t.Errorf("ParseSOAPFault(%q) should return error", data)
+		}
+	}
+}
+
+func TestParseSOAPEnvelopeSOAP12Fault(t *testing.T) {
+	envelope, err := ParseSOAPEnvelope(soap12SenderFault)
+	if err != nil {
+		t.Fatalf("ParseSOAPEnvelope should not return error, got: %v", err)
+	}
+	fault := envelope.GetFault()
+	if fault == nil || fault.Code != FaultCodeSender {
+		t.Fatalf("Expected Sender fault, got %+v", fault)
+	}
+	if !IsSOAPFaultResponse(soap12SenderFault) {
+		t.Error("Should detect SOAP 1.2 fault response")
+	}
+}
+
+func TestFaultError(t *testing.T) {
+	_, err := ExtractBodyContent(soap12ServerFault)
+	fault, ok := AsFaultError(err)
+	if !ok {
+		t.Fatalf("ExtractBodyContent should return FaultError, got: %v", err)
+	}
+	if !fault.IsServerFault() || fault.IsSchemaRejection() || !fault.Retryable() {
+		t.Error("Receiver fault should be a retryable server fault")
+	}
+	if fault.Error() != `[SEFAZ] SOAP fault Receiver/Indisponivel/Timeout: Servidor indisponível (<erro xmlns="urn:sefaz">banco de dados</erro>)` {
+		t.Errorf("Unexpected message: %s", fault.Error())
+	}
+	if !stderrors.Is(err, &errors.NFError{Type: errors.ErrSEFAZ}) {
+		t.Error("FaultError should match ErrSEFAZ")
+	}
+	if !stderrors.Is(err, &FaultError{SOAPFault: SOAPFault{Code: FaultCodeReceiver}}) {
+		t.Error("FaultError should match another fault with the same code")
+	}
+
+	_, err = ExtractBodyContent(soap12SenderFault)
+	fault, ok = AsFaultError(err)
+	if !ok || !fault.IsSchemaRejection() || fault.IsServerFault() || fault.Retryable() {
+		t.Errorf("Sender fault should be a schema rejection, got: %v", err)
+	}
+
+	legacy := NewFaultError(&SOAPFault{Code: "Server.Busy"})
+	if !legacy.IsServerFault() {
+		t.Error("SOAP 1.1 Server.Busy should be a server fault")
+	}
+}
+
+func TestSOAPClientFaultResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		attempts int
+	}{
+		{"server fault is retried", soap12ServerFault, 3},
+		{"schema rejection is not retried", soap12SenderFault, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.Header().Set("Content-Type", "application/soap+xml; charset=utf-8")
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			config := DefaultConfig()
+			config.MaxRetries = 2
+			config.RetryDelay = time.Millisecond
+			client := NewSOAPClient(config)
+
+			_, err := client.Call(context.Background(), &SOAPRequest{URL: server.URL, Version: SOAP12, Body: "<env/>"})
+			if _, ok := AsFaultError(err); !ok {
+				t.Errorf("Call should return FaultError, got: %v", err)
+			}
+			if attempts != test.attempts {
+				t.Errorf("Expected %d attempts, got %d", test.attempts, attempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package soap
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redactedMask replaces the content of redacted XML elements
+const redactedMask = "***"
+
+// redactedElements are the elements masked by RedactXML: personal data of
+// emitente, destinatário and transportador (CPF, names, addresses and
+// contacts) and the certificate and signature values of XML-DSig
+var redactedElements = []string{
+	"CPF", "idEstrangeiro", "xNome", "xFant",
+	"xLgr", "nro", "xCpl", "xBairro", "CEP", "fone", "email", "xEnder",
+	"X509Certificate", "X509SubjectName", "X509IssuerName", "X509SerialNumber", "SignatureValue",
+}
+
+// chaveExpr matches a chave de acesso, whose emitente CNPJ (positions 7-20)
+// may be alphanumeric
+const chaveExpr = `\d{6}[0-9A-Z]{14}\d{24}`
+
+var (
+	redactPattern = regexp.MustCompile(`(<(?:[\w.-]+:)?(?:` + strings.Join(redactedElements, "|") + `)(?:\s[^>]*)?>)[^<]+`)
+
+	cUFPattern     = regexp.MustCompile(`<(?:[\w.-]+:)?(?:cUF|cOrgao)>(\d{2})<`)
+	cnpjPattern    = regexp.MustCompile(`<(?:[\w.-]+:)?CNPJ>([0-9A-Z]{12}[0-9]{2})<`)
+	chavePattern   = regexp.MustCompile(`<(?:[\w.-]+:)?chNFe>(` + chaveExpr + `)<|Id="NFe(` + chaveExpr + `)"`)
+	cStatPattern   = regexp.MustCompile(`<(?:[\w.-]+:)?cStat>(\d{3})<`)
+	servicePattern = regexp.MustCompile(`[^/]+$`)
+)
+
+// RedactXML masks personal and certificate data of a SEFAZ message (CPF,
+// names, addresses, contacts and X.509 data) so it can be written to logs.
+// CNPJ, chaves and values are kept to identify the document.
+func RedactXML(xml string) string {
+	return redactPattern.ReplaceAllString(xml, "${1}"+redactedMask)
+}
+
+// redactedXML is a message logged through RedactXML, redacted only when the
+// record is actually written
+type redactedXML string
+
+// LogValue implements slog.LogValuer
+func (x redactedXML) LogValue() slog.Value {
+	return slog.StringValue(RedactXML(string(x)))
+}
+
+// messageInfo holds the identifiers of a SEFAZ message
+type messageInfo struct {
+	Service string
+	UF      string
+	CNPJ    string
+	Chaves  []string
+}
+
+// describeRequest extracts the service, UF, CNPJ and chaves of a request. The
+// service is the operation of the SOAP action; the UF comes from cUF or
+// cOrgao, or from the first chave, and the CNPJ from the first chave or the
+// first CNPJ element.
+func describeRequest(request *SOAPRequest) messageInfo {
+	info := messageInfo{
+		Service: servicePattern.FindString(request.Action),
+		Chaves:  appendChaves(nil, request.Body),
+	}
+
+	if match := cUFPattern.FindStringSubmatch(request.Body); match != nil {
+		info.UF = match[1]
+	} else if len(info.Chaves) > 0 {
+		info.UF = info.Chaves[0][:2]
+	}
+
+	if len(info.Chaves) > 0 {
+		info.CNPJ = info.Chaves[0][6:20]
+	} else if match := cnpjPattern.FindStringSubmatch(request.Body); match != nil {
+		info.CNPJ = match[1]
+	}
+	return info
+}
+
+// appendChaves appends the chaves found in body (chNFe elements and infNFe
+// Ids) that are not in chaves yet
+func appendChaves(chaves []string, body string) []string {
+	for _, match := range chavePattern.FindAllStringSubmatch(body, -1) {
+		chave := match[1] + match[2]
+		if !slices.Contains(chaves, chave) {
+			chaves = append(chaves, chave)
+		}
+	}
+	return chaves
+}
+
+// attrs returns the log attributes of the message
+func (m messageInfo) attrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, 3)
+	if m.Service != "" {
+		attrs = append(attrs, slog.String("service", m.Service))
+	}
+	if m.UF != "" {
+		attrs = append(attrs, slog.String("uf", m.UF))
+	}
+	if len(m.Chaves) > 0 {
+		attrs = append(attrs, slog.String("chave", strings.Join(m.Chaves, ",")))
+	}
+	return attrs
+}
+
+// responseCStat returns the first cStat of a response, which is the status of
+// the whole message (lote or consulta), or zero if there is none
+func responseCStat(body string) int {
+	match := cStatPattern.FindStringSubmatch(body)
+	if match == nil {
+		return 0
+	}
+	cStat, _ := strconv.Atoi(match[1])
+	return cStat
+}
+
+// logRequest records a request about to be sent
+func (c *SOAPClient) logRequest(ctx context.Context, info messageInfo, httpReq *http.Request, body string, attempt int) {
+	attrs := append(info.attrs(),
+		slog.Int("attempt", attempt),
+		slog.String("url", httpReq.URL.String()),
+		slog.Any("body", redactedXML(body)),
+	)
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "SOAP request", attrs...)
+}
+
+// logResponse records the outcome of an attempt: failures as warnings, and
+// responses with their cStat
+func (c *SOAPClient) logResponse(ctx context.Context, info messageInfo, attempt int, duration time.Duration, response *SOAPResponse, err error) {
+	attrs := append(info.attrs(),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", duration),
+	)
+	if response != nil {
+		attrs = append(attrs, slog.Int("status", response.StatusCode))
+		if cStat := responseCStat(response.Body); cStat != 0 {
+			attrs = append(attrs, slog.Int("cStat", cStat))
+		}
+		attrs = append(attrs, slog.Any("body", redactedXML(response.Body)))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "SOAP request failed", attrs...)
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "SOAP response", attrs...)
+}
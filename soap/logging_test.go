@@ -0,0 +1,170 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "destinatário",
+			input:    `<dest><CPF>12345678909</CPF><xNome>FULANO DE TAL</xNome><enderDest><xLgr>RUA A</xLgr><nro>10</nro><xBairro>CENTRO</xBairro><CEP>01001000</CEP><fone>11999999999</fone></enderDest><email>fulano@example.com</email></dest>`,
+			expected: `<dest><CPF>***</CPF><xNome>***</xNome><enderDest><xLgr>***</xLgr><nro>***</nro><xBairro>***</xBairro><CEP>***</CEP><fone>***</fone></enderDest><email>***</email></dest>`,
+		},
+		{
+			name:     "CNPJ and chave kept",
+			input:    `<emit><CNPJ>12345678000195</CNPJ><xNome>EMPRESA</xNome></emit><chNFe>35240112345678000195550010000000011000000010</chNFe>`,
+			expected: `<emit><CNPJ>12345678000195</CNPJ><xNome>***</xNome></emit><chNFe>35240112345678000195550010000000011000000010</chNFe>`,
+		},
+		{
+			name:     "prefixed elements with attributes",
+			input:    `<ds:SignatureValue Id="sig">YWJj</ds:SignatureValue><ds:X509Certificate>MIIB</ds:X509Certificate>`,
+			expected: `<ds:SignatureValue Id="sig">***</ds:SignatureValue><ds:X509Certificate>***</ds:X509Certificate>`,
+		},
+		{
+			name:     "similar names untouched",
+			input:    `<CPFCNPJ>1</CPFCNPJ><nRec>123</nRec><xNome/>`,
+			expected: `<CPFCNPJ>1</CPFCNPJ><nRec>123</nRec><xNome/>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactXML(tt.input); got != tt.expected {
+				t.Errorf("RedactXML() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDescribeRequest(t *testing.T) {
+	chave := "35240112345678000195550010000000011000000010"
+
+	info := describeRequest(&SOAPRequest{
+		Action: "http://www.portalfiscal.inf.br/nfe/wsdl/NFeConsultaProtocolo4/nfeConsultaNF",
+		Body:   `<consSitNFe><tpAmb>2</tpAmb><xServ>CONSULTAR</xServ><chNFe>` + chave + `</chNFe></consSitNFe>`,
+	})
+	if info.Service != "nfeConsultaNF" || info.UF != "35" || len(info.Chaves) != 1 || info.Chaves[0] != chave {
+		t.Errorf("Unexpected info %+v", info)
+	}
+
+	info = describeRequest(&SOAPRequest{
+		Body: `<enviNFe><NFe><infNFe Id="NFe` + chave + `"><ide><cUF>35</cUF></ide></infNFe></NFe>` +
+			`<NFe><infNFe Id="NFe41240112345678000195550010000000021000000020"><ide><cUF>41</cUF></ide></infNFe></NFe></enviNFe>`,
+	})
+	if info.UF != "35" || len(info.Chaves) != 2 || info.Chaves[1][:2] != "41" {
+		t.Errorf("Unexpected info %+v", info)
+	}
+
+	alphanumeric := "35240112ABC345000195550010000000011000000010"
+	info = describeRequest(&SOAPRequest{Body: `<consSitNFe><chNFe>` + alphanumeric + `</chNFe></consSitNFe>`})
+	if len(info.Chaves) != 1 || info.Chaves[0] != alphanumeric || info.CNPJ != "12ABC345000195" {
+		t.Errorf("Unexpected info for alphanumeric chave %+v", info)
+	}
+
+	info = describeRequest(&SOAPRequest{Body: `<distDFeInt><cUFAutor>35</cUFAutor><CNPJ>12ABC345000195</CNPJ></distDFeInt>`})
+	if info.CNPJ != "12ABC345000195" {
+		t.Errorf("Expected alphanumeric CNPJ, got %+v", info)
+	}
+
+	if cStat := responseCStat(`<retConsSitNFe><cStat>100</cStat><protNFe><infProt><cStat>100</cStat></infProt></protNFe></retConsSitNFe>`); cStat != 100 {
+		t.Errorf("Expected cStat 100, got %d", cStat)
+	}
+}
+
+func TestSOAPClientStructuredLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	transport := &recordingTransport{status: http.StatusOK, body: `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><retConsStatServ><cStat>107</cStat><xMotivo>Servico em Operacao</xMotivo></retConsStatServ></soap:Body></soap:Envelope>`}
+	config := DefaultConfig()
+	config.Transport = transport
+	config.Logger = logger
+	client := NewSOAPClient(config)
+
+	if !client.IsLoggingEnabled() {
+		t.Fatal("Logger should enable logging")
+	}
+
+	_, err := client.Call(context.Background(), &SOAPRequest{
+		URL:    "https://nfe.example.gov.br/ws",
+		Action: "http://www.portalfiscal.inf.br/nfe/wsdl/NFeStatusServico4/nfeStatusServicoNF",
+		Body:   `<consStatServ><tpAmb>2</tpAmb><cUF>35</cUF><xServ>STATUS</xServ><dest><CPF>12345678909</CPF></dest></consStatServ>`,
+	})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "12345678909") {
+		t.Errorf("Log should not contain the CPF: %s", buf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected request and response records, got %d", len(lines))
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("Invalid log record: %v", err)
+	}
+	expected := map[string]interface{}{
+		"msg":     "SOAP response",
+		"service": "nfeStatusServicoNF",
+		"uf":      "35",
+		"attempt": float64(1),
+		"cStat":   float64(107),
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, record[key])
+		}
+	}
+	if _, ok := record["duration"]; !ok {
+		t.Error("Response record should have a duration")
+	}
+
+	client.SetLogger(nil)
+	if client.IsLoggingEnabled() {
+		t.Error("SetLogger(nil) should disable logging")
+	}
+}
+
+func TestSOAPClientLogsAlphanumericCNPJ(t *testing.T) {
+	var buf bytes.Buffer
+	chave := "35240112ABC345000195550010000000011000000010"
+
+	config := DefaultConfig()
+	config.Transport = &recordingTransport{status: http.StatusOK, body: `<retConsSitNFe><cStat>100</cStat></retConsSitNFe>`}
+	config.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewSOAPClient(config)
+
+	_, err := client.Call(context.Background(), &SOAPRequest{
+		URL:    "https://nfe.example.gov.br/ws",
+		Action: "http://www.portalfiscal.inf.br/nfe/wsdl/NFeConsultaProtocolo4/nfeConsultaNF",
+		Body:   `<consSitNFe><tpAmb>2</tpAmb><xServ>CONSULTAR</xServ><chNFe>` + chave + `</chNFe></consSitNFe>`,
+	})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid log record: %v", err)
+		}
+		if record["chave"] != chave || record["uf"] != "35" {
+			t.Errorf("Expected chave %s and uf 35, got %v", chave, record)
+		}
+	}
+}
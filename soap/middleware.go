@@ -0,0 +1,76 @@
+package soap
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// Exchange is an HTTP attempt of a SOAP call, as seen by middleware hooks
+type Exchange struct {
+	Request *SOAPRequest
+	// HTTPRequest is the request about to be sent. Before hooks may add
+	// headers, replace the body or replace the request itself.
+	HTTPRequest *http.Request
+	// Attempt starts at 1 and grows with each retry
+	Attempt int
+	Start   time.Time
+
+	// Response, Err and Duration are the outcome of the attempt, set before the
+	// After hooks run. After hooks may replace Response and Err.
+	Response *SOAPResponse
+	Err      error
+	Duration time.Duration
+}
+
+// Middleware holds hooks run around every HTTP attempt. Before hooks run in
+// the order the middlewares were added and After hooks in reverse order, so
+// each middleware wraps the ones added after it. Either hook may be nil.
+type Middleware struct {
+	// Name identifies the middleware in errors
+	Name string
+	// Before runs before the request is sent; an error aborts the attempt
+	// without retries, after the After hooks of the middlewares already run
+	Before func(exchange *Exchange) error
+	// After runs once the attempt completes, successfully or not
+	After func(exchange *Exchange)
+}
+
+// Use appends middlewares to the chain. It must not be called while requests are in flight.
+func (c *SOAPClient) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// Middlewares returns the middleware chain
+func (c *SOAPClient) Middlewares() []Middleware {
+	return append([]Middleware(nil), c.middlewares...)
+}
+
+// runBefore runs the Before hooks in order, returning how many middlewares
+// were entered and the error that stopped the chain
+func (c *SOAPClient) runBefore(exchange *Exchange) (int, error) {
+	for i, middleware := range c.middlewares {
+		if middleware.Before == nil {
+			continue
+		}
+		if err := middleware.Before(exchange); err != nil {
+			message := "SOAP middleware rejected the request"
+			if middleware.Name != "" {
+				message = fmt.Sprintf("SOAP middleware %s rejected the request", middleware.Name)
+			}
+			return i, errors.WrapError(err, errors.ErrValidation, message)
+		}
+	}
+	return len(c.middlewares), nil
+}
+
+// runAfter runs the After hooks of the first n middlewares in reverse order
+func (c *SOAPClient) runAfter(exchange *Exchange, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if after := c.middlewares[i].After; after != nil {
+			after(exchange)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package soap
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingTransport answers every request with a canned SOAP response and
+// records the requests, without touching the network
+type recordingTransport struct {
+	status   int
+	body     string
+	requests []*http.Request
+	bodies   []string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, string(body))
+	return &http.Response{
+		StatusCode: r.status,
+		Header:     http.Header{"Content-Type": {"application/soap+xml"}},
+		Body:       io.NopCloser(strings.NewReader(r.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestSOAPClientCustomTransport(t *testing.T) {
+	transport := &recordingTransport{status: http.StatusOK, body: `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><ok/></soap:Body></soap:Envelope>`}
+	config := DefaultConfig()
+	config.Transport = transport
+	client := NewSOAPClient(config)
+
+	if client.GetTransport() != transport {
+		t.Error("GetTransport should return the injected transport")
+	}
+
+	response, err := client.Call(context.Background(), &SOAPRequest{URL: "https://nfe.example.gov.br/ws", Body: "<env/>", Version: SOAP12})
+	if err != nil {
+		t.Fatalf("Call should not return error, got: %v", err)
+	}
+	if !strings.Contains(response.Body, "<ok/>") {
+		t.Errorf("Unexpected response body: %s", response.Body)
+	}
+	if len(transport.requests) != 1 || transport.bodies[0] != "<env/>" {
+		t.Errorf("Transport should receive the request, got %d requests", len(transport.requests))
+	}
+
+	replacement := &recordingTransport{status: http.StatusOK, body: "<soap:Envelope><soap:Body/></soap:Envelope>"}
+	client.SetTransport(replacement)
+	if _, err := client.Call(context.Background(), &SOAPRequest{URL: "https://nfe.example.gov.br/ws", Body: "<env/>"}); err != nil {
+		t.Fatalf("Call should not return error, got: %v", err)
+	}
+	if len(replacement.requests) != 1 || len(transport.requests) != 1 {
+		t.Error("SetTransport should replace the transport")
+	}
+}
+
+func TestSOAPClientMiddlewareOrder(t *testing.T) {
+	transport := &recordingTransport{status: http.StatusOK, body: "<soap:Envelope><soap:Body><ok/></soap:Body></soap:Envelope>"}
+	var calls []string
+	hooks := func(name string) Middleware {
+		return Middleware{
+			Name: name,
+			Before: func(exchange *Exchange) error {
+				calls = append(calls, "before "+name)
+				exchange.HTTPRequest.Header.Add("X-Chain", name)
+				return nil
+			},
+			After: func(exchange *Exchange) {
+				calls = append(calls, fmt.Sprintf("after %s %d", name, exchange.Response.StatusCode))
+			},
+		}
+	}
+
+	config := DefaultConfig()
+	config.Transport = transport
+	config.Middlewares = []Middleware{hooks("metrics")}
+	client := NewSOAPClient(config)
+	client.Use(hooks("request-id"), Middleware{Name: "no-op"})
+
+	ctx := context.Background()
+	if _, err := client.Call(ctx, &SOAPRequest{URL: "https://nfe.example.gov.br/ws", Body: "<env/>"}); err != nil {
+		t.Fatalf("Call should not return error, got: %v", err)
+	}
+
+	want := []string{"before metrics", "before request-id", "after request-id 200", "after metrics 200"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("Unexpected hook order %v, want %v", calls, want)
+	}
+	if got := transport.requests[0].Header.Values("X-Chain"); fmt.Sprint(got) != "[metrics request-id]" {
+		t.Errorf("Before hooks should modify the request, got %v", got)
+	}
+	if len(client.Middlewares()) != 3 {
+		t.Errorf("Expected 3 middlewares, got %d", len(client.Middlewares()))
+	}
+}
+
+func TestSOAPClientMiddlewareErrors(t *testing.T) {
+	transport := &recordingTransport{status: http.StatusServiceUnavailable, body: "unavailable"}
+	var exchanges []*Exchange
+	config := DefaultConfig()
+	config.Transport = transport
+	config.MaxRetries = 2
+	config.RetryDelay = time.Millisecond
+	config.Middlewares = []Middleware{{
+		Name: "recorder",
+		After: func(exchange *Exchange) {
+			exchanges = append(exchanges, exchange)
+		},
+	}}
+	client := NewSOAPClient(config)
+
+	// After hooks see every attempt, including failures
+	if _, err := client.Call(context.Background(), &SOAPRequest{URL: "https://nfe.example.gov.br/ws", Body: "<env/>"}); err == nil {
+		t.Fatal("Call should return error for status 503")
+	}
+	if len(exchanges) != 3 {
+		t.Fatalf("Expected 3 recorded attempts, got %d", len(exchanges))
+	}
+	if exchanges[2].Attempt != 3 || exchanges[2].Err == nil || exchanges[2].Response.StatusCode != 503 || exchanges[2].Duration <= 0 {
+		t.Errorf("Unexpected last exchange: %+v", exchanges[2])
+	}
+
+	// A Before error aborts without retries, and entered middlewares still see it
+	signingErr := stderrors.New("certificate unavailable")
+	exchanges = nil
+	client.Use(Middleware{Name: "signer", Before: func(*Exchange) error { return signingErr }})
+	_, err := client.Call(context.Background(), &SOAPRequest{URL: "https://nfe.example.gov.br/ws", Body: "<env/>"})
+	if !stderrors.Is(err, signingErr) {
+		t.Errorf("Call should return the middleware error, got: %v", err)
+	}
+	if len(transport.requests) != 3 {
+		t.Errorf("Rejected request should not be sent, got %d requests", len(transport.requests))
+	}
+	if len(exchanges) != 1 || exchanges[0].Err == nil {
+		t.Errorf("After hooks of entered middlewares should run once, got %d", len(exchanges))
+	}
+
+	// After hooks may replace the outcome
+	transport.status = http.StatusOK
+	client = NewSOAPClient(&SOAPClientConfig{Transport: transport, Middlewares: []Middleware{{
+		After: func(exchange *Exchange) {
+			exchange.Err = signingErr
+		},
+	}}})
+	if _, err := client.Call(context.Background(), &SOAPRequest{URL: "https://nfe.example.gov.br/ws", Body: "<env/>"}); err != signingErr {
+		t.Errorf("After hook should replace the error, got: %v", err)
+	}
+}
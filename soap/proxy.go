@@ -0,0 +1,141 @@
+package soap
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/adrianodrix/sped-nfe-go/common"
+)
+
+// NoProxyFromEnvironment returns the hosts to reach without a proxy, read from
+// the NO_PROXY or no_proxy environment variable
+func NoProxyFromEnvironment() string {
+	if value := os.Getenv("NO_PROXY"); value != "" {
+		return value
+	}
+	return os.Getenv("no_proxy")
+}
+
+// ProxyFunc returns the function used by http.Transport to route requests
+// through proxy, or nil when proxy has no host. HTTPS requests are tunneled
+// with CONNECT, so the TLS handshake (and the client certificate) goes end to
+// end to the webservice. Hosts matching noProxy are reached directly.
+func ProxyFunc(proxy *common.ProxyConfig, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	proxyURL, err := proxy.URL()
+	if err != nil || proxyURL == nil {
+		return nil, err
+	}
+
+	bypass := parseNoProxy(noProxy)
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass.match(req.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// noProxyMatcher holds the entries of a NO_PROXY list
+type noProxyMatcher struct {
+	all      bool
+	networks []*net.IPNet
+	hosts    []noProxyHost
+}
+
+// noProxyHost is a domain or IP entry, optionally restricted to a port
+type noProxyHost struct {
+	name string
+	ip   net.IP
+	port string
+}
+
+// parseNoProxy parses a comma or space separated list in the format used by
+// curl and Go: "*", IP addresses, CIDR blocks and domains, with an optional
+// port. A domain, with or without a leading "." or "*.", matches itself and its
+// subdomains.
+func parseNoProxy(list string) *noProxyMatcher {
+	matcher := &noProxyMatcher{}
+	entries := strings.FieldsFunc(strings.ToLower(list), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+
+	for _, entry := range entries {
+		if entry == "*" {
+			matcher.all = true
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			matcher.networks = append(matcher.networks, network)
+			continue
+		}
+
+		host, port := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			host, port = h, p
+		}
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+
+		if ip := net.ParseIP(host); ip != nil {
+			matcher.hosts = append(matcher.hosts, noProxyHost{ip: ip, port: port})
+			continue
+		}
+
+		host = strings.TrimPrefix(strings.TrimPrefix(host, "*"), ".")
+		if host != "" {
+			matcher.hosts = append(matcher.hosts, noProxyHost{name: host, port: port})
+		}
+	}
+	return matcher
+}
+
+// match reports whether a request to target must bypass the proxy. Loopback
+// addresses are always reached directly.
+func (m *noProxyMatcher) match(target *url.URL) bool {
+	host := strings.ToLower(target.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+	if m.all {
+		return true
+	}
+
+	port := target.Port()
+	if port == "" {
+		port = "80"
+		if target.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	if ip != nil {
+		for _, network := range m.networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	for _, entry := range m.hosts {
+		if entry.port != "" && entry.port != port {
+			continue
+		}
+		if entry.ip != nil {
+			if ip != nil && entry.ip.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if host == entry.name || strings.HasSuffix(host, "."+entry.name) {
+			return true
+		}
+	}
+	return false
+}
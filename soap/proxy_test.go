@@ -0,0 +1,240 @@
+package soap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/common"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// connectProxy is an HTTP proxy accepting CONNECT with basic authentication and
+// tunneling every request to backend
+type connectProxy struct {
+	backend string
+	auth    string
+
+	mu      sync.Mutex
+	targets []string
+}
+
+func (p *connectProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("Proxy-Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte(p.auth)) {
+		w.Header().Set("Proxy-Authenticate", `Basic realm="proxy"`)
+		w.WriteHeader(http.StatusProxyAuthRequired)
+		return
+	}
+
+	p.mu.Lock()
+	p.targets = append(p.targets, r.Host)
+	p.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", p.backend)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+
+	go func() {
+		io.Copy(upstream, conn)
+		upstream.Close()
+	}()
+	io.Copy(conn, upstream)
+	conn.Close()
+}
+
+// clientCertificate generates a self-signed certificate for mutual TLS
+func clientCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "EMPRESA TESTE:12345678000195"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func TestSOAPClientProxyTunnelsMutualTLS(t *testing.T) {
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			http.Error(w, "client certificate required", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	backend.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	backend.StartTLS()
+	defer backend.Close()
+
+	proxy := &connectProxy{backend: backend.Listener.Addr().String(), auth: "usuario:s3nh@"}
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+	proxyHost, proxyPort, _ := net.SplitHostPort(proxyServer.Listener.Addr().String())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(backend.Certificate())
+
+	newClient := func(password string) *SOAPClient {
+		config := DefaultConfig()
+		config.MaxRetries = 0
+		config.TLSConfig = &tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{clientCertificate(t)},
+		}
+		config.Proxy = &common.ProxyConfig{
+			ProxyIP:   stringPtr(proxyHost),
+			ProxyPort: stringPtr(proxyPort),
+			ProxyUser: stringPtr("usuario"),
+			ProxyPass: stringPtr(password),
+		}
+		config.NoProxy = "sefaz.example.org"
+		return NewSOAPClient(config)
+	}
+
+	// example.com is covered by the httptest certificate; only the proxy can
+	// reach it, as the name is not resolved by the client
+	request := &SOAPRequest{URL: "https://example.com/ws/NFeStatusServico4.asmx", Body: "<soap:Envelope/>"}
+
+	response, err := newClient("s3nh@").Call(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Call through proxy failed: %v", err)
+	}
+	if response.Body != "EMPRESA TESTE:12345678000195" {
+		t.Errorf("Backend should receive the client certificate, got %q", response.Body)
+	}
+	if len(proxy.targets) != 1 || proxy.targets[0] != "example.com:443" {
+		t.Errorf("Expected CONNECT to example.com:443, got %v", proxy.targets)
+	}
+
+	if _, err := newClient("errada").Call(context.Background(), request); err == nil {
+		t.Error("Call with wrong proxy password should fail")
+	}
+	if len(proxy.targets) != 1 {
+		t.Errorf("Proxy should not tunnel unauthenticated requests, got %v", proxy.targets)
+	}
+}
+
+func TestSOAPClientInvalidProxy(t *testing.T) {
+	transport := &recordingTransport{status: http.StatusOK, body: "<ok/>"}
+
+	config := DefaultConfig()
+	config.Proxy = &common.ProxyConfig{ProxyIP: stringPtr("proxy_invalido"), ProxyPort: stringPtr("3128")}
+	client := NewSOAPClient(config)
+
+	_, err := client.Call(context.Background(), &SOAPRequest{URL: "https://example.com/ws", Body: "<soap:Envelope/>"})
+	if nfErr, ok := err.(*errors.NFError); !ok || nfErr.Type != errors.ErrConfig {
+		t.Fatalf("Expected config error, got %v", err)
+	}
+
+	client.SetTransport(transport)
+	if err := client.SetProxy(nil, ""); err != nil {
+		t.Fatalf("SetProxy(nil) should not fail: %v", err)
+	}
+	if _, err := client.Call(context.Background(), &SOAPRequest{URL: "https://example.com/ws", Body: "<soap:Envelope/>"}); err != nil {
+		t.Errorf("Call should succeed once the proxy is cleared, got %v", err)
+	}
+}
+
+func TestProxyFunc(t *testing.T) {
+	proxy := &common.ProxyConfig{
+		ProxyIP:   stringPtr("2001:db8::1"),
+		ProxyPort: stringPtr("3128"),
+		ProxyUser: stringPtr("usuario"),
+		ProxyPass: stringPtr("senha"),
+	}
+	noProxy := "*.intranet.local, .sefaz.rs.gov.br,10.0.0.0/8 192.168.0.10 sefaz.sp.gov.br:8443,[2001:db8::2]"
+
+	proxyFunc, err := ProxyFunc(proxy, noProxy)
+	if err != nil {
+		t.Fatalf("ProxyFunc failed: %v", err)
+	}
+
+	tests := []struct {
+		target string
+		direct bool
+	}{
+		{"https://nfe.fazenda.mg.gov.br/nfe2/services/NFeStatusServico4", false},
+		{"https://nfe.sefaz.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx", true},
+		{"https://sefaz.rs.gov.br/ws", true},
+		{"https://notsefaz.rs.gov.br/ws", false},
+		{"https://erp.intranet.local/ws", true},
+		{"https://10.1.2.3/ws", true},
+		{"https://11.1.2.3/ws", false},
+		{"https://192.168.0.10/ws", true},
+		{"https://sefaz.sp.gov.br:8443/ws", true},
+		{"https://sefaz.sp.gov.br/ws", false},
+		{"https://[2001:db8::2]/ws", true},
+		{"https://localhost:8080/ws", true},
+		{"https://127.0.0.1/ws", true},
+		{"https://[::1]/ws", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			target, _ := url.Parse(tt.target)
+			proxyURL, err := proxyFunc(&http.Request{URL: target})
+			if err != nil {
+				t.Fatalf("proxy func failed: %v", err)
+			}
+			if tt.direct && proxyURL != nil {
+				t.Errorf("Expected direct connection, got proxy %s", proxyURL)
+			}
+			if !tt.direct && (proxyURL == nil || proxyURL.String() != "http://usuario:senha@[2001:db8::1]:3128") {
+				t.Errorf("Expected proxy, got %v", proxyURL)
+			}
+		})
+	}
+
+	proxyFunc, err = ProxyFunc(proxy, "*")
+	if err != nil {
+		t.Fatalf("ProxyFunc failed: %v", err)
+	}
+	target, _ := url.Parse("https://nfe.fazenda.mg.gov.br/ws")
+	if proxyURL, _ := proxyFunc(&http.Request{URL: target}); proxyURL != nil {
+		t.Errorf("NO_PROXY=* should bypass the proxy, got %s", proxyURL)
+	}
+
+	if proxyFunc, err := ProxyFunc(nil, ""); err != nil || proxyFunc != nil {
+		t.Errorf("Nil proxy should return nil func, got %v", err)
+	}
+}
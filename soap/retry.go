@@ -0,0 +1,299 @@
+package soap
+
+import (
+	"context"
+	"crypto/tls"
+	stderrors "errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// Operation classifies SEFAZ requests by the effect of sending them twice
+type Operation string
+
+const (
+	// OperationUnknown is a request of an unknown webservice, retried as idempotent
+	OperationUnknown Operation = ""
+	// OperationStatus is NfeStatusServico
+	OperationStatus Operation = "status"
+	// OperationQuery covers consulta protocolo, recibo, cadastro and distribuição DF-e
+	OperationQuery Operation = "query"
+	// OperationAuthorization is the submission of a lote of NFe
+	OperationAuthorization Operation = "authorization"
+	// OperationEvent is the registration of events, EPEC included
+	OperationEvent Operation = "event"
+	// OperationInutilizacao is the inutilização of a range of numbers
+	OperationInutilizacao Operation = "inutilizacao"
+)
+
+// methodOperations maps the webservice methods to their operation
+var methodOperations = map[string]Operation{
+	"nfeStatusServicoNF":    OperationStatus,
+	"nfeConsultaNF":         OperationQuery,
+	"nfeRetAutorizacaoLote": OperationQuery,
+	"consultaCadastro":      OperationQuery,
+	"nfeDistDFeInteresse":   OperationQuery,
+	"nfeConsultaNFDest":     OperationQuery,
+	"nfeDownloadNF":         OperationQuery,
+	"nfeAutorizacaoLote":    OperationAuthorization,
+	"nfeRecepcaoEvento":     OperationEvent,
+	"nfeInutilizacaoNF":     OperationInutilizacao,
+}
+
+// OperationForMethod returns the operation of a webservice method, such as
+// nfeAutorizacaoLote, or OperationUnknown
+func OperationForMethod(method string) Operation {
+	return methodOperations[method]
+}
+
+// Idempotent reports whether sending the request twice has the same effect as
+// sending it once. Authorizations, events and inutilizações are registered by
+// SEFAZ: a second submission is rejected as a duplicate, hiding the result of
+// the first one.
+func (o Operation) Idempotent() bool {
+	switch o {
+	case OperationAuthorization, OperationEvent, OperationInutilizacao:
+		return false
+	}
+	return true
+}
+
+// operation returns the operation of a request, from Operation or else from
+// the method in the SOAP action
+func (r *SOAPRequest) operation() Operation {
+	if r.Operation != OperationUnknown {
+		return r.Operation
+	}
+	return OperationForMethod(servicePattern.FindString(r.Action))
+}
+
+// AttemptReport describes an attempt of a call, reported to RetryPolicy.OnAttempt
+type AttemptReport struct {
+	Operation  Operation
+	Attempt    int
+	Duration   time.Duration
+	StatusCode int
+	Err        error
+	// Retry tells whether another attempt follows, after Delay
+	Retry bool
+	Delay time.Duration
+}
+
+// RetryPolicy decides when a failed request is sent again
+type RetryPolicy struct {
+	// MaxRetries is the number of attempts after the first one
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled for each
+	// following one up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter randomizes each delay by up to this fraction (0.2 means ±20%),
+	// so that clients do not retry in lockstep
+	Jitter float64
+	// Idempotent allows resending after failures that may have reached the
+	// webservice: timeouts, dropped connections and server errors. Otherwise
+	// only requests refused before processing are resent (connection errors,
+	// HTTP 429 and 503, cStat 108 and 109), and the other failures are
+	// returned as *UncertainDeliveryError.
+	Idempotent bool
+	// OnAttempt is called after every attempt, successful or not
+	OnAttempt func(AttemptReport)
+}
+
+// DefaultRetryPolicy returns the policy of an operation: three retries with
+// exponential backoff from one second, and blind retries only for idempotent
+// operations
+func DefaultRetryPolicy(operation Operation) *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  1 * time.Second,
+		MaxDelay:   30 * time.Second,
+		Jitter:     0.2,
+		Idempotent: operation.Idempotent(),
+	}
+}
+
+// Retryable reports whether a failed attempt may be sent again under the policy
+func (p *RetryPolicy) Retryable(err error, response *SOAPResponse) bool {
+	if err == nil && (response == nil || response.StatusCode < 400) {
+		return false
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+
+	// SEFAZ return codes carry their own retry semantics; 108 and 109 mean
+	// the message was not processed
+	if sefazErr, ok := errors.AsSEFAZError(err); ok {
+		return sefazErr.Retryable()
+	}
+
+	if isPermanentError(err) {
+		return false
+	}
+
+	// SOAP faults are retried only when the server failed
+	if fault, ok := AsFaultError(err); ok {
+		return p.Idempotent && fault.Retryable()
+	}
+
+	if response != nil {
+		switch response.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		case http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+			return p.Idempotent
+		default:
+			return false
+		}
+	}
+
+	if notDelivered(err) {
+		return true
+	}
+	return p.Idempotent
+}
+
+// next decides whether the attempt is followed by another one, and after
+// which delay. A Retry-After longer than MaxDelay ends the call.
+func (p *RetryPolicy) next(attempt int, err error, response *SOAPResponse) (bool, time.Duration) {
+	if attempt > p.MaxRetries || !p.Retryable(err, response) {
+		return false, 0
+	}
+
+	if wait, ok := retryAfter(response); ok {
+		if p.MaxDelay > 0 && wait > p.MaxDelay {
+			return false, 0
+		}
+		return true, wait
+	}
+	return true, p.backoff(attempt)
+}
+
+// backoff returns the jittered delay before retry number n
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(n-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
+
+// report calls OnAttempt, if set
+func (p *RetryPolicy) report(report AttemptReport) {
+	if p.OnAttempt != nil {
+		p.OnAttempt(report)
+	}
+}
+
+// retryAfter reads the Retry-After header of a response, in seconds or as an HTTP date
+func retryAfter(response *SOAPResponse) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(http.Header(response.Headers).Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isPermanentError reports errors that fail the same way when resent:
+// invalid requests, configuration and certificate errors
+func isPermanentError(err error) bool {
+	var nfErr *errors.NFError
+	if stderrors.As(err, &nfErr) && nfErr.Type != nil {
+		switch nfErr.Type.Code {
+		case errors.ErrValidation.Code, errors.ErrConfig.Code, errors.ErrCertificate.Code:
+			return true
+		}
+	}
+
+	var certErr *tls.CertificateVerificationError
+	return stderrors.As(err, &certErr)
+}
+
+// notDelivered reports transport errors raised before the request left the
+// client: DNS resolution, connection to the server or to the proxy
+func notDelivered(err error) bool {
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return stderrors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "proxyconnect")
+}
+
+// possiblyProcessed reports whether a failed request may nevertheless have
+// been processed by the webservice
+func possiblyProcessed(err error, response *SOAPResponse) bool {
+	if _, ok := errors.AsSEFAZError(err); ok {
+		return false
+	}
+	if isPermanentError(err) || notDelivered(err) {
+		return false
+	}
+	if fault, ok := AsFaultError(err); ok {
+		return !fault.IsSchemaRejection()
+	}
+	if response != nil {
+		switch response.StatusCode {
+		case http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// UncertainDeliveryError is returned for a failed request that is not
+// idempotent and may nevertheless have been processed, such as a lote whose
+// response was lost. It must not be resent blindly: query the result first
+// (consulta protocolo by chave, or the recibo).
+type UncertainDeliveryError struct {
+	Operation Operation
+	Err       error
+}
+
+// Error implements the error interface
+func (e *UncertainDeliveryError) Error() string {
+	return fmt.Sprintf("[%s] %s may have been processed, query its result before resending: %v",
+		errors.ErrNetwork.Code, e.Operation, e.Err)
+}
+
+// Unwrap returns the error of the last attempt
+func (e *UncertainDeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// IsUncertainDelivery reports whether err, or an error it wraps, is an *UncertainDeliveryError
+func IsUncertainDelivery(err error) bool {
+	var uncertain *UncertainDeliveryError
+	return stderrors.As(err, &uncertain)
+}
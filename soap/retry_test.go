@@ -0,0 +1,213 @@
+package soap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+func TestOperationForMethod(t *testing.T) {
+	tests := []struct {
+		method     string
+		operation  Operation
+		idempotent bool
+	}{
+		{"nfeStatusServicoNF", OperationStatus, true},
+		{"nfeConsultaNF", OperationQuery, true},
+		{"nfeRetAutorizacaoLote", OperationQuery, true},
+		{"nfeDistDFeInteresse", OperationQuery, true},
+		{"nfeAutorizacaoLote", OperationAuthorization, false},
+		{"nfeRecepcaoEvento", OperationEvent, false},
+		{"nfeInutilizacaoNF", OperationInutilizacao, false},
+		{"testAction", OperationUnknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			operation := OperationForMethod(tt.method)
+			if operation != tt.operation {
+				t.Errorf("Expected operation %q, got %q", tt.operation, operation)
+			}
+			if operation.Idempotent() != tt.idempotent {
+				t.Errorf("Expected idempotent %t", tt.idempotent)
+			}
+		})
+	}
+
+	request := &SOAPRequest{Action: "http://www.portalfiscal.inf.br/nfe/wsdl/NFeAutorizacao4/nfeAutorizacaoLote"}
+	if request.operation() != OperationAuthorization {
+		t.Errorf("Operation should be derived from the action, got %q", request.operation())
+	}
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	dialErr := errors.NewNetworkError("HTTP request failed", &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")})
+	resetErr := errors.NewNetworkError("HTTP request failed", &net.OpError{Op: "read", Net: "tcp", Err: fmt.Errorf("connection reset by peer")})
+	httpErr := func(status int) error {
+		return errors.NewNetworkError(fmt.Sprintf("HTTP error: %d", status), fmt.Errorf("status_%d", status))
+	}
+
+	tests := []struct {
+		name          string
+		err           error
+		response      *SOAPResponse
+		idempotent    bool
+		nonIdempotent bool
+	}{
+		{"success", nil, &SOAPResponse{StatusCode: 200}, false, false},
+		{"HTTP 400", httpErr(400), &SOAPResponse{StatusCode: 400}, false, false},
+		{"HTTP 403", httpErr(403), &SOAPResponse{StatusCode: 403}, false, false},
+		{"HTTP 429", httpErr(429), &SOAPResponse{StatusCode: 429}, true, true},
+		{"HTTP 503", httpErr(503), &SOAPResponse{StatusCode: 503}, true, true},
+		{"HTTP 500", httpErr(500), &SOAPResponse{StatusCode: 500}, true, false},
+		{"HTTP 504", httpErr(504), &SOAPResponse{StatusCode: 504}, true, false},
+		{"connection refused", dialErr, nil, true, true},
+		{"connection reset", resetErr, nil, true, false},
+		{"server fault", NewFaultError(&SOAPFault{Code: FaultCodeReceiver}), &SOAPResponse{StatusCode: 500}, true, false},
+		{"schema rejection", NewFaultError(&SOAPFault{Code: FaultCodeSender}), &SOAPResponse{StatusCode: 500}, false, false},
+		{"service paralyzed", errors.NewSEFAZStatusError(108, ""), nil, true, true},
+		{"duplicate", errors.NewSEFAZStatusError(204, ""), nil, false, false},
+		{"validation", errors.NewValidationError("invalid", "body", ""), nil, false, false},
+		{"configuration", errors.NewConfigError("invalid proxy", "proxyIp", ""), nil, false, false},
+	}
+
+	idempotent := DefaultRetryPolicy(OperationQuery)
+	nonIdempotent := DefaultRetryPolicy(OperationAuthorization)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idempotent.Retryable(tt.err, tt.response); got != tt.idempotent {
+				t.Errorf("Idempotent policy: expected %t, got %t", tt.idempotent, got)
+			}
+			if got := nonIdempotent.Retryable(tt.err, tt.response); got != tt.nonIdempotent {
+				t.Errorf("Non-idempotent policy: expected %t, got %t", tt.nonIdempotent, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, Jitter: 0.2}
+
+	for n, base := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 5: 300 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(n)
+			if delay < base*8/10 || delay > base*12/10 {
+				t.Fatalf("Retry %d: delay %v outside %v ±20%%", n, delay, base)
+			}
+		}
+	}
+
+	busy := &SOAPResponse{StatusCode: 503, Headers: http.Header{"Retry-After": {"0"}}}
+	if retry, delay := policy.next(1, fmt.Errorf("busy"), busy); !retry || delay != 0 {
+		t.Errorf("Retry-After 0 should retry immediately, got %t %v", retry, delay)
+	}
+
+	busy.Headers = http.Header{"Retry-After": {"120"}}
+	if retry, _ := policy.next(1, fmt.Errorf("busy"), busy); retry {
+		t.Error("Retry-After beyond MaxDelay should not be retried")
+	}
+
+	busy.Headers = http.Header{"Retry-After": {time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)}}
+	if retry, delay := policy.next(1, fmt.Errorf("busy"), busy); !retry || delay != 0 {
+		t.Errorf("Retry-After in the past should retry immediately, got %t %v", retry, delay)
+	}
+
+	if retry, _ := policy.next(6, fmt.Errorf("busy"), busy); retry {
+		t.Error("Attempts beyond MaxRetries should not be retried")
+	}
+}
+
+func TestSOAPClientRetryPolicyByOperation(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		status    int
+		header    string
+		attempts  int
+		uncertain bool
+	}{
+		{"status is retried on server error", "nfeStatusServicoNF", 500, "", 3, false},
+		{"authorization is not resent on server error", "nfeAutorizacaoLote", 500, "", 1, true},
+		{"authorization is resent when the server is unavailable", "nfeAutorizacaoLote", 503, "0", 3, false},
+		{"event is not resent on gateway timeout", "nfeRecepcaoEvento", 504, "", 1, true},
+		{"client errors are never retried", "nfeStatusServicoNF", 404, "", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if tt.header != "" {
+					w.Header().Set("Retry-After", tt.header)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			var reports []AttemptReport
+			policy := DefaultRetryPolicy(OperationForMethod(tt.method))
+			policy.MaxRetries = 2
+			policy.BaseDelay = time.Millisecond
+			policy.OnAttempt = func(report AttemptReport) { reports = append(reports, report) }
+
+			config := DefaultConfig()
+			config.RetryPolicies = map[Operation]*RetryPolicy{OperationForMethod(tt.method): policy}
+			client := NewSOAPClient(config)
+
+			_, err := client.Call(context.Background(), &SOAPRequest{
+				URL:    server.URL,
+				Action: "http://www.portalfiscal.inf.br/nfe/wsdl/Servico4/" + tt.method,
+				Body:   "<env/>",
+			})
+			if err == nil {
+				t.Fatal("Call should fail")
+			}
+			if attempts != tt.attempts || len(reports) != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d sent and %d reported", tt.attempts, attempts, len(reports))
+			}
+			if IsUncertainDelivery(err) != tt.uncertain {
+				t.Errorf("Expected uncertain delivery %t, got: %v", tt.uncertain, err)
+			}
+
+			last := reports[len(reports)-1]
+			if last.Retry || last.StatusCode != tt.status || last.Err == nil || last.Attempt != tt.attempts {
+				t.Errorf("Unexpected last report %+v", last)
+			}
+			for _, report := range reports[:len(reports)-1] {
+				if !report.Retry {
+					t.Errorf("Report %d should announce a retry", report.Attempt)
+				}
+			}
+		})
+	}
+}
+
+func TestSOAPClientDefaultRetryPolicy(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxRetries = 5
+	config.RetryDelay = 2 * time.Second
+	client := NewSOAPClient(config)
+
+	policy := client.GetRetryPolicy(OperationAuthorization)
+	if policy.MaxRetries != 5 || policy.BaseDelay != 2*time.Second || policy.Idempotent {
+		t.Errorf("Unexpected default policy %+v", policy)
+	}
+
+	custom := &RetryPolicy{MaxRetries: 1}
+	client.SetRetryPolicy(OperationAuthorization, custom)
+	if client.GetRetryPolicy(OperationAuthorization) != custom {
+		t.Error("GetRetryPolicy should return the policy set")
+	}
+	client.SetRetryPolicy(OperationAuthorization, nil)
+	if client.GetRetryPolicy(OperationAuthorization) == custom {
+		t.Error("SetRetryPolicy(nil) should restore the default policy")
+	}
+}
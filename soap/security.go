@@ -480,7 +480,9 @@ func CreateWSSecurityConfig(certPEM, keyPEM []byte) (*WSSecurityConfig, error) {
 	}, nil
 }
 
-// VerifySignature verifies a digital signature (simplified implementation)
+// VerifySignature verifies the signature value of a Signature over its
+// canonicalized SignedInfo with the certificate public key. The digests of the
+// references need the signed document, see VerifyXMLSignatures.
 func VerifySignature(signature *Signature, cert *x509.Certificate) error {
 	if signature == nil {
 		return errors.NewValidationError("signature cannot be nil", "signature", "")
@@ -490,13 +492,6 @@ func VerifySignature(signature *Signature, cert *x509.Certificate) error {
 		return errors.NewValidationError("certificate cannot be nil", "certificate", "")
 	}
 
-	// In a real implementation, you would:
-	// 1. Canonicalize the SignedInfo
-	// 2. Hash the canonicalized SignedInfo
-	// 3. Verify the signature using the certificate's public key
-	// 4. Verify all digest values in references
-
-	// For this simplified implementation, we just validate the structure
 	if signature.SignatureValue.Value == "" {
 		return errors.NewValidationError("signature value cannot be empty", "signatureValue", "")
 	}
@@ -505,7 +500,7 @@ func VerifySignature(signature *Signature, cert *x509.Certificate) error {
 		return errors.NewValidationError("signature must have at least one reference", "references", "")
 	}
 
-	return nil
+	return verifyMarshaledSignature(signature, cert)
 }
 
 // GetCertificateFingerprint returns the SHA-256 fingerprint of a certificate
@@ -0,0 +1,474 @@
+package soap
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+)
+
+// Signature and digest algorithms accepted in received documents
+const (
+	RSAWithSHA1Algorithm = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	SHA1DigestAlgorithm  = "http://www.w3.org/2000/09/xmldsig#sha1"
+)
+
+// ICP-Brasil identifiers of the subject alternative name
+var (
+	oidSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+	oidICPBrasilCNPJ  = asn1.ObjectIdentifier{2, 16, 76, 1, 3, 3}
+)
+
+// VerifyOptions configures the verification of signed documents
+type VerifyOptions struct {
+	// Roots holds the trusted roots, such as the ICP-Brasil chain; the
+	// certificate chain is not verified when nil
+	Roots         *x509.CertPool
+	Intermediates *x509.CertPool
+	// CurrentTime is the time the chain is verified at; zero means now
+	CurrentTime time.Time
+	// CNPJ is the expected issuer of the documents; when empty the CNPJ of the
+	// signed element (emit or the author of an event) is used
+	CNPJ string
+}
+
+// SignatureVerification is the result of a verified signature
+type SignatureVerification struct {
+	// Reference is the Id of the signed element and Element its name
+	// (infNFe, infEvento, infProt, ...)
+	Reference   string
+	Element     string
+	Certificate *x509.Certificate
+	// CertificateCNPJ is taken from the ICP-Brasil subject alternative name or
+	// the common name; IssuerCNPJ is the CNPJ expected to have signed
+	CertificateCNPJ string
+	IssuerCNPJ      string
+	// CNPJMatches reports whether the certificate belongs to the issuer, which
+	// SEFAZ checks by the 8 digit root of the CNPJ. It is false when either
+	// CNPJ is unknown, as in the protocols signed by SEFAZ.
+	CNPJMatches   bool
+	ChainVerified bool
+}
+
+// VerifyXMLSignatures verifies every XMLDSig signature of a received
+// document (NFe, nfeProc, events and SEFAZ protocols): the digest of each
+// reference is recomputed over the canonicalized content and the signature
+// value is checked with the certificate of KeyInfo. A document without
+// signatures is an error.
+func VerifyXMLSignatures(data []byte, opts *VerifyOptions) ([]SignatureVerification, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.NewValidationError("signed XML cannot be empty", "xml", "")
+	}
+	if opts == nil {
+		opts = &VerifyOptions{}
+	}
+
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUniqueIDs(root); err != nil {
+		return nil, err
+	}
+
+	var signatures []*xmlNode
+	root.walk(func(n *xmlNode) {
+		if n.name.Local == "Signature" && n.namespace(n.name.Space) == XMLDigitalSignatureNS {
+			signatures = append(signatures, n)
+		}
+	})
+	if len(signatures) == 0 {
+		return nil, errors.NewValidationError("document is not signed", "Signature", "")
+	}
+
+	results := make([]SignatureVerification, 0, len(signatures))
+	for _, signature := range signatures {
+		result, err := verifySignatureNode(root, signature, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// verifySignatureNode verifies one Signature element of the document
+func verifySignatureNode(root, signature *xmlNode, opts *VerifyOptions) (SignatureVerification, error) {
+	var result SignatureVerification
+
+	signedInfo := signature.child(XMLDigitalSignatureNS, "SignedInfo")
+	if signedInfo == nil {
+		return result, errors.NewValidationError("signature without SignedInfo", "SignedInfo", "")
+	}
+	references := signedInfo.childrenNamed(XMLDigitalSignatureNS, "Reference")
+	if len(references) == 0 {
+		return result, errors.NewValidationError("signature must have at least one reference", "Reference", "")
+	}
+
+	for i, reference := range references {
+		signed, err := verifyReference(root, signature, reference)
+		if err != nil {
+			return result, err
+		}
+		if i == 0 {
+			result.Reference, _ = signed.attr("Id")
+			if result.Reference == "" {
+				result.Reference, _ = signed.attr("ID")
+			}
+			result.Element = signed.name.Local
+			result.IssuerCNPJ = issuerCNPJ(signed)
+		}
+	}
+
+	cert, chain, err := keyInfoCertificates(signature)
+	if err != nil {
+		return result, err
+	}
+	if err := verifySignedInfo(signedInfo, signature.child(XMLDigitalSignatureNS, "SignatureValue"), cert); err != nil {
+		return result, err
+	}
+	result.Certificate = cert
+
+	if opts.Roots != nil {
+		// the embedded chain goes into a copy, leaving the caller's pool
+		// untouched and safe to share between concurrent verifications
+		intermediates := x509.NewCertPool()
+		if opts.Intermediates != nil {
+			intermediates = opts.Intermediates.Clone()
+		}
+		for _, c := range chain {
+			intermediates.AddCert(c)
+		}
+		if _, err := cert.Verify(x509.VerifyOptions{
+			Roots:         opts.Roots,
+			Intermediates: intermediates,
+			CurrentTime:   opts.CurrentTime,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}); err != nil {
+			return result, errors.NewCertificateError("certificate chain validation failed", err)
+		}
+		result.ChainVerified = true
+	}
+
+	if opts.CNPJ != "" {
+		result.IssuerCNPJ = opts.CNPJ
+	}
+	result.IssuerCNPJ = strings.ToUpper(cleanCNPJ(result.IssuerCNPJ))
+	result.CertificateCNPJ = CertificateCNPJ(cert)
+	result.CNPJMatches = len(result.IssuerCNPJ) == 14 && len(result.CertificateCNPJ) == 14 &&
+		result.IssuerCNPJ[:8] == result.CertificateCNPJ[:8]
+	return result, nil
+}
+
+// verifyReference recomputes the digest of a reference, returning the signed element
+func verifyReference(root, signature, reference *xmlNode) (*xmlNode, error) {
+	uri, _ := reference.attr("URI")
+	signed := root
+	if uri != "" {
+		if !strings.HasPrefix(uri, "#") {
+			return nil, errors.NewValidationError("only same-document references are supported", "URI", uri)
+		}
+		var err error
+		if signed, err = signedSibling(signature, uri[1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &canonicalizer{}
+	if transforms := reference.child(XMLDigitalSignatureNS, "Transforms"); transforms != nil {
+		for _, transform := range transforms.childrenNamed(XMLDigitalSignatureNS, "Transform") {
+			algorithm, _ := transform.attr("Algorithm")
+			if algorithm == EnvelopedSignatureTransform {
+				c.exclude = signature
+				continue
+			}
+			method, err := newCanonicalizer(algorithm)
+			if err != nil {
+				return nil, err
+			}
+			c.exclusive, c.comments = method.exclusive, method.comments
+		}
+	}
+
+	digestMethod := reference.child(XMLDigitalSignatureNS, "DigestMethod")
+	if digestMethod == nil {
+		return nil, errors.NewValidationError("reference without DigestMethod", "URI", uri)
+	}
+	algorithm, _ := digestMethod.attr("Algorithm")
+	var digest []byte
+	switch algorithm {
+	case SHA1DigestAlgorithm:
+		sum := sha1.Sum(c.canonicalize(signed))
+		digest = sum[:]
+	case SHA256DigestAlgorithm:
+		sum := sha256.Sum256(c.canonicalize(signed))
+		digest = sum[:]
+	default:
+		return nil, errors.NewValidationError("unsupported digest algorithm", "DigestMethod", algorithm)
+	}
+
+	var expected string
+	if value := reference.child(XMLDigitalSignatureNS, "DigestValue"); value != nil {
+		expected = value.text()
+	}
+	decoded, err := decodeBase64(expected)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid DigestValue", "DigestValue", expected)
+	}
+	if !bytes.Equal(decoded, digest) {
+		return nil, errors.NewValidationError("digest does not match the signed content", "URI", uri)
+	}
+	return signed, nil
+}
+
+// verifySignedInfo checks the signature value over the canonicalized SignedInfo
+func verifySignedInfo(signedInfo, signatureValue *xmlNode, cert *x509.Certificate) error {
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.NewCertificateError("signature certificate must have an RSA key", nil)
+	}
+
+	var algorithm string
+	if method := signedInfo.child(XMLDigitalSignatureNS, "SignatureMethod"); method != nil {
+		algorithm, _ = method.attr("Algorithm")
+	}
+	var hash crypto.Hash
+	switch algorithm {
+	case RSAWithSHA1Algorithm:
+		hash = crypto.SHA1
+	case RSAWithSHA256Algorithm:
+		hash = crypto.SHA256
+	default:
+		return errors.NewValidationError("unsupported signature algorithm", "SignatureMethod", algorithm)
+	}
+
+	var canonicalization string
+	if method := signedInfo.child(XMLDigitalSignatureNS, "CanonicalizationMethod"); method != nil {
+		canonicalization, _ = method.attr("Algorithm")
+	}
+	c, err := newCanonicalizer(canonicalization)
+	if err != nil {
+		return err
+	}
+
+	if signatureValue == nil {
+		return errors.NewValidationError("signature value cannot be empty", "signatureValue", "")
+	}
+	value, err := decodeBase64(signatureValue.text())
+	if err != nil || len(value) == 0 {
+		return errors.NewValidationError("invalid signature value", "signatureValue", signatureValue.text())
+	}
+
+	h := hash.New()
+	h.Write(c.canonicalize(signedInfo))
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, h.Sum(nil), value); err != nil {
+		return errors.NewCertificateError("signature value does not match SignedInfo", err)
+	}
+	return nil
+}
+
+// keyInfoCertificates returns the signer certificate of KeyInfo/X509Data and
+// the other certificates sent with it
+func keyInfoCertificates(signature *xmlNode) (*x509.Certificate, []*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	if keyInfo := signature.child(XMLDigitalSignatureNS, "KeyInfo"); keyInfo != nil {
+		for _, data := range keyInfo.childrenNamed(XMLDigitalSignatureNS, "X509Data") {
+			for _, node := range data.childrenNamed(XMLDigitalSignatureNS, "X509Certificate") {
+				der, err := decodeBase64(node.text())
+				if err != nil {
+					return nil, nil, errors.NewCertificateError("invalid X509Certificate encoding", err)
+				}
+				cert, err := x509.ParseCertificate(der)
+				if err != nil {
+					return nil, nil, errors.NewCertificateError("failed to parse X509Certificate", err)
+				}
+				certs = append(certs, cert)
+			}
+		}
+	}
+	if len(certs) == 0 {
+		return nil, nil, errors.NewValidationError("signature without X509Certificate", "KeyInfo", "")
+	}
+	return certs[0], certs[1:], nil
+}
+
+// idAttributes are the attribute names used as element identifiers
+var idAttributes = []string{"Id", "ID", "id"}
+
+// elementID returns the identifier of an element, if any
+func elementID(n *xmlNode) (string, bool) {
+	for _, name := range idAttributes {
+		if value, ok := n.attr(name); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// checkUniqueIDs rejects documents in which two elements share an identifier,
+// which would let a forged element shadow the signed one
+func checkUniqueIDs(root *xmlNode) error {
+	seen := make(map[string]bool)
+	var duplicate string
+	root.walk(func(n *xmlNode) {
+		if id, ok := elementID(n); ok && duplicate == "" {
+			if seen[id] {
+				duplicate = id
+			}
+			seen[id] = true
+		}
+	})
+	if duplicate != "" {
+		return errors.NewValidationError("duplicate element identifier", "Id", duplicate)
+	}
+	return nil
+}
+
+// signedSibling returns the element referenced by id, which must be a sibling
+// of the signature (infNFe, infEvento, infProt): that is the element read by
+// consumers of the document. A second sibling with the same name is rejected
+// for the same reason.
+func signedSibling(signature *xmlNode, id string) (*xmlNode, error) {
+	if signature.parent == nil {
+		return nil, errors.NewValidationError("signed element not found", "URI", "#"+id)
+	}
+
+	var signed *xmlNode
+	for _, c := range signature.parent.children {
+		if node, ok := c.(*xmlNode); ok && node != signature {
+			if value, ok := elementID(node); ok && value == id {
+				signed = node
+				break
+			}
+		}
+	}
+	if signed == nil {
+		return nil, errors.NewValidationError("signed element not found", "URI", "#"+id)
+	}
+
+	if len(signature.parent.childrenNamed(signed.namespace(signed.name.Space), signed.name.Local)) > 1 {
+		return nil, errors.NewValidationError("signed element is not unique", signed.name.Local, id)
+	}
+	return signed, nil
+}
+
+// issuerCNPJ returns the CNPJ of the issuer of a signed element: emit/CNPJ
+// of an NFe or the CNPJ of the author of an event
+func issuerCNPJ(signed *xmlNode) string {
+	if emit := signed.child("", "emit"); emit != nil {
+		signed = emit
+	}
+	if cnpj := signed.child("", "CNPJ"); cnpj != nil {
+		return strings.TrimSpace(cnpj.text())
+	}
+	return ""
+}
+
+// CertificateCNPJ returns the CNPJ of an ICP-Brasil e-CNPJ certificate, taken
+// from the subject alternative name (OID 2.16.76.1.3.3) or from the common
+// name (RAZAO SOCIAL:CNPJ). It returns an empty string when there is none.
+func CertificateCNPJ(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidSubjectAltName) {
+			continue
+		}
+		if cnpj := otherNameValue(ext.Value, oidICPBrasilCNPJ); len(cnpj) == 14 {
+			return strings.ToUpper(cnpj)
+		}
+	}
+
+	if i := strings.LastIndex(cert.Subject.CommonName, ":"); i >= 0 {
+		if cnpj := cleanCNPJ(cert.Subject.CommonName[i+1:]); len(cnpj) == 14 {
+			return strings.ToUpper(cnpj)
+		}
+	}
+	return ""
+}
+
+// otherNameValue returns the value of the otherName with the given type in a
+// GeneralNames sequence
+func otherNameValue(der []byte, oid asn1.ObjectIdentifier) string {
+	var names asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &names); err != nil {
+		return ""
+	}
+	rest := names.Bytes
+	for len(rest) > 0 {
+		var name asn1.RawValue
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &name); err != nil {
+			return ""
+		}
+		if name.Class != asn1.ClassContextSpecific || name.Tag != 0 {
+			continue
+		}
+
+		var typeID asn1.ObjectIdentifier
+		value, err := asn1.Unmarshal(name.Bytes, &typeID)
+		if err != nil || !typeID.Equal(oid) {
+			continue
+		}
+		var explicit, inner asn1.RawValue
+		if _, err := asn1.Unmarshal(value, &explicit); err != nil {
+			continue
+		}
+		if _, err := asn1.Unmarshal(explicit.Bytes, &inner); err != nil {
+			continue
+		}
+		return strings.TrimSpace(string(inner.Bytes))
+	}
+	return ""
+}
+
+// cleanCNPJ removes the formatting of a CNPJ
+func cleanCNPJ(cnpj string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '/', '-', ' ':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(cnpj))
+}
+
+// decodeBase64 decodes base64 text that may be broken in lines
+func decodeBase64(text string) ([]byte, error) {
+	text = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r':
+			return -1
+		}
+		return r
+	}, text)
+	return base64.StdEncoding.DecodeString(text)
+}
+
+// verifyMarshaledSignature verifies the signature value of a Signature built
+// by this package, whose SignedInfo is canonicalized as marshaled
+func verifyMarshaledSignature(signature *Signature, cert *x509.Certificate) error {
+	data, err := xml.Marshal(signature)
+	if err != nil {
+		return errors.NewXMLError("failed to marshal signature", "Signature", err)
+	}
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return err
+	}
+	signedInfo := root.child(XMLDigitalSignatureNS, "SignedInfo")
+	if signedInfo == nil {
+		return errors.NewValidationError("signature without SignedInfo", "SignedInfo", "")
+	}
+	return verifySignedInfo(signedInfo, root.child(XMLDigitalSignatureNS, "SignatureValue"), cert)
+}
@@ -0,0 +1,293 @@
+package soap
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readSignedFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func testRoots(t *testing.T) *x509.CertPool {
+	t.Helper()
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(readSignedFixture(t, "ac_raiz_teste.pem")) {
+		t.Fatal("failed to load test root")
+	}
+	return roots
+}
+
+func TestVerifyXMLSignaturesNFeProc(t *testing.T) {
+	results, err := VerifyXMLSignatures(readSignedFixture(t, "nfeProc_signed.xml"), nil)
+	if err != nil {
+		t.Fatalf("VerifyXMLSignatures failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 signatures, got %d", len(results))
+	}
+
+	nfe := results[0]
+	if nfe.Element != "infNFe" || nfe.Reference != "NFe35240511222333000181550010000012341123456789" {
+		t.Errorf("unexpected signed element %s %s", nfe.Element, nfe.Reference)
+	}
+	if nfe.CertificateCNPJ != "11222333000181" || nfe.IssuerCNPJ != "11222333000181" || !nfe.CNPJMatches {
+		t.Errorf("expected matching CNPJ, got certificate %q issuer %q", nfe.CertificateCNPJ, nfe.IssuerCNPJ)
+	}
+	if nfe.ChainVerified {
+		t.Error("chain should not be verified without roots")
+	}
+
+	prot := results[1]
+	if prot.Element != "infProt" || prot.Reference != "ID135240000012345" {
+		t.Errorf("unexpected signed element %s %s", prot.Element, prot.Reference)
+	}
+	if prot.CertificateCNPJ != "46377222000129" || prot.CNPJMatches {
+		t.Errorf("protocol is signed by SEFAZ, got certificate %q matches %t", prot.CertificateCNPJ, prot.CNPJMatches)
+	}
+}
+
+func TestVerifyXMLSignaturesEventSHA256(t *testing.T) {
+	results, err := VerifyXMLSignatures(readSignedFixture(t, "evento_signed.xml"), nil)
+	if err != nil {
+		t.Fatalf("VerifyXMLSignatures failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Element != "infEvento" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if !results[0].CNPJMatches {
+		t.Errorf("event author should match the certificate, got %q", results[0].IssuerCNPJ)
+	}
+}
+
+func TestVerifyXMLSignaturesChain(t *testing.T) {
+	data := readSignedFixture(t, "nfeProc_signed.xml")
+
+	results, err := VerifyXMLSignatures(data, &VerifyOptions{Roots: testRoots(t)})
+	if err != nil {
+		t.Fatalf("VerifyXMLSignatures failed: %v", err)
+	}
+	for _, result := range results {
+		if !result.ChainVerified {
+			t.Errorf("chain of %s should be verified", result.Element)
+		}
+	}
+
+	if _, err := VerifyXMLSignatures(data, &VerifyOptions{Roots: x509.NewCertPool()}); err == nil {
+		t.Error("expected chain error with unrelated roots")
+	}
+
+	// the certificates embedded in the document must not leak into the
+	// caller's pool, which may be shared between goroutines
+	block, _ := pem.Decode(readSignedFixture(t, "ac_raiz_teste.pem"))
+	withChain := strings.Replace(string(data), "</X509Certificate></X509Data>",
+		"</X509Certificate><X509Certificate>"+base64.StdEncoding.EncodeToString(block.Bytes)+"</X509Certificate></X509Data>", 1)
+	intermediates := x509.NewCertPool()
+	if _, err := VerifyXMLSignatures([]byte(withChain), &VerifyOptions{Roots: testRoots(t), Intermediates: intermediates}); err != nil {
+		t.Fatalf("VerifyXMLSignatures failed: %v", err)
+	}
+	if !intermediates.Equal(x509.NewCertPool()) {
+		t.Error("VerifyXMLSignatures should not add certificates to the intermediates pool")
+	}
+}
+
+func TestVerifyXMLSignaturesExpectedCNPJ(t *testing.T) {
+	data := readSignedFixture(t, "evento_signed.xml")
+
+	tests := []struct {
+		cnpj    string
+		matches bool
+	}{
+		{"11.222.333/0001-81", true},
+		{"11222333000262", true}, // same root, another branch
+		{"99888777000166", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cnpj, func(t *testing.T) {
+			results, err := VerifyXMLSignatures(data, &VerifyOptions{CNPJ: tt.cnpj})
+			if err != nil {
+				t.Fatalf("VerifyXMLSignatures failed: %v", err)
+			}
+			if results[0].CNPJMatches != tt.matches {
+				t.Errorf("expected CNPJMatches %t, got %t", tt.matches, results[0].CNPJMatches)
+			}
+		})
+	}
+}
+
+func TestVerifyXMLSignaturesTampered(t *testing.T) {
+	data := string(readSignedFixture(t, "nfeProc_signed.xml"))
+
+	tests := []struct {
+		name string
+		xml  string
+		want string
+	}{
+		{"content", strings.Replace(data, "<vNF>100.00</vNF>", "<vNF>10.00</vNF>", 1), "digest"},
+		{"attribute", strings.Replace(data, `<det nItem="1">`, `<det nItem="2">`, 1), "digest"},
+		{"protocol", strings.Replace(data, "<cStat>100</cStat>", "<cStat>110</cStat>", 1), "digest"},
+		{"signed info", strings.Replace(data, "#rsa-sha1", "#rsa-sha1 ", 1), "signature algorithm"},
+		{"signature value", strings.Replace(data, "<SignatureValue>", "<SignatureValue>AAAA", 1), "SignedInfo"},
+		{"unsigned", strings.Replace(data, "Signature", "Assinatura", -1), "not signed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyXMLSignatures([]byte(tt.xml), nil)
+			if err == nil {
+				t.Fatal("expected verification error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error about %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestVerifyXMLSignaturesWrapping(t *testing.T) {
+	data := string(readSignedFixture(t, "nfeProc_signed.xml"))
+	start := strings.Index(data, "<infNFe ")
+	end := strings.Index(data, "</infNFe>") + len("</infNFe>")
+	signed := data[start:end]
+	forged := strings.Replace(signed, "Venda de mercadoria", "FORGED", 1)
+
+	tests := []struct {
+		name string
+		xml  string
+		want string
+	}{
+		{"duplicate Id before the signed element", data[:start] + forged + data[start:], "duplicate"},
+		{"duplicate Id outside the signed document", strings.Replace(data, "</nfeProc>", forged+"</nfeProc>", 1), "duplicate"},
+		{
+			"signed element moved away from the signature",
+			strings.Replace(data[:start]+strings.Replace(forged, `Id="NFe`, `Id="NFx`, 1)+data[end:],
+				"</nfeProc>", "<Object>"+signed+"</Object></nfeProc>", 1),
+			"not found",
+		},
+		{
+			"second element next to the signed one",
+			data[:start] + strings.Replace(forged, `Id="NFe`, `Id="NFx`, 1) + data[start:],
+			"not unique",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyXMLSignatures([]byte(tt.xml), nil)
+			if err == nil {
+				t.Fatal("expected verification error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error about %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestVerifyXMLSignaturesIgnoresOutsideFormatting(t *testing.T) {
+	data := string(readSignedFixture(t, "nfeProc_signed.xml"))
+	// whitespace outside the signed elements and the XML declaration are not signed
+	data = strings.Replace(data, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n", "", 1)
+	data = strings.Replace(data, "\n  <protNFe", "\n\n\t<protNFe", 1)
+
+	if _, err := VerifyXMLSignatures([]byte(data), nil); err != nil {
+		t.Errorf("VerifyXMLSignatures failed: %v", err)
+	}
+}
+
+func TestCertificateCNPJ(t *testing.T) {
+	tests := []struct {
+		name string
+		cn   string
+		want string
+	}{
+		{"common name", "EMPRESA LTDA:11222333000181", "11222333000181"},
+		{"no CNPJ", "FULANO DE TAL", ""},
+		{"CPF", "FULANO DE TAL:12345678909", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: tt.cn}}
+			if got := CertificateCNPJ(cert); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if got := CertificateCNPJ(nil); got != "" {
+		t.Errorf("expected empty CNPJ for nil, got %q", got)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "EMPRESA LTDA:11222333000181"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	signature := &Signature{
+		XmlnsDs: XMLDigitalSignatureNS,
+		SignedInfo: SignedInfo{
+			CanonicalizationMethod: CanonicalizationMethod{Algorithm: C14NAlgorithm},
+			SignatureMethod:        SignatureMethod{Algorithm: RSAWithSHA256Algorithm},
+			Reference: []SignatureReference{{
+				URI:          "#Timestamp-1",
+				DigestMethod: DigestMethod{Algorithm: SHA256DigestAlgorithm},
+				DigestValue:  "ZGlnZXN0",
+			}},
+		},
+	}
+	canonical := `<ds:SignedInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` +
+		`<ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:CanonicalizationMethod>` +
+		`<ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"></ds:SignatureMethod>` +
+		`<ds:Reference URI="#Timestamp-1"><ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"></ds:DigestMethod>` +
+		`<ds:DigestValue>ZGlnZXN0</ds:DigestValue></ds:Reference></ds:SignedInfo>`
+	digest := sha256.Sum256([]byte(canonical))
+	value, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature.SignatureValue.Value = base64.StdEncoding.EncodeToString(value)
+
+	if err := VerifySignature(signature, cert); err != nil {
+		t.Errorf("VerifySignature failed: %v", err)
+	}
+
+	signature.SignedInfo.Reference[0].DigestValue = "b3V0cm8="
+	if err := VerifySignature(signature, cert); err == nil {
+		t.Error("expected error for a modified SignedInfo")
+	}
+
+	if err := VerifySignature(nil, cert); err == nil {
+		t.Error("expected error for nil signature")
+	}
+	if err := VerifySignature(signature, nil); err == nil {
+		t.Error("expected error for nil certificate")
+	}
+}
@@ -0,0 +1,99 @@
+package tax
+
+import (
+	"math/big"
+	"slices"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+// DIFALMethod selects how the base of the destination UF is computed
+type DIFALMethod int
+
+const (
+	// DIFALBaseUnica uses the value of the operation as the base of both rates
+	DIFALBaseUnica DIFALMethod = iota
+	// DIFALBaseDupla removes the interstate ICMS from the value of the operation
+	// and grosses it up by the internal rate of the destination UF plus its FCP,
+	// both of which are charged on that base (LC 190/2022 as adopted by states
+	// such as MG, PR and SP)
+	DIFALBaseDupla
+)
+
+// DIFALInput holds the data needed to compute ICMSUFDest
+type DIFALInput struct {
+	// Values compose the base; IPI always integrates it in sales to final consumers
+	Values ItemValues
+	Method DIFALMethod
+
+	// PICMSUFDest is the internal rate of the destination UF and PFCPUFDest its FCP
+	PICMSUFDest decimal.Decimal
+	PFCPUFDest  decimal.Decimal
+	// PICMSInter is the interstate rate: 4, 7 or 12
+	PICMSInter decimal.Decimal
+	// PICMSInterPart is the share of the destination UF, 100 since 2019 (default)
+	PICMSInterPart decimal.Decimal
+}
+
+// interstateRates are the valid interstate ICMS rates
+var interstateRates = []decimal.Decimal{decimal.New(4, 0), decimal.New(7, 0), decimal.New(12, 0)}
+
+// ICMSUFDest computes the ICMS due to the destination UF in interstate
+// operations with final consumers that are not ICMS contributors
+func (c *Calculator) ICMSUFDest(in DIFALInput) (*nfe.ICMSUFDest, error) {
+	if err := validateValues(in.Values); err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(interstateRates, in.PICMSInter.Equal) {
+		return nil, errors.NewValidationError("pICMSInter must be 4, 7 or 12", "pICMSInter", in.PICMSInter)
+	}
+	if err := checkNonNegative([]namedValue{{"pICMSUFDest", in.PICMSUFDest}, {"pFCPUFDest", in.PFCPUFDest}}); err != nil {
+		return nil, err
+	}
+	// the rates are grossed up together under base dupla
+	if in.PICMSUFDest.Add(in.PFCPUFDest).Cmp(hundred) >= 0 {
+		return nil, errors.NewValidationError("pICMSUFDest plus pFCPUFDest must be below 100", "pICMSUFDest", in.PICMSUFDest)
+	}
+
+	part := in.PICMSInterPart
+	if part.IsZero() {
+		part = hundred
+	}
+	if part.Sign() < 0 || part.Cmp(hundred) > 0 {
+		return nil, errors.NewValidationError("pICMSInterPart must be between 0 and 100", "pICMSInterPart", part)
+	}
+
+	values := in.Values
+	values.IPIInBase = true
+	base := c.operationBase(values)
+	pInter := dec(in.PICMSInter)
+	pDest := dec(in.PICMSUFDest)
+	vICMSInter := c.percentOf(base, pInter)
+
+	vBCUFDest := base
+	if in.Method == DIFALBaseDupla {
+		// (base − ICMS interestadual) ÷ (1 − (alíquota interna + FCP) ÷ 100)
+		factor := sub(big.NewRat(1, 1), new(big.Rat).Quo(sum(pDest, dec(in.PFCPUFDest)), big.NewRat(100, 1)))
+		vBCUFDest = c.value(new(big.Rat).Quo(sub(base, vICMSInter), factor))
+	}
+
+	difal := nonNegative(sub(c.percentOf(vBCUFDest, pDest), vICMSInter))
+	vICMSUFDest := c.percentOf(difal, dec(part))
+
+	result := &nfe.ICMSUFDest{
+		VBCUFDest:      c.money(vBCUFDest),
+		PICMSUFDest:    c.rate(in.PICMSUFDest),
+		PICMSInter:     c.rate(in.PICMSInter),
+		PICMSInterPart: c.rate(part),
+		VICMSUFDest:    c.money(vICMSUFDest),
+		VICMSUFRemet:   c.money(sub(difal, vICMSUFDest)),
+	}
+	if in.PFCPUFDest.Sign() > 0 {
+		result.VBCFCPUFDest = c.money(vBCUFDest)
+		result.PFCPUFDest = c.rate(in.PFCPUFDest)
+		result.VFCPUFDest = c.money(c.percentOf(vBCUFDest, dec(in.PFCPUFDest)))
+	}
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+func TestICMSUFDest(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		input    DIFALInput
+		expected nfe.ICMSUFDest
+	}{
+		{
+			name: "base unica",
+			input: DIFALInput{
+				Values:      ItemValues{VProd: money(1000)},
+				PICMSUFDest: rate(18), PFCPUFDest: rate(2), PICMSInter: rate(12),
+			},
+			expected: nfe.ICMSUFDest{
+				VBCUFDest: money(1000), VBCFCPUFDest: money(1000), PFCPUFDest: rate(2), PICMSUFDest: rate(18), PICMSInter: rate(12),
+				PICMSInterPart: rate(100), VFCPUFDest: money(20), VICMSUFDest: money(60), VICMSUFRemet: money(0),
+			},
+		},
+		{
+			name: "base dupla",
+			input: DIFALInput{
+				Values:      ItemValues{VProd: money(1000)},
+				Method:      DIFALBaseDupla,
+				PICMSUFDest: rate(18), PICMSInter: rate(12),
+			},
+			expected: nfe.ICMSUFDest{
+				VBCUFDest: money(1073.17), PICMSUFDest: rate(18), PICMSInter: rate(12),
+				PICMSInterPart: rate(100), VICMSUFDest: money(73.17), VICMSUFRemet: money(0),
+			},
+		},
+		{
+			// (1000 − 120) ÷ (1 − 0.20) = 1100
+			name: "base dupla with FCP",
+			input: DIFALInput{
+				Values:      ItemValues{VProd: money(1000)},
+				Method:      DIFALBaseDupla,
+				PICMSUFDest: rate(18), PFCPUFDest: rate(2), PICMSInter: rate(12),
+			},
+			expected: nfe.ICMSUFDest{
+				VBCUFDest: money(1100), VBCFCPUFDest: money(1100), PFCPUFDest: rate(2), PICMSUFDest: rate(18), PICMSInter: rate(12),
+				PICMSInterPart: rate(100), VFCPUFDest: money(22), VICMSUFDest: money(78), VICMSUFRemet: money(0),
+			},
+		},
+		{
+			name: "IPI and freight in base",
+			input: DIFALInput{
+				Values:      ItemValues{VProd: money(900), VFrete: money(50), VIPI: money(50)},
+				PICMSUFDest: rate(17), PICMSInter: rate(7),
+			},
+			expected: nfe.ICMSUFDest{
+				VBCUFDest: money(1000), PICMSUFDest: rate(17), PICMSInter: rate(7), PICMSInterPart: rate(100), VICMSUFDest: money(100), VICMSUFRemet: money(0),
+			},
+		},
+		{
+			name: "partilha 2018",
+			input: DIFALInput{
+				Values:      ItemValues{VProd: money(1000)},
+				PICMSUFDest: rate(18), PICMSInter: rate(12), PICMSInterPart: rate(80),
+			},
+			expected: nfe.ICMSUFDest{
+				VBCUFDest: money(1000), PICMSUFDest: rate(18), PICMSInter: rate(12), PICMSInterPart: rate(80), VICMSUFDest: money(48), VICMSUFRemet: money(12),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calc.ICMSUFDest(test.input)
+			if err != nil {
+				t.Fatalf("ICMSUFDest should not return error, got: %v", err)
+			}
+			if *result != test.expected {
+				t.Errorf("ICMSUFDest() = %+v, expected %+v", *result, test.expected)
+			}
+		})
+	}
+}
+
+func TestICMSUFDestValidation(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name  string
+		input DIFALInput
+	}{
+		{"invalid interstate rate", DIFALInput{Values: ItemValues{VProd: money(100)}, PICMSUFDest: rate(18), PICMSInter: rate(10)}},
+		{"negative destination rate", DIFALInput{Values: ItemValues{VProd: money(100)}, PICMSUFDest: rate(-1), PICMSInter: rate(12)}},
+		{"invalid partilha", DIFALInput{Values: ItemValues{VProd: money(100)}, PICMSUFDest: rate(18), PICMSInter: rate(12), PICMSInterPart: rate(120)}},
+		{"negative value", DIFALInput{Values: ItemValues{VProd: money(-100)}, PICMSUFDest: rate(18), PICMSInter: rate(12)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := calc.ICMSUFDest(test.input); err == nil {
+				t.Errorf("Expected validation error")
+			}
+		})
+	}
+}
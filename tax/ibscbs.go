@@ -0,0 +1,164 @@
+package tax
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+// Test rates of the 2026 transition (LC 214/2025, art. 343 and 346): the CBS
+// at 0.9% and the IBS at 0.1%, both compensable with PIS and COFINS
+var (
+	CBSRate2026    = decimal.Rate(0.9)
+	IBSUFRate2026  = decimal.Rate(0.1)
+	IBSMunRate2026 = decimal.Rate(0)
+)
+
+// IBSCBSInput holds the data needed to compute the IBS and CBS of an item
+type IBSCBSInput struct {
+	// Values compose the base unless VBC is informed; the IPI never does
+	Values ItemValues
+	VBC    decimal.Decimal
+	// Deduction is subtracted from the base composed from Values, e.g. the
+	// ICMS, PIS, COFINS and ISS excluded from it during the transition
+	Deduction decimal.Decimal
+	// VIS is the Imposto Seletivo of the item, which integrates the base
+	VIS decimal.Decimal
+
+	CST        string
+	CClassTrib string
+
+	PIBSUF  decimal.Decimal
+	PIBSMun decimal.Decimal
+	PCBS    decimal.Decimal
+	// PRedAliqIBS and PRedAliqCBS reduce the rates (e.g. 60% for CST 200)
+	PRedAliqIBS decimal.Decimal
+	PRedAliqCBS decimal.Decimal
+	// PDifIBS and PDifCBS are the deferred percentages of the tax (CST 510)
+	PDifIBS decimal.Decimal
+	PDifCBS decimal.Decimal
+}
+
+// reformCST lists the IBS/CBS CSTs and whether they have the gIBSCBS group
+var reformCST = map[string]bool{
+	"000": true, "010": true, "011": true, "200": true, "210": true, "220": true,
+	"221": true, "222": true, "400": false, "410": false, "510": true, "550": true,
+}
+
+// reformValues holds a computed IBS or CBS group
+type reformValues struct {
+	rate  decimal.Decimal
+	dif   *nfe.GDif
+	red   *nfe.GRed
+	value decimal.Decimal
+}
+
+// reformTax computes one of the IBS or CBS groups: the rate reduced by pRed,
+// the value over the base and the deferred share subtracted from it
+func (c *Calculator) reformTax(base *big.Rat, rate, reduction, deferral decimal.Decimal) reformValues {
+	result := reformValues{rate: c.rate(rate)}
+
+	effective := result.rate
+	if reduction.Sign() > 0 {
+		effective = decimal.NewFromRat(sub(dec(result.rate), percent(dec(result.rate), dec(reduction))), ratePlaces, c.Rounding)
+		result.red = &nfe.GRed{PRedAliq: c.rate(reduction), PAliqEfet: effective}
+	}
+
+	value := c.percentOf(base, dec(effective))
+	if deferral.Sign() > 0 {
+		vDif := c.percentOf(value, dec(deferral))
+		result.dif = &nfe.GDif{PDif: c.rate(deferral), VDif: c.money(vDif)}
+		value = sub(value, vDif)
+	}
+	result.value = c.money(value)
+	return result
+}
+
+// IBSCBS computes the IBS and CBS group of an item. The base is the value of
+// the operation plus the Imposto Seletivo, minus the deduction; CSTs without
+// taxation (400, 410) carry only the classification.
+func (c *Calculator) IBSCBS(in IBSCBSInput) (*nfe.IBSCBS, error) {
+	if err := validateIBSCBSInput(in); err != nil {
+		return nil, err
+	}
+
+	group := &nfe.IBSCBS{CST: in.CST, CClassTrib: in.CClassTrib}
+	if !reformCST[in.CST] {
+		return group, nil
+	}
+
+	base := c.value(dec(in.VBC))
+	if in.VBC.IsZero() {
+		values := in.Values
+		values.IPIInBase = false
+		base = sum(c.operationBase(values), dec(in.VIS))
+		base = nonNegative(c.value(sub(base, dec(in.Deduction))))
+	}
+
+	uf := c.reformTax(base, in.PIBSUF, in.PRedAliqIBS, in.PDifIBS)
+	mun := c.reformTax(base, in.PIBSMun, in.PRedAliqIBS, in.PDifIBS)
+	cbs := c.reformTax(base, in.PCBS, in.PRedAliqCBS, in.PDifCBS)
+
+	group.GIBSCBS = &nfe.GIBSCBS{
+		VBC:     c.money(base),
+		GIBSUF:  nfe.GIBSUF{PIBSUF: uf.rate, GDif: uf.dif, GRed: uf.red, VIBSUF: uf.value},
+		GIBSMun: nfe.GIBSMun{PIBSMun: mun.rate, GDif: mun.dif, GRed: mun.red, VIBSMun: mun.value},
+		VIBS:    c.money(sum(dec(uf.value), dec(mun.value))),
+		GCBS:    nfe.GCBS{PCBS: cbs.rate, GDif: cbs.dif, GRed: cbs.red, VCBS: cbs.value},
+	}
+	return group, nil
+}
+
+// validateIBSCBSInput checks the CST and cClassTrib and rejects negative
+// values and rates
+func validateIBSCBSInput(in IBSCBSInput) error {
+	if _, ok := reformCST[in.CST]; !ok {
+		return errors.NewValidationError("invalid IBS/CBS CST", "CST", in.CST)
+	}
+	if err := validateClassTrib(in.CClassTrib, in.CST, "cClassTrib"); err != nil {
+		return err
+	}
+	if err := validateValues(in.Values); err != nil {
+		return err
+	}
+
+	err := checkNonNegative([]namedValue{
+		{"vBC", in.VBC}, {"deduction", in.Deduction}, {"vIS", in.VIS},
+		{"pIBSUF", in.PIBSUF}, {"pIBSMun", in.PIBSMun}, {"pCBS", in.PCBS},
+	})
+	if err != nil {
+		return err
+	}
+
+	percentages := []namedValue{
+		{"pRedAliqIBS", in.PRedAliqIBS}, {"pRedAliqCBS", in.PRedAliqCBS},
+		{"pDifIBS", in.PDifIBS}, {"pDifCBS", in.PDifCBS},
+	}
+	for _, field := range percentages {
+		if field.value.Sign() < 0 || field.value.Cmp(hundred) > 0 {
+			return errors.NewValidationError(fmt.Sprintf("%s must be between 0 and 100", field.name), field.name, field.value)
+		}
+	}
+	return nil
+}
+
+// validateClassTrib checks a classification code: 6 digits starting with the CST
+func validateClassTrib(code, cst, field string) error {
+	if len(code) != 6 || !isDigits(code) || code[:3] != cst {
+		return errors.NewValidationError(fmt.Sprintf("%s must have 6 digits starting with the CST %s", field, cst), field, code)
+	}
+	return nil
+}
+
+// isDigits reports whether s has only ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
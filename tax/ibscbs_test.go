@@ -0,0 +1,118 @@
+package tax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+func TestIBSCBS(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		input    IBSCBSInput
+		expected *nfe.IBSCBS
+	}{
+		{
+			name: "000 with the 2026 test rates",
+			input: IBSCBSInput{
+				Values: ItemValues{VProd: money(1000)}, CST: "000", CClassTrib: "000001",
+				PIBSUF: IBSUFRate2026, PIBSMun: IBSMunRate2026, PCBS: CBSRate2026,
+			},
+			expected: &nfe.IBSCBS{CST: "000", CClassTrib: "000001", GIBSCBS: &nfe.GIBSCBS{
+				VBC:     money(1000),
+				GIBSUF:  nfe.GIBSUF{PIBSUF: rate(0.1), VIBSUF: money(1)},
+				GIBSMun: nfe.GIBSMun{PIBSMun: rate(0), VIBSMun: money(0)},
+				VIBS:    money(1),
+				GCBS:    nfe.GCBS{PCBS: rate(0.9), VCBS: money(9)},
+			}},
+		},
+		{
+			name: "base with IS, deduction and without IPI",
+			input: IBSCBSInput{
+				Values: ItemValues{VProd: money(100), VIPI: money(10), IPIInBase: true}, VIS: money(5), Deduction: money(20),
+				CST: "000", CClassTrib: "000001", PIBSUF: IBSUFRate2026, PIBSMun: IBSMunRate2026, PCBS: CBSRate2026,
+			},
+			expected: &nfe.IBSCBS{CST: "000", CClassTrib: "000001", GIBSCBS: &nfe.GIBSCBS{
+				VBC:     money(85),
+				GIBSUF:  nfe.GIBSUF{PIBSUF: rate(0.1), VIBSUF: money(0.09)},
+				GIBSMun: nfe.GIBSMun{PIBSMun: rate(0), VIBSMun: money(0)},
+				VIBS:    money(0.09),
+				GCBS:    nfe.GCBS{PCBS: rate(0.9), VCBS: money(0.77)},
+			}},
+		},
+		{
+			name: "200 with reduced rates",
+			input: IBSCBSInput{
+				Values: ItemValues{VProd: money(100)}, CST: "200", CClassTrib: "200003",
+				PIBSUF: rate(17.7), PIBSMun: rate(0), PCBS: rate(8.8), PRedAliqIBS: rate(60), PRedAliqCBS: rate(60),
+			},
+			expected: &nfe.IBSCBS{CST: "200", CClassTrib: "200003", GIBSCBS: &nfe.GIBSCBS{
+				VBC: money(100),
+				GIBSUF: nfe.GIBSUF{
+					PIBSUF: rate(17.7), GRed: &nfe.GRed{PRedAliq: rate(60), PAliqEfet: rate(7.08)}, VIBSUF: money(7.08),
+				},
+				GIBSMun: nfe.GIBSMun{
+					PIBSMun: rate(0), GRed: &nfe.GRed{PRedAliq: rate(60), PAliqEfet: rate(0)}, VIBSMun: money(0),
+				},
+				VIBS: money(7.08),
+				GCBS: nfe.GCBS{PCBS: rate(8.8), GRed: &nfe.GRed{PRedAliq: rate(60), PAliqEfet: rate(3.52)}, VCBS: money(3.52)},
+			}},
+		},
+		{
+			name: "510 with deferral",
+			input: IBSCBSInput{
+				VBC: money(200), CST: "510", CClassTrib: "510001",
+				PIBSUF: IBSUFRate2026, PIBSMun: IBSMunRate2026, PCBS: CBSRate2026, PDifIBS: rate(50), PDifCBS: rate(50),
+			},
+			expected: &nfe.IBSCBS{CST: "510", CClassTrib: "510001", GIBSCBS: &nfe.GIBSCBS{
+				VBC: money(200),
+				GIBSUF: nfe.GIBSUF{
+					PIBSUF: rate(0.1), GDif: &nfe.GDif{PDif: rate(50), VDif: money(0.1)}, VIBSUF: money(0.1),
+				},
+				GIBSMun: nfe.GIBSMun{
+					PIBSMun: rate(0), GDif: &nfe.GDif{PDif: rate(50), VDif: money(0)}, VIBSMun: money(0),
+				},
+				VIBS: money(0.1),
+				GCBS: nfe.GCBS{PCBS: rate(0.9), GDif: &nfe.GDif{PDif: rate(50), VDif: money(0.9)}, VCBS: money(0.9)},
+			}},
+		},
+		{
+			name:     "410 imunidade",
+			input:    IBSCBSInput{Values: ItemValues{VProd: money(100)}, CST: "410", CClassTrib: "410001", PCBS: CBSRate2026},
+			expected: &nfe.IBSCBS{CST: "410", CClassTrib: "410001"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calc.IBSCBS(test.input)
+			if err != nil {
+				t.Fatalf("IBSCBS should not return error, got: %v", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("IBSCBS() = %+v, expected %+v", result.GIBSCBS, test.expected.GIBSCBS)
+			}
+		})
+	}
+}
+
+func TestIBSCBSValidation(t *testing.T) {
+	calc := NewCalculator()
+
+	invalid := []IBSCBSInput{
+		{CST: "999", CClassTrib: "999001"},
+		{CST: "000", CClassTrib: "200001"},
+		{CST: "000", CClassTrib: "00001"},
+		{CST: "000", CClassTrib: "000001", PCBS: rate(-1)},
+		{CST: "200", CClassTrib: "200001", PRedAliqIBS: rate(101)},
+		{CST: "000", CClassTrib: "000001", Values: ItemValues{VProd: money(-1)}},
+	}
+	for _, in := range invalid {
+		if _, err := calc.IBSCBS(in); err == nil {
+			t.Errorf("Expected error for %+v", in)
+		}
+	}
+}
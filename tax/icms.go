@@ -0,0 +1,614 @@
+package tax
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+// BaseMode is how the ICMS base of the own operation is determined (modBC).
+// The zero value is the value of the operation.
+type BaseMode int
+
+const (
+	BaseOperacao      BaseMode = iota // modBC 3 - valor da operação
+	BaseMVA                           // modBC 0 - margem valor agregado
+	BasePauta                         // modBC 1 - pauta (valor)
+	BasePrecoTabelado                 // modBC 2 - preço tabelado máximo
+)
+
+// Code returns the modBC code of the layout
+func (m BaseMode) Code() int {
+	switch m {
+	case BaseMVA:
+		return 0
+	case BasePauta:
+		return 1
+	case BasePrecoTabelado:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// STBaseMode is how the ICMS-ST base is determined (modBCST). The zero value
+// is the margem de valor agregado.
+type STBaseMode int
+
+const (
+	STBaseMVA           STBaseMode = iota // modBCST 4 - margem valor agregado
+	STBasePrecoTabelado                   // modBCST 0 - preço tabelado ou máximo sugerido
+	STBaseListaNegativa                   // modBCST 1 - lista negativa (valor)
+	STBaseListaPositiva                   // modBCST 2 - lista positiva (valor)
+	STBaseListaNeutra                     // modBCST 3 - lista neutra (valor)
+	STBasePauta                           // modBCST 5 - pauta (valor)
+	STBaseOperacao                        // modBCST 6 - valor da operação
+)
+
+// Code returns the modBCST code of the layout
+func (m STBaseMode) Code() int {
+	switch m {
+	case STBasePrecoTabelado:
+		return 0
+	case STBaseListaNegativa:
+		return 1
+	case STBaseListaPositiva:
+		return 2
+	case STBaseListaNeutra:
+		return 3
+	case STBasePauta:
+		return 5
+	case STBaseOperacao:
+		return 6
+	default:
+		return 4
+	}
+}
+
+// ICMSInput holds the data needed to compute the ICMS group of an item.
+// Exactly one of CST (regime normal) or CSOSN (Simples Nacional) must be set.
+type ICMSInput struct {
+	Values ItemValues
+	Orig   int
+	CST    string
+	CSOSN  string
+
+	// Own operation
+	Base     BaseMode
+	PMVA     decimal.Decimal // margin for BaseMVA
+	UnitBase decimal.Decimal // per unit base for BasePauta and BasePrecoTabelado
+	PRedBC   decimal.Decimal
+	PICMS    decimal.Decimal
+	PFCP     decimal.Decimal
+
+	// Deferral (CST 51)
+	PDif    decimal.Decimal
+	PFCPDif decimal.Decimal
+
+	// Substituição tributária. For CST 30 and CSOSN 201, 202, 203 and 900
+	// PICMS is the rate of the own operation deducted from the ST.
+	STBase     STBaseMode
+	PMVAST     decimal.Decimal
+	UnitBaseST decimal.Decimal // per unit base for STBasePrecoTabelado and STBasePauta
+	PRedBCST   decimal.Decimal
+	PICMSST    decimal.Decimal
+	PFCPST     decimal.Decimal
+
+	// Desoneração (CST 20, 30, 40, 41, 50, 70 and 90). For CST 30, 40, 41
+	// and 50 PICMS is the rate the exempted ICMS is computed with.
+	MotDesICMS    int
+	IndDeduzDeson bool
+
+	// ICMS-ST retained in a previous operation (CST 60 and CSOSN 500).
+	// VICMSSTRet is computed from VBCSTRet, PST and VICMSSubstituto when zero.
+	VBCSTRet        decimal.Decimal
+	PST             decimal.Decimal
+	VICMSSubstituto decimal.Decimal
+	VICMSSTRet      decimal.Decimal
+	VBCFCPSTRet     decimal.Decimal
+	PFCPSTRet       decimal.Decimal
+	// Effective ICMS of sales to final consumers (CST 60 and CSOSN 500)
+	PRedBCEfet decimal.Decimal
+	PICMSEfet  decimal.Decimal
+
+	// Simples Nacional credit (CSOSN 101, 201 and 900)
+	PCredSN decimal.Decimal
+}
+
+// ownOperation holds the computed ICMS of the own operation
+type ownOperation struct {
+	modBC    int
+	vBC      *big.Rat
+	vICMS    *big.Rat
+	vFCP     *big.Rat
+	fullICMS *big.Rat // ICMS without base reduction, used for desoneração
+}
+
+// substitution holds the computed ICMS-ST
+type substitution struct {
+	modBCST  int
+	vBCST    *big.Rat
+	vICMSST  *big.Rat
+	vBCFCPST *big.Rat
+	vFCPST   *big.Rat
+}
+
+// ICMS computes the ICMS group chosen by the CST or CSOSN of the input
+func (c *Calculator) ICMS(in ICMSInput) (*nfe.ICMS, error) {
+	if err := validateICMSInput(in); err != nil {
+		return nil, err
+	}
+	if in.CSOSN != "" {
+		return c.icmsSN(in)
+	}
+
+	own := c.ownOperation(in)
+	switch in.CST {
+	case "00":
+		return &nfe.ICMS{ICMS00: &nfe.ICMS00{
+			Orig:  in.Orig,
+			CST:   in.CST,
+			ModBC: own.modBC,
+			VBC:   c.money(own.vBC),
+			PICMS: c.rate(in.PICMS),
+			VICMS: c.money(own.vICMS),
+			PFCP:  c.optionalRate(in.PFCP),
+			VFCP:  c.optionalMoney(own.vFCP),
+		}}, nil
+
+	case "10":
+		st := c.substitution(in, own.vICMS)
+		return &nfe.ICMS{ICMS10: &nfe.ICMS10{
+			Orig:     in.Orig,
+			CST:      in.CST,
+			ModBC:    own.modBC,
+			VBC:      c.money(own.vBC),
+			PICMS:    c.rate(in.PICMS),
+			VICMS:    c.money(own.vICMS),
+			VBCFCP:   c.fcpBase(own.vBC, in.PFCP),
+			PFCP:     c.optionalRate(in.PFCP),
+			VFCP:     c.optionalMoney(own.vFCP),
+			ModBCST:  st.modBCST,
+			PMVAST:   c.optionalRate(in.PMVAST),
+			PRedBCST: c.optionalRate(in.PRedBCST),
+			VBCST:    c.money(st.vBCST),
+			PICMSST:  c.rate(in.PICMSST),
+			VICMSST:  c.money(st.vICMSST),
+			VBCFCPST: c.optionalMoney(st.vBCFCPST),
+			PFCPST:   c.optionalRate(in.PFCPST),
+			VFCPST:   c.optionalMoney(st.vFCPST),
+		}}, nil
+
+	case "20":
+		group := &nfe.ICMS20{
+			Orig:   in.Orig,
+			CST:    in.CST,
+			ModBC:  own.modBC,
+			PRedBC: c.rate(in.PRedBC),
+			VBC:    c.money(own.vBC),
+			PICMS:  c.rate(in.PICMS),
+			VICMS:  c.money(own.vICMS),
+			VBCFCP: c.fcpBase(own.vBC, in.PFCP),
+			PFCP:   c.optionalRate(in.PFCP),
+			VFCP:   c.optionalMoney(own.vFCP),
+		}
+		group.VICMSDeson, group.MotDesICMS, group.IndDeduzDeson = c.desoneracao(in, sub(own.fullICMS, own.vICMS))
+		return &nfe.ICMS{ICMS20: group}, nil
+
+	case "30":
+		st := c.substitution(in, own.vICMS)
+		group := &nfe.ICMS30{
+			Orig:     in.Orig,
+			CST:      in.CST,
+			ModBCST:  st.modBCST,
+			PMVAST:   c.optionalRate(in.PMVAST),
+			PRedBCST: c.optionalRate(in.PRedBCST),
+			VBCST:    c.money(st.vBCST),
+			PICMSST:  c.rate(in.PICMSST),
+			VICMSST:  c.money(st.vICMSST),
+			VBCFCPST: c.optionalMoney(st.vBCFCPST),
+			PFCPST:   c.optionalRate(in.PFCPST),
+			VFCPST:   c.optionalMoney(st.vFCPST),
+		}
+		group.VICMSDeson, group.MotDesICMS, group.IndDeduzDeson = c.desoneracao(in, own.vICMS)
+		return &nfe.ICMS{ICMS30: group}, nil
+
+	case "40", "41", "50":
+		group := &nfe.ICMS40{Orig: in.Orig, CST: in.CST}
+		group.VICMSDeson, group.MotDesICMS, group.IndDeduzDeson = c.desoneracao(in, own.fullICMS)
+		return &nfe.ICMS{ICMS40: group}, nil
+
+	case "51":
+		return &nfe.ICMS{ICMS51: c.icms51(in, own)}, nil
+
+	case "60":
+		ret := c.retained(in)
+		return &nfe.ICMS{ICMS60: &nfe.ICMS60{
+			Orig:            in.Orig,
+			CST:             in.CST,
+			VBCSTRet:        ret.vBCSTRet,
+			PST:             ret.pST,
+			VICMSSubstituto: ret.vICMSSubstituto,
+			VICMSSTRet:      ret.vICMSSTRet,
+			VBCFCPSTRet:     ret.vBCFCPSTRet,
+			PFCPSTRet:       ret.pFCPSTRet,
+			VFCPSTRet:       ret.vFCPSTRet,
+			PRedBCEfet:      ret.pRedBCEfet,
+			VBCEfet:         ret.vBCEfet,
+			PICMSEfet:       ret.pICMSEfet,
+			VICMSEfet:       ret.vICMSEfet,
+		}}, nil
+
+	case "70":
+		st := c.substitution(in, own.vICMS)
+		group := &nfe.ICMS70{
+			Orig:     in.Orig,
+			CST:      in.CST,
+			ModBC:    own.modBC,
+			PRedBC:   c.rate(in.PRedBC),
+			VBC:      c.money(own.vBC),
+			PICMS:    c.rate(in.PICMS),
+			VICMS:    c.money(own.vICMS),
+			VBCFCP:   c.fcpBase(own.vBC, in.PFCP),
+			PFCP:     c.optionalRate(in.PFCP),
+			VFCP:     c.optionalMoney(own.vFCP),
+			ModBCST:  st.modBCST,
+			PMVAST:   c.optionalRate(in.PMVAST),
+			PRedBCST: c.optionalRate(in.PRedBCST),
+			VBCST:    c.money(st.vBCST),
+			PICMSST:  c.rate(in.PICMSST),
+			VICMSST:  c.money(st.vICMSST),
+			VBCFCPST: c.optionalMoney(st.vBCFCPST),
+			PFCPST:   c.optionalRate(in.PFCPST),
+			VFCPST:   c.optionalMoney(st.vFCPST),
+		}
+		group.VICMSDeson, group.MotDesICMS, group.IndDeduzDeson = c.desoneracao(in, sub(own.fullICMS, own.vICMS))
+		return &nfe.ICMS{ICMS70: group}, nil
+
+	default: // "90"
+		return &nfe.ICMS{ICMS90: c.icms90(in, own)}, nil
+	}
+}
+
+// icms51 computes the deferred ICMS: vICMS = vICMSOp − vICMSDif
+func (c *Calculator) icms51(in ICMSInput, own ownOperation) *nfe.ICMS51 {
+	vICMSDif := c.percentOf(own.vICMS, dec(in.PDif))
+	group := &nfe.ICMS51{
+		Orig:     in.Orig,
+		CST:      in.CST,
+		ModBC:    own.modBC,
+		PRedBC:   c.optionalRate(in.PRedBC),
+		VBC:      c.money(own.vBC),
+		PICMS:    c.rate(in.PICMS),
+		VICMSOp:  c.money(own.vICMS),
+		PDif:     c.rate(in.PDif),
+		VICMSDif: c.money(vICMSDif),
+		VICMS:    c.money(sub(own.vICMS, vICMSDif)),
+		VBCFCP:   c.fcpBase(own.vBC, in.PFCP),
+		PFCP:     c.optionalRate(in.PFCP),
+		VFCP:     c.optionalMoney(own.vFCP),
+	}
+	if in.PFCPDif.Sign() > 0 {
+		vFCPDif := c.percentOf(own.vFCP, dec(in.PFCPDif))
+		group.PFCPDif = c.rate(in.PFCPDif)
+		group.VFCPDif = c.money(vFCPDif)
+		group.VFCPEfet = c.money(sub(own.vFCP, vFCPDif))
+	}
+	return group
+}
+
+// icms90 computes the optional own operation block (when PICMS is set) and
+// the optional ST block (when PICMSST is set)
+func (c *Calculator) icms90(in ICMSInput, own ownOperation) *nfe.ICMS90 {
+	group := &nfe.ICMS90{Orig: in.Orig, CST: in.CST}
+	deducted := new(big.Rat)
+	if in.PICMS.Sign() > 0 {
+		modBC := own.modBC
+		group.ModBC = &modBC
+		group.VBC = c.money(own.vBC)
+		group.PRedBC = c.optionalRate(in.PRedBC)
+		group.PICMS = c.rate(in.PICMS)
+		group.VICMS = c.money(own.vICMS)
+		group.VBCFCP = c.fcpBase(own.vBC, in.PFCP)
+		group.PFCP = c.optionalRate(in.PFCP)
+		group.VFCP = c.optionalMoney(own.vFCP)
+		deducted = own.vICMS
+	}
+	if in.PICMSST.Sign() > 0 {
+		st := c.substitution(in, deducted)
+		group.ModBCST = &st.modBCST
+		group.PMVAST = c.optionalRate(in.PMVAST)
+		group.PRedBCST = c.optionalRate(in.PRedBCST)
+		group.VBCST = c.money(st.vBCST)
+		group.PICMSST = c.rate(in.PICMSST)
+		group.VICMSST = c.money(st.vICMSST)
+		group.VBCFCPST = c.optionalMoney(st.vBCFCPST)
+		group.PFCPST = c.optionalRate(in.PFCPST)
+		group.VFCPST = c.optionalMoney(st.vFCPST)
+	}
+	group.VICMSDeson, group.MotDesICMS, group.IndDeduzDeson = c.desoneracao(in, sub(own.fullICMS, own.vICMS))
+	return group
+}
+
+// icmsSN computes the Simples Nacional groups
+func (c *Calculator) icmsSN(in ICMSInput) (*nfe.ICMS, error) {
+	own := c.ownOperation(in)
+	credit := c.percentOf(own.vBC, dec(in.PCredSN))
+
+	switch in.CSOSN {
+	case "101":
+		return &nfe.ICMS{ICMSSN101: &nfe.ICMSSN101{
+			Orig:        in.Orig,
+			CSOSN:       in.CSOSN,
+			PCredSN:     c.rate(in.PCredSN),
+			VCredICMSSN: c.money(credit),
+		}}, nil
+
+	case "102", "103", "300", "400":
+		return &nfe.ICMS{ICMSSN102: &nfe.ICMSSN102{Orig: in.Orig, CSOSN: in.CSOSN}}, nil
+
+	case "201":
+		st := c.substitution(in, own.vICMS)
+		return &nfe.ICMS{ICMSSN201: &nfe.ICMSSN201{
+			Orig:        in.Orig,
+			CSOSN:       in.CSOSN,
+			ModBCST:     st.modBCST,
+			PMVAST:      c.optionalRate(in.PMVAST),
+			PRedBCST:    c.optionalRate(in.PRedBCST),
+			VBCST:       c.money(st.vBCST),
+			PICMSST:     c.rate(in.PICMSST),
+			VICMSST:     c.money(st.vICMSST),
+			VBCFCPST:    c.optionalMoney(st.vBCFCPST),
+			PFCPST:      c.optionalRate(in.PFCPST),
+			VFCPST:      c.optionalMoney(st.vFCPST),
+			PCredSN:     c.rate(in.PCredSN),
+			VCredICMSSN: c.money(credit),
+		}}, nil
+
+	case "202", "203":
+		st := c.substitution(in, own.vICMS)
+		return &nfe.ICMS{ICMSSN202: &nfe.ICMSSN202{
+			Orig:     in.Orig,
+			CSOSN:    in.CSOSN,
+			ModBCST:  st.modBCST,
+			PMVAST:   c.optionalRate(in.PMVAST),
+			PRedBCST: c.optionalRate(in.PRedBCST),
+			VBCST:    c.money(st.vBCST),
+			PICMSST:  c.rate(in.PICMSST),
+			VICMSST:  c.money(st.vICMSST),
+			VBCFCPST: c.optionalMoney(st.vBCFCPST),
+			PFCPST:   c.optionalRate(in.PFCPST),
+			VFCPST:   c.optionalMoney(st.vFCPST),
+		}}, nil
+
+	case "500":
+		ret := c.retained(in)
+		return &nfe.ICMS{ICMSSN500: &nfe.ICMSSN500{
+			Orig:            in.Orig,
+			CSOSN:           in.CSOSN,
+			VBCSTRet:        ret.vBCSTRet,
+			PST:             ret.pST,
+			VICMSSubstituto: ret.vICMSSubstituto,
+			VICMSSTRet:      ret.vICMSSTRet,
+			VBCFCPSTRet:     ret.vBCFCPSTRet,
+			PFCPSTRet:       ret.pFCPSTRet,
+			VFCPSTRet:       ret.vFCPSTRet,
+			PRedBCEfet:      ret.pRedBCEfet,
+			VBCEfet:         ret.vBCEfet,
+			PICMSEfet:       ret.pICMSEfet,
+			VICMSEfet:       ret.vICMSEfet,
+		}}, nil
+
+	default: // "900"
+		group := &nfe.ICMSSN900{Orig: in.Orig, CSOSN: in.CSOSN}
+		deducted := new(big.Rat)
+		if in.PICMS.Sign() > 0 {
+			modBC := own.modBC
+			group.ModBC = &modBC
+			group.VBC = c.money(own.vBC)
+			group.PRedBC = c.optionalRate(in.PRedBC)
+			group.PICMS = c.rate(in.PICMS)
+			group.VICMS = c.money(own.vICMS)
+			deducted = own.vICMS
+		}
+		if in.PICMSST.Sign() > 0 {
+			st := c.substitution(in, deducted)
+			group.ModBCST = &st.modBCST
+			group.PMVAST = c.optionalRate(in.PMVAST)
+			group.PRedBCST = c.optionalRate(in.PRedBCST)
+			group.VBCST = c.money(st.vBCST)
+			group.PICMSST = c.rate(in.PICMSST)
+			group.VICMSST = c.money(st.vICMSST)
+			group.VBCFCPST = c.optionalMoney(st.vBCFCPST)
+			group.PFCPST = c.optionalRate(in.PFCPST)
+			group.VFCPST = c.optionalMoney(st.vFCPST)
+		}
+		if in.PCredSN.Sign() > 0 {
+			group.PCredSN = c.rate(in.PCredSN)
+			group.VCredICMSSN = c.money(credit)
+		}
+		return &nfe.ICMS{ICMSSN900: group}, nil
+	}
+}
+
+// ownOperation computes the base, ICMS and FCP of the own operation
+func (c *Calculator) ownOperation(in ICMSInput) ownOperation {
+	var base *big.Rat
+	switch in.Base {
+	case BaseMVA:
+		opBase := c.operationBase(in.Values)
+		base = c.value(sum(opBase, percent(opBase, dec(in.PMVA))))
+	case BasePauta, BasePrecoTabelado:
+		base = c.value(mul(dec(in.UnitBase), dec(in.Values.Quantity)))
+	default:
+		base = c.operationBase(in.Values)
+	}
+
+	vBC := c.reduce(base, dec(in.PRedBC))
+	return ownOperation{
+		modBC:    in.Base.Code(),
+		vBC:      vBC,
+		vICMS:    c.percentOf(vBC, dec(in.PICMS)),
+		vFCP:     c.percentOf(vBC, dec(in.PFCP)),
+		fullICMS: c.percentOf(base, dec(in.PICMS)),
+	}
+}
+
+// substitution computes the ICMS-ST. The base always includes the IPI; the
+// ICMS of the own operation is deducted from the ST and FCP-ST is computed
+// over the ST base without deduction.
+func (c *Calculator) substitution(in ICMSInput, ownICMS *big.Rat) substitution {
+	values := in.Values
+	values.IPIInBase = false
+	opBase := c.value(sum(c.operationBase(values), dec(values.VIPI)))
+
+	var base *big.Rat
+	switch in.STBase {
+	case STBasePrecoTabelado, STBasePauta:
+		base = c.value(mul(dec(in.UnitBaseST), dec(values.Quantity)))
+	case STBaseOperacao:
+		base = opBase
+	default:
+		base = c.value(sum(opBase, percent(opBase, dec(in.PMVAST))))
+	}
+
+	vBCST := c.reduce(base, dec(in.PRedBCST))
+	st := substitution{
+		modBCST:  in.STBase.Code(),
+		vBCST:    vBCST,
+		vICMSST:  nonNegative(sub(c.percentOf(vBCST, dec(in.PICMSST)), ownICMS)),
+		vBCFCPST: new(big.Rat),
+		vFCPST:   new(big.Rat),
+	}
+	if in.PFCPST.Sign() > 0 {
+		st.vBCFCPST = vBCST
+		st.vFCPST = c.percentOf(vBCST, dec(in.PFCPST))
+	}
+	return st
+}
+
+// retainedST holds the ICMS-ST retained in a previous operation
+type retainedST struct {
+	vBCSTRet, pST, vICMSSubstituto, vICMSSTRet decimal.Decimal
+	vBCFCPSTRet, pFCPSTRet, vFCPSTRet          decimal.Decimal
+	pRedBCEfet, vBCEfet, pICMSEfet, vICMSEfet  decimal.Decimal
+}
+
+// retained computes the retained ST fields of CST 60 and CSOSN 500
+func (c *Calculator) retained(in ICMSInput) retainedST {
+	ret := retainedST{
+		vBCSTRet:        c.optionalMoney(dec(in.VBCSTRet)),
+		pST:             c.optionalRate(in.PST),
+		vICMSSubstituto: c.optionalMoney(dec(in.VICMSSubstituto)),
+		vICMSSTRet:      c.optionalMoney(dec(in.VICMSSTRet)),
+	}
+	if in.VICMSSTRet.IsZero() && in.VBCSTRet.Sign() > 0 && in.PST.Sign() > 0 {
+		vBCSTRet := c.value(dec(in.VBCSTRet))
+		retained := sub(c.percentOf(vBCSTRet, dec(in.PST)), c.value(dec(in.VICMSSubstituto)))
+		ret.vICMSSTRet = c.money(nonNegative(retained))
+	}
+	if in.PFCPSTRet.Sign() > 0 {
+		vBCFCPSTRet := c.value(dec(in.VBCFCPSTRet))
+		ret.vBCFCPSTRet = c.money(vBCFCPSTRet)
+		ret.pFCPSTRet = c.rate(in.PFCPSTRet)
+		ret.vFCPSTRet = c.money(c.percentOf(vBCFCPSTRet, dec(in.PFCPSTRet)))
+	}
+	if in.PICMSEfet.Sign() > 0 {
+		vBCEfet := c.reduce(c.operationBase(in.Values), dec(in.PRedBCEfet))
+		ret.pRedBCEfet = c.optionalRate(in.PRedBCEfet)
+		ret.vBCEfet = c.money(vBCEfet)
+		ret.pICMSEfet = c.rate(in.PICMSEfet)
+		ret.vICMSEfet = c.money(c.percentOf(vBCEfet, dec(in.PICMSEfet)))
+	}
+	return ret
+}
+
+// desoneracao returns the exempted ICMS fields when a reason is informed
+func (c *Calculator) desoneracao(in ICMSInput, exempted *big.Rat) (decimal.Decimal, int, int) {
+	if in.MotDesICMS == 0 {
+		return decimal.Decimal{}, 0, 0
+	}
+	return c.money(nonNegative(exempted)), in.MotDesICMS, indicator(in.IndDeduzDeson)
+}
+
+// fcpBase returns the FCP base, informed only when there is FCP
+func (c *Calculator) fcpBase(vBC *big.Rat, pFCP decimal.Decimal) decimal.Decimal {
+	if pFCP.IsZero() {
+		return decimal.Decimal{}
+	}
+	return c.money(vBC)
+}
+
+var (
+	validCST   = map[string]bool{"00": true, "10": true, "20": true, "30": true, "40": true, "41": true, "50": true, "51": true, "60": true, "70": true, "90": true}
+	validCSOSN = map[string]bool{"101": true, "102": true, "103": true, "201": true, "202": true, "203": true, "300": true, "400": true, "500": true, "900": true}
+)
+
+// validateICMSInput checks the codes and rejects negative values and rates
+func validateICMSInput(in ICMSInput) error {
+	if in.Orig < 0 || in.Orig > 8 {
+		return errors.NewValidationError("orig must be between 0 and 8", "orig", in.Orig)
+	}
+
+	switch {
+	case in.CST != "" && in.CSOSN != "":
+		return errors.NewValidationError("only one of CST or CSOSN can be informed", "CST", in.CST)
+	case in.CST != "" && !validCST[in.CST]:
+		return errors.NewValidationError("invalid ICMS CST", "CST", in.CST)
+	case in.CSOSN != "" && !validCSOSN[in.CSOSN]:
+		return errors.NewValidationError("invalid ICMS CSOSN", "CSOSN", in.CSOSN)
+	case in.CST == "" && in.CSOSN == "":
+		return errors.NewValidationError("CST or CSOSN is required", "CST", "")
+	}
+
+	if err := validateValues(in.Values); err != nil {
+		return err
+	}
+
+	rates := []namedValue{
+		{"pMVA", in.PMVA}, {"pICMS", in.PICMS}, {"pFCP", in.PFCP}, {"pDif", in.PDif}, {"pFCPDif", in.PFCPDif},
+		{"pMVAST", in.PMVAST}, {"pICMSST", in.PICMSST}, {"pFCPST", in.PFCPST}, {"pST", in.PST},
+		{"pFCPSTRet", in.PFCPSTRet}, {"pICMSEfet", in.PICMSEfet}, {"pCredSN", in.PCredSN},
+	}
+	if err := checkNonNegative(rates); err != nil {
+		return err
+	}
+
+	percentages := []namedValue{
+		{"pRedBC", in.PRedBC}, {"pRedBCST", in.PRedBCST}, {"pRedBCEfet", in.PRedBCEfet},
+		{"pDif", in.PDif}, {"pFCPDif", in.PFCPDif},
+	}
+	for _, field := range percentages {
+		if field.value.Sign() < 0 || field.value.Cmp(hundred) > 0 {
+			return errors.NewValidationError(fmt.Sprintf("%s must be between 0 and 100", field.name), field.name, field.value)
+		}
+	}
+	return nil
+}
+
+// namedValue pairs a layout field name with its value for validation
+type namedValue struct {
+	name  string
+	value decimal.Decimal
+}
+
+// checkNonNegative rejects negative values
+func checkNonNegative(fields []namedValue) error {
+	for _, field := range fields {
+		if field.value.Sign() < 0 {
+			return errors.NewValidationError(fmt.Sprintf("%s cannot be negative", field.name), field.name, field.value)
+		}
+	}
+	return nil
+}
+
+// validateValues rejects negative item values
+func validateValues(values ItemValues) error {
+	return checkNonNegative([]namedValue{
+		{"vProd", values.VProd}, {"vFrete", values.VFrete}, {"vSeg", values.VSeg}, {"vDesc", values.VDesc},
+		{"vOutro", values.VOutro}, {"vIPI", values.VIPI}, {"qTrib", values.Quantity},
+	})
+}
@@ -0,0 +1,238 @@
+package tax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestICMSRegimeNormal(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		input    ICMSInput
+		expected *nfe.ICMS
+	}{
+		{
+			name:  "00 tributada integralmente",
+			input: ICMSInput{Values: ItemValues{VProd: money(100)}, CST: "00", PICMS: rate(18)},
+			expected: &nfe.ICMS{ICMS00: &nfe.ICMS00{
+				CST: "00", ModBC: 3, VBC: money(100), PICMS: rate(18), VICMS: money(18),
+			}},
+		},
+		{
+			name:  "00 with FCP, freight and discount",
+			input: ICMSInput{Values: ItemValues{VProd: money(250.5), VFrete: money(10), VDesc: money(0.5)}, Orig: 1, CST: "00", PICMS: rate(17), PFCP: rate(2)},
+			expected: &nfe.ICMS{ICMS00: &nfe.ICMS00{
+				Orig: 1, CST: "00", ModBC: 3, VBC: money(260), PICMS: rate(17), VICMS: money(44.2), PFCP: rate(2), VFCP: money(5.2),
+			}},
+		},
+		{
+			name: "10 with MVA and FCP-ST",
+			input: ICMSInput{
+				Values: ItemValues{VProd: money(1000), VIPI: money(100)}, CST: "10", PICMS: rate(12),
+				PMVAST: rate(40), PICMSST: rate(18), PFCPST: rate(2),
+			},
+			expected: &nfe.ICMS{ICMS10: &nfe.ICMS10{
+				CST: "10", ModBC: 3, VBC: money(1000), PICMS: rate(12), VICMS: money(120),
+				ModBCST: 4, PMVAST: rate(40), VBCST: money(1540), PICMSST: rate(18), VICMSST: money(157.2),
+				VBCFCPST: money(1540), PFCPST: rate(2), VFCPST: money(30.8),
+			}},
+		},
+		{
+			name:  "10 with pauta",
+			input: ICMSInput{Values: ItemValues{VProd: money(50), Quantity: quantity(10)}, CST: "10", PICMS: rate(18), STBase: STBasePauta, UnitBaseST: rate(5.5), PICMSST: rate(18)},
+			expected: &nfe.ICMS{ICMS10: &nfe.ICMS10{
+				CST: "10", ModBC: 3, VBC: money(50), PICMS: rate(18), VICMS: money(9),
+				ModBCST: 5, VBCST: money(55), PICMSST: rate(18), VICMSST: money(0.9),
+			}},
+		},
+		{
+			name:  "20 with reduction and desoneracao",
+			input: ICMSInput{Values: ItemValues{VProd: money(1000)}, CST: "20", PRedBC: rate(33.33), PICMS: rate(18), MotDesICMS: 9, IndDeduzDeson: true},
+			expected: &nfe.ICMS{ICMS20: &nfe.ICMS20{
+				CST: "20", ModBC: 3, PRedBC: rate(33.33), VBC: money(666.7), PICMS: rate(18), VICMS: money(120.01),
+				VICMSDeson: money(59.99), MotDesICMS: 9, IndDeduzDeson: 1,
+			}},
+		},
+		{
+			name:  "30 isenta com ST",
+			input: ICMSInput{Values: ItemValues{VProd: money(500)}, CST: "30", PICMS: rate(12), PMVAST: rate(50), PICMSST: rate(18), MotDesICMS: 7},
+			expected: &nfe.ICMS{ICMS30: &nfe.ICMS30{
+				CST: "30", ModBCST: 4, PMVAST: rate(50), VBCST: money(750), PICMSST: rate(18), VICMSST: money(75),
+				VICMSDeson: money(60), MotDesICMS: 7,
+			}},
+		},
+		{
+			name:     "40 isenta com desoneracao",
+			input:    ICMSInput{Values: ItemValues{VProd: money(200)}, CST: "40", PICMS: rate(18), MotDesICMS: 1},
+			expected: &nfe.ICMS{ICMS40: &nfe.ICMS40{CST: "40", VICMSDeson: money(36), MotDesICMS: 1}},
+		},
+		{
+			name:     "41 nao tributada",
+			input:    ICMSInput{Values: ItemValues{VProd: money(200)}, Orig: 2, CST: "41"},
+			expected: &nfe.ICMS{ICMS40: &nfe.ICMS40{Orig: 2, CST: "41"}},
+		},
+		{
+			name:  "51 diferimento",
+			input: ICMSInput{Values: ItemValues{VProd: money(1000)}, CST: "51", PICMS: rate(18), PDif: rate(33.33), PFCP: rate(2), PFCPDif: rate(50)},
+			expected: &nfe.ICMS{ICMS51: &nfe.ICMS51{
+				CST: "51", ModBC: 3, VBC: money(1000), PICMS: rate(18), VICMSOp: money(180), PDif: rate(33.33), VICMSDif: money(59.99), VICMS: money(120.01),
+				VBCFCP: money(1000), PFCP: rate(2), VFCP: money(20), PFCPDif: rate(50), VFCPDif: money(10), VFCPEfet: money(10),
+			}},
+		},
+		{
+			name: "60 retido anteriormente com ICMS efetivo",
+			input: ICMSInput{
+				Values: ItemValues{VProd: money(100)}, CST: "60",
+				VBCSTRet: money(150), PST: rate(18), VICMSSubstituto: money(12), PICMSEfet: rate(18),
+			},
+			expected: &nfe.ICMS{ICMS60: &nfe.ICMS60{
+				CST: "60", VBCSTRet: money(150), PST: rate(18), VICMSSubstituto: money(12), VICMSSTRet: money(15),
+				VBCEfet: money(100), PICMSEfet: rate(18), VICMSEfet: money(18),
+			}},
+		},
+		{
+			name: "70 com reducao e ST",
+			input: ICMSInput{
+				Values: ItemValues{VProd: money(1000)}, CST: "70", PRedBC: rate(20), PICMS: rate(18),
+				PMVAST: rate(30), PRedBCST: rate(20), PICMSST: rate(18),
+			},
+			expected: &nfe.ICMS{ICMS70: &nfe.ICMS70{
+				CST: "70", ModBC: 3, PRedBC: rate(20), VBC: money(800), PICMS: rate(18), VICMS: money(144),
+				ModBCST: 4, PMVAST: rate(30), PRedBCST: rate(20), VBCST: money(1040), PICMSST: rate(18), VICMSST: money(43.2),
+			}},
+		},
+		{
+			name:     "90 without ST",
+			input:    ICMSInput{Values: ItemValues{VProd: money(100)}, CST: "90", PICMS: rate(12)},
+			expected: &nfe.ICMS{ICMS90: &nfe.ICMS90{CST: "90", ModBC: intPtr(3), VBC: money(100), PICMS: rate(12), VICMS: money(12)}},
+		},
+		{
+			name:     "90 without values",
+			input:    ICMSInput{Values: ItemValues{VProd: money(100)}, CST: "90"},
+			expected: &nfe.ICMS{ICMS90: &nfe.ICMS90{CST: "90"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calc.ICMS(test.input)
+			if err != nil {
+				t.Fatalf("ICMS should not return error, got: %v", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("ICMS() = %+v, expected %+v", groupOf(result), groupOf(test.expected))
+			}
+		})
+	}
+}
+
+func TestICMSSimplesNacional(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		input    ICMSInput
+		expected *nfe.ICMS
+	}{
+		{
+			name:     "101 com credito",
+			input:    ICMSInput{Values: ItemValues{VProd: money(100)}, CSOSN: "101", PCredSN: rate(2.56)},
+			expected: &nfe.ICMS{ICMSSN101: &nfe.ICMSSN101{CSOSN: "101", PCredSN: rate(2.56), VCredICMSSN: money(2.56)}},
+		},
+		{
+			name:     "102 sem credito",
+			input:    ICMSInput{Values: ItemValues{VProd: money(100)}, CSOSN: "102"},
+			expected: &nfe.ICMS{ICMSSN102: &nfe.ICMSSN102{CSOSN: "102"}},
+		},
+		{
+			name:     "400 nao tributada",
+			input:    ICMSInput{Values: ItemValues{VProd: money(100)}, CSOSN: "400"},
+			expected: &nfe.ICMS{ICMSSN102: &nfe.ICMSSN102{CSOSN: "400"}},
+		},
+		{
+			name:  "201 com credito e ST",
+			input: ICMSInput{Values: ItemValues{VProd: money(100)}, CSOSN: "201", PICMS: rate(12), PMVAST: rate(50), PICMSST: rate(18), PCredSN: rate(1.25)},
+			expected: &nfe.ICMS{ICMSSN201: &nfe.ICMSSN201{
+				CSOSN: "201", ModBCST: 4, PMVAST: rate(50), VBCST: money(150), PICMSST: rate(18), VICMSST: money(15), PCredSN: rate(1.25), VCredICMSSN: money(1.25),
+			}},
+		},
+		{
+			name:  "203 isencao com ST",
+			input: ICMSInput{Values: ItemValues{VProd: money(100)}, CSOSN: "203", PICMS: rate(12), PMVAST: rate(50), PICMSST: rate(18), PFCPST: rate(2)},
+			expected: &nfe.ICMS{ICMSSN202: &nfe.ICMSSN202{
+				CSOSN: "203", ModBCST: 4, PMVAST: rate(50), VBCST: money(150), PICMSST: rate(18), VICMSST: money(15), VBCFCPST: money(150), PFCPST: rate(2), VFCPST: money(3),
+			}},
+		},
+		{
+			name:  "500 retido anteriormente",
+			input: ICMSInput{Values: ItemValues{VProd: money(100)}, CSOSN: "500", VBCSTRet: money(80), PST: rate(17), VICMSSTRet: money(5.6)},
+			expected: &nfe.ICMS{ICMSSN500: &nfe.ICMSSN500{
+				CSOSN: "500", VBCSTRet: money(80), PST: rate(17), VICMSSTRet: money(5.6),
+			}},
+		},
+		{
+			name:  "900 with ST only",
+			input: ICMSInput{Values: ItemValues{VProd: money(100)}, CSOSN: "900", STBase: STBaseOperacao, PICMSST: rate(18)},
+			expected: &nfe.ICMS{ICMSSN900: &nfe.ICMSSN900{
+				CSOSN: "900", ModBCST: intPtr(6), VBCST: money(100), PICMSST: rate(18), VICMSST: money(18),
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calc.ICMS(test.input)
+			if err != nil {
+				t.Fatalf("ICMS should not return error, got: %v", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("ICMS() = %+v, expected %+v", groupOf(result), groupOf(test.expected))
+			}
+		})
+	}
+}
+
+func TestICMSValidation(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name  string
+		input ICMSInput
+	}{
+		{"no CST or CSOSN", ICMSInput{Values: ItemValues{VProd: money(100)}}},
+		{"both CST and CSOSN", ICMSInput{Values: ItemValues{VProd: money(100)}, CST: "00", CSOSN: "102"}},
+		{"invalid CST", ICMSInput{Values: ItemValues{VProd: money(100)}, CST: "99"}},
+		{"invalid CSOSN", ICMSInput{Values: ItemValues{VProd: money(100)}, CSOSN: "104"}},
+		{"invalid orig", ICMSInput{Values: ItemValues{VProd: money(100)}, CST: "00", Orig: 9}},
+		{"negative value", ICMSInput{Values: ItemValues{VProd: money(-1)}, CST: "00"}},
+		{"negative rate", ICMSInput{Values: ItemValues{VProd: money(100)}, CST: "00", PICMS: rate(-18)}},
+		{"reduction above 100", ICMSInput{Values: ItemValues{VProd: money(100)}, CST: "20", PRedBC: rate(120)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := calc.ICMS(test.input); err == nil {
+				t.Errorf("Expected validation error")
+			}
+		})
+	}
+}
+
+// groupOf returns the group set in an ICMS for readable failure messages
+func groupOf(icms *nfe.ICMS) interface{} {
+	value := reflect.ValueOf(icms).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsNil() {
+			return value.Field(i).Interface()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package tax
+
+import (
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+// IIInput holds the data needed to compute the Imposto de Importação
+type IIInput struct {
+	// VBC is the customs value of the item
+	VBC      decimal.Decimal
+	PII      decimal.Decimal
+	VDespAdu decimal.Decimal
+	VIOF     decimal.Decimal
+}
+
+// II computes the Imposto de Importação group: vII = vBC × pII ÷ 100
+func (c *Calculator) II(in IIInput) (*nfe.II, error) {
+	err := checkNonNegative([]namedValue{
+		{"vBC", in.VBC}, {"pII", in.PII}, {"vDespAdu", in.VDespAdu}, {"vIOF", in.VIOF},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vBC := c.value(dec(in.VBC))
+	return &nfe.II{
+		VBC:      c.money(vBC),
+		VDespAdu: c.money(dec(in.VDespAdu)),
+		VII:      c.money(c.percentOf(vBC, dec(in.PII))),
+		VIOF:     c.money(dec(in.VIOF)),
+	}, nil
+}
@@ -0,0 +1,25 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+func TestII(t *testing.T) {
+	calc := NewCalculator()
+
+	result, err := calc.II(IIInput{VBC: money(5432.1), PII: rate(14), VDespAdu: money(154.23), VIOF: money(0)})
+	if err != nil {
+		t.Fatalf("II should not return error, got: %v", err)
+	}
+
+	expected := nfe.II{VBC: money(5432.1), VDespAdu: money(154.23), VII: money(760.49), VIOF: money(0)}
+	if *result != expected {
+		t.Errorf("II() = %+v, expected %+v", *result, expected)
+	}
+
+	if _, err := calc.II(IIInput{VBC: money(100), PII: rate(-1)}); err == nil {
+		t.Errorf("Expected error for negative rate")
+	}
+}
@@ -0,0 +1,66 @@
+package tax
+
+import (
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+// DefaultCEnq is the IPI legal framework code for operations without a specific one
+const DefaultCEnq = "999"
+
+// IPIInput holds the data needed to compute the IPI group of an item. The IPI
+// never integrates its own base, so Values.IPIInBase is ignored.
+type IPIInput struct {
+	Levy
+	CST string
+	// CEnq is the código de enquadramento legal, DefaultCEnq when empty
+	CEnq     string
+	CNPJProd string
+	CSelo    string
+	QSelo    int
+}
+
+var (
+	ipiTribCST = map[string]bool{"00": true, "49": true, "50": true, "99": true}
+	ipiNTCST   = map[string]bool{
+		"01": true, "02": true, "03": true, "04": true, "05": true,
+		"51": true, "52": true, "53": true, "54": true, "55": true,
+	}
+)
+
+// IPI computes the IPITrib or IPINT group chosen by the CST
+func (c *Calculator) IPI(in IPIInput) (*nfe.IPI, error) {
+	if err := validateLevy(in.Levy); err != nil {
+		return nil, err
+	}
+
+	ipi := &nfe.IPI{
+		CNPJProd: in.CNPJProd,
+		CSelo:    in.CSelo,
+		QSelo:    in.QSelo,
+		CEnq:     in.CEnq,
+	}
+	if ipi.CEnq == "" {
+		ipi.CEnq = DefaultCEnq
+	}
+
+	switch {
+	case ipiNTCST[in.CST]:
+		ipi.IPINT = &nfe.IPINT{CST: in.CST}
+	case ipiTribCST[in.CST]:
+		levy := in.Levy
+		levy.Values.IPIInBase = false
+		result := c.levy(levy)
+		ipi.IPITrib = &nfe.IPITrib{CST: in.CST, VIPI: c.money(result.value)}
+		if result.perUnit {
+			ipi.IPITrib.QUnid = result.quantity
+			ipi.IPITrib.VUnid = result.unitRate
+		} else {
+			ipi.IPITrib.VBC = c.money(result.vBC)
+			ipi.IPITrib.PIPI = result.rate
+		}
+	default:
+		return nil, errors.NewValidationError("invalid IPI CST", "CST", in.CST)
+	}
+	return ipi, nil
+}
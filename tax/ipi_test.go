@@ -0,0 +1,69 @@
+package tax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+func TestIPI(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		input    IPIInput
+		expected *nfe.IPI
+	}{
+		{
+			name:  "50 by percentage",
+			input: IPIInput{CST: "50", Levy: Levy{Values: ItemValues{VProd: money(1000), VFrete: money(50), VIPI: money(52.5), IPIInBase: true}, Rate: rate(5)}},
+			expected: &nfe.IPI{CEnq: "999", IPITrib: &nfe.IPITrib{
+				CST: "50", VBC: money(1050), PIPI: rate(5), VIPI: money(52.5),
+			}},
+		},
+		{
+			name:  "50 per unit",
+			input: IPIInput{CST: "50", CEnq: "301", Levy: Levy{Values: ItemValues{VProd: money(360), Quantity: quantity(12)}, UnitRate: rate(1.275)}},
+			expected: &nfe.IPI{CEnq: "301", IPITrib: &nfe.IPITrib{
+				CST: "50", QUnid: quantity(12), VUnid: rate(1.275), VIPI: money(15.3),
+			}},
+		},
+		{
+			name:  "00 import with informed base",
+			input: IPIInput{CST: "00", Levy: Levy{VBC: money(1234.56), Rate: rate(10)}},
+			expected: &nfe.IPI{CEnq: "999", IPITrib: &nfe.IPITrib{
+				CST: "00", VBC: money(1234.56), PIPI: rate(10), VIPI: money(123.46),
+			}},
+		},
+		{
+			name:     "53 nao tributado",
+			input:    IPIInput{CST: "53", CEnq: "001", Levy: Levy{Values: ItemValues{VProd: money(100)}}},
+			expected: &nfe.IPI{CEnq: "001", IPINT: &nfe.IPINT{CST: "53"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calc.IPI(test.input)
+			if err != nil {
+				t.Fatalf("IPI should not return error, got: %v", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("IPI() = %+v %+v %+v, expected %+v %+v %+v",
+					*result, result.IPITrib, result.IPINT, *test.expected, test.expected.IPITrib, test.expected.IPINT)
+			}
+		})
+	}
+}
+
+func TestIPIValidation(t *testing.T) {
+	calc := NewCalculator()
+
+	if _, err := calc.IPI(IPIInput{CST: "10", Levy: Levy{Values: ItemValues{VProd: money(100)}}}); err == nil {
+		t.Errorf("Expected error for invalid CST")
+	}
+	if _, err := calc.IPI(IPIInput{CST: "50", Levy: Levy{Values: ItemValues{VProd: money(100)}, Rate: rate(-5)}}); err == nil {
+		t.Errorf("Expected error for negative rate")
+	}
+}
@@ -0,0 +1,70 @@
+package tax
+
+import (
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+// ISInput holds the data needed to compute the Imposto Seletivo of an item
+type ISInput struct {
+	// Values compose the base unless VBC is informed; the IPI never does
+	Values ItemValues
+	VBC    decimal.Decimal
+
+	CSTIS        string
+	CClassTribIS string
+
+	PIS decimal.Decimal
+	// PISEspec is the specific rate per unit of UTrib, added to the
+	// percentage (alíquota ad rem); QTrib defaults to Values.Quantity
+	PISEspec decimal.Decimal
+	UTrib    string
+	QTrib    decimal.Decimal
+}
+
+// IS computes the Imposto Seletivo group: vIS = vBC × pIS ÷ 100 + qTrib × pISEspec
+func (c *Calculator) IS(in ISInput) (*nfe.IS, error) {
+	if len(in.CSTIS) != 3 || !isDigits(in.CSTIS) {
+		return nil, errors.NewValidationError("invalid IS CST", "CSTIS", in.CSTIS)
+	}
+	if err := validateClassTrib(in.CClassTribIS, in.CSTIS, "cClassTribIS"); err != nil {
+		return nil, err
+	}
+	if err := validateValues(in.Values); err != nil {
+		return nil, err
+	}
+	err := checkNonNegative([]namedValue{
+		{"vBCIS", in.VBC}, {"pIS", in.PIS}, {"pISEspec", in.PISEspec}, {"qTrib", in.QTrib},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	base := c.value(dec(in.VBC))
+	if in.VBC.IsZero() {
+		values := in.Values
+		values.IPIInBase = false
+		base = c.operationBase(values)
+	}
+
+	is := &nfe.IS{
+		CSTIS:        in.CSTIS,
+		CClassTribIS: in.CClassTribIS,
+		VBCIS:        c.money(base),
+		PIS:          c.rate(in.PIS),
+	}
+	value := c.percentOf(base, dec(in.PIS))
+	if in.PISEspec.Sign() > 0 {
+		quantity := in.QTrib
+		if quantity.IsZero() {
+			quantity = in.Values.Quantity
+		}
+		is.PISEspec = c.rate(in.PISEspec)
+		is.UTrib = in.UTrib
+		is.QTrib = c.Round(quantity, ratePlaces)
+		value = sum(value, c.value(mul(dec(is.QTrib), dec(is.PISEspec))))
+	}
+	is.VIS = c.money(value)
+	return is, nil
+}
@@ -0,0 +1,53 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+func TestIS(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		input    ISInput
+		expected nfe.IS
+	}{
+		{
+			name:     "percentage",
+			input:    ISInput{Values: ItemValues{VProd: money(1000), VIPI: money(50), IPIInBase: true}, CSTIS: "000", CClassTribIS: "000001", PIS: rate(2.5)},
+			expected: nfe.IS{CSTIS: "000", CClassTribIS: "000001", VBCIS: money(1000), PIS: rate(2.5), VIS: money(25)},
+		},
+		{
+			name: "percentage and specific rate",
+			input: ISInput{
+				Values: ItemValues{VProd: money(300), Quantity: quantity(12)}, CSTIS: "000", CClassTribIS: "000001",
+				PIS: rate(10), PISEspec: rate(1.5), UTrib: "LT",
+			},
+			expected: nfe.IS{
+				CSTIS: "000", CClassTribIS: "000001", VBCIS: money(300), PIS: rate(10),
+				PISEspec: rate(1.5), UTrib: "LT", QTrib: quantity(12), VIS: money(48),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calc.IS(test.input)
+			if err != nil {
+				t.Fatalf("IS should not return error, got: %v", err)
+			}
+			if *result != test.expected {
+				t.Errorf("IS() = %+v, expected %+v", *result, test.expected)
+			}
+		})
+	}
+
+	if _, err := calc.IS(ISInput{CSTIS: "00", CClassTribIS: "000001"}); err == nil {
+		t.Errorf("Expected error for invalid CSTIS")
+	}
+	if _, err := calc.IS(ISInput{CSTIS: "000", CClassTribIS: "000001", PIS: rate(-1)}); err == nil {
+		t.Errorf("Expected error for negative rate")
+	}
+}
@@ -0,0 +1,153 @@
+package tax
+
+import (
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+// PISCOFINSInput holds the data needed to compute the PIS or COFINS group of an item
+type PISCOFINSInput struct {
+	Levy
+	CST string
+}
+
+// PISCOFINSSTInput holds the data needed to compute PISST or COFINSST.
+// IndSoma reports whether the value composes the total of the NFe.
+type PISCOFINSSTInput struct {
+	Levy
+	IndSoma bool
+}
+
+// PIS/COFINS group kinds
+const (
+	groupAliq = "Aliq"
+	groupQtde = "Qtde"
+	groupNT   = "NT"
+	groupOutr = "Outr"
+)
+
+// pisCofinsGroup returns the group of a PIS/COFINS CST
+func pisCofinsGroup(cst string) string {
+	switch cst {
+	case "01", "02":
+		return groupAliq
+	case "03":
+		return groupQtde
+	case "04", "05", "06", "07", "08", "09":
+		return groupNT
+	case "49", "50", "51", "52", "53", "54", "55", "56",
+		"60", "61", "62", "63", "64", "65", "66", "67",
+		"70", "71", "72", "73", "74", "75", "98", "99":
+		return groupOutr
+	}
+	return ""
+}
+
+// contribution validates the input and computes the PIS or COFINS value
+func (c *Calculator) contribution(in PISCOFINSInput, tax string) (string, levyResult, error) {
+	group := pisCofinsGroup(in.CST)
+	switch {
+	case group == "":
+		return "", levyResult{}, errors.NewValidationError("invalid "+tax+" CST", "CST", in.CST)
+	case group == groupAliq && in.UnitRate.Sign() > 0:
+		return "", levyResult{}, errors.NewValidationError(tax+" CST "+in.CST+" is computed by percentage", "vAliqProd", in.UnitRate)
+	case group == groupQtde && in.UnitRate.Sign() <= 0:
+		return "", levyResult{}, errors.NewValidationError(tax+" CST 03 requires the value per unit", "vAliqProd", in.UnitRate)
+	}
+	if err := validateLevy(in.Levy); err != nil {
+		return "", levyResult{}, err
+	}
+	return group, c.levy(in.Levy), nil
+}
+
+// PIS computes the PIS group chosen by the CST
+func (c *Calculator) PIS(in PISCOFINSInput) (*nfe.PIS, error) {
+	group, result, err := c.contribution(in, "PIS")
+	if err != nil {
+		return nil, err
+	}
+
+	value := c.money(result.value)
+	switch group {
+	case groupAliq:
+		return &nfe.PIS{PISAliq: &nfe.PISAliq{CST: in.CST, VBC: c.money(result.vBC), PPIS: result.rate, VPIS: value}}, nil
+	case groupQtde:
+		return &nfe.PIS{PISQtde: &nfe.PISQtde{CST: in.CST, QBCProd: result.quantity, VAliqProd: result.unitRate, VPIS: value}}, nil
+	case groupNT:
+		return &nfe.PIS{PISNT: &nfe.PISNT{CST: in.CST}}, nil
+	}
+
+	outr := &nfe.PISOutr{CST: in.CST, VPIS: value}
+	if result.perUnit {
+		outr.QBCProd, outr.VAliqProd = result.quantity, result.unitRate
+	} else {
+		outr.VBC, outr.PPIS = c.money(result.vBC), result.rate
+	}
+	return &nfe.PIS{PISOutr: outr}, nil
+}
+
+// COFINS computes the COFINS group chosen by the CST
+func (c *Calculator) COFINS(in PISCOFINSInput) (*nfe.COFINS, error) {
+	group, result, err := c.contribution(in, "COFINS")
+	if err != nil {
+		return nil, err
+	}
+
+	value := c.money(result.value)
+	switch group {
+	case groupAliq:
+		return &nfe.COFINS{COFINSAliq: &nfe.COFINSAliq{CST: in.CST, VBC: c.money(result.vBC), PCOFINS: result.rate, VCOFINS: value}}, nil
+	case groupQtde:
+		return &nfe.COFINS{COFINSQtde: &nfe.COFINSQtde{CST: in.CST, QBCProd: result.quantity, VAliqProd: result.unitRate, VCOFINS: value}}, nil
+	case groupNT:
+		return &nfe.COFINS{COFINSNT: &nfe.COFINSNT{CST: in.CST}}, nil
+	}
+
+	outr := &nfe.COFINSOutr{CST: in.CST, VCOFINS: value}
+	if result.perUnit {
+		outr.QBCProd, outr.VAliqProd = result.quantity, result.unitRate
+	} else {
+		outr.VBC, outr.PCOFINS = c.money(result.vBC), result.rate
+	}
+	return &nfe.COFINS{COFINSOutr: outr}, nil
+}
+
+// PISST computes the PIS substituição tributária group
+func (c *Calculator) PISST(in PISCOFINSSTInput) (*nfe.PISST, error) {
+	if err := validateLevy(in.Levy); err != nil {
+		return nil, err
+	}
+
+	result := c.levy(in.Levy)
+	st := &nfe.PISST{VPIS: c.money(result.value), IndSomaPISST: indicator(in.IndSoma)}
+	if result.perUnit {
+		st.QBCProd, st.VAliqProd = result.quantity, result.unitRate
+	} else {
+		st.VBC, st.PPIS = c.money(result.vBC), result.rate
+	}
+	return st, nil
+}
+
+// COFINSST computes the COFINS substituição tributária group
+func (c *Calculator) COFINSST(in PISCOFINSSTInput) (*nfe.COFINSST, error) {
+	if err := validateLevy(in.Levy); err != nil {
+		return nil, err
+	}
+
+	result := c.levy(in.Levy)
+	st := &nfe.COFINSST{VCOFINS: c.money(result.value), IndSomaCOFINSST: indicator(in.IndSoma)}
+	if result.perUnit {
+		st.QBCProd, st.VAliqProd = result.quantity, result.unitRate
+	} else {
+		st.VBC, st.PCOFINS = c.money(result.vBC), result.rate
+	}
+	return st, nil
+}
+
+// indicator converts a flag to the 0/1 indicator of the layout
+func indicator(flag bool) int {
+	if flag {
+		return 1
+	}
+	return 0
+}
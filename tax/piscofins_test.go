@@ -0,0 +1,149 @@
+package tax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/nfe"
+)
+
+func TestPIS(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		input    PISCOFINSInput
+		expected *nfe.PIS
+	}{
+		{
+			name:     "01 aliquota basica",
+			input:    PISCOFINSInput{CST: "01", Levy: Levy{Values: ItemValues{VProd: money(100)}, Rate: rate(1.65)}},
+			expected: &nfe.PIS{PISAliq: &nfe.PISAliq{CST: "01", VBC: money(100), PPIS: rate(1.65), VPIS: money(1.65)}},
+		},
+		{
+			name:     "01 with ICMS excluded from base",
+			input:    PISCOFINSInput{CST: "01", Levy: Levy{Values: ItemValues{VProd: money(1000)}, Deduction: money(180), Rate: rate(1.65)}},
+			expected: &nfe.PIS{PISAliq: &nfe.PISAliq{CST: "01", VBC: money(820), PPIS: rate(1.65), VPIS: money(13.53)}},
+		},
+		{
+			name:     "03 por quantidade",
+			input:    PISCOFINSInput{CST: "03", Levy: Levy{Values: ItemValues{VProd: money(5000), Quantity: quantity(1000)}, UnitRate: rate(0.1309)}},
+			expected: &nfe.PIS{PISQtde: &nfe.PISQtde{CST: "03", QBCProd: quantity(1000), VAliqProd: rate(0.1309), VPIS: money(130.9)}},
+		},
+		{
+			name:     "06 aliquota zero",
+			input:    PISCOFINSInput{CST: "06", Levy: Levy{Values: ItemValues{VProd: money(100)}}},
+			expected: &nfe.PIS{PISNT: &nfe.PISNT{CST: "06"}},
+		},
+		{
+			name:     "99 outras por percentual",
+			input:    PISCOFINSInput{CST: "99", Levy: Levy{Values: ItemValues{VProd: money(100)}, Rate: rate(0.65)}},
+			expected: &nfe.PIS{PISOutr: &nfe.PISOutr{CST: "99", VBC: money(100), PPIS: rate(0.65), VPIS: money(0.65)}},
+		},
+		{
+			name:     "49 outras por quantidade",
+			input:    PISCOFINSInput{CST: "49", Levy: Levy{Quantity: quantity(10), UnitRate: rate(0.5)}},
+			expected: &nfe.PIS{PISOutr: &nfe.PISOutr{CST: "49", QBCProd: quantity(10), VAliqProd: rate(0.5), VPIS: money(5)}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calc.PIS(test.input)
+			if err != nil {
+				t.Fatalf("PIS should not return error, got: %v", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("PIS() = %+v, expected %+v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestCOFINS(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		input    PISCOFINSInput
+		expected *nfe.COFINS
+	}{
+		{
+			name:     "01 aliquota basica",
+			input:    PISCOFINSInput{CST: "01", Levy: Levy{Values: ItemValues{VProd: money(100)}, Rate: rate(7.6)}},
+			expected: &nfe.COFINS{COFINSAliq: &nfe.COFINSAliq{CST: "01", VBC: money(100), PCOFINS: rate(7.6), VCOFINS: money(7.6)}},
+		},
+		{
+			name:     "03 por quantidade",
+			input:    PISCOFINSInput{CST: "03", Levy: Levy{Values: ItemValues{Quantity: quantity(1000)}, UnitRate: rate(0.6045)}},
+			expected: &nfe.COFINS{COFINSQtde: &nfe.COFINSQtde{CST: "03", QBCProd: quantity(1000), VAliqProd: rate(0.6045), VCOFINS: money(604.5)}},
+		},
+		{
+			name:     "07 isenta",
+			input:    PISCOFINSInput{CST: "07"},
+			expected: &nfe.COFINS{COFINSNT: &nfe.COFINSNT{CST: "07"}},
+		},
+		{
+			name:     "50 outras",
+			input:    PISCOFINSInput{CST: "50", Levy: Levy{Values: ItemValues{VProd: money(333.33)}, Rate: rate(3)}},
+			expected: &nfe.COFINS{COFINSOutr: &nfe.COFINSOutr{CST: "50", VBC: money(333.33), PCOFINS: rate(3), VCOFINS: money(10)}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calc.COFINS(test.input)
+			if err != nil {
+				t.Fatalf("COFINS should not return error, got: %v", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("COFINS() = %+v, expected %+v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestPISCOFINSValidation(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name  string
+		input PISCOFINSInput
+	}{
+		{"invalid CST", PISCOFINSInput{CST: "10", Levy: Levy{Values: ItemValues{VProd: money(100)}, Rate: rate(1.65)}}},
+		{"aliquota per unit", PISCOFINSInput{CST: "01", Levy: Levy{Quantity: quantity(1), UnitRate: rate(0.5)}}},
+		{"quantidade by percentage", PISCOFINSInput{CST: "03", Levy: Levy{Values: ItemValues{VProd: money(100)}, Rate: rate(1.65)}}},
+		{"negative deduction", PISCOFINSInput{CST: "01", Levy: Levy{Values: ItemValues{VProd: money(100)}, Deduction: money(-1), Rate: rate(1.65)}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := calc.PIS(test.input); err == nil {
+				t.Errorf("Expected PIS validation error")
+			}
+			if _, err := calc.COFINS(test.input); err == nil {
+				t.Errorf("Expected COFINS validation error")
+			}
+		})
+	}
+}
+
+func TestPISCOFINSST(t *testing.T) {
+	calc := NewCalculator()
+
+	pisST, err := calc.PISST(PISCOFINSSTInput{Levy: Levy{Values: ItemValues{VProd: money(200)}, Rate: rate(1.65)}, IndSoma: true})
+	if err != nil {
+		t.Fatalf("PISST should not return error, got: %v", err)
+	}
+	if expected := (nfe.PISST{VBC: money(200), PPIS: rate(1.65), VPIS: money(3.3), IndSomaPISST: 1}); *pisST != expected {
+		t.Errorf("PISST() = %+v, expected %+v", *pisST, expected)
+	}
+
+	cofinsST, err := calc.COFINSST(PISCOFINSSTInput{Levy: Levy{Quantity: quantity(100), UnitRate: rate(0.25)}})
+	if err != nil {
+		t.Fatalf("COFINSST should not return error, got: %v", err)
+	}
+	if expected := (nfe.COFINSST{QBCProd: quantity(100), VAliqProd: rate(0.25), VCOFINS: money(25)}); *cofinsST != expected {
+		t.Errorf("COFINSST() = %+v, expected %+v", *cofinsST, expected)
+	}
+}
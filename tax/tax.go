@@ -0,0 +1,246 @@
+// Package tax computes the tax groups of NFe items (ICMS, ICMS-ST, FCP,
+// DIFAL, IPI, PIS, COFINS, II and the IBS, CBS and Imposto Seletivo of the
+// tax reform) from the item values and the rates of the operation, producing
+// the structs of the nfe document model. The group of each tax is chosen by
+// its CST; IPI, PIS and COFINS support both percentage and per unit (alíquota
+// ad rem) bases.
+//
+// Inputs and results are decimal.Decimal and arithmetic is exact: every field
+// is rounded to the precision of the layout (2 decimals for values, 4 for
+// rates) before being used to derive other fields, the same order the SEFAZ
+// validation rules follow. Optional fields that do not apply are left unset,
+// so they are omitted from the XML.
+//
+// Example:
+//
+//	calc := tax.NewCalculator()
+//	icms, err := calc.ICMS(tax.ICMSInput{
+//		Values: tax.ItemValues{VProd: decimal.Money(100)},
+//		Orig:   0,
+//		CST:    "00",
+//		PICMS:  decimal.Rate(18),
+//	})
+//	// icms.ICMS00.VBC is 100.00, icms.ICMS00.VICMS is 18.00
+package tax
+
+import (
+	"math/big"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+)
+
+// RoundingMode selects how values are rounded to the precision of the layout
+type RoundingMode = decimal.RoundingMode
+
+const (
+	// RoundHalfUp rounds ties away from zero, the rule applied by most SEFAZ validations
+	RoundHalfUp = decimal.RoundHalfUp
+	// RoundHalfEven rounds ties to the even digit (ABNT NBR 5891)
+	RoundHalfEven = decimal.RoundHalfEven
+)
+
+// Decimal places of the layout fields
+const (
+	valuePlaces = decimal.MoneyPlaces
+	ratePlaces  = decimal.RatePlaces
+)
+
+var hundred = decimal.New(100, 0)
+
+// ItemValues holds the values of an item that compose the tax bases
+type ItemValues struct {
+	VProd  decimal.Decimal
+	VFrete decimal.Decimal
+	VSeg   decimal.Decimal
+	VDesc  decimal.Decimal
+	VOutro decimal.Decimal
+	// VIPI is the IPI of the item, added to the ICMS-ST base and, when
+	// IPIInBase is set, to the ICMS base (sales to final consumers)
+	VIPI      decimal.Decimal
+	IPIInBase bool
+	// Quantity is the taxable quantity (qTrib), used by per-unit bases (pauta)
+	Quantity decimal.Decimal
+}
+
+// Calculator computes tax groups with a configurable rounding mode
+type Calculator struct {
+	Rounding RoundingMode
+}
+
+// NewCalculator creates a calculator using half-up rounding
+func NewCalculator() *Calculator {
+	return &Calculator{Rounding: RoundHalfUp}
+}
+
+// OperationBase returns the value of the operation: products, freight,
+// insurance and other charges minus discounts, plus IPI when it integrates the base
+func (c *Calculator) OperationBase(values ItemValues) decimal.Decimal {
+	return c.money(c.operationBase(values))
+}
+
+func (c *Calculator) operationBase(values ItemValues) *big.Rat {
+	base := sum(dec(values.VProd), dec(values.VFrete), dec(values.VSeg), dec(values.VOutro))
+	base.Sub(base, dec(values.VDesc))
+	if values.IPIInBase {
+		base.Add(base, dec(values.VIPI))
+	}
+	return c.round(base, valuePlaces)
+}
+
+// Levy is the common input of taxes computed either by percentage over a base
+// or per unit of the taxable quantity (alíquota ad rem)
+type Levy struct {
+	// Values compose the percentage base unless VBC is informed
+	Values ItemValues
+	VBC    decimal.Decimal
+	// Deduction is subtracted from the base composed from Values, e.g. the
+	// ICMS excluded from the PIS/COFINS base
+	Deduction decimal.Decimal
+	// Rate is the percentage; UnitRate the value per unit, which takes
+	// precedence when set. Quantity defaults to Values.Quantity.
+	Rate     decimal.Decimal
+	UnitRate decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// levyResult holds a computed Levy
+type levyResult struct {
+	perUnit  bool
+	vBC      *big.Rat
+	rate     decimal.Decimal
+	quantity decimal.Decimal
+	unitRate decimal.Decimal
+	value    *big.Rat
+}
+
+// levy computes a tax by percentage or per unit
+func (c *Calculator) levy(in Levy) levyResult {
+	if in.UnitRate.Sign() > 0 {
+		quantity := in.Quantity
+		if quantity.IsZero() {
+			quantity = in.Values.Quantity
+		}
+		q := c.Round(quantity, ratePlaces)
+		unit := c.rate(in.UnitRate)
+		return levyResult{
+			perUnit:  true,
+			vBC:      new(big.Rat),
+			quantity: q,
+			unitRate: unit,
+			value:    c.value(mul(dec(q), dec(unit))),
+		}
+	}
+
+	base := c.value(dec(in.VBC))
+	if in.VBC.IsZero() {
+		base = nonNegative(c.value(sub(c.operationBase(in.Values), dec(in.Deduction))))
+	}
+	return levyResult{
+		vBC:   base,
+		rate:  c.rate(in.Rate),
+		value: c.percentOf(base, dec(in.Rate)),
+	}
+}
+
+// validateLevy rejects negative values, rates and quantities
+func validateLevy(in Levy) error {
+	if err := validateValues(in.Values); err != nil {
+		return err
+	}
+	return checkNonNegative([]namedValue{
+		{"vBC", in.VBC}, {"deduction", in.Deduction}, {"rate", in.Rate},
+		{"unitRate", in.UnitRate}, {"quantity", in.Quantity},
+	})
+}
+
+// value rounds v to the precision of monetary fields
+func (c *Calculator) value(v *big.Rat) *big.Rat {
+	return c.round(v, valuePlaces)
+}
+
+// percentOf returns base × rate ÷ 100 rounded to the precision of monetary fields
+func (c *Calculator) percentOf(base, rate *big.Rat) *big.Rat {
+	return c.value(percent(base, rate))
+}
+
+// reduce applies a base reduction percentage: base × (1 − reduction ÷ 100)
+func (c *Calculator) reduce(base, reduction *big.Rat) *big.Rat {
+	if reduction.Sign() == 0 {
+		return base
+	}
+	return c.value(new(big.Rat).Sub(base, percent(base, reduction)))
+}
+
+// round rounds r to places decimals using the calculator rounding mode
+func (c *Calculator) round(r *big.Rat, places int) *big.Rat {
+	return decimal.NewFromRat(r, places, c.Rounding).Rat()
+}
+
+// Round rounds a value to the given decimal places using the calculator rounding mode
+func (c *Calculator) Round(v decimal.Decimal, places int) decimal.Decimal {
+	return v.Round(places, c.Rounding)
+}
+
+// money returns a required monetary field
+func (c *Calculator) money(r *big.Rat) decimal.Decimal {
+	return decimal.NewFromRat(r, valuePlaces, c.Rounding)
+}
+
+// optionalMoney returns an optional monetary field, left unset when zero
+func (c *Calculator) optionalMoney(r *big.Rat) decimal.Decimal {
+	if r.Sign() == 0 {
+		return decimal.Decimal{}
+	}
+	return c.money(r)
+}
+
+// rate returns a required rate field
+func (c *Calculator) rate(v decimal.Decimal) decimal.Decimal {
+	return c.Round(v, ratePlaces)
+}
+
+// optionalRate returns an optional rate field, left unset when zero
+func (c *Calculator) optionalRate(v decimal.Decimal) decimal.Decimal {
+	if v.IsZero() {
+		return decimal.Decimal{}
+	}
+	return c.rate(v)
+}
+
+// dec returns the exact value of v for intermediate arithmetic
+func dec(v decimal.Decimal) *big.Rat {
+	return v.Rat()
+}
+
+// sum returns the sum of values
+func sum(values ...*big.Rat) *big.Rat {
+	total := new(big.Rat)
+	for _, v := range values {
+		total.Add(total, v)
+	}
+	return total
+}
+
+// sub returns a − b
+func sub(a, b *big.Rat) *big.Rat {
+	return new(big.Rat).Sub(a, b)
+}
+
+// mul returns a × b
+func mul(a, b *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(a, b)
+}
+
+// percent returns base × rate ÷ 100 without rounding
+func percent(base, rate *big.Rat) *big.Rat {
+	r := new(big.Rat).Mul(base, rate)
+	return r.Quo(r, big.NewRat(100, 1))
+}
+
+// nonNegative returns zero for negative values
+func nonNegative(r *big.Rat) *big.Rat {
+	if r.Sign() < 0 {
+		return new(big.Rat)
+	}
+	return r
+}
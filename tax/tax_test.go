@@ -0,0 +1,66 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
+)
+
+// Short constructors keep the inputs and expected groups readable
+var (
+	money    = decimal.Money
+	rate     = decimal.Rate
+	quantity = decimal.Quantity
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		mode     RoundingMode
+		value    string
+		places   int
+		expected string
+	}{
+		{RoundHalfUp, "0.125", 2, "0.13"},
+		{RoundHalfEven, "0.125", 2, "0.12"},
+		{RoundHalfUp, "0.135", 2, "0.14"},
+		{RoundHalfEven, "0.135", 2, "0.14"},
+		{RoundHalfUp, "2.675", 2, "2.68"},
+		{RoundHalfUp, "1.005", 2, "1.01"},
+		{RoundHalfUp, "-1.005", 2, "-1.01"},
+		{RoundHalfEven, "-0.125", 2, "-0.12"},
+		{RoundHalfUp, "1.23456789", 4, "1.2346"},
+		{RoundHalfUp, "120.006", 2, "120.01"},
+		{RoundHalfUp, "100", 2, "100.00"},
+	}
+
+	for _, test := range tests {
+		calc := &Calculator{Rounding: test.mode}
+		if result := calc.Round(decimal.MustParse(test.value), test.places); result.String() != test.expected {
+			t.Errorf("Round(%s, %d) with mode %d = %s, expected %s", test.value, test.places, test.mode, result, test.expected)
+		}
+	}
+}
+
+func TestOperationBase(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name     string
+		values   ItemValues
+		expected decimal.Decimal
+	}{
+		{"products only", ItemValues{VProd: money(100)}, money(100)},
+		{"charges and discount", ItemValues{VProd: money(100), VFrete: money(10), VSeg: money(2.5), VOutro: money(1.25), VDesc: money(3.75)}, money(110)},
+		{"no binary drift", ItemValues{VProd: money(0.1), VFrete: money(0.2)}, money(0.3)},
+		{"IPI outside base", ItemValues{VProd: money(100), VIPI: money(10)}, money(100)},
+		{"IPI in base", ItemValues{VProd: money(100), VIPI: money(10), IPIInBase: true}, money(110)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := calc.OperationBase(test.values); result != test.expected {
+				t.Errorf("OperationBase() = %v, expected %v", result, test.expected)
+			}
+		})
+	}
+}
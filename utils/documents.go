@@ -8,20 +8,36 @@ import (
 	"strings"
 
 	"github.com/adrianodrix/sped-nfe-go/errors"
-	"github.com/adrianodrix/sped-nfe-go/types"
 )
 
 // CNPJ validation and formatting utilities
 
+var (
+	// cnpjPattern matches a clean CNPJ, numeric or alphanumeric
+	cnpjPattern = regexp.MustCompile(`^[0-9A-Z]{12}[0-9]{2}$`)
+
+	// documentFormatting matches what CleanDocument removes from upper-cased
+	// documents, and nonDigits what is removed from a CPF
+	documentFormatting = regexp.MustCompile(`[^0-9A-Z]`)
+	nonDigits          = regexp.MustCompile(`[^0-9]`)
+)
+
 // ValidateCNPJ validates a Brazilian CNPJ (Cadastro Nacional da Pessoa Jurídica)
-// with complete digit verification algorithm
+// with complete digit verification algorithm. Alphanumeric CNPJs (IN RFB
+// 2.229/2024) are accepted: the 12 first characters may be letters, each worth
+// its ASCII code minus 48 in the check digit calculation.
 func ValidateCNPJ(cnpj string) error {
-	// Remove non-numeric characters
-	cnpjClean := regexp.MustCompile(`[^0-9]`).ReplaceAllString(cnpj, "")
+	// Remove formatting characters, keeping the letters of alphanumeric CNPJs
+	cnpjClean := CleanDocument(cnpj)
 	
 	// Check length
 	if len(cnpjClean) != 14 {
-		return errors.NewValidationError("CNPJ must have exactly 14 digits", "cnpj", cnpj)
+		return errors.NewValidationError("CNPJ must have exactly 14 characters", "cnpj", cnpj)
+	}
+	
+	// Check the layout: 12 alphanumeric characters and 2 numeric check digits
+	if !cnpjPattern.MatchString(cnpjClean) {
+		return errors.NewValidationError("CNPJ must have 12 alphanumeric characters followed by 2 check digits", "cnpj", cnpj)
 	}
 	
 	// Check if all digits are the same
@@ -29,7 +45,7 @@ func ValidateCNPJ(cnpj string) error {
 		return errors.NewValidationError("CNPJ cannot have all same digits", "cnpj", cnpj)
 	}
 	
-	// Convert to int slice for calculation
+	// Convert to int slice for calculation ('0'-'9' are 0-9 and 'A'-'Z' are 17-42)
 	digits := make([]int, 14)
 	for i, digit := range cnpjClean {
 		digits[i] = int(digit - '0')
@@ -54,7 +70,7 @@ func ValidateCNPJ(cnpj string) error {
 // with complete digit verification algorithm
 func ValidateCPF(cpf string) error {
 	// Remove non-numeric characters
-	cpfClean := regexp.MustCompile(`[^0-9]`).ReplaceAllString(cpf, "")
+	cpfClean := nonDigits.ReplaceAllString(cpf, "")
 	
 	// Check length
 	if len(cpfClean) != 11 {
@@ -105,40 +121,6 @@ func ValidateCPF(cpf string) error {
 	return nil
 }
 
-// ValidateIE validates Inscrição Estadual (State Registration) by UF
-// This is a simplified validation - full IE validation is very complex and UF-specific
-func ValidateIE(ie string, uf types.UF) error {
-	// Remove non-alphanumeric characters
-	ieClean := regexp.MustCompile(`[^A-Za-z0-9]`).ReplaceAllString(ie, "")
-	ieClean = strings.ToUpper(ieClean)
-	
-	// Check for exempt IE
-	if ieClean == "ISENTO" {
-		return nil
-	}
-	
-	// Basic length validation by UF (simplified)
-	expectedLengths := getIEExpectedLengths()
-	expectedLength, exists := expectedLengths[uf]
-	if !exists {
-		return errors.NewValidationError("UF not supported for IE validation", "uf", uf)
-	}
-	
-	if len(ieClean) != expectedLength {
-		return errors.NewValidationError(
-			fmt.Sprintf("IE for %s must have %d characters", uf.String(), expectedLength),
-			"ie", ie)
-	}
-	
-	// For now, just validate format and length
-	// Full IE validation would require implementing specific algorithms for each UF
-	if !regexp.MustCompile(`^[0-9A-Z]+$`).MatchString(ieClean) {
-		return errors.NewValidationError("IE contains invalid characters", "ie", ie)
-	}
-	
-	return nil
-}
-
 // FormatCNPJ formats a CNPJ string with standard Brazilian mask (XX.XXX.XXX/XXXX-XX)
 func FormatCNPJ(cnpj string) (string, error) {
 	// Validate first
@@ -147,7 +129,7 @@ func FormatCNPJ(cnpj string) (string, error) {
 	}
 	
 	// Clean and format
-	cnpjClean := regexp.MustCompile(`[^0-9]`).ReplaceAllString(cnpj, "")
+	cnpjClean := CleanDocument(cnpj)
 	return fmt.Sprintf("%s.%s.%s/%s-%s",
 		cnpjClean[0:2], cnpjClean[2:5], cnpjClean[5:8], cnpjClean[8:12], cnpjClean[12:14]), nil
 }
@@ -160,14 +142,15 @@ func FormatCPF(cpf string) (string, error) {
 	}
 	
 	// Clean and format
-	cpfClean := regexp.MustCompile(`[^0-9]`).ReplaceAllString(cpf, "")
+	cpfClean := nonDigits.ReplaceAllString(cpf, "")
 	return fmt.Sprintf("%s.%s.%s-%s",
 		cpfClean[0:3], cpfClean[3:6], cpfClean[6:9], cpfClean[9:11]), nil
 }
 
-// CleanDocument removes all non-numeric characters from a document string
+// CleanDocument removes the formatting of a document string, keeping digits
+// and the letters of alphanumeric CNPJs in upper case
 func CleanDocument(document string) string {
-	return regexp.MustCompile(`[^0-9]`).ReplaceAllString(document, "")
+	return documentFormatting.ReplaceAllString(strings.ToUpper(document), "")
 }
 
 // IsValidDocument validates either CPF or CNPJ automatically based on length
@@ -180,7 +163,7 @@ func IsValidDocument(document string) error {
 	case 14:
 		return ValidateCNPJ(document)
 	default:
-		return errors.NewValidationError("document must be CPF (11 digits) or CNPJ (14 digits)", "document", document)
+		return errors.NewValidationError("document must be CPF (11 digits) or CNPJ (14 characters)", "document", document)
 	}
 }
 
@@ -211,38 +194,4 @@ func isAllSameDigits(s string) bool {
 		}
 	}
 	return true
-}
-
-// getIEExpectedLengths returns the expected length for IE by UF
-// This is a simplified mapping - real IE validation is much more complex
-func getIEExpectedLengths() map[types.UF]int {
-	return map[types.UF]int{
-		types.AC: 13, // Acre
-		types.AL: 9,  // Alagoas
-		types.AP: 9,  // Amapá
-		types.AM: 9,  // Amazonas
-		types.BA: 9,  // Bahia - can be 8 or 9
-		types.CE: 9,  // Ceará
-		types.DF: 13, // Distrito Federal
-		types.ES: 9,  // Espírito Santo
-		types.GO: 9,  // Goiás
-		types.MA: 9,  // Maranhão
-		types.MT: 11, // Mato Grosso
-		types.MS: 9,  // Mato Grosso do Sul
-		types.MG: 13, // Minas Gerais
-		types.PA: 9,  // Pará
-		types.PB: 9,  // Paraíba
-		types.PR: 10, // Paraná
-		types.PE: 9,  // Pernambuco - can be 9 or 14
-		types.PI: 9,  // Piauí
-		types.RJ: 8,  // Rio de Janeiro
-		types.RN: 10, // Rio Grande do Norte - can be 9 or 10
-		types.RS: 10, // Rio Grande do Sul
-		types.RO: 14, // Rondônia
-		types.RR: 9,  // Roraima
-		types.SC: 9,  // Santa Catarina
-		types.SP: 12, // São Paulo
-		types.SE: 9,  // Sergipe
-		types.TO: 11, // Tocantins
-	}
 }
\ No newline at end of file
@@ -18,7 +18,11 @@ func TestValidateCNPJ(t *testing.T) {
 		{"11222333000180", true, "invalid check digit"},
 		{"11111111111111", true, "all same digits"},
 		{"1122233300018", true, "wrong length"},
-		{"abc123def456gh", true, "contains letters"},
+		{"abc123def456gh", true, "letters in the check digits"},
+		{"12.ABC.345/01DE-35", false, "valid alphanumeric CNPJ with formatting"},
+		{"12ABC34501DE35", false, "valid alphanumeric CNPJ without formatting"},
+		{"12abc34501de35", false, "alphanumeric CNPJ in lower case"},
+		{"12ABC34501DE36", true, "alphanumeric CNPJ with invalid check digit"},
 		{"", true, "empty CNPJ"},
 	}
 
@@ -68,10 +72,11 @@ func TestValidateIE(t *testing.T) {
 		description string
 	}{
 		{"ISENTO", types.SP, false, "exempt IE"},
-		{"123456789012", types.SP, false, "valid SP IE format"},
-		{"123456789", types.AL, false, "valid AL IE format"},
+		{"110.042.490.114", types.SP, false, "valid SP IE"},
+		{"240000048", types.AL, false, "valid AL IE"},
+		{"123456789012", types.SP, true, "invalid SP check digits"},
 		{"12345", types.SP, true, "wrong length for SP"},
-		{"1234567A", types.RJ, false, "alphanumeric IE"},
+		{"1234567A", types.RJ, true, "letters in RJ IE"},
 		{"", types.SP, true, "empty IE"},
 	}
 
@@ -95,6 +100,7 @@ func TestFormatCNPJ(t *testing.T) {
 		{"11222333000181", "11.222.333/0001-81", false},
 		{"11.222.333/0001-81", "11.222.333/0001-81", false},
 		{"11222333000180", "", true}, // Invalid CNPJ
+		{"12abc34501de35", "12.ABC.345/01DE-35", false}, // Alphanumeric CNPJ
 	}
 
 	for _, test := range tests {
@@ -143,7 +149,8 @@ func TestCleanDocument(t *testing.T) {
 	}{
 		{"111.444.777-35", "11144477735"},
 		{"11.222.333/0001-81", "11222333000181"},
-		{"abc123def456", "123456"},
+		{"abc123def456", "ABC123DEF456"},
+		{"12.ABC.345/01DE-35", "12ABC34501DE35"},
 		{"  123  456  ", "123456"},
 		{"", ""},
 	}
@@ -166,6 +173,7 @@ func TestIsValidDocument(t *testing.T) {
 		{"11222333000181", false, "valid CNPJ"},
 		{"111.444.777-35", false, "valid CPF with formatting"},
 		{"11.222.333/0001-81", false, "valid CNPJ with formatting"},
+		{"12.ABC.345/01DE-35", false, "valid alphanumeric CNPJ"},
 		{"123456789", true, "invalid length"},
 		{"11144477734", true, "invalid CPF"},
 		{"11222333000180", true, "invalid CNPJ"},
@@ -219,26 +227,3 @@ func TestIsAllSameDigits(t *testing.T) {
 		}
 	}
 }
-
-func TestGetIEExpectedLengths(t *testing.T) {
-	lengths := getIEExpectedLengths()
-
-	// Test a few known values
-	if lengths[types.SP] != 12 {
-		t.Errorf("Expected SP IE length to be 12, got %d", lengths[types.SP])
-	}
-
-	if lengths[types.RJ] != 8 {
-		t.Errorf("Expected RJ IE length to be 8, got %d", lengths[types.RJ])
-	}
-
-	if lengths[types.MG] != 13 {
-		t.Errorf("Expected MG IE length to be 13, got %d", lengths[types.MG])
-	}
-
-	// Ensure all valid UFs are covered
-	expectedCount := 27 // All states including EX
-	if len(lengths) != expectedCount {
-		t.Errorf("Expected %d UFs in IE lengths map, got %d", expectedCount, len(lengths))
-	}
-}
\ No newline at end of file
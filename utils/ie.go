@@ -0,0 +1,503 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+// Inscrição Estadual validation and formatting utilities. The check digit
+// algorithms follow the rules published by each state in SINTEGRA.
+
+// ieRule validates a clean IE of a UF and returns the mask used to format it
+type ieRule func(ie string) (mask string, ok bool)
+
+// ieRules holds the rule of every UF
+var ieRules = map[types.UF]ieRule{
+	types.AC: ieAC,
+	types.AL: ieAL,
+	types.AP: ieAP,
+	types.AM: ieAM,
+	types.BA: ieBA,
+	types.CE: ieCE,
+	types.DF: ieDF,
+	types.ES: ieES,
+	types.GO: ieGO,
+	types.MA: ieMA,
+	types.MT: ieMT,
+	types.MS: ieMS,
+	types.MG: ieMG,
+	types.PA: iePA,
+	types.PB: iePB,
+	types.PR: iePR,
+	types.PE: iePE,
+	types.PI: iePI,
+	types.RJ: ieRJ,
+	types.RN: ieRN,
+	types.RS: ieRS,
+	types.RO: ieRO,
+	types.RR: ieRR,
+	types.SC: ieSC,
+	types.SP: ieSP,
+	types.SE: ieSE,
+	types.TO: ieTO,
+}
+
+// ValidateIE validates Inscrição Estadual (State Registration) by UF with the
+// check digit algorithm of the state. ISENTO is accepted for every UF.
+func ValidateIE(ie string, uf types.UF) error {
+	ieClean := cleanIE(ie)
+
+	// Check for exempt IE
+	if ieClean == "ISENTO" {
+		return nil
+	}
+
+	rule, exists := ieRules[uf]
+	if !exists {
+		return errors.NewValidationError("UF not supported for IE validation", "uf", uf)
+	}
+
+	if ieClean == "" {
+		return errors.NewValidationError("IE cannot be empty", "ie", ie)
+	}
+	if _, ok := rule(ieClean); !ok {
+		return errors.NewValidationError("invalid IE for "+uf.String(), "ie", ie)
+	}
+	return nil
+}
+
+// FormatIE formats a valid IE with the mask used by its UF
+func FormatIE(ie string, uf types.UF) (string, error) {
+	if err := ValidateIE(ie, uf); err != nil {
+		return "", err
+	}
+
+	ieClean := cleanIE(ie)
+	if ieClean == "ISENTO" {
+		return ieClean, nil
+	}
+	mask, _ := ieRules[uf](ieClean)
+	return applyMask(ieClean, mask), nil
+}
+
+// cleanIE removes the formatting of an IE
+func cleanIE(ie string) string {
+	return documentFormatting.ReplaceAllString(strings.ToUpper(ie), "")
+}
+
+// applyMask replaces each # of the mask by the next character of value
+func applyMask(value, mask string) string {
+	var formatted strings.Builder
+	i := 0
+	for _, char := range mask {
+		if char != '#' {
+			formatted.WriteRune(char)
+			continue
+		}
+		if i < len(value) {
+			formatted.WriteByte(value[i])
+			i++
+		}
+	}
+	return formatted.String()
+}
+
+// isIEDigits reports whether ie has only digits and one of the given lengths
+func isIEDigits(ie string, lengths ...int) bool {
+	if !isNumeric(ie) {
+		return false
+	}
+	for _, length := range lengths {
+		if len(ie) == length {
+			return true
+		}
+	}
+	return false
+}
+
+// weightedSum multiplies each digit of s by the weight in the same position
+func weightedSum(s string, weights []int) int {
+	sum := 0
+	for i, weight := range weights {
+		sum += int(s[i]-'0') * weight
+	}
+	return sum
+}
+
+// descendingWeights returns the weights from, from-1, ..., 2
+func descendingWeights(from int) []int {
+	weights := make([]int, 0, from-1)
+	for w := from; w >= 2; w-- {
+		weights = append(weights, w)
+	}
+	return weights
+}
+
+// mod11 returns 0 when the remainder of sum by 11 is 0 or 1 and 11 minus the
+// remainder otherwise, the most common IE check digit rule
+func mod11(sum int) int {
+	rest := sum % 11
+	if rest < 2 {
+		return 0
+	}
+	return 11 - rest
+}
+
+// mod11Zero returns 11 minus the remainder of sum by 11, or 0 when it is 10 or 11
+func mod11Zero(sum int) int {
+	dv := 11 - sum%11
+	if dv >= 10 {
+		return 0
+	}
+	return dv
+}
+
+// digit returns the value of the digit at position i of s
+func digit(s string, i int) int {
+	return int(s[i] - '0')
+}
+
+// simpleIE validates a 9 digit IE whose last digit is computed over the first
+// 8 with weights 9 to 2
+func simpleIE(ie string, dv func(int) int) bool {
+	return isIEDigits(ie, 9) && dv(weightedSum(ie, descendingWeights(9))) == digit(ie, 8)
+}
+
+// ieAC validates 01.NNN.NNN/NNN-DD
+func ieAC(ie string) (string, bool) {
+	if !isIEDigits(ie, 13) || !strings.HasPrefix(ie, "01") {
+		return "", false
+	}
+	dv1 := mod11Zero(weightedSum(ie, []int{4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}))
+	dv2 := mod11Zero(weightedSum(ie, []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}))
+	return "##.###.###/###-##", dv1 == digit(ie, 11) && dv2 == digit(ie, 12)
+}
+
+// ieAL validates 24TNNNNND, where T is the kind of company
+func ieAL(ie string) (string, bool) {
+	if !isIEDigits(ie, 9) || !strings.HasPrefix(ie, "24") || !strings.ContainsRune("03578", rune(ie[2])) {
+		return "", false
+	}
+	dv := weightedSum(ie, descendingWeights(9)) * 10 % 11
+	if dv == 10 {
+		dv = 0
+	}
+	return "#########", dv == digit(ie, 8)
+}
+
+// ieAP validates 03NNNNNND; the constants of the check digit depend on the
+// range of the number
+func ieAP(ie string) (string, bool) {
+	if !isIEDigits(ie, 9) || !strings.HasPrefix(ie, "03") {
+		return "", false
+	}
+	number, _ := strconv.Atoi(ie[:8])
+	p, d := 0, 0
+	switch {
+	case number >= 3000001 && number <= 3017000:
+		p, d = 5, 0
+	case number >= 3017001 && number <= 3019022:
+		p, d = 9, 1
+	}
+	dv := 11 - (p+weightedSum(ie, descendingWeights(9)))%11
+	switch dv {
+	case 10:
+		dv = 0
+	case 11:
+		dv = d
+	}
+	return "#########", dv == digit(ie, 8)
+}
+
+// ieAM validates NN.NNN.NNN-D
+func ieAM(ie string) (string, bool) {
+	if !isIEDigits(ie, 9) {
+		return "", false
+	}
+	sum := weightedSum(ie, descendingWeights(9))
+	dv := 0
+	if sum < 11 {
+		dv = 11 - sum
+	} else {
+		dv = mod11(sum)
+	}
+	return "##.###.###-#", dv == digit(ie, 8)
+}
+
+// ieBA validates the 8 and 9 digit formats; the first digit (second for 9
+// digits) tells whether the check digits use modulo 10 or 11. The second check
+// digit is computed first and takes part in the first.
+func ieBA(ie string) (string, bool) {
+	if !isIEDigits(ie, 8, 9) {
+		return "", false
+	}
+	n := len(ie) - 2 // digits before the check digits
+	kind := ie[0]
+	if len(ie) == 9 {
+		kind = ie[1]
+	}
+	modulo := 10
+	if strings.ContainsRune("679", rune(kind)) {
+		modulo = 11
+	}
+	dv := func(sum int) int {
+		rest := sum % modulo
+		if modulo == 11 && rest < 2 || modulo == 10 && rest == 0 {
+			return 0
+		}
+		return modulo - rest
+	}
+
+	dv2 := dv(weightedSum(ie, descendingWeights(n+1)))
+	dv1 := dv(weightedSum(ie[:n]+strconv.Itoa(dv2), descendingWeights(n+2)))
+	mask := "######-##"
+	if len(ie) == 9 {
+		mask = "#######-##"
+	}
+	return mask, dv1 == digit(ie, n) && dv2 == digit(ie, n+1)
+}
+
+// ieCE validates NNNNNNNN-D
+func ieCE(ie string) (string, bool) {
+	return "########-#", simpleIE(ie, mod11Zero)
+}
+
+// ieDF validates 07NNNNNNNNNDD
+func ieDF(ie string) (string, bool) {
+	if !isIEDigits(ie, 13) || !strings.HasPrefix(ie, "07") && !strings.HasPrefix(ie, "08") {
+		return "", false
+	}
+	dv1 := mod11Zero(weightedSum(ie, []int{4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}))
+	dv2 := mod11Zero(weightedSum(ie, []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}))
+	return "###########-##", dv1 == digit(ie, 11) && dv2 == digit(ie, 12)
+}
+
+// ieES validates NNNNNNNND
+func ieES(ie string) (string, bool) {
+	return "#########", simpleIE(ie, mod11)
+}
+
+// ieGOPrefix matches the prefixes of a Goiás IE
+var ieGOPrefix = regexp.MustCompile(`^(1[01]|2[0-9])`)
+
+// ieGO validates NN.NNN.NNN-D starting with 10, 11 or 20 to 29. A remainder
+// of 1 gives check digit 1 in the range 10103105 to 10119997.
+func ieGO(ie string) (string, bool) {
+	if !isIEDigits(ie, 9) || !ieGOPrefix.MatchString(ie) {
+		return "", false
+	}
+	if ie[:8] == "11094402" {
+		// registered before the rule, accepted with both check digits
+		return "##.###.###-#", ie[8] == '0' || ie[8] == '1'
+	}
+	rest := weightedSum(ie, descendingWeights(9)) % 11
+	dv := 11 - rest
+	switch rest {
+	case 0:
+		dv = 0
+	case 1:
+		number, _ := strconv.Atoi(ie[:8])
+		dv = 0
+		if number >= 10103105 && number <= 10119997 {
+			dv = 1
+		}
+	}
+	return "##.###.###-#", dv == digit(ie, 8)
+}
+
+// ieMA validates 12NNNNNND
+func ieMA(ie string) (string, bool) {
+	return "#########", strings.HasPrefix(ie, "12") && simpleIE(ie, mod11)
+}
+
+// ieMT validates NNNNNNNNNN-D; shorter numbers are padded with zeros
+func ieMT(ie string) (string, bool) {
+	if !isIEDigits(ie, 9, 10, 11) {
+		return "", false
+	}
+	ie = strings.Repeat("0", 11-len(ie)) + ie
+	dv := mod11(weightedSum(ie, []int{3, 2, 9, 8, 7, 6, 5, 4, 3, 2}))
+	return "##########-#", dv == digit(ie, 10)
+}
+
+// ieMS validates 28NNNNNND and 50NNNNNND
+func ieMS(ie string) (string, bool) {
+	if !strings.HasPrefix(ie, "28") && !strings.HasPrefix(ie, "50") {
+		return "", false
+	}
+	return "#########", simpleIE(ie, mod11Zero)
+}
+
+// ieMG validates MMMNNNNNNOODD. The first check digit sums the digits of the
+// products by 1 and 2 of the number with a 0 after the municipality code.
+func ieMG(ie string) (string, bool) {
+	if !isIEDigits(ie, 13) {
+		return "", false
+	}
+	padded := ie[:3] + "0" + ie[3:11]
+	sum := 0
+	for i := range padded {
+		product := digit(padded, i) * (1 + i%2)
+		sum += product/10 + product%10
+	}
+	dv1 := (10 - sum%10) % 10
+	dv2 := mod11(weightedSum(ie[:11]+strconv.Itoa(dv1), []int{3, 2, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2}))
+	return "###.###.###/####", dv1 == digit(ie, 11) && dv2 == digit(ie, 12)
+}
+
+// iePA validates 15-NNNNNN-D
+func iePA(ie string) (string, bool) {
+	return "##-######-#", strings.HasPrefix(ie, "15") && simpleIE(ie, mod11)
+}
+
+// iePB validates NNNNNNNN-D
+func iePB(ie string) (string, bool) {
+	return "########-#", simpleIE(ie, mod11Zero)
+}
+
+// iePR validates NNNNNNNN-DD
+func iePR(ie string) (string, bool) {
+	if !isIEDigits(ie, 10) {
+		return "", false
+	}
+	dv1 := mod11(weightedSum(ie, []int{3, 2, 7, 6, 5, 4, 3, 2}))
+	dv2 := mod11(weightedSum(ie, []int{4, 3, 2, 7, 6, 5, 4, 3, 2}))
+	return "########-##", dv1 == digit(ie, 8) && dv2 == digit(ie, 9)
+}
+
+// iePE validates the eFisco format NNNNNNN-DD and the former 14 digit format
+func iePE(ie string) (string, bool) {
+	if isIEDigits(ie, 14) {
+		dv := 11 - weightedSum(ie, []int{5, 4, 3, 2, 1, 9, 8, 7, 6, 5, 4, 3, 2})%11
+		if dv > 9 {
+			dv -= 10
+		}
+		return "##.#.###.#######-#", dv == digit(ie, 13)
+	}
+	if !isIEDigits(ie, 9) {
+		return "", false
+	}
+	dv1 := mod11(weightedSum(ie, descendingWeights(8)))
+	dv2 := mod11(weightedSum(ie, descendingWeights(9)))
+	return "#######-##", dv1 == digit(ie, 7) && dv2 == digit(ie, 8)
+}
+
+// iePI validates NNNNNNNND
+func iePI(ie string) (string, bool) {
+	return "#########", simpleIE(ie, mod11Zero)
+}
+
+// ieRJ validates NN.NNN.NN-D
+func ieRJ(ie string) (string, bool) {
+	if !isIEDigits(ie, 8) {
+		return "", false
+	}
+	dv := mod11(weightedSum(ie, []int{2, 7, 6, 5, 4, 3, 2}))
+	return "##.###.##-#", dv == digit(ie, 7)
+}
+
+// ieRN validates 20.NNN.NNN-D and 20.N.NNN.NNN-D
+func ieRN(ie string) (string, bool) {
+	if !isIEDigits(ie, 9, 10) || !strings.HasPrefix(ie, "20") {
+		return "", false
+	}
+	n := len(ie) - 1
+	dv := weightedSum(ie, descendingWeights(n+1)) * 10 % 11
+	if dv == 10 {
+		dv = 0
+	}
+	mask := "##.###.###-#"
+	if len(ie) == 10 {
+		mask = "##.#.###.###-#"
+	}
+	return mask, dv == digit(ie, n)
+}
+
+// ieRS validates NNN/NNNNNND
+func ieRS(ie string) (string, bool) {
+	if !isIEDigits(ie, 10) {
+		return "", false
+	}
+	dv := mod11Zero(weightedSum(ie, []int{2, 9, 8, 7, 6, 5, 4, 3, 2}))
+	return "###/#######", dv == digit(ie, 9)
+}
+
+// ieRO validates the 14 digit format and the 9 digit format used until
+// 2000, whose check digit only covers the 5 digits after the municipality
+func ieRO(ie string) (string, bool) {
+	dv := func(sum int) int {
+		dv := 11 - sum%11
+		if dv >= 10 {
+			dv -= 10
+		}
+		return dv
+	}
+	switch {
+	case isIEDigits(ie, 14):
+		return "#############-#", dv(weightedSum(ie, []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2})) == digit(ie, 13)
+	case isIEDigits(ie, 9):
+		return "###.#####-#", dv(weightedSum(ie[3:], descendingWeights(6))) == digit(ie, 8)
+	}
+	return "", false
+}
+
+// ieRR validates 24NNNNNN-D, whose check digit is modulo 9
+func ieRR(ie string) (string, bool) {
+	if !isIEDigits(ie, 9) || !strings.HasPrefix(ie, "24") {
+		return "", false
+	}
+	return "########-#", weightedSum(ie, []int{1, 2, 3, 4, 5, 6, 7, 8})%9 == digit(ie, 8)
+}
+
+// ieSC validates NNN.NNN.NND
+func ieSC(ie string) (string, bool) {
+	return "###.###.###", simpleIE(ie, mod11)
+}
+
+// ieSP validates the industrial and commercial format NNNNNNNND.NND and the
+// rural producer format P-NNNNNNNND.NNN. The check digits are the last digit
+// of the remainder by 11.
+func ieSP(ie string) (string, bool) {
+	first := []int{1, 3, 4, 5, 6, 7, 8, 10}
+	if strings.HasPrefix(ie, "P") {
+		rural := ie[1:]
+		if !isIEDigits(rural, 12) {
+			return "", false
+		}
+		dv := weightedSum(rural, first) % 11 % 10
+		return "#-########.#/###", dv == digit(rural, 8)
+	}
+	if !isIEDigits(ie, 12) {
+		return "", false
+	}
+	dv1 := weightedSum(ie, first) % 11 % 10
+	dv2 := weightedSum(ie, []int{3, 2, 10, 9, 8, 7, 6, 5, 4, 3, 2}) % 11 % 10
+	return "###.###.###.###", dv1 == digit(ie, 8) && dv2 == digit(ie, 11)
+}
+
+// ieSE validates NNNNNNNN-D
+func ieSE(ie string) (string, bool) {
+	return "########-#", simpleIE(ie, mod11Zero)
+}
+
+// ieTO validates the 9 digit format and the former 11 digit format, whose
+// third and fourth digits (01, 02, 03 or 99) are left out of the check digit
+func ieTO(ie string) (string, bool) {
+	switch {
+	case isIEDigits(ie, 11):
+		switch ie[2:4] {
+		case "01", "02", "03", "99":
+		default:
+			return "", false
+		}
+		base := ie[:2] + ie[4:]
+		return "##.##.######-#", mod11(weightedSum(base, descendingWeights(9))) == digit(ie, 10)
+	case isIEDigits(ie, 9):
+		return "########-#", simpleIE(ie, mod11)
+	}
+	return "", false
+}
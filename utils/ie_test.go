@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+// ieTestCases holds valid IEs of every UF with their formatted form
+var ieTestCases = []struct {
+	uf        types.UF
+	ie        string
+	formatted string
+}{
+	{types.AC, "0100482300112", "01.004.823/001-12"},
+	{types.AL, "240000048", "240000048"},
+	{types.AP, "030123459", "030123459"},
+	{types.AP, "030001000", "030001000"}, // first range, p = 5
+	{types.AP, "030175005", "030175005"}, // second range, p = 9
+	{types.AM, "042933684", "04.293.368-4"},
+	{types.BA, "12345663", "123456-63"},   // 8 digits, modulo 10
+	{types.BA, "61234557", "612345-57"},   // 8 digits, modulo 11
+	{types.BA, "100000306", "1000003-06"}, // 9 digits
+	{types.CE, "060000015", "06000001-5"},
+	{types.DF, "0730000100109", "07300001001-09"},
+	{types.ES, "999999990", "999999990"},
+	{types.GO, "109876547", "10.987.654-7"},
+	{types.GO, "101031051", "10.103.105-1"}, // remainder 1 inside the special range
+	{types.GO, "101200030", "10.120.003-0"}, // remainder 1 outside the special range
+	{types.MA, "120000385", "120000385"},
+	{types.MT, "00130000019", "0013000001-9"},
+	{types.MS, "283115947", "283115947"},
+	{types.MG, "0623079040081", "062.307.904/0081"},
+	{types.PA, "159999995", "15-999999-5"},
+	{types.PB, "060000015", "06000001-5"},
+	{types.PR, "1234567850", "12345678-50"},
+	{types.PE, "032141840", "0321418-40"},              // eFisco
+	{types.PE, "18100100000049", "18.1.001.0000004-9"}, // former format
+	{types.PI, "012345679", "012345679"},
+	{types.RJ, "99999993", "99.999.99-3"},
+	{types.RN, "200400401", "20.040.040-1"},
+	{types.RN, "2000400400", "20.0.040.040-0"},
+	{types.RS, "2243658792", "224/3658792"},
+	{types.RO, "00000000625213", "0000000062521-3"},
+	{types.RO, "101625213", "101.62521-3"}, // former format
+	{types.RR, "240066281", "24006628-1"},
+	{types.SC, "251040852", "251.040.852"},
+	{types.SP, "110042490114", "110.042.490.114"},
+	{types.SP, "P011004243002", "P-01100424.3/002"}, // rural producer
+	{types.SE, "271234563", "27123456-3"},
+	{types.TO, "29010227836", "29.01.022783-6"}, // former format
+	{types.TO, "290227836", "29022783-6"},
+}
+
+func TestValidateIEByUF(t *testing.T) {
+	covered := make(map[types.UF]bool)
+	for _, test := range ieTestCases {
+		covered[test.uf] = true
+		t.Run(test.uf.String()+"/"+test.ie, func(t *testing.T) {
+			if err := ValidateIE(test.ie, test.uf); err != nil {
+				t.Errorf("IE should be valid, got: %v", err)
+			}
+			if err := ValidateIE(test.formatted, test.uf); err != nil {
+				t.Errorf("formatted IE should be valid, got: %v", err)
+			}
+
+			// changing a check digit must invalidate the IE; it is the last
+			// digit except for the SP rural producer
+			i := len(test.ie) - 1
+			if test.ie[0] == 'P' {
+				i = 9
+			}
+			tampered := test.ie[:i] + string('0'+(test.ie[i]-'0'+1)%10) + test.ie[i+1:]
+			if err := ValidateIE(tampered, test.uf); err == nil {
+				t.Errorf("IE %s should be invalid", tampered)
+			}
+		})
+	}
+
+	if len(covered) != len(ieRules) {
+		t.Errorf("expected cases for %d UFs, got %d", len(ieRules), len(covered))
+	}
+}
+
+func TestValidateIEInvalid(t *testing.T) {
+	tests := []struct {
+		uf          types.UF
+		ie          string
+		description string
+	}{
+		{types.AC, "0200482300112", "AC must start with 01"},
+		{types.AL, "250000048", "AL must start with 24"},
+		{types.AP, "040123459", "AP must start with 03"},
+		{types.BA, "1234566", "BA with 7 digits"},
+		{types.GO, "309876547", "GO must start with 10, 11 or 20-29"},
+		{types.MA, "130000385", "MA must start with 12"},
+		{types.PA, "169999995", "PA must start with 15"},
+		{types.RN, "210400401", "RN must start with 20"},
+		{types.SP, "P01100424300", "SP rural producer with 11 digits"},
+		{types.SP, "X011004243002", "SP with an invalid letter"},
+		{types.TO, "29040227836", "TO with invalid category"},
+		{types.SP, "", "empty IE"},
+		{types.EX, "123456789", "UF without IE"},
+	}
+
+	for _, test := range tests {
+		if err := ValidateIE(test.ie, test.uf); err == nil {
+			t.Errorf("IE '%s' (%s) should return error", test.ie, test.description)
+		}
+	}
+}
+
+func TestFormatIE(t *testing.T) {
+	for _, test := range ieTestCases {
+		result, err := FormatIE(test.ie, test.uf)
+		if err != nil {
+			t.Errorf("FormatIE('%s', %s) should not return error, got: %v", test.ie, test.uf.String(), err)
+			continue
+		}
+		if result != test.formatted {
+			t.Errorf("FormatIE('%s', %s) = '%s', expected '%s'", test.ie, test.uf.String(), result, test.formatted)
+		}
+	}
+
+	if result, err := FormatIE("isento", types.SP); err != nil || result != "ISENTO" {
+		t.Errorf("FormatIE('isento') = '%s', %v, expected 'ISENTO'", result, err)
+	}
+	if _, err := FormatIE("110042490115", types.SP); err == nil {
+		t.Error("FormatIE should reject an invalid IE")
+	}
+}
@@ -46,10 +46,14 @@ func GenerateAccessKey(components NFEKeyComponents) (string, error) {
 		return "", errors.NewValidationError("number must be between 1 and 999999999", "number", components.Number)
 	}
 	
-	// Validate and clean CNPJ/CPF
+	if components.EmitType < 1 || components.EmitType > 9 {
+		return "", errors.NewValidationError("emission type must be between 1 and 9", "emitType", components.EmitType)
+	}
+	
+	// Validate and clean CNPJ/CPF; CNPJs may be alphanumeric (IN RFB 2.229/2024)
 	cnpjClean := CleanDocument(components.CNPJ)
-	if len(cnpjClean) != 11 && len(cnpjClean) != 14 {
-		return "", errors.NewValidationError("CNPJ/CPF must have 11 or 14 digits", "cnpj", components.CNPJ)
+	if len(cnpjClean) == 11 && !isNumeric(cnpjClean) || len(cnpjClean) != 11 && len(cnpjClean) != 14 {
+		return "", errors.NewValidationError("CNPJ/CPF must have 11 or 14 characters", "cnpj", components.CNPJ)
 	}
 	
 	// Generate random code if not provided
@@ -90,11 +94,13 @@ func ValidateAccessKey(key string) error {
 			"key", key)
 	}
 	
-	// Check if all characters are numeric
-	for _, char := range keyClean {
-		if char < '0' || char > '9' {
-			return errors.NewValidationError("access key must contain only numeric characters", "key", key)
+	// Check if all characters are numeric, except the emitter CNPJ (positions
+	// 7-20), which may be alphanumeric
+	for i, char := range keyClean {
+		if char >= '0' && char <= '9' || i >= 6 && i < 20 && char >= 'A' && char <= 'Z' {
+			continue
 		}
+		return errors.NewValidationError("access key must contain only numeric characters", "key", key)
 	}
 	
 	// Extract components and validate
@@ -251,7 +257,9 @@ func buildKey43(components NFEKeyComponents, cnpj string, code int) string {
 }
 
 // calculateCheckDigit calculates the check digit using the modulo 11 algorithm
-// This replicates the exact logic from Keys::verifyingDigit() in the PHP project
+// This replicates the exact logic from Keys::verifyingDigit() in the PHP project.
+// Each character is worth its ASCII code minus 48, so the letters of an
+// alphanumeric CNPJ count from 17 ('A') on.
 func calculateCheckDigit(key43 string) string {
 	if len(key43) != 43 {
 		return ""
@@ -302,6 +310,16 @@ func generateRandomCode(nfeNumber int) (int, error) {
 	return 0, fmt.Errorf("failed to generate unique code after %d attempts", maxAttempts)
 }
 
+// isNumeric checks if s contains only digits
+func isNumeric(s string) bool {
+	for _, char := range s {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // isValidModel checks if the NFe model is valid
 func isValidModel(model types.ModeloNFe) bool {
 	return model == types.ModeloNFe55 || model == types.ModeloNFCe65
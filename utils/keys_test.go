@@ -350,4 +350,38 @@ func TestBuildKey43(t *testing.T) {
 	if key43[4:6] != "05" { // May -> 05
 		t.Errorf("Key should contain month 05, got %s", key43[4:6])
 	}
-}
\ No newline at end of file
+}
+func TestGenerateAccessKeyAlphanumericCNPJ(t *testing.T) {
+	code := 12345678
+	components := NFEKeyComponents{
+		UF:       types.SP,
+		DateTime: time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC),
+		CNPJ:     "12.abc.345/01de-35",
+		Model:    types.ModeloNFe55,
+		Series:   1,
+		Number:   1234,
+		EmitType: types.TeNormal,
+		Code:     &code,
+	}
+
+	key, err := GenerateAccessKey(components)
+	if err != nil {
+		t.Fatalf("GenerateAccessKey should accept alphanumeric CNPJ, got: %v", err)
+	}
+	if key != "35260712ABC34501DE35550010000012341123456780" {
+		t.Errorf("Unexpected key %s", key)
+	}
+	if err := ValidateAccessKey(key); err != nil {
+		t.Errorf("Generated key should be valid, got error: %v", err)
+	}
+
+	// letters are only allowed in the CNPJ positions
+	if err := ValidateAccessKey("3526071" + "2ABC34501DE3555001000001234112345678A"); err == nil {
+		t.Error("ValidateAccessKey should reject letters outside the CNPJ")
+	}
+
+	components.EmitType = 0
+	if _, err := GenerateAccessKey(components); err == nil {
+		t.Error("GenerateAccessKey should reject an invalid emission type")
+	}
+}
@@ -4,10 +4,10 @@ package utils
 
 import (
 	"regexp"
-	"strconv"
 	"strings"
 	"unicode"
 
+	"github.com/adrianodrix/sped-nfe-go/decimal"
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
@@ -64,29 +64,39 @@ func isValidXMLChar(r rune) bool {
 }
 
 // FormatMoney formats a monetary value for NFe XML
-// Converts float to string with exactly 2 decimal places
-func FormatMoney(value float64) string {
-	return strconv.FormatFloat(value, 'f', 2, 64)
+// Rounds half-up to exactly 2 decimal places
+func FormatMoney(value decimal.Decimal) string {
+	return FormatMoneyRounding(value, decimal.RoundHalfUp)
+}
+
+// FormatMoneyRounding formats a monetary value for NFe XML with exactly 2
+// decimal places, rounding ties with mode
+func FormatMoneyRounding(value decimal.Decimal, mode decimal.RoundingMode) string {
+	return value.Round(decimal.MoneyPlaces, mode).String()
 }
 
 // FormatQuantity formats a quantity value for NFe XML
 // Uses up to 4 decimal places, removing trailing zeros
-func FormatQuantity(value float64) string {
-	formatted := strconv.FormatFloat(value, 'f', 4, 64)
-	
-	// Remove trailing zeros after decimal point
-	if strings.Contains(formatted, ".") {
-		formatted = strings.TrimRight(formatted, "0")
-		formatted = strings.TrimRight(formatted, ".")
-	}
-	
-	return formatted
+func FormatQuantity(value decimal.Decimal) string {
+	return FormatQuantityRounding(value, decimal.RoundHalfUp)
+}
+
+// FormatQuantityRounding formats a quantity value for NFe XML with up to 4
+// decimal places, rounding ties with mode and removing trailing zeros
+func FormatQuantityRounding(value decimal.Decimal, mode decimal.RoundingMode) string {
+	return value.Round(decimal.QuantityPlaces, mode).Trim(0).String()
 }
 
 // FormatPercentage formats a percentage value for NFe XML
-// Uses up to 4 decimal places for tax percentages
-func FormatPercentage(value float64) string {
-	return strconv.FormatFloat(value, 'f', 4, 64)
+// Uses 4 decimal places for tax percentages
+func FormatPercentage(value decimal.Decimal) string {
+	return FormatPercentageRounding(value, decimal.RoundHalfUp)
+}
+
+// FormatPercentageRounding formats a percentage value for NFe XML with exactly
+// 4 decimal places, rounding ties with mode
+func FormatPercentageRounding(value decimal.Decimal, mode decimal.RoundingMode) string {
+	return value.Round(decimal.RatePlaces, mode).String()
 }
 
 // PadLeft pads a string to the left with the specified character
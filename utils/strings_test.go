@@ -2,6 +2,8 @@ package utils
 
 import (
 	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/decimal"
 )
 
 func TestRemoveAccents(t *testing.T) {
@@ -123,10 +125,12 @@ func TestFormatMoney(t *testing.T) {
 		{1234.567, "1234.57"},
 		{0, "0.00"},
 		{-123.45, "-123.45"},
+		{2.675, "2.68"},
+		{1.005, "1.01"},
 	}
 
 	for _, test := range tests {
-		result := FormatMoney(test.input)
+		result := FormatMoney(decimal.NewFromFloat(test.input))
 		if result != test.expected {
 			t.Errorf("FormatMoney(%f) = '%s', expected '%s'", test.input, result, test.expected)
 		}
@@ -150,7 +154,7 @@ func TestFormatQuantity(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result := FormatQuantity(test.input)
+		result := FormatQuantity(decimal.NewFromFloat(test.input))
 		if result != test.expected {
 			t.Errorf("FormatQuantity(%f) = '%s', expected '%s'", test.input, result, test.expected)
 		}
@@ -170,13 +174,39 @@ func TestFormatPercentage(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result := FormatPercentage(test.input)
+		result := FormatPercentage(decimal.NewFromFloat(test.input))
 		if result != test.expected {
 			t.Errorf("FormatPercentage(%f) = '%s', expected '%s'", test.input, result, test.expected)
 		}
 	}
 }
 
+func TestFormatRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   func(decimal.Decimal, decimal.RoundingMode) string
+		input    string
+		halfUp   string
+		halfEven string
+	}{
+		{"money tie to even", FormatMoneyRounding, "2.665", "2.67", "2.66"},
+		{"money tie to odd", FormatMoneyRounding, "2.675", "2.68", "2.68"},
+		{"money negative", FormatMoneyRounding, "-0.125", "-0.13", "-0.12"},
+		{"quantity", FormatQuantityRounding, "1.00005", "1.0001", "1"},
+		{"percentage", FormatPercentageRounding, "12.34565", "12.3457", "12.3456"},
+	}
+
+	for _, test := range tests {
+		value := decimal.MustParse(test.input)
+		if got := test.format(value, decimal.RoundHalfUp); got != test.halfUp {
+			t.Errorf("%s: half-up %s = '%s', expected '%s'", test.name, test.input, got, test.halfUp)
+		}
+		if got := test.format(value, decimal.RoundHalfEven); got != test.halfEven {
+			t.Errorf("%s: half-even %s = '%s', expected '%s'", test.name, test.input, got, test.halfEven)
+		}
+	}
+}
+
 func TestPadLeft(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,360 @@
+package webservices
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/adrianodrix/sped-nfe-go/errors"
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+// serviceTypes lists every service an Environment can hold, in the order used by
+// the PHP project's wsnfe_4.00_mod55.xml
+var serviceTypes = []ServiceType{
+	ServiceStatusServico,
+	ServiceAutorizacao,
+	ServiceConsultaProtocolo,
+	ServiceInutilizacao,
+	ServiceRetAutorizacao,
+	ServiceRecepcaoEvento,
+	ServiceConsultaCadastro,
+	ServiceDistribuicaoDFe,
+	ServiceConsultaDest,
+	ServiceDownloadNF,
+	ServiceRecepcaoEPEC,
+}
+
+// nfceConsultaEntries are the entries of the NFC-e files of the PHP project that
+// hold the QR Code and access key consultation URLs printed on the DANFE NFC-e.
+// They are not webservices and are skipped when parsing.
+var nfceConsultaEntries = map[string]bool{
+	"NfeConsultaQR":    true,
+	"NfeConsultaChave": true,
+}
+
+var (
+	authorizerPattern = regexp.MustCompile(`^[A-Z]{2,5}$`)
+	versionPattern    = regexp.MustCompile(`^[0-9]\.[0-9]{2}$`)
+)
+
+// active holds the configuration used by GetWebserviceURL for each model.
+// Configurations are never modified once published: a reload replaces the
+// whole map entry, so services returned to requests in flight stay valid.
+var active = struct {
+	mu      sync.RWMutex
+	configs map[types.ModeloNFe]WebserviceConfig
+}{
+	configs: map[types.ModeloNFe]WebserviceConfig{
+		types.ModeloNFe55:  NFe55Config,
+		types.ModeloNFCe65: NFCe65Config,
+	},
+}
+
+// DefaultWebserviceConfig returns the configuration embedded in the library for a model
+func DefaultWebserviceConfig(modelo types.ModeloNFe) WebserviceConfig {
+	if modelo == types.ModeloNFCe65 {
+		return NFCe65Config
+	}
+	return NFe55Config
+}
+
+// SetWebserviceConfig validates a configuration and makes a copy of it the one
+// used for a model, so later changes to config do not affect requests. It is
+// safe to call while requests are in flight.
+func SetWebserviceConfig(modelo types.ModeloNFe, config WebserviceConfig) error {
+	if modelo != types.ModeloNFe55 && modelo != types.ModeloNFCe65 {
+		return errors.NewConfigError(fmt.Sprintf("model %d not supported", int(modelo)), "modelo", int(modelo))
+	}
+
+	copied := config.Merge(nil)
+	if err := copied.Validate(); err != nil {
+		return err
+	}
+
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	active.configs[modelo] = copied
+	return nil
+}
+
+// ReloadWebserviceConfig loads a JSON or XML file, merges it over the embedded
+// defaults of the model and replaces the configuration in use. The current
+// configuration is kept if the file cannot be loaded or is invalid.
+func ReloadWebserviceConfig(modelo types.ModeloNFe, path string) error {
+	override, err := LoadWebserviceConfig(path)
+	if err != nil {
+		return err
+	}
+	return SetWebserviceConfig(modelo, DefaultWebserviceConfig(modelo).Merge(override))
+}
+
+// ResetWebserviceConfig restores the embedded configuration of a model
+func ResetWebserviceConfig(modelo types.ModeloNFe) {
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	active.configs[modelo] = DefaultWebserviceConfig(modelo)
+}
+
+// LoadWebserviceConfig reads a configuration file, either JSON in the format of
+// ToJSON or XML in the format of the PHP project's wsnfe_4.00_mod55.xml
+func LoadWebserviceConfig(path string) (WebserviceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewConfigError("failed to read webservice configuration", "path", path)
+	}
+
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(data) > 0 && data[0] == '<' {
+		return ParseWebserviceConfigXML(data)
+	}
+	return ParseWebserviceConfigJSON(data)
+}
+
+// ParseWebserviceConfigJSON parses and validates a configuration in the format produced by ToJSON
+func ParseWebserviceConfigJSON(data []byte) (WebserviceConfig, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.NewConfigError("webservice configuration cannot be empty", "", nil)
+	}
+
+	var config WebserviceConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, errors.NewConfigError("invalid webservice configuration JSON", "", err.Error())
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// xmlWebservices mirrors the layout of wsnfe_4.00_mod55.xml:
+//
+//	<WS><UF><sigla>SP</sigla><homologacao>
+//	  <NfeStatusServico method="..." operation="..." version="4.00">https://...</NfeStatusServico>
+//	</homologacao><producao>...</producao></UF></WS>
+type xmlWebservices struct {
+	UFs []struct {
+		Sigla       string          `xml:"sigla"`
+		Homologacao *xmlEnvironment `xml:"homologacao"`
+		Producao    *xmlEnvironment `xml:"producao"`
+	} `xml:"UF"`
+}
+
+type xmlEnvironment struct {
+	Services []struct {
+		XMLName   xml.Name
+		Method    string `xml:"method,attr"`
+		Operation string `xml:"operation,attr"`
+		Version   string `xml:"version,attr"`
+		URL       string `xml:",chardata"`
+	} `xml:",any"`
+}
+
+// ParseWebserviceConfigXML parses and validates a configuration in the layout of
+// the PHP project's wsnfe_4.00_mod55.xml. Entries without URL, which that file
+// uses for services a state does not offer, and the NFC-e QR Code and
+// consultation URLs (NfeConsultaQR, NfeConsultaChave) are skipped; any other
+// unknown service name is rejected.
+func ParseWebserviceConfigXML(data []byte) (WebserviceConfig, error) {
+	var doc xmlWebservices
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.NewXMLError("invalid webservice configuration XML", "WS", err)
+	}
+	if len(doc.UFs) == 0 {
+		return nil, errors.NewConfigError("webservice configuration has no UF entries", "UF", nil)
+	}
+
+	config := make(WebserviceConfig, len(doc.UFs))
+	for _, uf := range doc.UFs {
+		sigla := strings.TrimSpace(uf.Sigla)
+		homologacao, err := uf.Homologacao.toEnvironment(sigla + ".homologacao")
+		if err != nil {
+			return nil, err
+		}
+		producao, err := uf.Producao.toEnvironment(sigla + ".producao")
+		if err != nil {
+			return nil, err
+		}
+		config[sigla] = &StateWebservices{Homologacao: homologacao, Producao: producao}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// toEnvironment converts the services of an XML environment, rejecting unknown
+// service names so that a typo does not silently drop a service
+func (x *xmlEnvironment) toEnvironment(path string) (*Environment, error) {
+	if x == nil {
+		return nil, nil
+	}
+
+	env := &Environment{}
+	for _, entry := range x.Services {
+		name := entry.XMLName.Local
+		if nfceConsultaEntries[name] {
+			continue
+		}
+		if !slices.Contains(serviceTypes, ServiceType(name)) {
+			field := path + "." + name
+			return nil, errors.NewConfigError(fmt.Sprintf("unknown service %s", field), field, name)
+		}
+
+		serviceURL := strings.TrimSpace(entry.URL)
+		if serviceURL == "" {
+			continue
+		}
+		setServiceInEnvironment(env, ServiceType(name), &Service{
+			Method:    strings.TrimSpace(entry.Method),
+			Operation: strings.TrimSpace(entry.Operation),
+			Version:   strings.TrimSpace(entry.Version),
+			URL:       serviceURL,
+		})
+	}
+	return env, nil
+}
+
+// Validate checks every entry of the configuration: authorizer names, and the
+// method, operation, version and HTTPS URL of each service
+func (config WebserviceConfig) Validate() error {
+	if len(config) == 0 {
+		return errors.NewConfigError("webservice configuration is empty", "", nil)
+	}
+
+	authorizers := make([]string, 0, len(config))
+	for authorizer := range config {
+		authorizers = append(authorizers, authorizer)
+	}
+	sort.Strings(authorizers)
+
+	for _, authorizer := range authorizers {
+		state := config[authorizer]
+		if !authorizerPattern.MatchString(authorizer) {
+			return errors.NewConfigError(fmt.Sprintf("invalid authorizer name: %q", authorizer), "authorizer", authorizer)
+		}
+		if state == nil || state.Homologacao == nil && state.Producao == nil {
+			return errors.NewConfigError(fmt.Sprintf("authorizer %s has no environment", authorizer), "authorizer", authorizer)
+		}
+
+		for _, name := range []string{"homologacao", "producao"} {
+			env := state.Homologacao
+			if name == "producao" {
+				env = state.Producao
+			}
+			for _, serviceType := range serviceTypes {
+				service := getServiceFromEnvironment(env, serviceType)
+				if service == nil {
+					continue
+				}
+				if err := service.validate(); err != nil {
+					field := fmt.Sprintf("%s.%s.%s", authorizer, name, serviceType)
+					return errors.NewConfigError(fmt.Sprintf("invalid service %s: %v", field, err), field, service.URL)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the fields of a single service
+func (s *Service) validate() error {
+	if s.Method == "" {
+		return fmt.Errorf("method is required")
+	}
+	if s.Operation == "" {
+		return fmt.Errorf("operation is required")
+	}
+	if !versionPattern.MatchString(s.Version) {
+		return fmt.Errorf("invalid version %q", s.Version)
+	}
+	parsed, err := url.Parse(s.URL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("URL must be an absolute https URL, got %q", s.URL)
+	}
+	return nil
+}
+
+// Merge returns a new configuration with the services of override replacing
+// those of config. Authorizers, environments and services absent from override
+// are kept; neither configuration is modified.
+func (config WebserviceConfig) Merge(override WebserviceConfig) WebserviceConfig {
+	merged := make(WebserviceConfig, len(config)+len(override))
+	for authorizer, state := range config {
+		merged[authorizer] = mergeState(state, nil)
+	}
+	for authorizer, state := range override {
+		merged[authorizer] = mergeState(merged[authorizer], state)
+	}
+	return merged
+}
+
+// mergeState copies base and applies the environments of override
+func mergeState(base, override *StateWebservices) *StateWebservices {
+	merged := &StateWebservices{}
+	if base != nil {
+		merged.Homologacao = mergeEnvironment(base.Homologacao, nil)
+		merged.Producao = mergeEnvironment(base.Producao, nil)
+	}
+	if override != nil {
+		merged.Homologacao = mergeEnvironment(merged.Homologacao, override.Homologacao)
+		merged.Producao = mergeEnvironment(merged.Producao, override.Producao)
+	}
+	return merged
+}
+
+// mergeEnvironment copies base and applies the services of override
+func mergeEnvironment(base, override *Environment) *Environment {
+	if base == nil && override == nil {
+		return nil
+	}
+
+	merged := &Environment{}
+	for _, env := range []*Environment{base, override} {
+		for _, serviceType := range serviceTypes {
+			if service := getServiceFromEnvironment(env, serviceType); service != nil {
+				copied := *service
+				setServiceInEnvironment(merged, serviceType, &copied)
+			}
+		}
+	}
+	return merged
+}
+
+// setServiceInEnvironment stores a service in an environment, ignoring unknown service types
+func setServiceInEnvironment(env *Environment, serviceType ServiceType, service *Service) {
+	switch serviceType {
+	case ServiceStatusServico:
+		env.NfeStatusServico = service
+	case ServiceAutorizacao:
+		env.NfeAutorizacao = service
+	case ServiceConsultaProtocolo:
+		env.NfeConsultaProtocolo = service
+	case ServiceInutilizacao:
+		env.NfeInutilizacao = service
+	case ServiceRetAutorizacao:
+		env.NfeRetAutorizacao = service
+	case ServiceRecepcaoEvento:
+		env.RecepcaoEvento = service
+	case ServiceConsultaCadastro:
+		env.NfeConsultaCadastro = service
+	case ServiceDistribuicaoDFe:
+		env.NfeDistribuicaoDFe = service
+	case ServiceConsultaDest:
+		env.NfeConsultaDest = service
+	case ServiceDownloadNF:
+		env.NfeDownloadNF = service
+	case ServiceRecepcaoEPEC:
+		env.RecepcaoEPEC = service
+	}
+}
@@ -0,0 +1,242 @@
+package webservices
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adrianodrix/sped-nfe-go/types"
+)
+
+func TestParseWebserviceConfigXML(t *testing.T) {
+	data, err := os.ReadFile("testdata/wsnfe_4.00_mod55.xml")
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+
+	config, err := ParseWebserviceConfigXML(data)
+	if err != nil {
+		t.Fatalf("ParseWebserviceConfigXML should not return error, got: %v", err)
+	}
+
+	if len(config) != 2 {
+		t.Fatalf("expected 2 authorizers, got %d", len(config))
+	}
+
+	sp := config["SP"]
+	if sp == nil || sp.Homologacao == nil || sp.Producao == nil {
+		t.Fatal("SP should have both environments")
+	}
+	status := sp.Homologacao.NfeStatusServico
+	if status == nil || status.Method != "nfeStatusServicoNF" || status.Operation != "NFeStatusServico4" || status.Version != "4.00" {
+		t.Errorf("unexpected SP homologacao NfeStatusServico: %+v", status)
+	}
+	if sp.Producao.NfeAutorizacao.URL != "https://nfe2.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx" {
+		t.Errorf("unexpected SP producao NfeAutorizacao URL: %s", sp.Producao.NfeAutorizacao.URL)
+	}
+	if sp.Homologacao.NfeConsultaDest != nil {
+		t.Error("entries without URL should be skipped")
+	}
+	if config["XX"].Producao != nil {
+		t.Error("missing environments should stay nil")
+	}
+
+	nfce := `<WS><UF><sigla>SP</sigla><producao>` +
+		`<NfeAutorizacao method="nfeAutorizacaoLote" operation="NFeAutorizacao4" version="4.00">https://nfce.fazenda.sp.gov.br/ws/NFeAutorizacao4.asmx</NfeAutorizacao>` +
+		`<NfeConsultaQR method="" operation="" version="">https://www.nfce.fazenda.sp.gov.br/qrcode</NfeConsultaQR>` +
+		`<NfeConsultaChave method="" operation="" version="">https://www.nfce.fazenda.sp.gov.br/consulta</NfeConsultaChave>` +
+		`</producao></UF></WS>`
+	if _, err := ParseWebserviceConfigXML([]byte(nfce)); err != nil {
+		t.Errorf("NFC-e consultation entries should be skipped, got: %v", err)
+	}
+}
+
+func TestParseWebserviceConfigErrors(t *testing.T) {
+	service := `{"method": "nfeStatusServicoNF", "operation": "NFeStatusServico4", "version": "4.00", "url": "%s"}`
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"invalid JSON", "{"},
+		{"empty config", "{}"},
+		{"invalid authorizer", `{"sp": {"producao": {"NfeStatusServico": ` + strings.Replace(service, "%s", "https://x.gov.br", 1) + `}}}`},
+		{"no environment", `{"SP": {}}`},
+		{"http URL", `{"SP": {"producao": {"NfeStatusServico": ` + strings.Replace(service, "%s", "http://x.gov.br", 1) + `}}}`},
+		{"relative URL", `{"SP": {"producao": {"NfeStatusServico": ` + strings.Replace(service, "%s", "/ws/status", 1) + `}}}`},
+		{"missing method", `{"SP": {"producao": {"NfeStatusServico": {"operation": "NFeStatusServico4", "version": "4.00", "url": "https://x.gov.br"}}}}`},
+		{"invalid version", `{"SP": {"producao": {"NfeStatusServico": {"method": "m", "operation": "o", "version": "4", "url": "https://x.gov.br"}}}}`},
+	}
+
+	for _, test := range tests {
+		if _, err := ParseWebserviceConfigJSON([]byte(test.data)); err == nil {
+			t.Errorf("ParseWebserviceConfigJSON (%s) should return error", test.name)
+		}
+	}
+
+	xmlTests := []string{
+		"",
+		"<WS>",
+		"<WS></WS>",
+		`<WS><UF><sigla>SP</sigla><producao><NfeAutorizacao method="nfeAutorizacaoLote" operation="NFeAutorizacao4" version="4.00">ftp://x.gov.br</NfeAutorizacao></producao></UF></WS>`,
+		// a misspelled service must not be dropped silently
+		`<WS><UF><sigla>SP</sigla><producao><NfeAutorizacao4 method="nfeAutorizacaoLote" operation="NFeAutorizacao4" version="4.00">https://x.gov.br</NfeAutorizacao4></producao></UF></WS>`,
+	}
+	for _, data := range xmlTests {
+		if _, err := ParseWebserviceConfigXML([]byte(data)); err == nil {
+			t.Errorf("ParseWebserviceConfigXML(%q) should return error", data)
+		}
+	}
+}
+
+func TestWebserviceConfigJSONRoundTrip(t *testing.T) {
+	jsonStr, err := NFe55Config.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON should not return error, got: %v", err)
+	}
+
+	config, err := ParseWebserviceConfigJSON([]byte(jsonStr))
+	if err != nil {
+		t.Fatalf("ParseWebserviceConfigJSON should not return error, got: %v", err)
+	}
+	if len(config) != len(NFe55Config) {
+		t.Errorf("expected %d authorizers, got %d", len(NFe55Config), len(config))
+	}
+	if config["SP"].Producao.NfeAutorizacao.URL != NFe55Config["SP"].Producao.NfeAutorizacao.URL {
+		t.Error("round trip should keep the SP URL")
+	}
+}
+
+func TestEmbeddedConfigsAreValid(t *testing.T) {
+	for _, modelo := range []types.ModeloNFe{types.ModeloNFe55, types.ModeloNFCe65} {
+		if err := DefaultWebserviceConfig(modelo).Validate(); err != nil {
+			t.Errorf("embedded configuration for model %d should be valid, got: %v", int(modelo), err)
+		}
+	}
+}
+
+func TestWebserviceConfigMerge(t *testing.T) {
+	override := WebserviceConfig{
+		"SP": &StateWebservices{
+			Producao: &Environment{
+				NfeAutorizacao: &Service{
+					Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+					URL: "https://nfe2.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx",
+				},
+			},
+		},
+	}
+
+	merged := NFe55Config.Merge(override)
+	if merged["SP"].Producao.NfeAutorizacao.URL != "https://nfe2.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx" {
+		t.Error("override should replace the SP producao NfeAutorizacao")
+	}
+	if merged["SP"].Producao.NfeStatusServico.URL != NFe55Config["SP"].Producao.NfeStatusServico.URL {
+		t.Error("services absent from override should be kept")
+	}
+	if merged["SP"].Homologacao.NfeAutorizacao.URL != NFe55Config["SP"].Homologacao.NfeAutorizacao.URL {
+		t.Error("environments absent from override should be kept")
+	}
+	if len(merged) != len(NFe55Config) {
+		t.Errorf("expected %d authorizers, got %d", len(NFe55Config), len(merged))
+	}
+	if NFe55Config["SP"].Producao.NfeAutorizacao.URL != "https://nfe.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx" {
+		t.Error("Merge should not modify the defaults")
+	}
+	if merged["SP"].Producao.NfeStatusServico == NFe55Config["SP"].Producao.NfeStatusServico {
+		t.Error("Merge should copy services")
+	}
+}
+
+func TestReloadWebserviceConfig(t *testing.T) {
+	defer ResetWebserviceConfig(types.ModeloNFe55)
+
+	if err := ReloadWebserviceConfig(types.ModeloNFe55, "testdata/wsnfe_4.00_mod55.xml"); err != nil {
+		t.Fatalf("ReloadWebserviceConfig should not return error, got: %v", err)
+	}
+
+	service, err := GetWebserviceURL(types.SP, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao)
+	if err != nil {
+		t.Fatalf("GetWebserviceURL should not return error, got: %v", err)
+	}
+	if service.URL != "https://nfe2.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx" {
+		t.Errorf("reloaded URL should be used, got %s", service.URL)
+	}
+	if !IsServiceAvailable(types.MG, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao) {
+		t.Error("defaults should be kept for authorizers absent from the file")
+	}
+
+	// An invalid file keeps the configuration in use
+	invalid := filepath.Join(t.TempDir(), "ws.json")
+	if err := os.WriteFile(invalid, []byte(`{"SP": {"producao": {"NfeAutorizacao": {"url": "http://x"}}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReloadWebserviceConfig(types.ModeloNFe55, invalid); err == nil {
+		t.Error("ReloadWebserviceConfig should reject an invalid file")
+	}
+	if err := ReloadWebserviceConfig(types.ModeloNFe55, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("ReloadWebserviceConfig should return error for a missing file")
+	}
+	service, _ = GetWebserviceURL(types.SP, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao)
+	if service.URL != "https://nfe2.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx" {
+		t.Errorf("failed reload should keep the configuration in use, got %s", service.URL)
+	}
+
+	ResetWebserviceConfig(types.ModeloNFe55)
+	service, _ = GetWebserviceURL(types.SP, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao)
+	if service.URL != NFe55Config["SP"].Producao.NfeAutorizacao.URL {
+		t.Errorf("ResetWebserviceConfig should restore the defaults, got %s", service.URL)
+	}
+
+	if err := SetWebserviceConfig(types.ModeloNFe(99), NFe55Config); err == nil {
+		t.Error("SetWebserviceConfig should reject unsupported models")
+	}
+}
+
+func TestSetWebserviceConfigCopies(t *testing.T) {
+	defer ResetWebserviceConfig(types.ModeloNFe55)
+
+	config := NFe55Config.Merge(nil)
+	if err := SetWebserviceConfig(types.ModeloNFe55, config); err != nil {
+		t.Fatalf("SetWebserviceConfig should not return error, got: %v", err)
+	}
+
+	config["SP"].Producao.NfeAutorizacao.URL = "http://x.gov.br"
+	delete(config, "MG")
+
+	service, err := GetWebserviceURL(types.SP, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao)
+	if err != nil {
+		t.Fatalf("GetWebserviceURL should not return error, got: %v", err)
+	}
+	if service.URL != NFe55Config["SP"].Producao.NfeAutorizacao.URL {
+		t.Errorf("changes to the caller's map should not affect the configuration in use, got %s", service.URL)
+	}
+	if !IsServiceAvailable(types.MG, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao) {
+		t.Error("authorizers removed from the caller's map should be kept")
+	}
+}
+
+func TestReloadWebserviceConfigConcurrent(t *testing.T) {
+	defer ResetWebserviceConfig(types.ModeloNFe55)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := ReloadWebserviceConfig(types.ModeloNFe55, "testdata/wsnfe_4.00_mod55.xml"); err != nil {
+				t.Errorf("ReloadWebserviceConfig should not return error, got: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			service, err := GetWebserviceURL(types.SP, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao)
+			if err != nil || service.URL == "" {
+				t.Errorf("GetWebserviceURL during reload = %v, %v", service, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
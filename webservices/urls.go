@@ -18,6 +18,19 @@ type Service struct {
 	URL       string `json:"url"`
 }
 
+// WSDLNamespace is the base of the namespaces of the SEFAZ 4.00 webservices
+const WSDLNamespace = "http://www.portalfiscal.inf.br/nfe/wsdl/"
+
+// Namespace returns the namespace of the service messages (nfeDadosMsg and nfeResultMsg)
+func (s *Service) Namespace() string {
+	return WSDLNamespace + s.Operation
+}
+
+// Action returns the SOAP action of the service method
+func (s *Service) Action() string {
+	return s.Namespace() + "/" + s.Method
+}
+
 // Environment represents either production or testing environment
 type Environment struct {
 	NfeStatusServico      *Service `json:"NfeStatusServico,omitempty"`
@@ -65,6 +78,18 @@ var AuthorizeMapping = map[types.ModeloNFe]map[types.UF]string{
 	},
 }
 
+// ContingencyMapping maps each state to the SVC server that replaces its
+// authorizer under tpEmis 6 (SVC-AN) or 7 (SVC-RS), as defined in NT 2013.007
+var ContingencyMapping = map[types.UF]string{
+	types.AC: "SVCAN", types.AL: "SVCAN", types.AM: "SVCRS", types.AP: "SVCAN",
+	types.BA: "SVCRS", types.CE: "SVCRS", types.DF: "SVCAN", types.ES: "SVCAN",
+	types.GO: "SVCRS", types.MA: "SVCRS", types.MG: "SVCAN", types.MS: "SVCRS",
+	types.MT: "SVCRS", types.PA: "SVCRS", types.PB: "SVCAN", types.PE: "SVCRS",
+	types.PI: "SVCRS", types.PR: "SVCRS", types.RJ: "SVCAN", types.RN: "SVCRS",
+	types.RO: "SVCAN", types.RR: "SVCAN", types.RS: "SVCAN", types.SC: "SVCAN",
+	types.SE: "SVCAN", types.SP: "SVCAN", types.TO: "SVCAN",
+}
+
 // ServiceType represents the different types of webservice operations
 type ServiceType string
 
@@ -246,140 +271,1288 @@ var NFe55Config = WebserviceConfig{
 			},
 		},
 	},
-	// SVRS (Sefaz Virtual do Rio Grande do Sul) - Default for many states
-	"SVRS": &StateWebservices{
+	"GO": &StateWebservices{
 		Homologacao: &Environment{
 			NfeStatusServico: &Service{
 				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
-				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeStatusServico4",
 			},
 			NfeAutorizacao: &Service{
 				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
-				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeAutorizacao4",
 			},
 			NfeConsultaProtocolo: &Service{
 				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
-				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeConsultaProtocolo4",
 			},
 			NfeInutilizacao: &Service{
 				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
-				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeInutilizacao4",
 			},
 			NfeRetAutorizacao: &Service{
 				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
-				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeRetAutorizacao4",
 			},
 			RecepcaoEvento: &Service{
 				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
-				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeRecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/CadConsultaCadastro4",
 			},
 		},
 		Producao: &Environment{
 			NfeStatusServico: &Service{
 				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
-				URL: "https://nfe.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeStatusServico4",
 			},
 			NfeAutorizacao: &Service{
 				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
-				URL: "https://nfe.svrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeAutorizacao4",
 			},
 			NfeConsultaProtocolo: &Service{
 				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
-				URL: "https://nfe.svrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeConsultaProtocolo4",
 			},
 			NfeInutilizacao: &Service{
 				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
-				URL: "https://nfe.svrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeInutilizacao4",
 			},
 			NfeRetAutorizacao: &Service{
 				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
-				URL: "https://nfe.svrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeRetAutorizacao4",
 			},
 			RecepcaoEvento: &Service{
 				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
-				URL: "https://nfe.svrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeRecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/CadConsultaCadastro4",
 			},
 		},
 	},
-}
-
-// GetWebserviceURL retrieves the webservice URL for a specific state, environment and service type
-func GetWebserviceURL(uf types.UF, ambiente types.Ambiente, modelo types.ModeloNFe, serviceType ServiceType) (*Service, error) {
-	// Get the authorizing entity for this state and model
-	authorizer, err := GetAuthorizer(uf, modelo)
-	if err != nil {
-		return nil, err
-	}
-
-	// Get the webservice configuration for the authorizer
-	config := getWebserviceConfig(modelo)
-	stateConfig, exists := config[authorizer]
-	if !exists {
-		return nil, errors.NewValidationError(
-			fmt.Sprintf("webservice configuration not found for authorizer: %s", authorizer),
-			"authorizer", authorizer,
-		)
-	}
-
-	// Select the appropriate environment
-	var env *Environment
-	if ambiente == types.AmbienteProducao {
-		env = stateConfig.Producao
-	} else {
-		env = stateConfig.Homologacao
-	}
-
-	if env == nil {
-		return nil, errors.NewValidationError(
-			fmt.Sprintf("environment configuration not found for %s in %s", ambiente.String(), authorizer),
-			"ambiente", ambiente.String(),
-		)
-	}
-
-	// Get the specific service
-	service := getServiceFromEnvironment(env, serviceType)
-	if service == nil {
-		return nil, errors.NewValidationError(
-			fmt.Sprintf("service %s not available for %s in %s environment", serviceType, authorizer, ambiente.String()),
-			"service", string(serviceType),
-		)
-	}
-
-	return service, nil
-}
-
-// GetAuthorizer returns the authorizing entity for a given state and model
-func GetAuthorizer(uf types.UF, modelo types.ModeloNFe) (string, error) {
-	modelMapping, exists := AuthorizeMapping[modelo]
-	if !exists {
-		return "", errors.NewValidationError(
-			fmt.Sprintf("model %d not supported", int(modelo)),
-			"modelo", fmt.Sprintf("%d", int(modelo)),
-		)
-	}
-
-	authorizer, exists := modelMapping[uf]
-	if !exists {
-		return "", errors.NewValidationError(
-			fmt.Sprintf("UF %s not supported for model %d", uf.String(), int(modelo)),
-			"uf", uf.String(),
-		)
-	}
-
-	return authorizer, nil
-}
-
-// getWebserviceConfig returns the appropriate configuration based on model
-func getWebserviceConfig(modelo types.ModeloNFe) WebserviceConfig {
-	switch modelo {
-	case types.ModeloNFe55:
-		return NFe55Config
-	case types.ModeloNFCe65:
-		// For now, NFCe uses the same endpoints as NFe
-		return NFe55Config
-	default:
-		return NFe55Config
+	"MG": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://hnfe.fazenda.mg.gov.br/nfe2/services/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://hnfe.fazenda.mg.gov.br/nfe2/services/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://hnfe.fazenda.mg.gov.br/nfe2/services/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://hnfe.fazenda.mg.gov.br/nfe2/services/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://hnfe.fazenda.mg.gov.br/nfe2/services/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://hnfe.fazenda.mg.gov.br/nfe2/services/NFeRecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://hnfe.fazenda.mg.gov.br/nfe2/services/CadConsultaCadastro4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.fazenda.mg.gov.br/nfe2/services/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.fazenda.mg.gov.br/nfe2/services/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.fazenda.mg.gov.br/nfe2/services/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.fazenda.mg.gov.br/nfe2/services/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.fazenda.mg.gov.br/nfe2/services/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.fazenda.mg.gov.br/nfe2/services/NFeRecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://nfe.fazenda.mg.gov.br/nfe2/services/CadConsultaCadastro4",
+			},
+		},
+	},
+	"MS": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://hom.nfe.sefaz.ms.gov.br/ws/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://hom.nfe.sefaz.ms.gov.br/ws/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://hom.nfe.sefaz.ms.gov.br/ws/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://hom.nfe.sefaz.ms.gov.br/ws/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://hom.nfe.sefaz.ms.gov.br/ws/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://hom.nfe.sefaz.ms.gov.br/ws/NFeRecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://hom.nfe.sefaz.ms.gov.br/ws/CadConsultaCadastro4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.sefaz.ms.gov.br/ws/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.ms.gov.br/ws/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.sefaz.ms.gov.br/ws/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.ms.gov.br/ws/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.ms.gov.br/ws/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.sefaz.ms.gov.br/ws/NFeRecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://nfe.sefaz.ms.gov.br/ws/CadConsultaCadastro4",
+			},
+		},
+	},
+	"MT": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfews/v2/services/NfeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfews/v2/services/NfeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfews/v2/services/NfeConsulta4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfews/v2/services/NfeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfews/v2/services/NfeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfews/v2/services/RecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfews/v2/services/CadConsultaCadastro4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.sefaz.mt.gov.br/nfews/v2/services/NfeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.mt.gov.br/nfews/v2/services/NfeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.sefaz.mt.gov.br/nfews/v2/services/NfeConsulta4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.mt.gov.br/nfews/v2/services/NfeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.mt.gov.br/nfews/v2/services/NfeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.sefaz.mt.gov.br/nfews/v2/services/RecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://nfe.sefaz.mt.gov.br/nfews/v2/services/CadConsultaCadastro4",
+			},
+		},
+	},
+	"PE": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfehomolog.sefaz.pe.gov.br/nfe-service/services/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfehomolog.sefaz.pe.gov.br/nfe-service/services/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfehomolog.sefaz.pe.gov.br/nfe-service/services/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfehomolog.sefaz.pe.gov.br/nfe-service/services/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfehomolog.sefaz.pe.gov.br/nfe-service/services/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfehomolog.sefaz.pe.gov.br/nfe-service/services/NFeRecepcaoEvento4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.sefaz.pe.gov.br/nfe-service/services/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.pe.gov.br/nfe-service/services/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.sefaz.pe.gov.br/nfe-service/services/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.pe.gov.br/nfe-service/services/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.pe.gov.br/nfe-service/services/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.sefaz.pe.gov.br/nfe-service/services/NFeRecepcaoEvento4",
+			},
+		},
+	},
+	"PR": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://homologacao.nfe.sefa.pr.gov.br/nfe/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.nfe.sefa.pr.gov.br/nfe/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://homologacao.nfe.sefa.pr.gov.br/nfe/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://homologacao.nfe.sefa.pr.gov.br/nfe/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.nfe.sefa.pr.gov.br/nfe/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://homologacao.nfe.sefa.pr.gov.br/nfe/NFeRecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://homologacao.nfe.sefa.pr.gov.br/nfe/CadConsultaCadastro4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.sefa.pr.gov.br/nfe/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefa.pr.gov.br/nfe/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.sefa.pr.gov.br/nfe/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.sefa.pr.gov.br/nfe/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefa.pr.gov.br/nfe/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.sefa.pr.gov.br/nfe/NFeRecepcaoEvento4",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://nfe.sefa.pr.gov.br/nfe/CadConsultaCadastro4",
+			},
+		},
+	},
+	"RS": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe-homologacao.sefazrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe-homologacao.sefazrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe-homologacao.sefazrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe-homologacao.sefazrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe-homologacao.sefazrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe-homologacao.sefazrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://cad-homologacao.sefazrs.rs.gov.br/ws/cadconsultacadastro/cadconsultacadastro4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.sefazrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefazrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.sefazrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.sefazrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefazrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.sefazrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://cad.sefazrs.rs.gov.br/ws/cadconsultacadastro/cadconsultacadastro4.asmx",
+			},
+		},
+	},
+	"SP": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://homologacao.nfe.fazenda.sp.gov.br/ws/nfestatusservico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.nfe.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://homologacao.nfe.fazenda.sp.gov.br/ws/nfeconsultaprotocolo4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://homologacao.nfe.fazenda.sp.gov.br/ws/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.nfe.fazenda.sp.gov.br/ws/nferetautorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://homologacao.nfe.fazenda.sp.gov.br/ws/nferecepcaoevento4.asmx",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://homologacao.nfe.fazenda.sp.gov.br/ws/cadconsultacadastro4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.fazenda.sp.gov.br/ws/nfestatusservico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.fazenda.sp.gov.br/ws/nfeconsultaprotocolo4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.fazenda.sp.gov.br/ws/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.fazenda.sp.gov.br/ws/nferetautorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.fazenda.sp.gov.br/ws/nferecepcaoevento4.asmx",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://nfe.fazenda.sp.gov.br/ws/cadconsultacadastro4.asmx",
+			},
+		},
+	},
+	// SVAN (Sefaz Virtual do Ambiente Nacional)
+	"SVAN": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://hom.sefazvirtual.fazenda.gov.br/NFeStatusServico4/NFeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://hom.sefazvirtual.fazenda.gov.br/NFeAutorizacao4/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://hom.sefazvirtual.fazenda.gov.br/NFeConsultaProtocolo4/NFeConsultaProtocolo4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://hom.sefazvirtual.fazenda.gov.br/NFeInutilizacao4/NFeInutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://hom.sefazvirtual.fazenda.gov.br/NFeRetAutorizacao4/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://hom.sefazvirtual.fazenda.gov.br/NFeRecepcaoEvento4/NFeRecepcaoEvento4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://www.sefazvirtual.fazenda.gov.br/NFeStatusServico4/NFeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://www.sefazvirtual.fazenda.gov.br/NFeAutorizacao4/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://www.sefazvirtual.fazenda.gov.br/NFeConsultaProtocolo4/NFeConsultaProtocolo4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://www.sefazvirtual.fazenda.gov.br/NFeInutilizacao4/NFeInutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://www.sefazvirtual.fazenda.gov.br/NFeRetAutorizacao4/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://www.sefazvirtual.fazenda.gov.br/NFeRecepcaoEvento4/NFeRecepcaoEvento4.asmx",
+			},
+		},
+	},
+	// SVC-AN (Sefaz Virtual de Contingência do Ambiente Nacional); SVC does not accept inutilização
+	"SVCAN": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://hom.svc.fazenda.gov.br/NFeStatusServico4/NFeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://hom.svc.fazenda.gov.br/NFeAutorizacao4/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://hom.svc.fazenda.gov.br/NFeConsultaProtocolo4/NFeConsultaProtocolo4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://hom.svc.fazenda.gov.br/NFeRetAutorizacao4/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://hom.svc.fazenda.gov.br/NFeRecepcaoEvento4/NFeRecepcaoEvento4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://www.svc.fazenda.gov.br/NFeStatusServico4/NFeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://www.svc.fazenda.gov.br/NFeAutorizacao4/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://www.svc.fazenda.gov.br/NFeConsultaProtocolo4/NFeConsultaProtocolo4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://www.svc.fazenda.gov.br/NFeRetAutorizacao4/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://www.svc.fazenda.gov.br/NFeRecepcaoEvento4/NFeRecepcaoEvento4.asmx",
+			},
+		},
+	},
+	// SVC-RS (Sefaz Virtual de Contingência do Rio Grande do Sul)
+	"SVCRS": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+		},
+	},
+	// SVRS (Sefaz Virtual do Rio Grande do Sul) - Default for many states
+	"SVRS": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe-homologacao.svrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://cad-homologacao.svrs.rs.gov.br/ws/cadconsultacadastro/cadconsultacadastro4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.svrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+			NfeConsultaCadastro: &Service{
+				Method: "consultaCadastro", Operation: "CadConsultaCadastro4", Version: "2.00",
+				URL: "https://cad.svrs.rs.gov.br/ws/cadconsultacadastro/cadconsultacadastro4.asmx",
+			},
+		},
+	},
+}
+
+// NFCe 4.0 Model 65 Webservices Configuration. NFC-e has no SVC servers: its
+// contingency is offline emission (tpEmis 9), transmitted later to the authorizer.
+var NFCe65Config = WebserviceConfig{
+	"AM": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://homnfce.sefaz.am.gov.br/nfce-services/services/NfeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://homnfce.sefaz.am.gov.br/nfce-services/services/NfeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://homnfce.sefaz.am.gov.br/nfce-services/services/NfeConsulta4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://homnfce.sefaz.am.gov.br/nfce-services/services/NfeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://homnfce.sefaz.am.gov.br/nfce-services/services/NfeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://homnfce.sefaz.am.gov.br/nfce-services/services/RecepcaoEvento4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce.sefaz.am.gov.br/nfce-services/services/NfeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.am.gov.br/nfce-services/services/NfeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce.sefaz.am.gov.br/nfce-services/services/NfeConsulta4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.am.gov.br/nfce-services/services/NfeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.am.gov.br/nfce-services/services/NfeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce.sefaz.am.gov.br/nfce-services/services/RecepcaoEvento4",
+			},
+		},
+	},
+	"GO": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://homolog.sefaz.go.gov.br/nfe/services/NFeRecepcaoEvento4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfe.sefaz.go.gov.br/nfe/services/NFeRecepcaoEvento4",
+			},
+		},
+	},
+	"MG": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://hnfce.fazenda.mg.gov.br/nfce/services/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://hnfce.fazenda.mg.gov.br/nfce/services/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://hnfce.fazenda.mg.gov.br/nfce/services/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://hnfce.fazenda.mg.gov.br/nfce/services/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://hnfce.fazenda.mg.gov.br/nfce/services/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://hnfce.fazenda.mg.gov.br/nfce/services/NFeRecepcaoEvento4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce.fazenda.mg.gov.br/nfce/services/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce.fazenda.mg.gov.br/nfce/services/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce.fazenda.mg.gov.br/nfce/services/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce.fazenda.mg.gov.br/nfce/services/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce.fazenda.mg.gov.br/nfce/services/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce.fazenda.mg.gov.br/nfce/services/NFeRecepcaoEvento4",
+			},
+		},
+	},
+	"MS": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://hom.nfce.sefaz.ms.gov.br/ws/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://hom.nfce.sefaz.ms.gov.br/ws/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://hom.nfce.sefaz.ms.gov.br/ws/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://hom.nfce.sefaz.ms.gov.br/ws/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://hom.nfce.sefaz.ms.gov.br/ws/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://hom.nfce.sefaz.ms.gov.br/ws/NFeRecepcaoEvento4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce.sefaz.ms.gov.br/ws/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.ms.gov.br/ws/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce.sefaz.ms.gov.br/ws/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.ms.gov.br/ws/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.ms.gov.br/ws/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce.sefaz.ms.gov.br/ws/NFeRecepcaoEvento4",
+			},
+		},
+	},
+	"MT": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfcews/services/NfeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfcews/services/NfeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfcews/services/NfeConsulta4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfcews/services/NfeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfcews/services/NfeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://homologacao.sefaz.mt.gov.br/nfcews/services/RecepcaoEvento4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce.sefaz.mt.gov.br/nfcews/services/NfeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.mt.gov.br/nfcews/services/NfeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce.sefaz.mt.gov.br/nfcews/services/NfeConsulta4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.mt.gov.br/nfcews/services/NfeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefaz.mt.gov.br/nfcews/services/NfeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce.sefaz.mt.gov.br/nfcews/services/RecepcaoEvento4",
+			},
+		},
+	},
+	"PR": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://homologacao.nfce.sefa.pr.gov.br/nfce/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.nfce.sefa.pr.gov.br/nfce/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://homologacao.nfce.sefa.pr.gov.br/nfce/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://homologacao.nfce.sefa.pr.gov.br/nfce/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.nfce.sefa.pr.gov.br/nfce/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://homologacao.nfce.sefa.pr.gov.br/nfce/NFeRecepcaoEvento4",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce.sefa.pr.gov.br/nfce/NFeStatusServico4",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefa.pr.gov.br/nfce/NFeAutorizacao4",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce.sefa.pr.gov.br/nfce/NFeConsultaProtocolo4",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce.sefa.pr.gov.br/nfce/NFeInutilizacao4",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefa.pr.gov.br/nfce/NFeRetAutorizacao4",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce.sefa.pr.gov.br/nfce/NFeRecepcaoEvento4",
+			},
+		},
+	},
+	"RS": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce-homologacao.sefazrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce-homologacao.sefazrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce-homologacao.sefazrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce-homologacao.sefazrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce-homologacao.sefazrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce-homologacao.sefazrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce.sefazrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefazrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce.sefazrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce.sefazrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce.sefazrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce.sefazrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+		},
+	},
+	"SP": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://homologacao.nfce.fazenda.sp.gov.br/ws/NFeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.nfce.fazenda.sp.gov.br/ws/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://homologacao.nfce.fazenda.sp.gov.br/ws/NFeConsultaProtocolo4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://homologacao.nfce.fazenda.sp.gov.br/ws/NFeInutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://homologacao.nfce.fazenda.sp.gov.br/ws/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://homologacao.nfce.fazenda.sp.gov.br/ws/NFeRecepcaoEvento4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce.fazenda.sp.gov.br/ws/NFeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce.fazenda.sp.gov.br/ws/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce.fazenda.sp.gov.br/ws/NFeConsultaProtocolo4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce.fazenda.sp.gov.br/ws/NFeInutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce.fazenda.sp.gov.br/ws/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce.fazenda.sp.gov.br/ws/NFeRecepcaoEvento4.asmx",
+			},
+		},
+	},
+	// SVRS (Sefaz Virtual do Rio Grande do Sul) - Default for many states
+	"SVRS": &StateWebservices{
+		Homologacao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce-homologacao.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce-homologacao.svrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce-homologacao.svrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce-homologacao.svrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce-homologacao.svrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce-homologacao.svrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+		},
+		Producao: &Environment{
+			NfeStatusServico: &Service{
+				Method: "nfeStatusServicoNF", Operation: "NFeStatusServico4", Version: "4.00",
+				URL: "https://nfce.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx",
+			},
+			NfeAutorizacao: &Service{
+				Method: "nfeAutorizacaoLote", Operation: "NFeAutorizacao4", Version: "4.00",
+				URL: "https://nfce.svrs.rs.gov.br/ws/NfeAutorizacao/NFeAutorizacao4.asmx",
+			},
+			NfeConsultaProtocolo: &Service{
+				Method: "nfeConsultaNF", Operation: "NFeConsultaProtocolo4", Version: "4.00",
+				URL: "https://nfce.svrs.rs.gov.br/ws/NfeConsulta/NfeConsulta4.asmx",
+			},
+			NfeInutilizacao: &Service{
+				Method: "nfeInutilizacaoNF", Operation: "NFeInutilizacao4", Version: "4.00",
+				URL: "https://nfce.svrs.rs.gov.br/ws/nfeinutilizacao/nfeinutilizacao4.asmx",
+			},
+			NfeRetAutorizacao: &Service{
+				Method: "nfeRetAutorizacaoLote", Operation: "NFeRetAutorizacao4", Version: "4.00",
+				URL: "https://nfce.svrs.rs.gov.br/ws/NfeRetAutorizacao/NFeRetAutorizacao4.asmx",
+			},
+			RecepcaoEvento: &Service{
+				Method: "nfeRecepcaoEvento", Operation: "NFeRecepcaoEvento4", Version: "1.00",
+				URL: "https://nfce.svrs.rs.gov.br/ws/recepcaoevento/recepcaoevento4.asmx",
+			},
+		},
+	},
+}
+
+// GetWebserviceURL retrieves the webservice URL for a specific state, environment and service type
+func GetWebserviceURL(uf types.UF, ambiente types.Ambiente, modelo types.ModeloNFe, serviceType ServiceType) (*Service, error) {
+	// Get the authorizing entity for this state and model
+	authorizer, err := GetAuthorizer(uf, modelo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the webservice configuration for the authorizer
+	config := getWebserviceConfig(modelo)
+	stateConfig, exists := config[authorizer]
+	if !exists {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("webservice configuration not found for authorizer: %s", authorizer),
+			"authorizer", authorizer,
+		)
+	}
+
+	// Select the appropriate environment
+	var env *Environment
+	if ambiente == types.AmbienteProducao {
+		env = stateConfig.Producao
+	} else {
+		env = stateConfig.Homologacao
+	}
+
+	if env == nil {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("environment configuration not found for %s in %s", ambiente.String(), authorizer),
+			"ambiente", ambiente.String(),
+		)
+	}
+
+	// Get the specific service
+	service := getServiceFromEnvironment(env, serviceType)
+	if service == nil {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("service %s not available for %s in %s environment", serviceType, authorizer, ambiente.String()),
+			"service", string(serviceType),
+		)
+	}
+
+	return service, nil
+}
+
+// GetAuthorizer returns the authorizing entity for a given state and model
+func GetAuthorizer(uf types.UF, modelo types.ModeloNFe) (string, error) {
+	modelMapping, exists := AuthorizeMapping[modelo]
+	if !exists {
+		return "", errors.NewValidationError(
+			fmt.Sprintf("model %d not supported", int(modelo)),
+			"modelo", fmt.Sprintf("%d", int(modelo)),
+		)
+	}
+
+	authorizer, exists := modelMapping[uf]
+	if !exists {
+		return "", errors.NewValidationError(
+			fmt.Sprintf("UF %s not supported for model %d", uf.String(), int(modelo)),
+			"uf", uf.String(),
+		)
+	}
+
+	return authorizer, nil
+}
+
+// GetContingencyAuthorizer returns the SVC server that takes over for a state
+// during contingency. Only model 55 has SVC servers.
+func GetContingencyAuthorizer(uf types.UF) (string, error) {
+	authorizer, exists := ContingencyMapping[uf]
+	if !exists {
+		return "", errors.NewValidationError(
+			fmt.Sprintf("UF %s has no contingency server", uf.String()),
+			"uf", uf.String(),
+		)
+	}
+	return authorizer, nil
+}
+
+// getWebserviceConfig returns the configuration in use for a model
+func getWebserviceConfig(modelo types.ModeloNFe) WebserviceConfig {
+	active.mu.RLock()
+	defer active.mu.RUnlock()
+
+	if config, exists := active.configs[modelo]; exists {
+		return config
 	}
+	return active.configs[types.ModeloNFe55]
 }
 
 // getServiceFromEnvironment extracts a specific service from an environment configuration
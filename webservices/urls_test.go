@@ -229,7 +229,7 @@ func TestServiceTypes(t *testing.T) {
 
 func TestNFe55ConfigStructure(t *testing.T) {
 	// Test that NFe55Config has required states
-	requiredStates := []string{"AM", "AN", "BA", "SVRS"}
+	requiredStates := []string{"AM", "AN", "BA", "GO", "MG", "MS", "MT", "PE", "PR", "RS", "SP", "SVAN", "SVCAN", "SVCRS", "SVRS"}
 	
 	for _, state := range requiredStates {
 		if _, exists := NFe55Config[state]; !exists {
@@ -326,4 +326,107 @@ func containsHelper(s, substr string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+func TestAuthorizersHaveWebservices(t *testing.T) {
+	for modelo, mapping := range AuthorizeMapping {
+		config := getWebserviceConfig(modelo)
+		for uf, authorizer := range mapping {
+			stateConfig, exists := config[authorizer]
+			if !exists {
+				t.Errorf("model %d: authorizer %s (UF %s) has no webservice configuration", int(modelo), authorizer, uf.String())
+				continue
+			}
+			if stateConfig.Homologacao == nil || stateConfig.Producao == nil {
+				t.Errorf("model %d: authorizer %s should have both environments", int(modelo), authorizer)
+				continue
+			}
+			if authorizer == "AN" {
+				continue
+			}
+			for _, ambiente := range []types.Ambiente{types.AmbienteHomologacao, types.AmbienteProducao} {
+				for _, serviceType := range []ServiceType{ServiceStatusServico, ServiceAutorizacao, ServiceRetAutorizacao, ServiceConsultaProtocolo, ServiceRecepcaoEvento} {
+					if !IsServiceAvailable(uf, ambiente, modelo, serviceType) {
+						t.Errorf("model %d: %s not available for UF %s in %s", int(modelo), serviceType, uf.String(), ambiente.String())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGetWebserviceURLCatalog(t *testing.T) {
+	tests := []struct {
+		uf          types.UF
+		ambiente    types.Ambiente
+		modelo      types.ModeloNFe
+		serviceType ServiceType
+		expected    string
+	}{
+		{types.SP, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao, "https://nfe.fazenda.sp.gov.br/ws/nfeautorizacao4.asmx"},
+		{types.SP, types.AmbienteHomologacao, types.ModeloNFe55, ServiceConsultaCadastro, "https://homologacao.nfe.fazenda.sp.gov.br/ws/cadconsultacadastro4.asmx"},
+		{types.MG, types.AmbienteHomologacao, types.ModeloNFe55, ServiceStatusServico, "https://hnfe.fazenda.mg.gov.br/nfe2/services/NFeStatusServico4"},
+		{types.MA, types.AmbienteProducao, types.ModeloNFe55, ServiceRecepcaoEvento, "https://www.sefazvirtual.fazenda.gov.br/NFeRecepcaoEvento4/NFeRecepcaoEvento4.asmx"},
+		{types.SC, types.AmbienteProducao, types.ModeloNFe55, ServiceConsultaCadastro, "https://cad.svrs.rs.gov.br/ws/cadconsultacadastro/cadconsultacadastro4.asmx"},
+		{types.SVCAN, types.AmbienteProducao, types.ModeloNFe55, ServiceAutorizacao, "https://www.svc.fazenda.gov.br/NFeAutorizacao4/NFeAutorizacao4.asmx"},
+		{types.SVCRS, types.AmbienteHomologacao, types.ModeloNFe55, ServiceStatusServico, "https://nfe-homologacao.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx"},
+		{types.AN, types.AmbienteProducao, types.ModeloNFe55, ServiceDistribuicaoDFe, "https://www1.nfe.fazenda.gov.br/NFeDistribuicaoDFe/NFeDistribuicaoDFe.asmx"},
+		{types.SP, types.AmbienteProducao, types.ModeloNFCe65, ServiceAutorizacao, "https://nfce.fazenda.sp.gov.br/ws/NFeAutorizacao4.asmx"},
+		{types.AC, types.AmbienteHomologacao, types.ModeloNFCe65, ServiceStatusServico, "https://nfce-homologacao.svrs.rs.gov.br/ws/NfeStatusServico/NfeStatusServico4.asmx"},
+	}
+
+	for _, test := range tests {
+		service, err := GetWebserviceURL(test.uf, test.ambiente, test.modelo, test.serviceType)
+		if err != nil {
+			t.Errorf("GetWebserviceURL(%s, %s, %d, %s) should not return error, got: %v", test.uf.String(), test.ambiente.String(), int(test.modelo), test.serviceType, err)
+			continue
+		}
+		if service.URL != test.expected {
+			t.Errorf("GetWebserviceURL(%s, %s, %d, %s) = %s, expected %s", test.uf.String(), test.ambiente.String(), int(test.modelo), test.serviceType, service.URL, test.expected)
+		}
+	}
+
+	// SVC servers do not accept inutilização
+	if IsServiceAvailable(types.SVCAN, types.AmbienteProducao, types.ModeloNFe55, ServiceInutilizacao) {
+		t.Error("SVCAN should not offer NfeInutilizacao")
+	}
+}
+
+func TestGetContingencyAuthorizer(t *testing.T) {
+	tests := []struct {
+		uf       types.UF
+		expected string
+		hasError bool
+	}{
+		{types.SP, "SVCAN", false},
+		{types.MG, "SVCAN", false},
+		{types.BA, "SVCRS", false},
+		{types.PR, "SVCRS", false},
+		{types.SVRS, "", true},
+	}
+
+	for _, test := range tests {
+		result, err := GetContingencyAuthorizer(test.uf)
+		if test.hasError {
+			if err == nil {
+				t.Errorf("GetContingencyAuthorizer(%s) should return error", test.uf.String())
+			}
+			continue
+		}
+		if err != nil || result != test.expected {
+			t.Errorf("GetContingencyAuthorizer(%s) = %s, %v, expected %s", test.uf.String(), result, err, test.expected)
+		}
+		if config := NFe55Config[result]; config == nil || config.Producao == nil || config.Producao.NfeAutorizacao == nil {
+			t.Errorf("contingency server %s should have a production NfeAutorizacao", result)
+		}
+	}
+}
+
+func TestServiceNamespaceAndAction(t *testing.T) {
+	service := NFe55Config["SVRS"].Producao.NfeAutorizacao
+	if service.Namespace() != "http://www.portalfiscal.inf.br/nfe/wsdl/NFeAutorizacao4" {
+		t.Errorf("unexpected namespace %s", service.Namespace())
+	}
+	if service.Action() != "http://www.portalfiscal.inf.br/nfe/wsdl/NFeAutorizacao4/nfeAutorizacaoLote" {
+		t.Errorf("unexpected action %s", service.Action())
+	}
+}